@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/team-inu/inu-backyard/entity"
 	"github.com/team-inu/inu-backyard/infrastructure/database"
@@ -20,16 +21,29 @@ func main() {
 		panic(err)
 	}
 
+	// accounts older than the password expiry have no change date yet, they start a full expiry period now
+	backfillPasswordChangedAt := !gormDB.Migrator().HasColumn(&entity.User{}, "PasswordChangedAt")
+
 	err = gormDB.AutoMigrate(
 		&entity.AssignmentGroup{},
 		&entity.Assignment{},
 		&entity.CourseLearningOutcome{},
+		&entity.CloPo{},
+		&entity.CloSubplo{},
+		&entity.CloSubso{},
+		&entity.AssessmentItem{},
+		&entity.AssessmentItemScore{},
+		&entity.Rubric{},
+		&entity.RubricCriterion{},
+		&entity.RubricLevel{},
 		&entity.CourseStream{},
+		&entity.CurriculumMapLevel{},
 		&entity.Course{},
 		&entity.Department{},
 		&entity.Enrollment{},
 		&entity.Faculty{},
 		&entity.Feedback{},
+		&entity.FeedbackToken{},
 		&entity.Grade{},
 		&entity.GraduatedStudent{},
 		&entity.User{},
@@ -42,13 +56,36 @@ func main() {
 		&entity.Score{},
 		&entity.Semester{},
 		&entity.Session{},
+		&entity.PasswordResetToken{},
+		&entity.MailOutbox{},
+		&entity.Notification{},
+		&entity.NotificationPreference{},
+		&entity.SemesterMilestone{},
+		&entity.SchedulerLease{},
+		&entity.ReportJob{},
+		&entity.StoredFile{},
+		&entity.StudentCloAttainment{},
+		&entity.StudentPloAttainment{},
+		&entity.StudentPoAttainment{},
+		&entity.StudentSoAttainment{},
+		&entity.StudentSubPloAttainment{},
+		&entity.StudentSubSoAttainment{},
+		&entity.AttainmentRefresh{},
+		&entity.LoginThrottle{},
+		&entity.PasswordHistory{},
+		&entity.RecoveryCode{},
+		&entity.TwoFactorPolicy{},
 		&entity.StudentOutcome{},
 		&entity.Student{},
 		&entity.SubProgramLearningOutcome{},
 		&entity.Survey{},
+		&entity.SurveyInvitation{},
 		&entity.Question{},
 		&entity.QScore{},
 	)
+	if err == nil && backfillPasswordChangedAt {
+		err = gormDB.Model(&entity.User{}).Where("password_changed_at IS NULL").Update("password_changed_at", time.Now()).Error
+	}
 
 	fmt.Println(err)
 }
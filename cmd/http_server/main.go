@@ -10,7 +10,6 @@ import (
 	"github.com/team-inu/inu-backyard/infrastructure/fiber"
 	"github.com/team-inu/inu-backyard/internal/config"
 	"github.com/team-inu/inu-backyard/internal/logger"
-	"github.com/team-inu/inu-backyard/internal/utils/session"
 )
 
 func MustGetenv(key string) string {
@@ -38,14 +37,11 @@ func main() {
 
 	turnstile := captcha.NewTurnstile(fiberConfig.Client.Auth.Turnstile.SecretKey)
 
-	session := session.NewSession()
-
 	fiberServer := fiber.NewFiberServer(
 		fiberConfig,
 		gormDB,
 		turnstile,
 		zapLogger,
-		session,
 	)
 
 	fiberServer.Run()
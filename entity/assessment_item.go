@@ -0,0 +1,94 @@
+package entity
+
+import "time"
+
+// AssessmentItem is a question of an assignment scored and linked to CLOs on its own, the score of an assignment
+// with items is the total of its item scores and its CLOs are evaluated from the items instead of the whole assignment
+type AssessmentItem struct {
+	Id           string  `json:"id" gorm:"primaryKey;type:char(255)"`
+	AssignmentId string  `json:"assignment_id" gorm:"type:char(255);index;not null"`
+	Name         string  `json:"name"`
+	Description  string  `json:"description"`
+	MaxScore     float64 `json:"max_score"`
+	// share of the max score to pass the item
+	ExpectedScorePercentage float64 `json:"expected_score_percentage"`
+	Sequence                int     `json:"sequence"`
+
+	CourseLearningOutcomes []*CourseLearningOutcome `json:"course_learning_outcomes" gorm:"many2many:clo_assessment_item"`
+
+	// the item of a criterion of the rubric of the assignment, it is scored by the levels of the criterion
+	RubricCriterionId *string          `json:"rubric_criterion_id" gorm:"type:char(255)"`
+	RubricCriterion   *RubricCriterion `json:"rubric_criterion,omitempty"`
+}
+
+type AssessmentItemScore struct {
+	AssessmentItemId string    `json:"assessment_item_id" gorm:"primaryKey;type:char(255)"`
+	StudentId        string    `json:"student_id" gorm:"primaryKey;type:char(255)"`
+	Score            float64   `json:"score"`
+	UserId           string    `json:"user_id"`
+	UpdatedAt        time.Time `json:"updated_at"`
+
+	// the level the score comes from when the item is scored by a rubric criterion
+	RubricLevelId *string `json:"rubric_level_id" gorm:"type:char(255)"`
+}
+
+type AssessmentItemRepository interface {
+	GetById(id string) (*AssessmentItem, error)
+	GetByAssignmentId(assignmentId string) ([]AssessmentItem, error)
+	Create(item *AssessmentItem) error
+	Update(id string, item *AssessmentItem) error
+	// Delete removes the item with its scores and sets the scores of the assignment to the totals of the remaining items
+	Delete(id string) error
+
+	CreateLinkCourseLearningOutcome(id string, courseLearningOutcomeIds []string) error
+	DeleteLinkCourseLearningOutcome(id string, courseLearningOutcomeId string) error
+
+	GetScoresByAssignmentId(assignmentId string) ([]AssessmentItemScore, error)
+	// SaveScores upserts the item scores and sets the scores of the assignment of their students to their totals
+	SaveScores(assignmentId string, userId string, scores []AssessmentItemScore) error
+}
+
+type AssessmentItemUseCase interface {
+	GetById(id string) (*AssessmentItem, error)
+	GetByAssignmentId(assignmentId string) ([]AssessmentItem, error)
+	Create(assignmentId string, payload CreateAssessmentItemPayload) error
+	Update(id string, payload UpdateAssessmentItemPayload) error
+	Delete(id string) error
+
+	CreateLinkCourseLearningOutcome(id string, courseLearningOutcomeIds []string) error
+	DeleteLinkCourseLearningOutcome(id string, courseLearningOutcomeId string) error
+
+	GetScoresByAssignmentId(assignmentId string) ([]AssessmentItemScore, error)
+	SaveScores(user User, assignmentId string, studentScores []StudentItemScores) error
+}
+
+// the max scores of the items of an assignment add up to at most the max score of the assignment
+type CreateAssessmentItemPayload struct {
+	Name        string   `json:"name" validate:"required"`
+	Description string   `json:"description"`
+	MaxScore    *float64 `json:"max_score" validate:"required,gt=0"`
+	// the one of the assignment when not given
+	ExpectedScorePercentage  *float64 `json:"expected_score_percentage" validate:"omitempty,min=0,max=100"`
+	Sequence                 int      `json:"sequence"`
+	CourseLearningOutcomeIds []string `json:"course_learning_outcome_ids"`
+}
+
+type UpdateAssessmentItemPayload struct {
+	Name                    string   `json:"name"`
+	Description             string   `json:"description"`
+	MaxScore                *float64 `json:"max_score" validate:"omitempty,gt=0"`
+	ExpectedScorePercentage *float64 `json:"expected_score_percentage" validate:"omitempty,min=0,max=100"`
+	Sequence                *int     `json:"sequence"`
+}
+
+type StudentItemScores struct {
+	StudentId string `json:"student_id" validate:"required"`
+	// by assessment item id, the items not given keep their scores
+	Scores map[string]float64 `json:"scores" validate:"dive,min=0"`
+	// rubric level id by assessment item id, for the items of rubric criteria
+	Levels map[string]string `json:"levels" validate:"dive,required"`
+}
+
+type SaveAssessmentItemScoresPayload struct {
+	StudentScores []StudentItemScores `json:"student_scores" validate:"required,dive"`
+}
@@ -57,6 +57,9 @@ type Assignment struct {
 	AssignmentGroupId                string                   `json:"assignment_group_id" gorm:"not null"`
 	CourseId                         string                   `json:"course_id" gorm:"->;-:migration"`
 	CourseLearningOutcomes           []*CourseLearningOutcome `json:"course_learning_outcomes" gorm:"many2many:clo_assignment"`
+
+	// the rubric grading the assignment through assessment items
+	RubricId *string `json:"rubric_id" gorm:"type:char(255);index"`
 }
 
 type AssignmentGroup struct {
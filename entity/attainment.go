@@ -0,0 +1,182 @@
+package entity
+
+import "time"
+
+// AttainmentMethod is how the results of a student on the assignments of a CLO, or on the CLOs of an outcome, make an attainment
+type AttainmentMethod string
+
+const (
+	// passes when the weighted share of passed items meets the expected percentage
+	AttainmentMethodThreshold AttainmentMethod = "THRESHOLD"
+	// passes when the average score percentage, weighted by max score and link weight, meets the expected percentage
+	AttainmentMethodWeightedAverage AttainmentMethod = "WEIGHTED_AVERAGE"
+	// passes when any item passes, the percentage is the best score percentage
+	AttainmentMethodBestOf AttainmentMethod = "BEST_OF"
+	// passes when every item passes
+	AttainmentMethodAllMustPass AttainmentMethod = "ALL_MUST_PASS"
+	// passes when the average rubric level of the items reaches the passing level
+	AttainmentMethodRubricLevel AttainmentMethod = "RUBRIC_LEVEL"
+)
+
+var AttainmentMethods = []AttainmentMethod{
+	AttainmentMethodThreshold,
+	AttainmentMethodWeightedAverage,
+	AttainmentMethodBestOf,
+	AttainmentMethodAllMustPass,
+	AttainmentMethodRubricLevel,
+}
+
+func (m AttainmentMethod) IsValid() bool {
+	for _, method := range AttainmentMethods {
+		if m == method {
+			return true
+		}
+	}
+
+	return false
+}
+
+// StudentCloAttainment is the materialized result of a student on a CLO, rows exist for every enrolled student
+// with a score in the course and every CLO with included assignments, a missing score counts as not passed
+type StudentCloAttainment struct {
+	StudentId               string `json:"student_id" gorm:"primaryKey;type:char(255)"`
+	CourseLearningOutcomeId string `json:"course_learning_outcome_id" gorm:"primaryKey;type:char(255)"`
+	CourseId                string `json:"course_id" gorm:"type:char(255);index"`
+	// share of the linked assignments passed, or a score percentage depending on the attainment method
+	Percentage float64   `json:"percentage"`
+	Passed     bool      `json:"passed"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// StudentPloAttainment is the result of a student on a PLO in a course, rolled up from the CLOs linked through its sub PLOs
+type StudentPloAttainment struct {
+	CourseId                 string `json:"course_id" gorm:"primaryKey;type:char(255)"`
+	StudentId                string `json:"student_id" gorm:"primaryKey;type:char(255)"`
+	ProgramLearningOutcomeId string `json:"program_learning_outcome_id" gorm:"primaryKey;type:char(255)"`
+	// share of the linked CLOs passed, or a score percentage depending on the attainment method
+	Percentage float64   `json:"percentage"`
+	Passed     bool      `json:"passed"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// StudentSubPloAttainment is the result of a student on a sub PLO in a course, the course reports read them
+type StudentSubPloAttainment struct {
+	CourseId                    string    `json:"course_id" gorm:"primaryKey;type:char(255)"`
+	StudentId                   string    `json:"student_id" gorm:"primaryKey;type:char(255)"`
+	SubProgramLearningOutcomeId string    `json:"sub_program_learning_outcome_id" gorm:"primaryKey;type:char(255)"`
+	Percentage                  float64   `json:"percentage"`
+	Passed                      bool      `json:"passed"`
+	UpdatedAt                   time.Time `json:"updated_at"`
+}
+
+type StudentPoAttainment struct {
+	CourseId         string    `json:"course_id" gorm:"primaryKey;type:char(255)"`
+	StudentId        string    `json:"student_id" gorm:"primaryKey;type:char(255)"`
+	ProgramOutcomeId string    `json:"program_outcome_id" gorm:"primaryKey;type:char(255)"`
+	Percentage       float64   `json:"percentage"`
+	Passed           bool      `json:"passed"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// StudentSoAttainment is the result of a student on an SO in a course, rolled up from the CLOs linked through its sub SOs
+type StudentSoAttainment struct {
+	CourseId         string    `json:"course_id" gorm:"primaryKey;type:char(255)"`
+	StudentId        string    `json:"student_id" gorm:"primaryKey;type:char(255)"`
+	StudentOutcomeId string    `json:"student_outcome_id" gorm:"primaryKey;type:char(255)"`
+	Percentage       float64   `json:"percentage"`
+	Passed           bool      `json:"passed"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+type StudentSubSoAttainment struct {
+	CourseId            string    `json:"course_id" gorm:"primaryKey;type:char(255)"`
+	StudentId           string    `json:"student_id" gorm:"primaryKey;type:char(255)"`
+	SubStudentOutcomeId string    `json:"sub_student_outcome_id" gorm:"primaryKey;type:char(255)"`
+	Percentage          float64   `json:"percentage"`
+	Passed              bool      `json:"passed"`
+	UpdatedAt           time.Time `json:"updated_at"`
+}
+
+// AttainmentRefresh marks the attainments of a course stale, an empty student id marks the whole course
+type AttainmentRefresh struct {
+	CourseId    string    `gorm:"primaryKey;type:char(255)"`
+	StudentId   string    `gorm:"primaryKey;type:char(255)"`
+	RequestedAt time.Time `gorm:"index"`
+}
+
+// AttainmentAssignment is an assignment or an assessment item of an assignment, CLOs of an assignment with items are
+// evaluated from the items
+type AttainmentAssignment struct {
+	Id                      string
+	MaxScore                float64
+	ExpectedScorePercentage float64
+}
+
+type AttainmentClo struct {
+	Id                                  string
+	ExpectedPassingAssignmentPercentage float64
+	AssignmentIds                       []string
+}
+
+// AttainmentScore is the score of a student on an assignment included in CLO evaluation
+type AttainmentScore struct {
+	StudentId    string
+	AssignmentId string
+	Score        float64
+}
+
+// AttainmentOutcomeLink links a CLO to an outcome, PLOs and SOs are linked through their sub outcomes with the highest weight among them
+type AttainmentOutcomeLink struct {
+	CourseLearningOutcomeId string
+	OutcomeId               string
+	Weight                  float64
+}
+
+// AttainmentSource is what the attainments of the stale students of a course are computed from
+type AttainmentSource struct {
+	CourseId                     string
+	ExpectedPassingCloPercentage float64
+	// the method of the course, else of its programme, empty when neither chose one
+	Method AttainmentMethod
+	// enrolled students to recompute, the stored rows of every other stale student are removed
+	StudentIds  []string
+	Assignments []AttainmentAssignment
+	Clos        []AttainmentClo
+	Scores      []AttainmentScore
+	PloLinks    []AttainmentOutcomeLink
+	SubPloLinks []AttainmentOutcomeLink
+	PoLinks     []AttainmentOutcomeLink
+	SoLinks     []AttainmentOutcomeLink
+	SubSoLinks  []AttainmentOutcomeLink
+}
+
+type CourseAttainment struct {
+	Clos    []StudentCloAttainment
+	Plos    []StudentPloAttainment
+	SubPlos []StudentSubPloAttainment
+	Pos     []StudentPoAttainment
+	Sos     []StudentSoAttainment
+	SubSos  []StudentSubSoAttainment
+}
+
+// IsAttained tells whether the passing weight out of the total meets the expected percentage, nothing to pass is not attained
+func IsAttained(weight float64, totalWeight float64, expectedPercentage float64) bool {
+	return totalWeight > 0 && weight*100 >= expectedPercentage*totalWeight
+}
+
+type AttainmentRepository interface {
+	// Queue marks the students of the course stale, the whole course when no student is given
+	Queue(courseId string, studentIds []string) error
+	// QueueAll marks every course stale and returns the number of courses
+	QueueAll() (int64, error)
+	// RefreshNext claims the course queued first, replaces the attainments of its stale students with the result of
+	// evaluate and removes it from the queue in one transaction, it returns false when nothing is queued
+	RefreshNext(evaluate func(source AttainmentSource) CourseAttainment) (bool, error)
+}
+
+type AttainmentUseCase interface {
+	// RefreshAll recomputes the attainments of every course in the background
+	RefreshAll() (int64, error)
+	// ProcessNext recomputes the stale attainments of one course, it returns false when nothing is stale
+	ProcessNext() (bool, error)
+}
@@ -1,18 +1,108 @@
 package entity
 
-import "github.com/gofiber/fiber/v2"
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// appended to the session cookie name for the cookie holding the single sign-on state
+const SsoStateCookieSuffix = "_sso"
 
 type AuthUseCase interface {
-	Authenticate(header string) (*User, error)
-	SignIn(payload SignInPayload, ipAddress string, userAgent string) (*fiber.Cookie, error)
+	Authenticate(header string) (*Authentication, error)
+	SignIn(payload SignInPayload, ipAddress string, userAgent string) (*SignInResult, error)
+	// VerifyTwoFactor completes a sign in with a TOTP or recovery code, confirming the enrollment when it is pending
+	VerifyTwoFactor(payload VerifyTwoFactorPayload, ipAddress string) (*TwoFactorSignInResult, error)
+	// BeginChallengeEnrollment lets a user who must use two-factor enroll during sign in
+	BeginChallengeEnrollment(challenge string) (*TotpEnrollment, error)
 	SignOut(header string) (*fiber.Cookie, error)
 	ChangePassword(userId string, oldPassword string, newPassword string) error
-	ForgotPassword(email string) error
-	ResetPassword(email string, token string, newPassword string) error
-	GetSession(email string) (string, error)
+	ForgotPassword(email string, ipAddress string) error
+	ResetPassword(email string, token string, newPassword string, ipAddress string) error
+	// UnlockUser lifts the lockouts of the user after too many failed attempts
+	UnlockUser(userId string) error
+
+	// BeginSsoSignIn returns the identity provider address to redirect to and the cookie holding the login state
+	BeginSsoSignIn() (string, *fiber.Cookie, error)
+	// SsoSignIn completes a single sign-on, returning a two-factor challenge like SignIn when the user needs a second factor
+	SsoSignIn(payload SsoCallbackPayload, stateCookie string, ipAddress string, userAgent string) (*SignInResult, error)
 }
 
 type SignInPayload struct {
 	Email    string `json:"email" validate:"email,required"`
 	Password string `json:"password" validate:"required"`
 }
+
+// Cookie is set when the session was renewed by the sliding expiration
+type Authentication struct {
+	User    *User
+	Session *Session
+	Cookie  *fiber.Cookie
+	// only changing the password and signing out are allowed until the user sets a new password
+	PasswordChangeRequired bool
+}
+
+// Either the session cookie, or the challenge when a second factor is needed
+type SignInResult struct {
+	Cookie                 *fiber.Cookie
+	TwoFactorChallenge     *TwoFactorChallenge
+	PasswordChangeRequired bool
+}
+
+type TwoFactorChallenge struct {
+	Challenge string `json:"challenge"`
+	// the user has to enroll before the first code can be verified
+	EnrollmentRequired bool      `json:"enrollment_required"`
+	ExpiredAt          time.Time `json:"expired_at"`
+}
+
+type VerifyTwoFactorPayload struct {
+	Challenge string `json:"challenge" validate:"required"`
+	Code      string `json:"code" validate:"required"`
+}
+
+type ChallengeEnrollmentPayload struct {
+	Challenge string `json:"challenge" validate:"required"`
+}
+
+type TwoFactorSignInResult struct {
+	Cookie *fiber.Cookie
+	// only set when the sign in confirmed a new enrollment
+	RecoveryCodes          []string
+	PasswordChangeRequired bool
+}
+
+type SsoCallbackPayload struct {
+	Code  string `query:"code" validate:"required"`
+	State string `query:"state" validate:"required"`
+}
+
+// Identity asserted by the identity provider after a successful login
+type SsoIdentity struct {
+	Subject string
+	Email   string
+}
+
+// SsoProvider is a single sign-on protocol, OIDC is the only one for now
+type SsoProvider interface {
+	AuthCodeUrl(state string, nonce string, codeChallenge string) (string, error)
+	Exchange(code string, codeVerifier string, nonce string) (*SsoIdentity, error)
+}
+
+// Credential accepted by a CredentialVerifier
+type VerifiedCredential struct {
+	Email       string
+	FirstNameEN string
+	LastNameEN  string
+	// roles mapped from directory groups, empty when no group is mapped
+	Roles []UserRole
+	// whether the user can be created on first sign in
+	CreateUser bool
+}
+
+// CredentialVerifier checks an email and password on sign in, verifiers are tried in order until one accepts
+type CredentialVerifier interface {
+	// Verify returns nil credential and nil error when the email is unknown to the verifier
+	Verify(email string, password string) (*VerifiedCredential, error)
+}
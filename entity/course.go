@@ -98,6 +98,9 @@ type Course struct {
 
 	CriteriaGrade
 
+	// empty uses the method of the programme
+	AttainmentMethod AttainmentMethod `json:"attainment_method" gorm:"type:char(32)"`
+
 	ProgrammeId string `json:"programme_id"`
 	SemesterId  string `json:"semester_id"`
 
@@ -118,6 +121,8 @@ type CreateCoursePayload struct {
 	ExpectedPassingCloPercentage float64  `json:"expected_passing_clo_percentage" validate:"required"`
 	ProgrammeId                  string   `json:"programme_id" validate:"required"`
 	CriteriaGrade
+
+	AttainmentMethod AttainmentMethod `json:"attainment_method" validate:"omitempty,oneof=THRESHOLD WEIGHTED_AVERAGE BEST_OF ALL_MUST_PASS RUBRIC_LEVEL"`
 }
 
 type UpdateCoursePayload struct {
@@ -132,6 +137,8 @@ type UpdateCoursePayload struct {
 	ExpectedPassingCloPercentage float64  `json:"expected_passing_clo_percentage" `
 	ProgrammeId                  string   `json:"programme_id" `
 	CriteriaGrade
+
+	AttainmentMethod AttainmentMethod `json:"attainment_method" validate:"omitempty,oneof=THRESHOLD WEIGHTED_AVERAGE BEST_OF ALL_MUST_PASS RUBRIC_LEVEL"`
 }
 
 type Lecturer struct {
@@ -5,9 +5,10 @@ type CourseLearningOutcomeRepository interface {
 	GetById(id string) (*CourseLearningOutcome, error)
 	GetByCourseId(courseId string) ([]CourseLearningOutcome, error)
 	Create(courseLearningOutcome *CourseLearningOutcome) error
-	CreateLinkProgramOutcome(id string, programOutcomeIds []string) error
-	CreateLinkSubProgramLearningOutcome(id string, subProgramLearningOutcomeIds []string) error
-	CreateLinkSubStudentOutcome(id string, subStudentOutcomeIds []string) error
+	// CreateLink* link the CLO with the weight of each outcome, linking an outcome again updates its weight
+	CreateLinkProgramOutcome(id string, programOutcomeIds []string, weights map[string]float64) error
+	CreateLinkSubProgramLearningOutcome(id string, subProgramLearningOutcomeIds []string, weights map[string]float64) error
+	CreateLinkSubStudentOutcome(id string, subStudentOutcomeIds []string, weights map[string]float64) error
 	Update(id string, courseLearningOutcome *CourseLearningOutcome) error
 	Delete(id string) error
 	DeleteLinkProgramOutcome(id string, programOutcomeId string) error
@@ -23,9 +24,10 @@ type CourseLearningOutcomeUseCase interface {
 	GetById(id string) (*CourseLearningOutcome, error)
 	GetByCourseId(courseId string) ([]GetCloResponse, error)
 	Create(dto CreateCourseLearningOutcomePayload) error
-	CreateLinkProgramOutcome(id string, programOutcomeIds []string) error
-	CreateLinkSubProgramLearningOutcome(id string, subProgramLearningOutcomeIds []string) error
-	CreateLinkSubStudentOutcome(id string, subStudentOutcomeIds []string) error
+	// CreateLink* weigh the outcomes without a weight with DefaultOutcomeLinkWeight
+	CreateLinkProgramOutcome(id string, programOutcomeIds []string, weights map[string]float64) error
+	CreateLinkSubProgramLearningOutcome(id string, subProgramLearningOutcomeIds []string, weights map[string]float64) error
+	CreateLinkSubStudentOutcome(id string, subStudentOutcomeIds []string, weights map[string]float64) error
 	Update(id string, dto UpdateCourseLearningOutcomePayload) error
 	Delete(id string) error
 	DeleteLinkProgramOutcome(id string, programOutcomeId string) error
@@ -52,6 +54,30 @@ type CourseLearningOutcome struct {
 	Course                     Course                       `json:"course"`
 }
 
+// DefaultOutcomeLinkWeight is the weight of a CLO linked to an outcome without one, e.g. 1 low, 2 medium and 3 high strength
+const DefaultOutcomeLinkWeight = 1
+
+// CloPo is the join table of CourseLearningOutcome.ProgramOutcomes, the weight is how much the CLO contributes to the PO
+type CloPo struct {
+	CourseLearningOutcomeId string  `json:"course_learning_outcome_id" gorm:"primaryKey;type:char(255)"`
+	ProgramOutcomeId        string  `json:"program_outcome_id" gorm:"primaryKey;type:char(255)"`
+	Weight                  float64 `json:"weight" gorm:"not null;default:1"`
+}
+
+// CloSubplo is the join table of CourseLearningOutcome.SubProgramLearningOutcomes
+type CloSubplo struct {
+	CourseLearningOutcomeId     string  `json:"course_learning_outcome_id" gorm:"primaryKey;type:char(255)"`
+	SubProgramLearningOutcomeId string  `json:"sub_program_learning_outcome_id" gorm:"primaryKey;type:char(255)"`
+	Weight                      float64 `json:"weight" gorm:"not null;default:1"`
+}
+
+// CloSubso is the join table of CourseLearningOutcome.SubStudentOutcomes
+type CloSubso struct {
+	CourseLearningOutcomeId string  `json:"course_learning_outcome_id" gorm:"primaryKey;type:char(255)"`
+	SubStudentOutcomeId     string  `json:"sub_student_outcome_id" gorm:"primaryKey;type:char(255)"`
+	Weight                  float64 `json:"weight" gorm:"not null;default:1"`
+}
+
 type CourseLearningOutcomeDal struct {
 	Code                                string  `json:"code"`
 	Description                         string  `json:"description"`
@@ -133,16 +159,20 @@ type UpdateCourseLearningOutcomePayload struct {
 	Status                              string  `json:"status"`
 }
 
+// weights are by outcome id, outcomes without one weigh DefaultOutcomeLinkWeight
 type CreateLinkProgramOutcomePayload struct {
-	ProgramOutcomeIds []string `json:"program_outcome_ids" validate:"required"`
+	ProgramOutcomeIds []string           `json:"program_outcome_ids" validate:"required"`
+	Weights           map[string]float64 `json:"weights" validate:"dive,gt=0"`
 }
 
 type CreateLinkSubProgramLearningOutcomePayload struct {
-	SubProgramLearningOutcomeIds []string `json:"sub_program_learning_outcome_ids" validate:"required"`
+	SubProgramLearningOutcomeIds []string           `json:"sub_program_learning_outcome_ids" validate:"required"`
+	Weights                      map[string]float64 `json:"weights" validate:"dive,gt=0"`
 }
 
 type CreateLinkSubStudentOutcomePayload struct {
-	SubStudentOutcomeIds []string `json:"sub_student_outcome_ids" validate:"required"`
+	SubStudentOutcomeIds []string           `json:"sub_student_outcome_ids" validate:"required"`
+	Weights              map[string]float64 `json:"weights" validate:"dive,gt=0"`
 }
 
 type StudentPassCLOResp struct {
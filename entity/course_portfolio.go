@@ -9,10 +9,14 @@ type CoursePortfolioRepository interface {
 	EvaluatePassingCloStudents(courseId string) ([]CloPassingStudentGorm, error)
 	EvaluatePassingPloStudents(courseId string) ([]PloPassingStudentGorm, error)
 	EvaluatePassingPoStudents(courseId string) ([]PoPassingStudentGorm, error)
+	EvaluatePassingSoStudents(courseId string) ([]SoPassingStudentGorm, error)
 	EvaluateAllPloCourses() ([]PloCoursesGorm, error)
 	EvaluateAllPoCourses() ([]PoCoursesGorm, error)
+	EvaluateAllSoCourses() ([]SoCoursesGorm, error)
+	EvaluateSoCoursesByProgrammeId(programmeId string) ([]SoCoursesGorm, error)
 	EvaluateProgramLearningOutcomesByStudentId(studentId string) ([]StudentPlosGorm, error)
 	EvaluateProgramOutcomesByStudentId(studentId string) ([]StudentPosGorm, error)
+	EvaluateStudentOutcomesByStudentId(studentId string) ([]StudentSosGorm, error)
 	GetCourseCloAssessment(programmeId string, fromSerm, toSerm int) ([]FlatRow, error)
 	GetCourseLinkedOutcomes(programmeId string, fromSerm, toSerm int) ([]FlatRow, error)
 	GetCourseOutcomesSuccessRate(programmeId string, fromSerm, toSerm int) ([]CourseOutcomeSuccessRate, error)
@@ -29,10 +33,14 @@ type CoursePortfolioUseCase interface {
 	GetStudentOutcomesStatusByCourseId(courseId string) ([]StudentOutcomeStatus, error)
 	GetAllProgramLearningOutcomeCourses() ([]PloCourses, error)
 	GetAllProgramOutcomeCourses() ([]PoCourses, error)
+	GetAllStudentOutcomeCourses() ([]SoCourses, error)
 	GetOutcomesByStudentId(studentId string) ([]StudentOutcomes, error)
-	GetCourseCloAssessment(programmeId string, fromSerm, toSerm int) (*FileResponse, error)
-	GetCourseLinkedOutcomes(programmeId string, fromSerm, toSerm int) (*FileResponse, error)
-	GetCourseOutcomesSuccessRate(programmeId string, fromSerm, toSerm int) (*FileResponse, error)
+	GetProgrammeStudentOutcomeAttainment(user User, programmeId string, fromSerm, toSerm int) (*ProgrammeSoAttainment, error)
+	GetOutcomeAttainmentTrend(programmeId string, fromSerm, toSerm int) (*ProgrammeOutcomeTrend, error)
+	GetOutcomeAttainmentTrendFile(programmeId string, fromSerm, toSerm int, fileDir string) (*FileResponse, error)
+	GetCourseCloAssessment(programmeId string, fromSerm, toSerm int, fileDir string) (*FileResponse, error)
+	GetCourseLinkedOutcomes(programmeId string, fromSerm, toSerm int, fileDir string) (*FileResponse, error)
+	GetCourseOutcomesSuccessRate(programmeId string, fromSerm, toSerm int, fileDir string) (*FileResponse, error)
 	GetCourseOutcomes(courseId string) (*CoursePortfolioOutcome, error)
 
 	UpdateCoursePortfolio(courseId string, implement Implementation, educationOutcomes EducationOutcome, continuous ContinuousDevelopment) error
@@ -147,6 +155,8 @@ type CourseDevelopment struct {
 	Acts            []string        `json:"acts"`
 	SubjectComments SubjectComments `json:"subject_comments"`
 	OtherComment    string          `json:"other_comment"`
+	// anonymized course feedback given by students
+	StudentFeedback *CourseFeedbackSummary `json:"student_feedback"`
 }
 
 // Course Portfolio
@@ -237,10 +247,26 @@ type PoPassingStudentGorm struct {
 	ProgramOutcomeId string `gorm:"column:p_id"`
 }
 
+type SoData struct {
+	Id              string `json:"id"`
+	Code            string `json:"code"`
+	DescriptionThai string `json:"description_thai"`
+	Pass            bool   `json:"pass"`
+}
+
+type SoPassingStudentGorm struct {
+	Code             string
+	DescriptionThai  string
+	StudentId        string
+	Pass             bool
+	StudentOutcomeId string `gorm:"column:so_id"`
+}
+
 type StudentOutcomeStatus struct {
 	StudentId               string    `json:"student_id"`
 	ProgramLearningOutcomes []PloData `json:"program_learning_outcomes"`
 	ProgramOutcomes         []PoData  `json:"program_outcomes"`
+	StudentOutcomes         []SoData  `json:"student_outcomes"`
 	CourseLearningOutcomes  []CloData `json:"course_learning_outcomes"`
 }
 
@@ -283,6 +309,21 @@ type PoCoursesGorm struct {
 	SemesterSequence  string
 }
 
+type SoCourses struct {
+	StudentOutcomeId string       `json:"student_outcome_id"`
+	Courses          []CourseData `json:"courses"`
+}
+
+type SoCoursesGorm struct {
+	PassingPercentage float64
+	StudentOutcomeId  string `gorm:"column:so_id"`
+	CourseId          string
+	Name              string
+	Code              string
+	Year              int
+	SemesterSequence  string
+}
+
 type StudentCourseData struct {
 	Id               string `json:"id"`
 	Code             string `json:"code"`
@@ -307,10 +348,18 @@ type StudentPoData struct {
 	Courses          []StudentCourseData `json:"courses"`
 }
 
+type StudentSoData struct {
+	StudentOutcomeId string              `json:"student_outcome_id"`
+	Code             string              `json:"code"`
+	DescriptionThai  string              `json:"description_thai"`
+	Courses          []StudentCourseData `json:"courses"`
+}
+
 type StudentOutcomes struct {
 	StudentId               string           `json:"student_id"`
 	ProgramLearningOutcomes []StudentPloData `json:"program_learning_outcomes"`
 	ProgramOutcomes         []StudentPoData  `json:"program_outcomes"`
+	StudentOutcomes         []StudentSoData  `json:"student_outcomes"`
 }
 
 type StudentPlosGorm struct {
@@ -327,6 +376,19 @@ type StudentPlosGorm struct {
 	SemesterSequence           string
 }
 
+type StudentSosGorm struct {
+	StudentId          string
+	StudentOutcomeId   string `gorm:"column:so_id"`
+	StudentOutcomeCode string `gorm:"column:so_code"`
+	DescriptionThai    string
+	CourseId           string
+	CourseCode         string
+	CourseName         string
+	Pass               bool
+	Year               int
+	SemesterSequence   string
+}
+
 type StudentPosGorm struct {
 	StudentId          string
 	ProgramOutcomeId   string `gorm:"column:p_id"`
@@ -340,6 +402,67 @@ type StudentPosGorm struct {
 	SemesterSequence   string
 }
 
+// SO attainment of a programme, used by ABET report
+type SoAttainment struct {
+	StudentOutcomeId                string       `json:"student_outcome_id"`
+	Code                            string       `json:"code"`
+	DescriptionThai                 string       `json:"description_thai"`
+	DescriptionEng                  string       `json:"description_eng"`
+	ExpectedCoursePassingPercentage float64      `json:"expected_course_passing_percentage"`
+	AttainmentPercentage            float64      `json:"attainment_percentage"`
+	PassingCourseCount              int          `json:"passing_course_count"`
+	IsAttained                      bool         `json:"is_attained"`
+	Courses                         []CourseData `json:"courses"`
+}
+
+type ProgrammeSoAttainment struct {
+	ProgrammeId     string                               `json:"programme_id"`
+	ProgrammeName   string                               `json:"programme_name"`
+	FromYear        int                                  `json:"from_year"`
+	ToYear          int                                  `json:"to_year"`
+	StudentOutcomes []SoAttainment                       `json:"student_outcomes"`
+	PEOs            []ProgramEducationalObjectiveMapping `json:"peos"`
+}
+
+type OutcomeType string
+
+const (
+	OutcomeTypePLO  OutcomeType = "PLO"
+	OutcomeTypeSPLO OutcomeType = "SPLO"
+	OutcomeTypePO   OutcomeType = "PO"
+	OutcomeTypeSO   OutcomeType = "SO"
+	OutcomeTypePEO  OutcomeType = "PEO"
+)
+
+// attainment of one outcome in one semester, delta is nil on the first semester with data
+type OutcomeTrendPoint struct {
+	Year                 int      `json:"year"`
+	SemesterSequence     string   `json:"semester_sequence"`
+	Semester             string   `json:"semester"`
+	CourseCount          int      `json:"course_count"`
+	AttainmentPercentage float64  `json:"attainment_percentage"`
+	Delta                *float64 `json:"delta"`
+	IsBelowExpected      bool     `json:"is_below_expected"`
+}
+
+type OutcomeTrend struct {
+	OutcomeType                     OutcomeType         `json:"outcome_type"`
+	OutcomeId                       string              `json:"outcome_id"`
+	Code                            string              `json:"code"`
+	Description                     string              `json:"description"`
+	ExpectedCoursePassingPercentage float64             `json:"expected_course_passing_percentage"`
+	Points                          []OutcomeTrendPoint `json:"points"`
+}
+
+type ProgrammeOutcomeTrend struct {
+	ProgrammeId   string         `json:"programme_id"`
+	ProgrammeName string         `json:"programme_name"`
+	FromYear      int            `json:"from_year"`
+	ToYear        int            `json:"to_year"`
+	Semesters     []string       `json:"semesters"`
+	Outcomes      []OutcomeTrend `json:"outcomes"`
+}
+
 // ///
 
 // type NameObject struct {
@@ -441,6 +564,8 @@ type CloPassingRate struct {
 	PassedPercentage                    float64
 	ExpectedPassingAssignmentPercentage float64
 	Assignments                         map[string]AssignmentPassingRate
+	// contribution of the CLO to the outcome it is listed under, 0 when listed by itself
+	Weight float64
 }
 
 type PoPassingRate struct {
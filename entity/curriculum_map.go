@@ -0,0 +1,117 @@
+package entity
+
+type CurriculumLevel int
+
+const (
+	CurriculumLevelNone       CurriculumLevel = 0
+	CurriculumLevelIntroduced CurriculumLevel = 1
+	CurriculumLevelReinforced CurriculumLevel = 2
+	CurriculumLevelMastered   CurriculumLevel = 3
+)
+
+func (l CurriculumLevel) Label() string {
+	switch l {
+	case CurriculumLevelIntroduced:
+		return "I"
+	case CurriculumLevelReinforced:
+		return "R"
+	case CurriculumLevelMastered:
+		return "M"
+	default:
+		return ""
+	}
+}
+
+type CurriculumGapReason string
+
+const (
+	CurriculumGapNoCourse      CurriculumGapReason = "NO_COURSE"
+	CurriculumGapNeverMastered CurriculumGapReason = "NEVER_MASTERED"
+)
+
+type CurriculumMapRepository interface {
+	GetCourseOutcomeLinks(programmeId string) ([]CurriculumCourseOutcomeLink, error)
+	GetLevels(programmeId string) ([]CurriculumMapLevel, error)
+	GetLevelById(id string) (*CurriculumMapLevel, error)
+	GetLevel(programmeId string, courseCode string, outcomeType OutcomeType, outcomeId string) (*CurriculumMapLevel, error)
+	CreateLevel(level *CurriculumMapLevel) error
+	UpdateLevel(id string, level *CurriculumMapLevel) error
+	DeleteLevel(id string) error
+}
+
+type CurriculumMapUseCase interface {
+	Get(programmeId string) (*CurriculumMap, error)
+	// GetFile writes the curriculum map into fileDir, it is generated by a report job
+	GetFile(programmeId string, fileDir string) (*FileResponse, error)
+	SetLevel(programmeId string, payload SetCurriculumMapLevelPayload) error
+	DeleteLevel(programmeId string, id string) error
+}
+
+// CurriculumMapLevel is a manual override of the level derived from CLO links
+type CurriculumMapLevel struct {
+	Id          string          `json:"id" gorm:"primaryKey;type:char(255)"`
+	ProgrammeId string          `json:"programme_id" gorm:"type:char(255)"`
+	CourseCode  string          `json:"course_code"`
+	OutcomeType OutcomeType     `json:"outcome_type"`
+	OutcomeId   string          `json:"outcome_id" gorm:"type:char(255)"`
+	Level       CurriculumLevel `json:"level"`
+
+	Programme *Programme `json:"programme,omitempty" gorm:"foreignKey:ProgrammeId"`
+}
+
+type CurriculumCourseOutcomeLink struct {
+	CourseCode string
+	CourseName string
+	CloId      string `gorm:"column:clo_id"`
+	PloId      string `gorm:"column:plo_id"`
+	SploId     string `gorm:"column:splo_id"`
+	SoId       string `gorm:"column:so_id"`
+}
+
+type CurriculumMapOutcome struct {
+	OutcomeType OutcomeType `json:"outcome_type"`
+	OutcomeId   string      `json:"outcome_id"`
+	Code        string      `json:"code"`
+	Description string      `json:"description"`
+	ParentId    string      `json:"parent_id,omitempty"`
+}
+
+type CurriculumMapCell struct {
+	OutcomeType    OutcomeType     `json:"outcome_type"`
+	OutcomeId      string          `json:"outcome_id"`
+	Code           string          `json:"code"`
+	Level          CurriculumLevel `json:"level"`
+	Label          string          `json:"label"`
+	LinkedCloCount int             `json:"linked_clo_count"`
+	OverrideId     string          `json:"override_id,omitempty"`
+}
+
+type CurriculumMapCourse struct {
+	CourseCode string              `json:"course_code"`
+	CourseName string              `json:"course_name"`
+	CloCount   int                 `json:"clo_count"`
+	Cells      []CurriculumMapCell `json:"cells"`
+}
+
+type CurriculumMapGap struct {
+	OutcomeType  OutcomeType         `json:"outcome_type"`
+	OutcomeId    string              `json:"outcome_id"`
+	Code         string              `json:"code"`
+	Reason       CurriculumGapReason `json:"reason"`
+	HighestLevel CurriculumLevel     `json:"highest_level"`
+}
+
+type CurriculumMap struct {
+	ProgrammeId   string                 `json:"programme_id"`
+	ProgrammeName string                 `json:"programme_name"`
+	Outcomes      []CurriculumMapOutcome `json:"outcomes"`
+	Courses       []CurriculumMapCourse  `json:"courses"`
+	Gaps          []CurriculumMapGap     `json:"gaps"`
+}
+
+type SetCurriculumMapLevelPayload struct {
+	CourseCode  string          `json:"course_code" validate:"required"`
+	OutcomeType OutcomeType     `json:"outcome_type" validate:"required,oneof=PLO SPLO SO"`
+	OutcomeId   string          `json:"outcome_id" validate:"required"`
+	Level       CurriculumLevel `json:"level" validate:"required,min=1,max=3"`
+}
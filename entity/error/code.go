@@ -131,6 +131,7 @@ const (
 	ErrSignatureMismatch = 21608
 	ErrSessionPrefix     = 21609
 	ErrDupSession        = 21610
+	ErrSessionPermission = 21611
 
 	ErrPredictionNotFound = 21700
 	ErrCreatePrediction   = 21701
@@ -148,4 +149,141 @@ const (
 	ErrUpdateSurvey   = 21902
 	ErrDeleteSurvey   = 21903
 	ErrQuerySurvey    = 21904
+
+	ErrCurriculumMapLevelNotFound = 22000
+	ErrCreateCurriculumMapLevel   = 22001
+	ErrUpdateCurriculumMapLevel   = 22002
+	ErrDeleteCurriculumMapLevel   = 22003
+	ErrQueryCurriculumMap         = 22004
+
+	ErrPEONotFound = 22100
+	ErrCreatePEO   = 22101
+	ErrUpdatePEO   = 22102
+	ErrDeletePEO   = 22103
+	ErrQueryPEO    = 22104
+
+	ErrProgramImprovementNotFound      = 22200
+	ErrCreateProgramImprovement        = 22201
+	ErrUpdateProgramImprovement        = 22202
+	ErrDeleteProgramImprovement        = 22203
+	ErrQueryProgramImprovement         = 22204
+	ErrInvalidProgramImprovementStatus = 22205
+
+	ErrGraduatedStudentNotFound = 22300
+	ErrCreateGraduatedStudent   = 22301
+	ErrUpdateGraduatedStudent   = 22302
+	ErrDeleteGraduatedStudent   = 22303
+	ErrQueryGraduatedStudent    = 22304
+	ErrDupGraduatedStudent      = 22305
+
+	ErrSurveyInvitationNotFound = 22400
+	ErrCreateSurveyInvitation   = 22401
+	ErrQuerySurveyInvitation    = 22402
+	ErrSurveyInvitationUsed     = 22403
+	ErrSurveyInvitationExpired  = 22404
+	ErrSubmitSurveyResponse     = 22405
+
+	ErrCreateFeedback      = 22500
+	ErrQueryFeedback       = 22501
+	ErrDupFeedback         = 22502
+	ErrFeedbackNotEnrolled = 22503
+	ErrFeedbackPermission  = 22504
+
+	ErrFeedbackTokenNotFound = 22505
+	ErrFeedbackTokenUsed     = 22506
+	ErrFeedbackTokenExpired  = 22507
+
+	ErrSsoDisabled = 22600
+	ErrSsoSignIn   = 22601
+	ErrSsoProvider = 22602
+
+	ErrLdap = 22700
+
+	ErrTwoFactorInvalidCode    = 22800
+	ErrTwoFactorNotEnrolled    = 22801
+	ErrTwoFactorAlreadyEnabled = 22802
+	ErrTwoFactorRequired       = 22803
+	ErrUpdateTwoFactor         = 22804
+	ErrQueryTwoFactor          = 22805
+	ErrTwoFactorPermission     = 22806
+
+	ErrResetTokenInvalid      = 22900
+	ErrResetTokenLocked       = 22901
+	ErrResetTokenRequestLimit = 22902
+	ErrCreateResetToken       = 22903
+	ErrQueryResetToken        = 22904
+	ErrUpdateResetToken       = 22905
+
+	ErrTooManyAttempts    = 23000
+	ErrUpdateThrottle     = 23001
+	ErrThrottlePermission = 23002
+
+	ErrPasswordPolicy         = 23100
+	ErrPasswordChangeRequired = 23101
+	ErrUpdatePassword         = 23102
+	ErrQueryPassword          = 23103
+
+	ErrMailTemplate      = 23200
+	ErrCreateMail        = 23201
+	ErrQueryMail         = 23202
+	ErrUpdateMail        = 23203
+	ErrMailNotFound      = 23204
+	ErrMailPermission    = 23205
+	ErrMailNotRetryable  = 23206
+	ErrInvalidMailStatus = 23207
+
+	ErrCreateNotification      = 23300
+	ErrQueryNotification       = 23301
+	ErrUpdateNotification      = 23302
+	ErrInvalidNotificationType = 23303
+
+	ErrMilestoneNotFound   = 23400
+	ErrCreateMilestone     = 23401
+	ErrUpdateMilestone     = 23402
+	ErrDeleteMilestone     = 23403
+	ErrQueryMilestone      = 23404
+	ErrInvalidMilestone    = 23405
+	ErrDupMilestone        = 23406
+	ErrMilestonePermission = 23407
+	ErrScoreEntryLocked    = 23408
+	ErrSchedulerLease      = 23409
+
+	ErrReportJobNotFound   = 23500
+	ErrCreateReportJob     = 23501
+	ErrQueryReportJob      = 23502
+	ErrUpdateReportJob     = 23503
+	ErrInvalidReportJob    = 23504
+	ErrReportJobPermission = 23505
+	ErrReportJobNotReady   = 23506
+	ErrReportLinkInvalid   = 23507
+
+	ErrFileNotFound = 23600
+	ErrCreateFile   = 23601
+	ErrQueryFile    = 23602
+	ErrDeleteFile   = 23603
+	ErrFileTooLarge = 23604
+	ErrInvalidFile  = 23605
+
+	ErrRefreshAttainment    = 23700
+	ErrAttainmentPermission = 23701
+
+	ErrAssessmentItemNotFound     = 23800
+	ErrCreateAssessmentItem       = 23801
+	ErrUpdateAssessmentItem       = 23802
+	ErrDeleteAssessmentItem       = 23803
+	ErrQueryAssessmentItem        = 23804
+	ErrInvalidAssessmentItem      = 23805
+	ErrSaveAssessmentItemScore    = 23806
+	ErrInvalidAssessmentItemScore = 23807
+	ErrAssessmentItemPermission   = 23808
+	ErrScoreByAssessmentItems     = 23809
+
+	ErrRubricNotFound = 23900
+	ErrCreateRubric   = 23901
+	ErrUpdateRubric   = 23902
+	ErrDeleteRubric   = 23903
+	ErrQueryRubric    = 23904
+	ErrInvalidRubric  = 23905
+	ErrRubricInUse    = 23906
+	ErrAttachRubric   = 23907
 )
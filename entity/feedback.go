@@ -2,10 +2,32 @@ package entity
 
 import "time"
 
+type FeedbackRepository interface {
+	GetByCourseId(courseId string) ([]Feedback, error)
+	GetByLecturerId(lecturerId string, semesterId string) ([]Feedback, error)
+	GetByCourseIdAndStudentId(courseId string, studentId string) (*Feedback, error)
+	Create(feedback *Feedback) error
+
+	CreateToken(token *FeedbackToken) error
+	GetTokenByHash(tokenHash string) (*FeedbackToken, error)
+	// CreateWithToken stores the feedback and consumes the token, it reports false when the token was already used
+	CreateWithToken(tokenId string, feedback *Feedback) (bool, error)
+}
+
+type FeedbackUseCase interface {
+	// RequestLink mails a one-time feedback link to the email of an enrolled student, requests are throttled like password resets
+	RequestLink(payload RequestFeedbackLinkPayload, ipAddress string) error
+	Create(payload CreateFeedbackPayload, ipAddress string) error
+	GetCourseSummary(user User, courseId string) (*CourseFeedbackSummary, error)
+	GetLecturerSummaries(user User, semesterId string) ([]CourseFeedbackSummary, error)
+	// GetSummaryByCourseId skips the permission check, it is used to build the course portfolio
+	GetSummaryByCourseId(courseId string) (*CourseFeedbackSummary, error)
+}
+
 type Feedback struct {
 	Id        string    `json:"id" gorm:"type:char(255);primaryKey"`
-	CourseId  string    `json:"course_id" gorm:"type:char(255)"`
-	StudentId string    `json:"student_id" gorm:"type:char(255)"`
+	CourseId  string    `json:"course_id" gorm:"type:char(255);uniqueIndex:idx_feedback_course_student"`
+	StudentId string    `json:"student_id" gorm:"type:char(255);uniqueIndex:idx_feedback_course_student"`
 	Comments  string    `json:"comments" gorm:"type:text"`
 	Rating    int       `json:"rating" gorm:"check:rating >= 1 AND rating <= 5"`
 	Date      time.Time `json:"date"`
@@ -13,3 +35,46 @@ type Feedback struct {
 	Course  Course  `json:"course" gorm:"foreignKey:CourseId"`
 	Student Student `json:"student" gorm:"foreignKey:StudentId"`
 }
+
+// One-time link for a student, who has no account, to give feedback to a course
+type FeedbackToken struct {
+	Id        string     `json:"id" gorm:"primaryKey;type:char(255)"`
+	CourseId  string     `json:"course_id" gorm:"type:char(255);index"`
+	StudentId string     `json:"student_id" gorm:"type:char(255)"`
+	TokenHash string     `json:"-" gorm:"type:char(64);uniqueIndex"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// The link is mailed to the email of the student, the email given here only has to match it
+type RequestFeedbackLinkPayload struct {
+	CourseId  string `json:"course_id" validate:"required"`
+	StudentId string `json:"student_id" validate:"required"`
+	Email     string `json:"email" validate:"required,email"`
+}
+
+type CreateFeedbackPayload struct {
+	Token    string `json:"token" validate:"required"`
+	Comments string `json:"comments"`
+	Rating   int    `json:"rating" validate:"required,min=1,max=5"`
+}
+
+type RatingCount struct {
+	Rating int `json:"rating"`
+	Count  int `json:"count"`
+}
+
+// Anonymized feedback of a course, without student ids
+type CourseFeedbackSummary struct {
+	CourseId         string        `json:"course_id"`
+	CourseCode       string        `json:"course_code"`
+	CourseName       string        `json:"course_name"`
+	SemesterId       string        `json:"semester_id"`
+	Year             int           `json:"year"`
+	SemesterSequence string        `json:"semester_sequence"`
+	ResponseCount    int           `json:"response_count"`
+	AverageRating    float64       `json:"average_rating"`
+	RatingHistogram  []RatingCount `json:"rating_histogram"`
+	Comments         []string      `json:"comments"`
+}
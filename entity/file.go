@@ -0,0 +1,43 @@
+package entity
+
+import (
+	"io"
+	"time"
+)
+
+// StoredFile is the metadata of a file kept in the file store, the content is found by its key
+type StoredFile struct {
+	Id          string `json:"id" gorm:"primaryKey;type:char(255)"`
+	Key         string `json:"-" gorm:"type:varchar(512);uniqueIndex"`
+	Name        string `json:"name"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+	// sha256 of the content in hex
+	Checksum  string    `json:"checksum" gorm:"type:char(64)"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// FileStore keeps file contents outside the container, a missing key is reported with an error wrapping os.ErrNotExist
+type FileStore interface {
+	Put(key string, contentType string, content io.Reader, size int64) error
+	Open(key string) (io.ReadCloser, error)
+	Delete(key string) error
+}
+
+type FileRepository interface {
+	Create(file *StoredFile) error
+	GetById(id string) (*StoredFile, error)
+	GetByIds(ids []string) ([]StoredFile, error)
+	Delete(ids []string) error
+}
+
+type FileUseCase interface {
+	// Save stores the content under the prefix, the content type is detected when it is empty
+	Save(prefix string, name string, contentType string, content io.Reader) (*StoredFile, error)
+	// SaveLocal moves a file written on the local disk into the store
+	SaveLocal(prefix string, file FileResponse) (*StoredFile, error)
+	GetById(id string) (*StoredFile, error)
+	// Open returns the metadata and content of the file, the caller closes the content
+	Open(id string) (*StoredFile, io.ReadCloser, error)
+	Delete(ids []string) error
+}
@@ -1,27 +1,129 @@
 package entity
 
+type EmploymentStatus string
+
+const (
+	EmploymentStatusEmployed     EmploymentStatus = "EMPLOYED"
+	EmploymentStatusFurtherStudy EmploymentStatus = "FURTHER_STUDY"
+	EmploymentStatusSeeking      EmploymentStatus = "SEEKING"
+)
+
+type SalaryBand string
+
+// Monthly salary in Thai baht
+const (
+	SalaryBandBelow15K SalaryBand = "BELOW_15K"
+	SalaryBand15KTo25K SalaryBand = "15K_25K"
+	SalaryBand25KTo35K SalaryBand = "25K_35K"
+	SalaryBand35KTo50K SalaryBand = "35K_50K"
+	SalaryBandAbove50K SalaryBand = "ABOVE_50K"
+)
+
+var SalaryBands = []SalaryBand{
+	SalaryBandBelow15K,
+	SalaryBand15KTo25K,
+	SalaryBand25KTo35K,
+	SalaryBand35KTo50K,
+	SalaryBandAbove50K,
+}
+
 type GraduatedStudent struct {
-	Id        string `gorm:"primaryKey;type:char(255)"`
-	StudentId string
-	Year      string //Year of graduation
-	Workplace string
-	Remarks   string
+	Id        string `json:"id" gorm:"primaryKey;type:char(255)"`
+	StudentId string `json:"student_id" gorm:"type:char(255);uniqueIndex"`
+	Year      string `json:"year"` //Year of graduation
+	Workplace string `json:"workplace"`
+	Remarks   string `json:"remarks"`
 
-	Student Student
+	// empty until the graduate has reported
+	EmploymentStatus EmploymentStatus `json:"employment_status" gorm:"type:char(32)"`
+	// months between graduation and first employment
+	MonthsToEmployment *int       `json:"months_to_employment"`
+	SalaryBand         SalaryBand `json:"salary_band" gorm:"type:char(32)"`
+
+	Student Student `json:"student"`
 }
 
 type GraduatedStudentRepository interface {
 	GetAll() ([]GraduatedStudent, error)
+	GetByParams(programmeId string, year string) ([]GraduatedStudent, error)
 	GetById(id string) (*GraduatedStudent, error)
 	Create(graduatedStudent *GraduatedStudent) error
-	Update(graduatedStudent *GraduatedStudent) error
+	CreateMany(graduatedStudents []GraduatedStudent) error
+	Update(id string, graduatedStudent *GraduatedStudent) error
 	Delete(id string) error
+	FilterExisted(studentIds []string) ([]string, error)
 }
 
 type GraduatedStudentUseCase interface {
 	GetAll() ([]GraduatedStudent, error)
+	GetByParams(programmeId string, year string) ([]GraduatedStudent, error)
 	GetById(id string) (*GraduatedStudent, error)
-	Create(studentId string, year string, workplace string, remarks string) (*GraduatedStudent, error)
-	Update(graduatedStudent *GraduatedStudent) error
+	Create(payload CreateGraduatedStudentPayload) (*GraduatedStudent, error)
+	GraduateCohort(payload GraduateCohortPayload) (*GraduateCohortResult, error)
+	Update(id string, payload UpdateGraduatedStudentPayload) error
 	Delete(id string) error
+
+	GetAlumniSummary(programmeId string) (*AlumniSummary, error)
+}
+
+type CreateGraduatedStudentPayload struct {
+	StudentId          string           `json:"student_id" validate:"required"`
+	Year               string           `json:"year" validate:"required"`
+	Workplace          string           `json:"workplace"`
+	Remarks            string           `json:"remarks"`
+	EmploymentStatus   EmploymentStatus `json:"employment_status" validate:"omitempty,oneof=EMPLOYED FURTHER_STUDY SEEKING"`
+	MonthsToEmployment *int             `json:"months_to_employment" validate:"omitempty,min=0"`
+	SalaryBand         SalaryBand       `json:"salary_band" validate:"omitempty,oneof=BELOW_15K 15K_25K 25K_35K 35K_50K ABOVE_50K"`
+}
+
+type UpdateGraduatedStudentPayload struct {
+	Year               string           `json:"year"`
+	Workplace          string           `json:"workplace"`
+	Remarks            string           `json:"remarks"`
+	EmploymentStatus   EmploymentStatus `json:"employment_status" validate:"omitempty,oneof=EMPLOYED FURTHER_STUDY SEEKING"`
+	MonthsToEmployment *int             `json:"months_to_employment" validate:"omitempty,min=0"`
+	SalaryBand         SalaryBand       `json:"salary_band" validate:"omitempty,oneof=BELOW_15K 15K_25K 25K_35K 35K_50K ABOVE_50K"`
+}
+
+// Graduates every student of a programme's admission year, or only StudentIds when given
+type GraduateCohortPayload struct {
+	ProgrammeId    string   `json:"programme_id" validate:"required"`
+	StudentYear    string   `json:"student_year" validate:"required"`
+	GraduationYear string   `json:"graduation_year" validate:"required"`
+	StudentIds     []string `json:"student_ids"`
+}
+
+type GraduateCohortResult struct {
+	GraduatedStudentIds []string `json:"graduated_student_ids"`
+	// students that already had a graduation record
+	SkippedStudentIds []string `json:"skipped_student_ids"`
+}
+
+type SalaryBandCount struct {
+	SalaryBand SalaryBand `json:"salary_band"`
+	Count      int        `json:"count"`
+}
+
+type AlumniOutcome struct {
+	TotalGraduates            int               `json:"total_graduates"`
+	Reported                  int               `json:"reported"`
+	Employed                  int               `json:"employed"`
+	FurtherStudy              int               `json:"further_study"`
+	Seeking                   int               `json:"seeking"`
+	EmploymentRate            float64           `json:"employment_rate"`
+	FurtherStudyRate          float64           `json:"further_study_rate"`
+	AverageMonthsToEmployment *float64          `json:"average_months_to_employment"`
+	SalaryBands               []SalaryBandCount `json:"salary_bands"`
+}
+
+type AlumniYearSummary struct {
+	Year string `json:"year"`
+	AlumniOutcome
+}
+
+type AlumniSummary struct {
+	ProgrammeId   string `json:"programme_id"`
+	ProgrammeName string `json:"programme_name"`
+	AlumniOutcome
+	Years []AlumniYearSummary `json:"years"`
 }
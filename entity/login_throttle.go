@@ -0,0 +1,40 @@
+package entity
+
+import "time"
+
+type ThrottleAction string
+
+const (
+	ThrottleActionSignIn         ThrottleAction = "sign_in"
+	ThrottleActionForgotPassword ThrottleAction = "forgot_password"
+	ThrottleActionResetPassword  ThrottleAction = "reset_password"
+	// feedback links requested and feedback submitted by students without an account
+	ThrottleActionCourseFeedback ThrottleAction = "course_feedback"
+)
+
+// LoginThrottle counts failed attempts of an action by one email or one ip address
+type LoginThrottle struct {
+	Key          string     `json:"key" gorm:"primaryKey;column:throttle_key;type:varchar(255)"`
+	Failures     int        `json:"failures"`
+	LockCount    int        `json:"lock_count"`
+	LockedUntil  *time.Time `json:"locked_until"`
+	LastFailedAt time.Time  `json:"last_failed_at"`
+}
+
+type LoginThrottleRepository interface {
+	GetByKeys(keys []string) ([]LoginThrottle, error)
+	// Update locks the row of the key, creating it when missing, and saves the changes made by update
+	Update(key string, update func(throttle *LoginThrottle)) (*LoginThrottle, error)
+	DeleteByKeys(keys []string) error
+	DeleteStale(before time.Time) (int64, error)
+}
+
+type LoginThrottleUseCase interface {
+	// Check returns ErrTooManyAttempts while the email or the ip address is locked for the action, an empty one is skipped
+	Check(action ThrottleAction, email string, ipAddress string) error
+	// Fail counts a failed attempt, locks with exponential backoff and notifies the user when the sign in gets locked, an empty email or ip address is not counted
+	Fail(action ThrottleAction, email string, ipAddress string) error
+	Succeed(action ThrottleAction, email string) error
+	Unlock(email string) error
+	DeleteStale() (int64, error)
+}
@@ -1,14 +1,107 @@
 package entity
 
+import "time"
+
+// PasswordResetToken keeps only the bcrypt hash of the code sent by email
+type PasswordResetToken struct {
+	Id        string     `json:"id" gorm:"primaryKey;type:char(255)"`
+	Email     string     `json:"email" gorm:"index"`
+	TokenHash string     `json:"-"`
+	Attempts  int        `json:"attempts"`
+	ExpiredAt time.Time  `json:"expired_at"`
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+type MailTemplate string
+
+const (
+	MailTemplateForgotPassword   MailTemplate = "forgot_password"
+	MailTemplateAccountLocked    MailTemplate = "account_locked"
+	MailTemplateSurveyInvitation MailTemplate = "survey_invitation"
+	MailTemplateNotification     MailTemplate = "notification"
+	MailTemplateFeedbackLink     MailTemplate = "feedback_link"
+)
+
+type MailStatus string
+
+const (
+	MailStatusPending MailStatus = "PENDING"
+	MailStatusSent    MailStatus = "SENT"
+	// given up after too many attempts, an admin can retry it
+	MailStatusFailed MailStatus = "FAILED"
+)
+
+type MailMessage struct {
+	To      string
+	Subject string
+	Html    string
+}
+
+type Mailer interface {
+	Send(message MailMessage) error
+}
+
+type MailRenderer interface {
+	// Render returns the subject and html body of the template in the language, th or en
+	Render(template MailTemplate, language string, data interface{}) (string, string, error)
+}
+
+// MailOutbox is an email queued for the worker, the body is encrypted since it may hold codes and cleared once the mail is sent
+type MailOutbox struct {
+	Id            string       `json:"id" gorm:"primaryKey;type:char(255)"`
+	Recipient     string       `json:"recipient" gorm:"index"`
+	Template      MailTemplate `json:"template"`
+	Language      string       `json:"language"`
+	Subject       string       `json:"subject"`
+	Body          string       `json:"-" gorm:"type:mediumtext"`
+	Status        MailStatus   `json:"status" gorm:"index"`
+	Attempts      int          `json:"attempts"`
+	LastError     string       `json:"last_error" gorm:"type:text"`
+	NextAttemptAt time.Time    `json:"next_attempt_at" gorm:"index"`
+	SentAt        *time.Time   `json:"sent_at"`
+	CreatedAt     time.Time    `json:"created_at"`
+	UpdatedAt     time.Time    `json:"updated_at"`
+}
+
 type MailUseCase interface {
 	SendForgotPasswordEmail(to string) error
-	ValidateResetPasswordToken(email, token string) error
-	GetToken(email string) (string, error)
-	DeleteToken(email string) error
+	// ConsumeResetPasswordToken checks the code and marks it used, the token is locked after too many wrong codes
+	ConsumeResetPasswordToken(email string, token string) error
+	DeleteExpiredTokens() (int64, error)
+	SendAccountLockedEmail(to string, lockedUntil time.Time) error
+	SendSurveyInvitationEmail(to string, name string, surveyTitle string, link string, expiresAt time.Time) error
+	SendFeedbackLinkEmail(to string, courseName string, link string, expiresAt time.Time) error
+	// SendNotificationEmail links to a path of the client
+	SendNotificationEmail(to string, title string, message string, link string) error
+
+	// ProcessOutbox sends the due mails and returns how many were sent
+	ProcessOutbox() (int, error)
+	GetOutbox(status string, pageIndex string, pageSize string) (*Pagination, error)
+	GetOutboxById(id string) (*MailOutbox, error)
+	// RetryOutbox queues a failed mail again
+	RetryOutbox(id string) error
+	// DeleteOldOutbox deletes sent and failed mails older than the retention
+	DeleteOldOutbox() (int64, error)
 }
 
 type MailRepository interface {
-	SaveToken(email string, token string) error
-	GetToken(email string) (string, error)
-	DeleteToken(email string) error
+	// CreateToken invalidates unused tokens of the email before saving the new one
+	CreateToken(token *PasswordResetToken) error
+	// GetToken returns the latest unused token of the email
+	GetToken(email string) (*PasswordResetToken, error)
+	CountTokensSince(email string, since time.Time) (int64, error)
+	// IncreaseAttempts counts an attempt unless the token already has maxAttempts, it returns false when not counted
+	IncreaseAttempts(id string, maxAttempts int) (bool, error)
+	// UseToken marks the token used and returns false when it was already used
+	UseToken(id string) (bool, error)
+	DeleteExpiredTokens(before time.Time) (int64, error)
+
+	CreateOutbox(mail *MailOutbox) error
+	// ClaimOutbox returns due pending mails and postpones them by lease, so other workers skip them while they are sent
+	ClaimOutbox(now time.Time, lease time.Duration, limit int) ([]MailOutbox, error)
+	UpdateOutbox(mail *MailOutbox) error
+	GetOutbox(status MailStatus, offset int, limit int) (*Pagination, error)
+	GetOutboxById(id string) (*MailOutbox, error)
+	DeleteOutboxBefore(before time.Time) (int64, error)
 }
@@ -0,0 +1,75 @@
+package entity
+
+import "time"
+
+type MilestoneType string
+
+const (
+	MilestoneTypeScoreEntryClose MilestoneType = "SCORE_ENTRY_CLOSE"
+	MilestoneTypePortfolioDue    MilestoneType = "PORTFOLIO_DUE"
+	MilestoneTypeSurveyClose     MilestoneType = "SURVEY_CLOSE"
+)
+
+var MilestoneTypes = []MilestoneType{
+	MilestoneTypeScoreEntryClose,
+	MilestoneTypePortfolioDue,
+	MilestoneTypeSurveyClose,
+}
+
+// SemesterMilestone is an accreditation deadline of a semester, a semester has at most one of each type
+type SemesterMilestone struct {
+	Id         string        `json:"id" gorm:"primaryKey;type:char(255)"`
+	SemesterId string        `json:"semester_id" gorm:"uniqueIndex:idx_semester_milestone_type;type:char(255)"`
+	Type       MilestoneType `json:"type" gorm:"uniqueIndex:idx_semester_milestone_type;type:char(64)"`
+	DueAt      time.Time     `json:"due_at"`
+	// lecturers with incomplete items are reminded this many days before the deadline
+	RemindDaysBefore int `json:"remind_days_before"`
+	// only for score entry, lecturers cannot change scores of the semester after the deadline
+	LockScoreEntry bool       `json:"lock_score_entry"`
+	RemindedAt     *time.Time `json:"reminded_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// IsReminderDue reports whether the reminder should be sent, it is not sent once the deadline has passed
+func (m SemesterMilestone) IsReminderDue(now time.Time) bool {
+	return m.RemindedAt == nil && now.Before(m.DueAt) && !now.Before(m.DueAt.AddDate(0, 0, -m.RemindDaysBefore))
+}
+
+type MilestoneRepository interface {
+	GetById(id string) (*SemesterMilestone, error)
+	GetBySemesterId(semesterId string) ([]SemesterMilestone, error)
+	// GetUnreminded returns milestones not reminded yet whose deadline has not passed
+	GetUnreminded(now time.Time) ([]SemesterMilestone, error)
+	Create(milestone *SemesterMilestone) error
+	Update(milestone *SemesterMilestone) error
+	// MarkReminded returns false when another run has already reminded the milestone
+	MarkReminded(id string, remindedAt time.Time) (bool, error)
+	Delete(id string) error
+	// GetIncompleteCourses returns the courses of the semester, with their lecturers, that have not met the milestone
+	GetIncompleteCourses(semesterId string, milestoneType MilestoneType) ([]Course, error)
+}
+
+type MilestoneUseCase interface {
+	GetById(id string) (*SemesterMilestone, error)
+	GetBySemesterId(semesterId string) ([]SemesterMilestone, error)
+	Create(semesterId string, payload CreateMilestonePayload) error
+	Update(id string, payload UpdateMilestonePayload) error
+	Delete(id string) error
+	IsScoreEntryLocked(semesterId string) (bool, error)
+	// SendReminders notifies lecturers with incomplete items of upcoming deadlines and returns how many milestones were reminded
+	SendReminders() (int, error)
+}
+
+type CreateMilestonePayload struct {
+	Type             MilestoneType `json:"type" validate:"required"`
+	DueAt            time.Time     `json:"due_at" validate:"required"`
+	RemindDaysBefore *int          `json:"remind_days_before" validate:"omitempty,min=0,max=60"`
+	LockScoreEntry   bool          `json:"lock_score_entry"`
+}
+
+type UpdateMilestonePayload struct {
+	DueAt            time.Time `json:"due_at" validate:"required"`
+	RemindDaysBefore *int      `json:"remind_days_before" validate:"omitempty,min=0,max=60"`
+	LockScoreEntry   bool      `json:"lock_score_entry"`
+}
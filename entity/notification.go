@@ -0,0 +1,102 @@
+package entity
+
+import "time"
+
+type NotificationType string
+
+const (
+	NotificationTypeCourseStream       NotificationType = "COURSE_STREAM"
+	NotificationTypePortfolioSubmitted NotificationType = "PORTFOLIO_SUBMITTED"
+	NotificationTypeImportCompleted    NotificationType = "IMPORT_COMPLETED"
+	NotificationTypeMissingScores      NotificationType = "MISSING_SCORES"
+	NotificationTypeSurveyResponse     NotificationType = "SURVEY_RESPONSE"
+	NotificationTypeDeadlineReminder   NotificationType = "DEADLINE_REMINDER"
+)
+
+var NotificationTypes = []NotificationType{
+	NotificationTypeCourseStream,
+	NotificationTypePortfolioSubmitted,
+	NotificationTypeImportCompleted,
+	NotificationTypeMissingScores,
+	NotificationTypeSurveyResponse,
+	NotificationTypeDeadlineReminder,
+}
+
+type Notification struct {
+	Id      string           `json:"id" gorm:"primaryKey;type:char(255)"`
+	UserId  string           `json:"user_id" gorm:"index"`
+	Type    NotificationType `json:"type" gorm:"type:char(64)"`
+	Title   string           `json:"title"`
+	Message string           `json:"message" gorm:"type:text"`
+	// frontend path to open, e.g. /courses/<id>
+	Link string `json:"link"`
+	// the course or survey the notification is about, unread notifications of the same type and reference are grouped
+	ReferenceId string `json:"reference_id" gorm:"index"`
+	// events grouped into this notification while it was unread
+	Count     int        `json:"count"`
+	ReadAt    *time.Time `json:"read_at"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+
+	User User `json:"-"`
+}
+
+// NotificationPreference is saved only once the user changes it, in-app is on and email is off by default
+type NotificationPreference struct {
+	UserId string           `json:"-" gorm:"primaryKey;type:char(255)"`
+	Type   NotificationType `json:"type" gorm:"primaryKey;type:char(64)"`
+	InApp  bool             `json:"in_app"`
+	Email  bool             `json:"email"`
+
+	User User `json:"-"`
+}
+
+// NotificationEvent is published by other use cases when something happens that users should know about
+type NotificationEvent struct {
+	Type        NotificationType
+	Title       string
+	Message     string
+	Link        string
+	ReferenceId string
+}
+
+type NotificationRepository interface {
+	// Create groups the notification into the unread one of the same user, type and reference, it returns false when grouped
+	Create(notification *Notification) (bool, error)
+	GetByUserId(userId string, isUnreadOnly bool, offset int, limit int) (*Pagination, error)
+	CountUnread(userId string) (int64, error)
+	// MarkRead marks all unread notifications of the user when ids is empty
+	MarkRead(userId string, ids []string, readAt time.Time) error
+	GetPreferences(userId string) ([]NotificationPreference, error)
+	UpdatePreferences(preferences []NotificationPreference) error
+	GetUserIdsByRole(role UserRole) ([]string, error)
+	DeleteReadBefore(before time.Time) (int64, error)
+}
+
+type NotificationUseCase interface {
+	Notify(userIds []string, event NotificationEvent) error
+	NotifyRole(role UserRole, event NotificationEvent) error
+	GetByUserId(userId string, isUnreadOnly bool, pageIndex string, pageSize string) (*Pagination, error)
+	CountUnread(userId string) (int64, error)
+	MarkRead(userId string, ids []string) error
+	MarkAllRead(userId string) error
+	// GetPreferences returns a preference for every notification type
+	GetPreferences(userId string) ([]NotificationPreference, error)
+	UpdatePreferences(userId string, payload UpdateNotificationPreferencesPayload) error
+	// DeleteOld deletes notifications read before the retention
+	DeleteOld() (int64, error)
+}
+
+type MarkNotificationsReadPayload struct {
+	Ids []string `json:"ids" validate:"required,min=1"`
+}
+
+type NotificationPreferencePayload struct {
+	Type  NotificationType `json:"type" validate:"required"`
+	InApp bool             `json:"in_app"`
+	Email bool             `json:"email"`
+}
+
+type UpdateNotificationPreferencesPayload struct {
+	Preferences []NotificationPreferencePayload `json:"preferences" validate:"required,dive"`
+}
@@ -0,0 +1,26 @@
+package entity
+
+import "time"
+
+type PasswordHistory struct {
+	Id           string    `json:"id" gorm:"primaryKey;type:char(255)"`
+	UserId       string    `json:"user_id" gorm:"index"`
+	PasswordHash string    `json:"-"`
+	CreatedAt    time.Time `json:"created_at"`
+
+	User User `json:"-"`
+}
+
+type PasswordRepository interface {
+	GetHistory(userId string, limit int) ([]PasswordHistory, error)
+	// UpdatePassword saves the hash, clears the forced change and keeps the latest historySize hashes in the history
+	UpdatePassword(userId string, passwordHash string, changedAt time.Time, historySize int) error
+}
+
+type PasswordUseCase interface {
+	// Validate checks the policy, and the current and previous passwords of the user when it is not nil
+	Validate(password string, user *User) error
+	Change(user User, newPassword string) error
+	// IsChangeRequired is true on the first sign in of bulk created users and once the password expired for a role of the user
+	IsChangeRequired(user User) bool
+}
@@ -1,9 +1,65 @@
 package entity
 
+type ProgramEducationalObjectiveRepository interface {
+	GetByProgrammeId(programmeId string) ([]ProgramEducationalObjective, error)
+	GetById(id string) (*ProgramEducationalObjective, error)
+	Create(peo *ProgramEducationalObjective) error
+	Update(id string, peo *ProgramEducationalObjective) error
+	Delete(id string) error
+
+	CreateLinkProgramLearningOutcome(id string, ploIds []string) error
+	CreateLinkStudentOutcome(id string, soIds []string) error
+	DeleteLinkProgramLearningOutcome(id string, ploId string) error
+	DeleteLinkStudentOutcome(id string, soId string) error
+}
+
+type ProgramEducationalObjectiveUseCase interface {
+	GetByProgrammeId(programmeId string) ([]ProgramEducationalObjective, error)
+	GetById(programmeId string, id string) (*ProgramEducationalObjective, error)
+	Create(programmeId string, payload CreateProgramEducationalObjectivePayload) error
+	Update(programmeId string, id string, payload UpdateProgramEducationalObjectivePayload) error
+	Delete(programmeId string, id string) error
+
+	CreateLinkProgramLearningOutcome(programmeId string, id string, ploIds []string) error
+	CreateLinkStudentOutcome(programmeId string, id string, soIds []string) error
+	DeleteLinkProgramLearningOutcome(programmeId string, id string, ploId string) error
+	DeleteLinkStudentOutcome(programmeId string, id string, soId string) error
+}
+
 type ProgramEducationalObjective struct {
-	Id            string `json:"id" gorm:"type:char(255);primaryKey"`
-	Description   string `json:"description" gorm:"type:text;not null"`
-	ProgrammeName string `json:"programme_name"  gorm:"type:char(255)"`
+	Id          string `json:"id" gorm:"type:char(255);primaryKey"`
+	Code        string `json:"code"`
+	Description string `json:"description" gorm:"type:text;not null"`
+	ProgrammeId string `json:"programme_id" gorm:"type:char(255)"`
+
+	Programme               *Programme                `json:"programme,omitempty" gorm:"foreignKey:ProgrammeId"`
+	ProgramLearningOutcomes []*ProgramLearningOutcome `json:"program_learning_outcomes" gorm:"many2many:peo_plo"`
+	StudentOutcomes         []*StudentOutcome         `json:"student_outcomes" gorm:"many2many:peo_so"`
+}
+
+type CreateProgramEducationalObjectivePayload struct {
+	Code        string `json:"code" validate:"required"`
+	Description string `json:"description" validate:"required"`
+}
+
+type UpdateProgramEducationalObjectivePayload struct {
+	Code        string `json:"code"`
+	Description string `json:"description"`
+}
+
+type CreateLinkPEOProgramLearningOutcomePayload struct {
+	ProgramLearningOutcomeIds []string `json:"program_learning_outcome_ids" validate:"required"`
+}
+
+type CreateLinkPEOStudentOutcomePayload struct {
+	StudentOutcomeIds []string `json:"student_outcome_ids" validate:"required"`
+}
 
-	Programme Programme `gorm:"foreignKey:ProgrammeName"`
+// PEO with the codes of its mapped outcomes, used in programme reports
+type ProgramEducationalObjectiveMapping struct {
+	Id          string   `json:"id"`
+	Code        string   `json:"code"`
+	Description string   `json:"description"`
+	PLOs        []string `json:"plos"`
+	SOs         []string `json:"sos"`
 }
@@ -2,21 +2,105 @@ package entity
 
 import "time"
 
+type ProgramImprovementStatus string
+
+const (
+	ProgramImprovementStatusOpen       ProgramImprovementStatus = "OPEN"
+	ProgramImprovementStatusInProgress ProgramImprovementStatus = "IN_PROGRESS"
+	ProgramImprovementStatusClosed     ProgramImprovementStatus = "CLOSED"
+	ProgramImprovementStatusVerified   ProgramImprovementStatus = "VERIFIED"
+)
+
+func (s ProgramImprovementStatus) Label() string {
+	switch s {
+	case ProgramImprovementStatusOpen:
+		return "Open"
+	case ProgramImprovementStatusInProgress:
+		return "In progress"
+	case ProgramImprovementStatusClosed:
+		return "Closed"
+	case ProgramImprovementStatusVerified:
+		return "Verified"
+	default:
+		return string(s)
+	}
+}
+
+type ProgramImprovementRepository interface {
+	GetByProgrammeId(programmeId string, status ProgramImprovementStatus) ([]ProgramImprovement, error)
+	GetById(id string) (*ProgramImprovement, error)
+	Create(improvement *ProgramImprovement) error
+	Update(id string, improvement *ProgramImprovement) error
+	Delete(id string) error
+
+	ReplaceLinks(id string, links ProgramImprovementLinks) error
+}
+
+type ProgramImprovementUseCase interface {
+	GetByProgrammeId(programmeId string, status ProgramImprovementStatus) ([]ProgramImprovement, error)
+	GetById(programmeId string, id string) (*ProgramImprovement, error)
+	Create(user User, programmeId string, payload CreateProgramImprovementRequestPayload) error
+	Update(programmeId string, id string, payload UpdateProgramImprovementRequestPayload) error
+	Delete(programmeId string, id string) error
+
+	// GetLogFile writes the improvement log into fileDir, it is generated by a report job
+	GetLogFile(programmeId string, fileDir string) (*FileResponse, error)
+}
+
 type ProgramImprovement struct {
-	Id              string    `json:"id" gorm:"type:char(255);primaryKey"`
-	ProgrammeName   string    `json:"programme_name" gorm:"type:char(255);primaryKey"`
-	IssueIdentified string    `json:"issue_identified" gorm:"type:text"`
-	ActionTaken     string    `json:"action_taken" gorm:"type:text"`
-	Result          string    `json:"result" gorm:"type:text"`
-	Date            time.Time `json:"date"`
-
-	Programme Programme `gorm:"foreignKey:ProgrammeName"`
+	Id              string                   `json:"id" gorm:"type:char(255);primaryKey"`
+	ProgrammeId     string                   `json:"programme_id" gorm:"type:char(255);index"`
+	IssueIdentified string                   `json:"issue_identified" gorm:"type:text"`
+	ActionTaken     string                   `json:"action_taken" gorm:"type:text"`
+	Result          string                   `json:"result" gorm:"type:text"`
+	Date            time.Time                `json:"date"`
+	Status          ProgramImprovementStatus `json:"status" gorm:"type:char(32);default:'OPEN'"`
+	OwnerId         string                   `json:"owner_id" gorm:"type:char(255)"`
+	DueDate         *time.Time               `json:"due_date"`
+	CreatedAt       time.Time                `json:"created_at"`
+	UpdatedAt       time.Time                `json:"updated_at"`
+
+	Programme               *Programme                `json:"programme,omitempty" gorm:"foreignKey:ProgrammeId"`
+	Owner                   *User                     `json:"owner,omitempty" gorm:"foreignKey:OwnerId"`
+	Courses                 []*Course                 `json:"courses" gorm:"many2many:improvement_course"`
+	ProgramLearningOutcomes []*ProgramLearningOutcome `json:"program_learning_outcomes" gorm:"many2many:improvement_plo"`
+	ProgramOutcomes         []*ProgramOutcome         `json:"program_outcomes" gorm:"many2many:improvement_po"`
+	Surveys                 []*Survey                 `json:"surveys" gorm:"many2many:improvement_survey"`
+}
+
+// Courses, outcomes and survey findings that triggered an improvement item
+type ProgramImprovementLinks struct {
+	CourseIds                 []string
+	ProgramLearningOutcomeIds []string
+	ProgramOutcomeIds         []string
+	SurveyIds                 []string
 }
 
 type CreateProgramImprovementRequestPayload struct {
-	ProgrammeName   string    `json:"programme_name" validate:"required"`
-	IssueIdentified string    `json:"issue_identified" validate:"required"`
-	ActionTaken     string    `json:"action_taken" validate:"required"`
-	Result          string    `json:"result" validate:"required"`
-	Date            time.Time `json:"date" validate:"required"`
+	IssueIdentified           string                   `json:"issue_identified" validate:"required"`
+	ActionTaken               string                   `json:"action_taken" validate:"required"`
+	Result                    string                   `json:"result"`
+	Date                      time.Time                `json:"date" validate:"required"`
+	Status                    ProgramImprovementStatus `json:"status" validate:"omitempty,oneof=OPEN IN_PROGRESS CLOSED VERIFIED"`
+	OwnerId                   string                   `json:"owner_id"`
+	DueDate                   *time.Time               `json:"due_date"`
+	CourseIds                 []string                 `json:"course_ids"`
+	ProgramLearningOutcomeIds []string                 `json:"program_learning_outcome_ids"`
+	ProgramOutcomeIds         []string                 `json:"program_outcome_ids"`
+	SurveyIds                 []string                 `json:"survey_ids"`
+}
+
+// Link ids left out of the payload keep their current links, an empty list clears them
+type UpdateProgramImprovementRequestPayload struct {
+	IssueIdentified           string                   `json:"issue_identified"`
+	ActionTaken               string                   `json:"action_taken"`
+	Result                    string                   `json:"result"`
+	Date                      *time.Time               `json:"date"`
+	Status                    ProgramImprovementStatus `json:"status" validate:"omitempty,oneof=OPEN IN_PROGRESS CLOSED VERIFIED"`
+	OwnerId                   string                   `json:"owner_id"`
+	DueDate                   *time.Time               `json:"due_date"`
+	CourseIds                 []string                 `json:"course_ids"`
+	ProgramLearningOutcomeIds []string                 `json:"program_learning_outcome_ids"`
+	ProgramOutcomeIds         []string                 `json:"program_outcome_ids"`
+	SurveyIds                 []string                 `json:"survey_ids"`
 }
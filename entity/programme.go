@@ -17,6 +17,8 @@ type ProgrammeRepository interface {
 	GetAllPO(programmeId string) ([]ProgramOutcome, error)
 	GetAllPLO(programmeId string) ([]ProgramLearningOutcome, error)
 	GetAllSO(programmeId string) ([]StudentOutcome, error)
+	GetAllPEO(programmeId string) ([]ProgramEducationalObjective, error)
+	GetStakeholderFeedback(programmeId string) ([]StakeholderFeedbackRecord, error)
 	Create(programme *Programme) error
 	Update(name string, programme *Programme) error
 	Delete(name string) error
@@ -46,6 +48,9 @@ type ProgrammeUseCase interface {
 	GetAllPO(programmeId string) ([]ProgramOutcome, error)
 	GetAllPLO(programmeId string) ([]ProgramLearningOutcome, error)
 	GetAllSO(programmeId string) ([]StudentOutcome, error)
+	GetAllPEO(programmeId string) ([]ProgramEducationalObjective, error)
+	GetAllPEOMapping(programmeId string) ([]ProgramEducationalObjectiveMapping, error)
+	GetStakeholderFeedback(programmeId string) ([]StakeholderOutcomeFeedback, error)
 
 	Create(payload CreateProgrammePayload) error
 	Update(name string, programme *UpdateProgrammePayload) error
@@ -89,7 +94,9 @@ type Programme struct {
 	AcademicYear  string `json:"academic_year" gorm:"not null"`
 	DepartmentId  string `json:"department_id" gorm:"not null"`
 
-	Structure datatypes.JSON `json:"structure" gorm:"type:json"`
+	// empty uses the method of the config
+	AttainmentMethod AttainmentMethod `json:"attainment_method" gorm:"type:char(32)"`
+	Structure        datatypes.JSON   `json:"structure" gorm:"type:json"`
 
 	Department              Department                `gorm:"foreignKey:DepartmentId" json:"department"`
 	ProgramOutcomes         []*ProgramOutcome         `gorm:"many2many:programme_po" json:"program_outcomes"`
@@ -109,7 +116,8 @@ type CreateProgrammePayload struct {
 	Year          string `json:"year" validate:"required"`
 	DepartmentId  string `json:"department_id" validate:"required"`
 
-	Structure ProgrammeStructure `json:"structure"`
+	AttainmentMethod AttainmentMethod   `json:"attainment_method" validate:"omitempty,oneof=THRESHOLD WEIGHTED_AVERAGE BEST_OF ALL_MUST_PASS RUBRIC_LEVEL"`
+	Structure        ProgrammeStructure `json:"structure"`
 }
 
 type GetProgrammesByParamsPayload struct {
@@ -129,7 +137,8 @@ type UpdateProgrammePayload struct {
 	Year          string `json:"year" validate:"required"`
 	DepartmentId  string `json:"department_id" validate:"required"`
 
-	Structure ProgrammeStructure `json:"structure" validate:"required"`
+	AttainmentMethod AttainmentMethod   `json:"attainment_method" validate:"omitempty,oneof=THRESHOLD WEIGHTED_AVERAGE BEST_OF ALL_MUST_PASS RUBRIC_LEVEL"`
+	Structure        ProgrammeStructure `json:"structure" validate:"required"`
 }
 
 type AllCourseOutcome struct {
@@ -181,19 +190,23 @@ type ProgrammeLinkedPO struct {
 }
 
 type ProgrammeLinkedPLO struct {
-	ProgrammeName    string              `json:"programme_name"`
-	ProgrammeYear    string              `json:"programme_year"`
-	CourseLinkedPLOs []CourseLinkedPLO   `json:"outcomes"`
-	AllPLOs          map[string][]string `json:"all_plos"`
-	AllCourse        []string            `json:"all_course"`
+	ProgrammeName    string                               `json:"programme_name"`
+	ProgrammeYear    string                               `json:"programme_year"`
+	CourseLinkedPLOs []CourseLinkedPLO                    `json:"outcomes"`
+	AllPLOs          map[string][]string                  `json:"all_plos"`
+	AllCourse        []string                             `json:"all_course"`
+	PEOs             []ProgramEducationalObjectiveMapping `json:"peos"`
+	// alumni and employer survey results for PLOs and PEOs
+	StakeholderFeedback []StakeholderOutcomeFeedback `json:"stakeholder_feedback"`
 }
 
 type ProgrammeLinkedSO struct {
-	ProgrammeName   string              `json:"programme_name"`
-	ProgrammeYear   string              `json:"programme_year"`
-	CourseLinkedSOs []CourseLinkedSO    `json:"outcomes"`
-	AllSOs          map[string][]string `json:"all_sos"`
-	AllCourse       []string            `json:"all_course"`
+	ProgrammeName   string                               `json:"programme_name"`
+	ProgrammeYear   string                               `json:"programme_year"`
+	CourseLinkedSOs []CourseLinkedSO                     `json:"outcomes"`
+	AllSOs          map[string][]string                  `json:"all_sos"`
+	AllCourse       []string                             `json:"all_course"`
+	PEOs            []ProgramEducationalObjectiveMapping `json:"peos"`
 }
 
 type CourseLinkedPO struct {
@@ -0,0 +1,77 @@
+package entity
+
+import (
+	"io"
+	"time"
+)
+
+type ReportType string
+
+const (
+	ReportTypeCourseCloAssessment       ReportType = "COURSE_CLO_ASSESSMENT"
+	ReportTypeCourseLinkedOutcomes      ReportType = "COURSE_LINKED_OUTCOMES"
+	ReportTypeCourseOutcomesSuccessRate ReportType = "COURSE_OUTCOMES_SUCCESS_RATE"
+	ReportTypeOutcomeAttainmentTrend    ReportType = "OUTCOME_ATTAINMENT_TREND"
+)
+
+// programme reports cover the current state of the programme, so they have no semester range
+const (
+	ReportTypeCurriculumMap         ReportType = "CURRICULUM_MAP"
+	ReportTypeProgramImprovementLog ReportType = "PROGRAM_IMPROVEMENT_LOG"
+)
+
+type ReportJobStatus string
+
+const (
+	ReportJobStatusPending   ReportJobStatus = "PENDING"
+	ReportJobStatusRunning   ReportJobStatus = "RUNNING"
+	ReportJobStatusCompleted ReportJobStatus = "COMPLETED"
+	ReportJobStatusFailed    ReportJobStatus = "FAILED"
+)
+
+// ReportJob is a report generated in the background, the file is kept in the file store and downloaded through a signed link
+type ReportJob struct {
+	Id           string          `json:"id" gorm:"primaryKey;type:char(255)"`
+	UserId       string          `json:"user_id" gorm:"index"`
+	Type         ReportType      `json:"type" gorm:"type:char(64)"`
+	ProgrammeId  string          `json:"programme_id"`
+	FromSemester int             `json:"from_semester"`
+	ToSemester   int             `json:"to_semester"`
+	Status       ReportJobStatus `json:"status" gorm:"type:char(32);index"`
+	Error        string          `json:"error,omitempty" gorm:"type:text"`
+	Attempts     int             `json:"-"`
+	FileName     string          `json:"file_name,omitempty"`
+	FileId       string          `json:"-"`
+	StartedAt    *time.Time      `json:"started_at"`
+	FinishedAt   *time.Time      `json:"finished_at"`
+	CreatedAt    time.Time       `json:"created_at"`
+	UpdatedAt    time.Time       `json:"updated_at"`
+
+	// set when the job is completed
+	DownloadUrl       string     `json:"download_url,omitempty" gorm:"-"`
+	DownloadExpiresAt *time.Time `json:"download_expires_at,omitempty" gorm:"-"`
+
+	User User `json:"-"`
+}
+
+type ReportJobRepository interface {
+	Create(job *ReportJob) error
+	GetById(id string) (*ReportJob, error)
+	// ClaimNext marks the oldest pending job running, a job running longer than the lease is claimed again
+	ClaimNext(now time.Time, lease time.Duration) (*ReportJob, error)
+	Update(job *ReportJob) error
+	GetFinishedBefore(before time.Time) ([]ReportJob, error)
+	Delete(ids []string) error
+}
+
+type ReportJobUseCase interface {
+	Enqueue(user User, reportType ReportType, programmeId string, fromSemester int, toSemester int) (*ReportJob, error)
+	// GetById returns the job of the user with a signed download link once it is completed
+	GetById(user User, id string) (*ReportJob, error)
+	// ProcessNext generates the oldest pending report, it returns false when no job is pending
+	ProcessNext() (bool, error)
+	// OpenDownload checks the signature and expiry of a download link and returns the file of the completed job, the caller closes the content
+	OpenDownload(id string, expires string, signature string) (*StoredFile, io.ReadCloser, error)
+	// DeleteExpired deletes finished jobs older than the retention with their files
+	DeleteExpired() (int64, error)
+}
@@ -0,0 +1,117 @@
+package entity
+
+// Rubric grades an assignment by criteria of performance levels, it belongs to a programme to be reused by its courses
+type Rubric struct {
+	Id          string            `json:"id" gorm:"primaryKey;type:char(255)"`
+	ProgrammeId string            `json:"programme_id" gorm:"type:char(255);index;not null"`
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	Criteria    []RubricCriterion `json:"criteria" gorm:"foreignKey:RubricId"`
+}
+
+type RubricCriterion struct {
+	Id          string        `json:"id" gorm:"primaryKey;type:char(255)"`
+	RubricId    string        `json:"rubric_id" gorm:"type:char(255);index;not null"`
+	Name        string        `json:"name"`
+	Description string        `json:"description"`
+	Sequence    int           `json:"sequence"`
+	Levels      []RubricLevel `json:"levels" gorm:"foreignKey:RubricCriterionId"`
+}
+
+type RubricLevel struct {
+	Id                string  `json:"id" gorm:"primaryKey;type:char(255)"`
+	RubricCriterionId string  `json:"rubric_criterion_id" gorm:"type:char(255);index;not null"`
+	Name              string  `json:"name"`
+	Descriptor        string  `json:"descriptor"`
+	Points            float64 `json:"points"`
+}
+
+// MaxPoints is the max score of the assessment item of the criterion
+func (c RubricCriterion) MaxPoints() float64 {
+	maxPoints := 0.0
+	for _, level := range c.Levels {
+		if level.Points > maxPoints {
+			maxPoints = level.Points
+		}
+	}
+
+	return maxPoints
+}
+
+func (r Rubric) MaxScore() float64 {
+	maxScore := 0.0
+	for _, criterion := range r.Criteria {
+		maxScore += criterion.MaxPoints()
+	}
+
+	return maxScore
+}
+
+type RubricRepository interface {
+	GetById(id string) (*Rubric, error)
+	GetByProgrammeId(programmeId string) ([]Rubric, error)
+	Create(rubric *Rubric) error
+	// Update replaces the criteria of the rubric with their levels when it has any
+	Update(id string, rubric *Rubric) error
+	Delete(id string) error
+	IsAttached(id string) (bool, error)
+
+	// Attach grades the assignment by the rubric through an assessment item per criterion
+	Attach(assignmentId string, rubricId string, items []AssessmentItem) error
+	// Detach removes the assessment items of the rubric with their scores from the assignment
+	Detach(assignmentId string) error
+}
+
+type RubricUseCase interface {
+	GetById(programmeId string, id string) (*Rubric, error)
+	GetByProgrammeId(programmeId string) ([]Rubric, error)
+	Create(programmeId string, payload CreateRubricPayload) error
+	Update(programmeId string, id string, payload UpdateRubricPayload) error
+	Delete(programmeId string, id string) error
+
+	Attach(assignmentId string, payload AttachRubricPayload) error
+	Detach(assignmentId string) error
+	SaveGrades(user User, assignmentId string, studentGrades []StudentRubricGrade) error
+}
+
+type RubricLevelPayload struct {
+	Name       string   `json:"name" validate:"required"`
+	Descriptor string   `json:"descriptor"`
+	Points     *float64 `json:"points" validate:"required,min=0"`
+}
+
+type RubricCriterionPayload struct {
+	Name        string               `json:"name" validate:"required"`
+	Description string               `json:"description"`
+	Sequence    int                  `json:"sequence"`
+	Levels      []RubricLevelPayload `json:"levels" validate:"required,min=1,dive"`
+}
+
+type CreateRubricPayload struct {
+	Name        string                   `json:"name" validate:"required"`
+	Description string                   `json:"description"`
+	Criteria    []RubricCriterionPayload `json:"criteria" validate:"required,min=1,dive"`
+}
+
+// the criteria are replaced only while the rubric is not attached to any assignment
+type UpdateRubricPayload struct {
+	Name        string                   `json:"name"`
+	Description string                   `json:"description"`
+	Criteria    []RubricCriterionPayload `json:"criteria" validate:"omitempty,dive"`
+}
+
+type AttachRubricPayload struct {
+	RubricId string `json:"rubric_id" validate:"required"`
+	// by criterion id, the CLOs of the course assessed by the criterion
+	CourseLearningOutcomeIds map[string][]string `json:"course_learning_outcome_ids"`
+}
+
+type StudentRubricGrade struct {
+	StudentId string `json:"student_id" validate:"required"`
+	// rubric level id by criterion id
+	Levels map[string]string `json:"levels" validate:"required,dive,required"`
+}
+
+type SaveRubricGradesPayload struct {
+	StudentGrades []StudentRubricGrade `json:"student_grades" validate:"required,dive"`
+}
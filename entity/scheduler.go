@@ -0,0 +1,20 @@
+package entity
+
+import "time"
+
+// SchedulerLease is held by the replica that runs scheduled work, another replica takes over once it expires
+type SchedulerLease struct {
+	Name      string `gorm:"primaryKey;type:char(64)"`
+	Holder    string `gorm:"type:char(255)"`
+	ExpiresAt time.Time
+}
+
+type SchedulerLeaseRepository interface {
+	// Acquire takes or renews the lease, it returns false while the lease of another holder has not expired
+	Acquire(name string, holder string, now time.Time, expiresAt time.Time) (bool, error)
+}
+
+type SchedulerUseCase interface {
+	// Run sends due reminders when this replica holds the lease, nothing is run while another replica holds it
+	Run() (int, error)
+}
@@ -4,6 +4,8 @@ type Semester struct {
 	Id               string `json:"id" gorm:"primaryKey;type:char(255)"`
 	Year             int    `json:"year"`
 	SemesterSequence string `json:"semester_sequence"`
+
+	Milestones []SemesterMilestone `json:"milestones,omitempty" gorm:"foreignKey:SemesterId"`
 }
 
 type CreateSemesterPayload struct {
@@ -14,15 +14,39 @@ type Session struct {
 	ExpiredAt time.Time `json:"expiredAt" db:"expired_at"`
 	CreatedAt time.Time `json:"createdAt" db:"created_at"`
 
+	// password is verified but the second factor is not, the session cannot be used to authenticate yet
+	TwoFactorPending  bool `json:"-"`
+	TwoFactorAttempts int  `json:"-"`
+
 	User User `gorm:"foreignKey:UserId"`
 }
 
+// ActiveSession is a session shown to its owner, Current marks the session of the request
+type ActiveSession struct {
+	Id        string    `json:"id"`
+	IpAddress string    `json:"ipAddress"`
+	UserAgent string    `json:"userAgent"`
+	Device    string    `json:"device"`
+	CreatedAt time.Time `json:"createdAt"`
+	ExpiredAt time.Time `json:"expiredAt"`
+	Current   bool      `json:"current"`
+}
+
 type SessionRepository interface {
 	Create(session *Session) error
 	Get(id string) (*Session, error)
 	Delete(id string) error
 	DeleteByUserId(userId string) error
+	DeleteByUserIdExcept(userId string, id string) error
 	DeleteDuplicates(userId string, ipAddress string, userAgent string) error
+	// IncreaseTwoFactorAttempts counts a wrong second factor unless the session already has maxAttempts, it returns false when not counted
+	IncreaseTwoFactorAttempts(id string, maxAttempts int) (bool, error)
+	// Activate moves a pending session to newId, it returns false when the session is no longer pending
+	Activate(id string, newId string, expiredAt time.Time) (bool, error)
+	// GetActiveByUserId returns sessions of the user that are not expired or waiting for the second factor
+	GetActiveByUserId(userId string) ([]Session, error)
+	UpdateExpiredAt(id string, expiredAt time.Time) error
+	DeleteExpired(before time.Time) (int64, error)
 }
 
 type SessionUseCase interface {
@@ -33,4 +57,19 @@ type SessionUseCase interface {
 	Destroy(id string) (*fiber.Cookie, error)
 	DestroyByUserId(userId string) (*fiber.Cookie, error)
 	Validate(header string) (*Session, error)
+
+	// CreatePending creates a session waiting for the second factor and returns the signed challenge
+	CreatePending(userId string, ipAddress string, userAgent string) (*Session, string, error)
+	GetPending(challenge string) (*Session, error)
+	// FailPending counts a wrong second factor and destroys the session after too many
+	FailPending(session Session) error
+	ActivatePending(id string) (*fiber.Cookie, error)
+
+	// Renew extends a session past half of its max age and returns the new cookie, it returns nil when no renewal is needed
+	Renew(session Session) (*fiber.Cookie, error)
+	GetActiveByUserId(userId string, currentId string) ([]ActiveSession, error)
+	// Revoke destroys a session of the user, sessions of other users are not found
+	Revoke(userId string, id string) (*fiber.Cookie, error)
+	DestroyOthers(userId string, currentId string) error
+	DeleteExpired() (int64, error)
 }
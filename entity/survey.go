@@ -19,6 +19,13 @@ type SurveyRepository interface {
 	Update(survey *Survey) error
 
 	GetSurveysWithCourseAndOutcomes() ([]SurveyWithCourseAndOutcomes, error)
+
+	GetByProgrammeId(programmeId string) ([]Survey, error)
+	CreateInvitations(invitations []SurveyInvitation) error
+	GetInvitationsBySurveyId(surveyId string) ([]SurveyInvitation, error)
+	GetInvitationByTokenHash(tokenHash string) (*SurveyInvitation, error)
+	// SubmitResponse stores the answers and consumes the invitation, it reports false when the invitation was already used
+	SubmitResponse(invitationId string, scores []QScore) (bool, error)
 }
 
 type SurveyUseCase interface {
@@ -36,8 +43,22 @@ type SurveyUseCase interface {
 	UpdateQuestion(id string, request *UpdateQuestionRequest) error
 
 	GetSurveysWithCourseAndOutcomes() ([]SurveyWithCourseAndOutcomes, error)
+
+	GetByProgrammeId(programmeId string) ([]Survey, error)
+	CreateInvitations(surveyId string, payload CreateSurveyInvitationPayload) (*CreateSurveyInvitationResult, error)
+	GetInvitationsBySurveyId(surveyId string) ([]SurveyInvitation, error)
+	GetFormByToken(token string) (*SurveyForm, error)
+	SubmitResponse(token string, payload SubmitSurveyResponsePayload) error
 }
 
+type SurveyAudience string
+
+const (
+	SurveyAudienceCourse   SurveyAudience = "COURSE"
+	SurveyAudienceAlumni   SurveyAudience = "ALUMNI"
+	SurveyAudienceEmployer SurveyAudience = "EMPLOYER"
+)
+
 type Survey struct {
 	Id          string     `json:"id" gorm:"primaryKey;type:char(255)"`
 	Title       string     `json:"title"`
@@ -46,6 +67,9 @@ type Survey struct {
 	CreateAt    time.Time  `json:"create_at" gorm:"autoCreateTime"`
 	Questions   []Question `json:"questions" gorm:"foreignKey:SurveyId"`
 	CourseId    string     `json:"course_id" gorm:"index"`
+	// programme-scoped stakeholder surveys have no course
+	ProgrammeId string         `json:"programme_id" gorm:"index"`
+	Audience    SurveyAudience `json:"audience" gorm:"type:char(32);default:'COURSE'"`
 }
 
 type Question struct {
@@ -54,6 +78,7 @@ type Question struct {
 	POId     string `json:"po_id"`
 	PLOId    string `json:"plo_id"`
 	SOId     string `json:"so_id"`
+	PEOId    string `json:"peo_id"`
 	SurveyId string `json:"survey_id" gorm:"index"`
 
 	Scores []QScore `json:"q_scores" gorm:"foreignKey:QuestionId;constraint:OnDelete:CASCADE;"`
@@ -66,11 +91,13 @@ type QScore struct {
 }
 
 type CreateSurveyRequest struct {
-	Title       string     `json:"title"`
-	Description string     `json:"description"`
-	IsComplete  bool       `json:"is_complete"`
-	CourseId    string     `json:"course_id"`
-	Questions   []Question `json:"questions"`
+	Title       string         `json:"title"`
+	Description string         `json:"description"`
+	IsComplete  bool           `json:"is_complete"`
+	CourseId    string         `json:"course_id"`
+	ProgrammeId string         `json:"programme_id"`
+	Audience    SurveyAudience `json:"audience" validate:"omitempty,oneof=COURSE ALUMNI EMPLOYER"`
+	Questions   []Question     `json:"questions"`
 }
 
 type UpdateSurveyRequest struct {
@@ -86,6 +113,7 @@ type CreateQuestionRequest struct {
 	POId     string `json:"po_id"`
 	PLOId    string `json:"plo_id"`
 	SOId     string `json:"so_id"`
+	PEOId    string `json:"peo_id"`
 }
 
 type UpdateQuestionRequest struct {
@@ -93,6 +121,76 @@ type UpdateQuestionRequest struct {
 	POId     string `json:"po_id,omitempty"`
 	PLOId    string `json:"plo_id,omitempty"`
 	SOId     string `json:"so_id,omitempty"`
+	PEOId    string `json:"peo_id,omitempty"`
+}
+
+// One-time invitation for an alumnus or employer to answer a programme survey
+type SurveyInvitation struct {
+	Id                 string     `json:"id" gorm:"primaryKey;type:char(255)"`
+	SurveyId           string     `json:"survey_id" gorm:"index"`
+	Email              string     `json:"email"`
+	Name               string     `json:"name"`
+	GraduatedStudentId string     `json:"graduated_student_id"`
+	TokenHash          string     `json:"-" gorm:"type:char(64);uniqueIndex"`
+	ExpiresAt          time.Time  `json:"expires_at"`
+	RespondedAt        *time.Time `json:"responded_at"`
+	CreatedAt          time.Time  `json:"created_at"`
+}
+
+type SurveyRecipientPayload struct {
+	Email string `json:"email" validate:"required,email"`
+	Name  string `json:"name"`
+}
+
+type CreateSurveyInvitationPayload struct {
+	Recipients []SurveyRecipientPayload `json:"recipients" validate:"dive"`
+	// alumni surveys may invite every graduate of the programme from this year
+	GraduationYear string `json:"graduation_year"`
+	ExpiresInDays  int    `json:"expires_in_days" validate:"omitempty,min=1,max=90"`
+}
+
+type CreateSurveyInvitationResult struct {
+	InvitedEmails []string `json:"invited_emails"`
+}
+
+type SurveyFormQuestion struct {
+	Id       string `json:"id"`
+	Question string `json:"question"`
+}
+
+type SurveyForm struct {
+	SurveyId    string               `json:"survey_id"`
+	Title       string               `json:"title"`
+	Description string               `json:"description"`
+	Audience    SurveyAudience       `json:"audience"`
+	ExpiresAt   time.Time            `json:"expires_at"`
+	Questions   []SurveyFormQuestion `json:"questions"`
+}
+
+type SurveyAnswerPayload struct {
+	QuestionId string  `json:"question_id" validate:"required"`
+	Score      float64 `json:"score" validate:"gte=0,lte=5"`
+}
+
+type SubmitSurveyResponsePayload struct {
+	Answers []SurveyAnswerPayload `json:"answers" validate:"required,dive"`
+}
+
+type StakeholderFeedbackRecord struct {
+	OutcomeType   OutcomeType
+	Audience      SurveyAudience
+	OutcomeId     string
+	ResponseCount int
+	AverageScore  float64
+}
+
+type StakeholderOutcomeFeedback struct {
+	OutcomeType   OutcomeType    `json:"outcome_type"`
+	OutcomeId     string         `json:"outcome_id"`
+	Code          string         `json:"code"`
+	Audience      SurveyAudience `json:"audience"`
+	ResponseCount int            `json:"response_count"`
+	AverageScore  float64        `json:"average_score"`
 }
 
 type SurveyWithCourseAndOutcomes struct {
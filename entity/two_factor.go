@@ -0,0 +1,65 @@
+package entity
+
+import "time"
+
+// One-time code to sign in when the authenticator app is lost, only the hash is stored
+type RecoveryCode struct {
+	Id       string     `json:"id" gorm:"primaryKey;type:char(255)"`
+	UserId   string     `json:"user_id" gorm:"type:char(255);index"`
+	CodeHash string     `json:"-" gorm:"type:char(64)"`
+	UsedAt   *time.Time `json:"used_at"`
+
+	User User `json:"-"`
+}
+
+// Roles listed here cannot sign in without two-factor authentication
+type TwoFactorPolicy struct {
+	Role UserRole `json:"role" gorm:"primaryKey;type:char(64)"`
+}
+
+type TwoFactorRepository interface {
+	UpdateTotp(userId string, secret string, enabled bool) error
+	// UpdateTotpLastUsedStep saves the step only when it is after the last used one, it returns false when the step was already used
+	UpdateTotpLastUsedStep(userId string, step int64) (bool, error)
+	ReplaceRecoveryCodes(userId string, codes []RecoveryCode) error
+	// UseRecoveryCode marks the code as used and returns false when it does not exist or was used
+	UseRecoveryCode(userId string, codeHash string) (bool, error)
+	CountUnusedRecoveryCodes(userId string) (int64, error)
+	GetPolicies() ([]TwoFactorPolicy, error)
+	ReplacePolicies(policies []TwoFactorPolicy) error
+}
+
+type TwoFactorUseCase interface {
+	BeginEnrollment(user User) (*TotpEnrollment, error)
+	ConfirmEnrollment(user User, code string) ([]string, error)
+	Disable(user User, code string) error
+	// Reset removes the two-factor of another user who lost the device
+	Reset(userId string) error
+	RegenerateRecoveryCodes(user User, code string) ([]string, error)
+	GetStatus(user User) (*TwoFactorStatus, error)
+	// Verify accepts a TOTP or a recovery code
+	Verify(user User, code string) error
+	IsRequired(user User) (bool, error)
+	GetPolicy() ([]UserRole, error)
+	UpdatePolicy(roles []UserRole) error
+}
+
+type TotpEnrollment struct {
+	Secret string `json:"secret"`
+	// otpauth address to be shown as a QR code
+	ProvisioningUri string `json:"provisioning_uri"`
+}
+
+type TwoFactorStatus struct {
+	Enabled                bool  `json:"enabled"`
+	Required               bool  `json:"required"`
+	RemainingRecoveryCodes int64 `json:"remaining_recovery_codes"`
+}
+
+type TwoFactorCodePayload struct {
+	Code string `json:"code" validate:"required"`
+}
+
+type UpdateTwoFactorPolicyPayload struct {
+	Roles []UserRole `json:"roles" validate:"dive,oneof=LECTURER MODERATOR HEAD_OF_CURRICULUM AUN-QA_MANAGER TABEE_MANAGER ABET_MANAGER"`
+}
@@ -2,6 +2,7 @@ package entity
 
 import (
 	"strings"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -83,6 +84,15 @@ type User struct {
 	DegreeTH           string   `json:"degree_th"`
 	DegreeEN           string   `json:"degree_en"`
 	Tel                string   `json:"tel"`
+
+	TotpEnabled bool   `json:"totp_enabled"`
+	TotpSecret  string `json:"-"`
+	// last accepted time step, a code cannot be used twice
+	TotpLastUsedStep int64 `json:"-"`
+
+	// nil only for users created without a password of their own (single sign-on, LDAP), the password expiry does not apply to them
+	PasswordChangedAt  *time.Time `json:"password_changed_at"`
+	MustChangePassword bool       `json:"must_change_password"`
 }
 
 type CreateUserPayload struct {
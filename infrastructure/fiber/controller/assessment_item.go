@@ -0,0 +1,146 @@
+package controller
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/team-inu/inu-backyard/entity"
+	"github.com/team-inu/inu-backyard/infrastructure/fiber/middleware"
+	"github.com/team-inu/inu-backyard/infrastructure/fiber/response"
+	"github.com/team-inu/inu-backyard/internal/validator"
+)
+
+type AssessmentItemController struct {
+	AssessmentItemUseCase entity.AssessmentItemUseCase
+	Validator             validator.PayloadValidator
+}
+
+func NewAssessmentItemController(validator validator.PayloadValidator, assessmentItemUseCase entity.AssessmentItemUseCase) *AssessmentItemController {
+	return &AssessmentItemController{
+		AssessmentItemUseCase: assessmentItemUseCase,
+		Validator:             validator,
+	}
+}
+
+func (c AssessmentItemController) GetById(ctx *fiber.Ctx) error {
+	itemId := ctx.Params("itemId")
+
+	item, err := c.AssessmentItemUseCase.GetById(itemId)
+	if err != nil {
+		return err
+	}
+
+	if item == nil {
+		return response.NewSuccessResponse(ctx, fiber.StatusNotFound, item)
+	}
+
+	return response.NewSuccessResponse(ctx, fiber.StatusOK, item)
+}
+
+func (c AssessmentItemController) GetByAssignmentId(ctx *fiber.Ctx) error {
+	assignmentId := ctx.Params("assignmentId")
+
+	items, err := c.AssessmentItemUseCase.GetByAssignmentId(assignmentId)
+	if err != nil {
+		return err
+	}
+
+	return response.NewSuccessResponse(ctx, fiber.StatusOK, items)
+}
+
+func (c AssessmentItemController) Create(ctx *fiber.Ctx) error {
+	var payload entity.CreateAssessmentItemPayload
+	if ok, err := c.Validator.Validate(&payload, ctx); !ok {
+		return err
+	}
+
+	assignmentId := ctx.Params("assignmentId")
+
+	err := c.AssessmentItemUseCase.Create(assignmentId, payload)
+	if err != nil {
+		return err
+	}
+
+	return response.NewSuccessResponse(ctx, fiber.StatusCreated, nil)
+}
+
+func (c AssessmentItemController) Update(ctx *fiber.Ctx) error {
+	var payload entity.UpdateAssessmentItemPayload
+	if ok, err := c.Validator.Validate(&payload, ctx); !ok {
+		return err
+	}
+
+	itemId := ctx.Params("itemId")
+
+	err := c.AssessmentItemUseCase.Update(itemId, payload)
+	if err != nil {
+		return err
+	}
+
+	return response.NewSuccessResponse(ctx, fiber.StatusOK, nil)
+}
+
+func (c AssessmentItemController) Delete(ctx *fiber.Ctx) error {
+	itemId := ctx.Params("itemId")
+
+	err := c.AssessmentItemUseCase.Delete(itemId)
+	if err != nil {
+		return err
+	}
+
+	return response.NewSuccessResponse(ctx, fiber.StatusOK, nil)
+}
+
+func (c AssessmentItemController) CreateLinkCourseLearningOutcome(ctx *fiber.Ctx) error {
+	var payload entity.CreateLinkCourseLearningOutcomePayload
+	if ok, err := c.Validator.Validate(&payload, ctx); !ok {
+		return err
+	}
+
+	itemId := ctx.Params("itemId")
+
+	err := c.AssessmentItemUseCase.CreateLinkCourseLearningOutcome(itemId, payload.CourseLearningOutcomeIds)
+	if err != nil {
+		return err
+	}
+
+	return response.NewSuccessResponse(ctx, fiber.StatusCreated, nil)
+}
+
+func (c AssessmentItemController) DeleteLinkCourseLearningOutcome(ctx *fiber.Ctx) error {
+	itemId := ctx.Params("itemId")
+	cloId := ctx.Params("cloId")
+
+	err := c.AssessmentItemUseCase.DeleteLinkCourseLearningOutcome(itemId, cloId)
+	if err != nil {
+		return err
+	}
+
+	return response.NewSuccessResponse(ctx, fiber.StatusOK, nil)
+}
+
+func (c AssessmentItemController) GetScoresByAssignmentId(ctx *fiber.Ctx) error {
+	assignmentId := ctx.Params("assignmentId")
+
+	scores, err := c.AssessmentItemUseCase.GetScoresByAssignmentId(assignmentId)
+	if err != nil {
+		return err
+	}
+
+	return response.NewSuccessResponse(ctx, fiber.StatusOK, scores)
+}
+
+func (c AssessmentItemController) SaveScores(ctx *fiber.Ctx) error {
+	var payload entity.SaveAssessmentItemScoresPayload
+	if ok, err := c.Validator.Validate(&payload, ctx); !ok {
+		return err
+	}
+
+	user := middleware.GetUserFromCtx(ctx)
+	assignmentId := ctx.Params("assignmentId")
+
+	err := c.AssessmentItemUseCase.SaveScores(*user, assignmentId, payload.StudentScores)
+	if err != nil {
+		return err
+	}
+
+	return response.NewSuccessResponse(ctx, fiber.StatusOK, nil)
+}
@@ -0,0 +1,37 @@
+package controller
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/team-inu/inu-backyard/entity"
+	errs "github.com/team-inu/inu-backyard/entity/error"
+	"github.com/team-inu/inu-backyard/infrastructure/fiber/middleware"
+	"github.com/team-inu/inu-backyard/infrastructure/fiber/response"
+	"github.com/team-inu/inu-backyard/internal/validator"
+)
+
+type AttainmentController struct {
+	AttainmentUseCase entity.AttainmentUseCase
+	Validator         validator.PayloadValidator
+}
+
+func NewAttainmentController(validator validator.PayloadValidator, attainmentUseCase entity.AttainmentUseCase) *AttainmentController {
+	return &AttainmentController{
+		AttainmentUseCase: attainmentUseCase,
+		Validator:         validator,
+	}
+}
+
+// RefreshAll recomputes the attainments of every course, the results are updated in the background
+func (c AttainmentController) RefreshAll(ctx *fiber.Ctx) error {
+	user := middleware.GetUserFromCtx(ctx)
+	if !user.IsRoles([]entity.UserRole{entity.UserRoleHeadOfCurriculum}) {
+		return errs.New(errs.ErrAttainmentPermission, "no permission to refresh attainments")
+	}
+
+	count, err := c.AttainmentUseCase.RefreshAll()
+	if err != nil {
+		return err
+	}
+
+	return response.NewSuccessResponse(ctx, fiber.StatusAccepted, fiber.Map{"course_count": count})
+}
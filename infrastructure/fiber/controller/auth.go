@@ -1,12 +1,15 @@
 package controller
 
 import (
+	"net/url"
+	"strconv"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/team-inu/inu-backyard/entity"
 	errs "github.com/team-inu/inu-backyard/entity/error"
 	"github.com/team-inu/inu-backyard/infrastructure/captcha"
+	"github.com/team-inu/inu-backyard/infrastructure/fiber/middleware"
 	"github.com/team-inu/inu-backyard/infrastructure/fiber/response"
 	"github.com/team-inu/inu-backyard/internal/config"
 	"github.com/team-inu/inu-backyard/internal/validator"
@@ -68,18 +71,112 @@ func (c AuthController) SignIn(ctx *fiber.Ctx) error {
 
 	}
 
-	cookie, err := c.AuthUseCase.SignIn(payload, ipAddress, userAgent)
+	result, err := c.AuthUseCase.SignIn(payload, ipAddress, userAgent)
 	if err != nil {
 		return err
 	}
 
-	ctx.Cookie(cookie)
+	if result.TwoFactorChallenge != nil {
+		return response.NewSuccessResponse(ctx, fiber.StatusOK, fiber.Map{
+			"two_factor_required": true,
+			"challenge":           result.TwoFactorChallenge.Challenge,
+			"enrollment_required": result.TwoFactorChallenge.EnrollmentRequired,
+			"expired_at":          result.TwoFactorChallenge.ExpiredAt,
+		})
+	}
+
+	ctx.Cookie(result.Cookie)
+
+	return response.NewSuccessResponse(ctx, fiber.StatusOK, fiber.Map{
+		"expired_at":               result.Cookie.Expires,
+		"password_change_required": result.PasswordChangeRequired,
+	})
+}
+
+func (c AuthController) VerifyTwoFactor(ctx *fiber.Ctx) error {
+	var payload entity.VerifyTwoFactorPayload
+	if ok, err := c.Validator.Validate(&payload, ctx); !ok {
+		return err
+	}
+
+	result, err := c.AuthUseCase.VerifyTwoFactor(payload, ctx.IP())
+	if err != nil {
+		return err
+	}
+
+	ctx.Cookie(result.Cookie)
 
 	return response.NewSuccessResponse(ctx, fiber.StatusOK, fiber.Map{
-		"expired_at": cookie.Expires,
+		"expired_at":               result.Cookie.Expires,
+		"recovery_codes":           result.RecoveryCodes,
+		"password_change_required": result.PasswordChangeRequired,
 	})
 }
 
+func (c AuthController) BeginChallengeEnrollment(ctx *fiber.Ctx) error {
+	var payload entity.ChallengeEnrollmentPayload
+	if ok, err := c.Validator.Validate(&payload, ctx); !ok {
+		return err
+	}
+
+	enrollment, err := c.AuthUseCase.BeginChallengeEnrollment(payload.Challenge)
+	if err != nil {
+		return err
+	}
+
+	return response.NewSuccessResponse(ctx, fiber.StatusOK, enrollment)
+}
+
+func (c AuthController) SsoSignIn(ctx *fiber.Ctx) error {
+	authUrl, stateCookie, err := c.AuthUseCase.BeginSsoSignIn()
+	if err != nil {
+		return err
+	}
+
+	ctx.Cookie(stateCookie)
+
+	return ctx.Redirect(authUrl, fiber.StatusFound)
+}
+
+func (c AuthController) SsoCallback(ctx *fiber.Ctx) error {
+	var payload entity.SsoCallbackPayload
+	if ok, err := c.Validator.Validate(&payload, ctx); !ok {
+		return err
+	}
+
+	stateCookieName := c.Config.Session.CookieName + entity.SsoStateCookieSuffix
+	stateCookie := ctx.Cookies(stateCookieName)
+	ctx.ClearCookie(stateCookieName)
+
+	ipAddress := ctx.IP()
+	userAgent := string(ctx.Context().UserAgent())
+
+	result, err := c.AuthUseCase.SsoSignIn(payload, stateCookie, ipAddress, userAgent)
+	if err != nil {
+		return err
+	}
+
+	redirectUrl, err := url.Parse(c.Config.Oidc.PostLoginRedirectUrl)
+	if err != nil {
+		return errs.New(errs.ErrSsoSignIn, "invalid post login redirect url", err)
+	}
+
+	// the frontend continues with the same two-factor and password change steps as a password sign in
+	query := redirectUrl.Query()
+	if result.TwoFactorChallenge != nil {
+		query.Set("challenge", result.TwoFactorChallenge.Challenge)
+		query.Set("enrollment_required", strconv.FormatBool(result.TwoFactorChallenge.EnrollmentRequired))
+	} else {
+		ctx.Cookie(result.Cookie)
+		if result.PasswordChangeRequired {
+			query.Set("password_change_required", "true")
+		}
+	}
+	redirectUrl.RawQuery = query.Encode()
+
+	return ctx.Redirect(redirectUrl.String(), fiber.StatusFound)
+}
+
 func (c AuthController) SignOut(ctx *fiber.Ctx) error {
 	sid := ctx.Cookies(c.Config.Session.CookieName)
 	cookie, err := c.AuthUseCase.SignOut(sid)
@@ -99,7 +196,7 @@ func (c AuthController) ForgotPassword(ctx *fiber.Ctx) error {
 		return err
 	}
 
-	err := c.AuthUseCase.ForgotPassword(payload.Email)
+	err := c.AuthUseCase.ForgotPassword(payload.Email, ctx.IP())
 	if err != nil {
 		return err
 	}
@@ -107,13 +204,15 @@ func (c AuthController) ForgotPassword(ctx *fiber.Ctx) error {
 	return response.NewSuccessResponse(ctx, fiber.StatusOK, nil)
 }
 
-func (c AuthController) ResetPassword(ctx *fiber.Ctx) error {
-	var payload entity.ResetPasswordPayload
+func (c AuthController) ChangePassword(ctx *fiber.Ctx) error {
+	var payload entity.ChangePasswordPayload
 	if ok, err := c.Validator.Validate(&payload, ctx); !ok {
 		return err
 	}
 
-	err := c.AuthUseCase.ResetPassword(payload.Email, payload.Token, payload.NewPassword)
+	user := middleware.GetUserFromCtx(ctx)
+
+	err := c.AuthUseCase.ChangePassword(user.Id, payload.OldPassword, payload.NewPassword)
 	if err != nil {
 		return err
 	}
@@ -121,12 +220,16 @@ func (c AuthController) ResetPassword(ctx *fiber.Ctx) error {
 	return response.NewSuccessResponse(ctx, fiber.StatusOK, nil)
 }
 
-func (c AuthController) GetSessionData(ctx *fiber.Ctx) error {
-	email := ctx.Params("email")
-	session, err := c.AuthUseCase.GetSession(email)
+func (c AuthController) ResetPassword(ctx *fiber.Ctx) error {
+	var payload entity.ResetPasswordPayload
+	if ok, err := c.Validator.Validate(&payload, ctx); !ok {
+		return err
+	}
+
+	err := c.AuthUseCase.ResetPassword(payload.Email, payload.Token, payload.NewPassword, ctx.IP())
 	if err != nil {
 		return err
 	}
 
-	return response.NewSuccessResponse(ctx, fiber.StatusOK, session)
+	return response.NewSuccessResponse(ctx, fiber.StatusOK, nil)
 }
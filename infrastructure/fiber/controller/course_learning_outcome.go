@@ -77,7 +77,7 @@ func (c CourseLearningOutcomeController) CreateLinkProgramOutcome(ctx *fiber.Ctx
 		return err
 	}
 
-	err := c.CourseLearningOutcomeUseCase.CreateLinkProgramOutcome(cloId, payload.ProgramOutcomeIds)
+	err := c.CourseLearningOutcomeUseCase.CreateLinkProgramOutcome(cloId, payload.ProgramOutcomeIds, payload.Weights)
 	if err != nil {
 		return err
 	}
@@ -93,7 +93,7 @@ func (c CourseLearningOutcomeController) CreateLinkSubProgramLearningOutcome(ctx
 		return err
 	}
 
-	err := c.CourseLearningOutcomeUseCase.CreateLinkSubProgramLearningOutcome(cloId, payload.SubProgramLearningOutcomeIds)
+	err := c.CourseLearningOutcomeUseCase.CreateLinkSubProgramLearningOutcome(cloId, payload.SubProgramLearningOutcomeIds, payload.Weights)
 	if err != nil {
 		return err
 	}
@@ -109,7 +109,7 @@ func (c CourseLearningOutcomeController) CreateLinkSubStudentOutcome(ctx *fiber.
 		return err
 	}
 
-	err := c.CourseLearningOutcomeUseCase.CreateLinkSubStudentOutcome(cloId, payload.SubStudentOutcomeIds)
+	err := c.CourseLearningOutcomeUseCase.CreateLinkSubStudentOutcome(cloId, payload.SubStudentOutcomeIds, payload.Weights)
 	if err != nil {
 		return err
 	}
@@ -1,23 +1,25 @@
 package controller
 
 import (
-	"fmt"
 	"strconv"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/team-inu/inu-backyard/entity"
+	"github.com/team-inu/inu-backyard/infrastructure/fiber/middleware"
 	"github.com/team-inu/inu-backyard/infrastructure/fiber/response"
 	"github.com/team-inu/inu-backyard/internal/validator"
 )
 
 type CoursePortfolioController struct {
 	CoursePortfolioUseCase entity.CoursePortfolioUseCase
+	ReportJobUseCase       entity.ReportJobUseCase
 	Validator              validator.PayloadValidator
 }
 
-func NewCoursePortfolioController(validator validator.PayloadValidator, coursePortfolioUseCase entity.CoursePortfolioUseCase) *CoursePortfolioController {
+func NewCoursePortfolioController(validator validator.PayloadValidator, coursePortfolioUseCase entity.CoursePortfolioUseCase, reportJobUseCase entity.ReportJobUseCase) *CoursePortfolioController {
 	return &CoursePortfolioController{
 		CoursePortfolioUseCase: coursePortfolioUseCase,
+		ReportJobUseCase:       reportJobUseCase,
 		Validator:              validator,
 	}
 }
@@ -68,6 +70,38 @@ func (c CoursePortfolioController) GetAllProgramOutcomeCourses(ctx *fiber.Ctx) e
 	return response.NewSuccessResponse(ctx, fiber.StatusOK, records)
 }
 
+func (c CoursePortfolioController) GetAllStudentOutcomeCourses(ctx *fiber.Ctx) error {
+	records, err := c.CoursePortfolioUseCase.GetAllStudentOutcomeCourses()
+	if err != nil {
+		return err
+	}
+	return response.NewSuccessResponse(ctx, fiber.StatusOK, records)
+}
+
+func (c CoursePortfolioController) GetProgrammeStudentOutcomeAttainment(ctx *fiber.Ctx) error {
+	user := middleware.GetUserFromCtx(ctx)
+	programmeId := ctx.Params("programmeId")
+	toSerm, err := strconv.Atoi(ctx.Query("to"))
+	if err != nil {
+		return response.NewErrorResponse(ctx, fiber.StatusBadRequest, nil)
+	}
+	fromSerm, err := strconv.Atoi(ctx.Query("from"))
+	if err != nil {
+		return response.NewErrorResponse(ctx, fiber.StatusBadRequest, nil)
+	}
+
+	if toSerm < fromSerm {
+		return response.NewErrorResponse(ctx, fiber.StatusBadRequest, nil)
+	}
+
+	report, err := c.CoursePortfolioUseCase.GetProgrammeStudentOutcomeAttainment(*user, programmeId, fromSerm, toSerm)
+	if err != nil {
+		return err
+	}
+
+	return response.NewSuccessResponse(ctx, fiber.StatusOK, report)
+}
+
 func (c CoursePortfolioController) Update(ctx *fiber.Ctx) error {
 	var payload entity.SaveCoursePortfolioPayload
 	if ok, err := c.Validator.Validate(&payload, ctx); !ok {
@@ -126,16 +160,7 @@ func (c CoursePortfolioController) GetCourseCloAssessment(ctx *fiber.Ctx) error
 		return response.NewErrorResponse(ctx, fiber.StatusBadRequest, nil)
 	}
 
-	file, err := c.CoursePortfolioUseCase.GetCourseCloAssessment(programmeId, fromSerm, toSerm)
-	if err != nil {
-		return err
-	}
-
-	ctx.Set("Content-Type", file.FileType)
-	ctx.Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, file.FileName))
-
-	// Send file from disk
-	return ctx.SendFile(file.FilePath)
+	return c.enqueueReport(ctx, entity.ReportTypeCourseCloAssessment, programmeId, fromSerm, toSerm)
 }
 
 func (c CoursePortfolioController) GetCourseLinkedOutcomes(ctx *fiber.Ctx) error {
@@ -159,16 +184,7 @@ func (c CoursePortfolioController) GetCourseLinkedOutcomes(ctx *fiber.Ctx) error
 		return response.NewErrorResponse(ctx, fiber.StatusBadRequest, nil)
 	}
 
-	file, err := c.CoursePortfolioUseCase.GetCourseLinkedOutcomes(programmeId, fromSerm, toSerm)
-	if err != nil {
-		return err
-	}
-
-	ctx.Set("Content-Type", file.FileType)
-	ctx.Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, file.FileName))
-
-	// Send file from disk
-	return ctx.SendFile(file.FilePath)
+	return c.enqueueReport(ctx, entity.ReportTypeCourseLinkedOutcomes, programmeId, fromSerm, toSerm)
 }
 
 func (c CoursePortfolioController) GetCourseOutcomesSuccessRate(ctx *fiber.Ctx) error {
@@ -192,26 +208,69 @@ func (c CoursePortfolioController) GetCourseOutcomesSuccessRate(ctx *fiber.Ctx)
 		return response.NewErrorResponse(ctx, fiber.StatusBadRequest, nil)
 	}
 
-	file, err := c.CoursePortfolioUseCase.GetCourseOutcomesSuccessRate(programmeId, fromSerm, toSerm)
+	return c.enqueueReport(ctx, entity.ReportTypeCourseOutcomesSuccessRate, programmeId, fromSerm, toSerm)
+}
+
+func (c CoursePortfolioController) GetCourseOutcomesSuccessRateByCourseId(ctx *fiber.Ctx) error {
+	courseId := ctx.Params("courseId")
+
+	result, err := c.CoursePortfolioUseCase.GetCourseOutcomes(courseId)
+	if err != nil {
+		return err
+	}
+
+	return response.NewSuccessResponse(ctx, fiber.StatusOK, result)
+}
+
+func (c CoursePortfolioController) GetOutcomeAttainmentTrend(ctx *fiber.Ctx) error {
+	programmeId := ctx.Params("programmeId")
+	toSerm, err := strconv.Atoi(ctx.Query("to"))
+	if err != nil {
+		return response.NewErrorResponse(ctx, fiber.StatusBadRequest, nil)
+	}
+	fromSerm, err := strconv.Atoi(ctx.Query("from"))
+	if err != nil {
+		return response.NewErrorResponse(ctx, fiber.StatusBadRequest, nil)
+	}
+
+	if toSerm < fromSerm {
+		return response.NewErrorResponse(ctx, fiber.StatusBadRequest, nil)
+	}
+
+	trend, err := c.CoursePortfolioUseCase.GetOutcomeAttainmentTrend(programmeId, fromSerm, toSerm)
 	if err != nil {
 		return err
 	}
 
-	// Set headers to indicate download
-	ctx.Set("Content-Type", file.FileType)
-	ctx.Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, file.FileName))
+	return response.NewSuccessResponse(ctx, fiber.StatusOK, trend)
+}
+
+func (c CoursePortfolioController) GetOutcomeAttainmentTrendFile(ctx *fiber.Ctx) error {
+	programmeId := ctx.Params("programmeId")
+	toSerm, err := strconv.Atoi(ctx.Query("to"))
+	if err != nil {
+		return response.NewErrorResponse(ctx, fiber.StatusBadRequest, nil)
+	}
+	fromSerm, err := strconv.Atoi(ctx.Query("from"))
+	if err != nil {
+		return response.NewErrorResponse(ctx, fiber.StatusBadRequest, nil)
+	}
+
+	if toSerm < fromSerm {
+		return response.NewErrorResponse(ctx, fiber.StatusBadRequest, nil)
+	}
 
-	// Send file from disk
-	return ctx.SendFile(file.FilePath)
+	return c.enqueueReport(ctx, entity.ReportTypeOutcomeAttainmentTrend, programmeId, fromSerm, toSerm)
 }
 
-func (c CoursePortfolioController) GetCourseOutcomesSuccessRateByCourseId(ctx *fiber.Ctx) error {
-	courseId := ctx.Params("courseId")
+// enqueueReport answers with the job to poll, the file is generated in the background
+func (c CoursePortfolioController) enqueueReport(ctx *fiber.Ctx, reportType entity.ReportType, programmeId string, fromSerm int, toSerm int) error {
+	user := middleware.GetUserFromCtx(ctx)
 
-	result, err := c.CoursePortfolioUseCase.GetCourseOutcomes(courseId)
+	job, err := c.ReportJobUseCase.Enqueue(*user, reportType, programmeId, fromSerm, toSerm)
 	if err != nil {
 		return err
 	}
 
-	return response.NewSuccessResponse(ctx, fiber.StatusOK, result)
+	return response.NewSuccessResponse(ctx, fiber.StatusAccepted, job)
 }
@@ -0,0 +1,75 @@
+package controller
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/team-inu/inu-backyard/entity"
+	"github.com/team-inu/inu-backyard/infrastructure/fiber/middleware"
+	"github.com/team-inu/inu-backyard/infrastructure/fiber/response"
+	"github.com/team-inu/inu-backyard/internal/validator"
+)
+
+type CurriculumMapController struct {
+	CurriculumMapUseCase entity.CurriculumMapUseCase
+	ReportJobUseCase     entity.ReportJobUseCase
+	Validator            validator.PayloadValidator
+}
+
+func NewCurriculumMapController(validator validator.PayloadValidator, curriculumMapUseCase entity.CurriculumMapUseCase, reportJobUseCase entity.ReportJobUseCase) *CurriculumMapController {
+	return &CurriculumMapController{
+		CurriculumMapUseCase: curriculumMapUseCase,
+		ReportJobUseCase:     reportJobUseCase,
+		Validator:            validator,
+	}
+}
+
+func (c CurriculumMapController) Get(ctx *fiber.Ctx) error {
+	programmeId := ctx.Params("programmeId")
+
+	curriculumMap, err := c.CurriculumMapUseCase.Get(programmeId)
+	if err != nil {
+		return err
+	}
+
+	return response.NewSuccessResponse(ctx, fiber.StatusOK, curriculumMap)
+}
+
+// GetFile queues the export, the file is downloaded through the report job
+func (c CurriculumMapController) GetFile(ctx *fiber.Ctx) error {
+	user := middleware.GetUserFromCtx(ctx)
+	programmeId := ctx.Params("programmeId")
+
+	job, err := c.ReportJobUseCase.Enqueue(*user, entity.ReportTypeCurriculumMap, programmeId, 0, 0)
+	if err != nil {
+		return err
+	}
+
+	return response.NewSuccessResponse(ctx, fiber.StatusAccepted, job)
+}
+
+func (c CurriculumMapController) SetLevel(ctx *fiber.Ctx) error {
+	var payload entity.SetCurriculumMapLevelPayload
+	if ok, err := c.Validator.Validate(&payload, ctx); !ok {
+		return err
+	}
+
+	programmeId := ctx.Params("programmeId")
+
+	err := c.CurriculumMapUseCase.SetLevel(programmeId, payload)
+	if err != nil {
+		return err
+	}
+
+	return response.NewSuccessResponse(ctx, fiber.StatusCreated, nil)
+}
+
+func (c CurriculumMapController) DeleteLevel(ctx *fiber.Ctx) error {
+	programmeId := ctx.Params("programmeId")
+	levelId := ctx.Params("levelId")
+
+	err := c.CurriculumMapUseCase.DeleteLevel(programmeId, levelId)
+	if err != nil {
+		return err
+	}
+
+	return response.NewSuccessResponse(ctx, fiber.StatusOK, nil)
+}
@@ -0,0 +1,73 @@
+package controller
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/team-inu/inu-backyard/entity"
+	"github.com/team-inu/inu-backyard/infrastructure/fiber/middleware"
+	"github.com/team-inu/inu-backyard/infrastructure/fiber/response"
+	"github.com/team-inu/inu-backyard/internal/validator"
+)
+
+type FeedbackController struct {
+	FeedbackUseCase entity.FeedbackUseCase
+	Validator       validator.PayloadValidator
+}
+
+func NewFeedbackController(validator validator.PayloadValidator, feedbackUseCase entity.FeedbackUseCase) *FeedbackController {
+	return &FeedbackController{
+		FeedbackUseCase: feedbackUseCase,
+		Validator:       validator,
+	}
+}
+
+func (c FeedbackController) RequestLink(ctx *fiber.Ctx) error {
+	var payload entity.RequestFeedbackLinkPayload
+	if ok, err := c.Validator.Validate(&payload, ctx); !ok {
+		return err
+	}
+
+	err := c.FeedbackUseCase.RequestLink(payload, ctx.IP())
+	if err != nil {
+		return err
+	}
+
+	return response.NewSuccessResponse(ctx, fiber.StatusOK, nil)
+}
+
+func (c FeedbackController) Create(ctx *fiber.Ctx) error {
+	var payload entity.CreateFeedbackPayload
+	if ok, err := c.Validator.Validate(&payload, ctx); !ok {
+		return err
+	}
+
+	err := c.FeedbackUseCase.Create(payload, ctx.IP())
+	if err != nil {
+		return err
+	}
+
+	return response.NewSuccessResponse(ctx, fiber.StatusCreated, nil)
+}
+
+func (c FeedbackController) GetCourseSummary(ctx *fiber.Ctx) error {
+	user := middleware.GetUserFromCtx(ctx)
+	courseId := ctx.Params("courseId")
+
+	summary, err := c.FeedbackUseCase.GetCourseSummary(*user, courseId)
+	if err != nil {
+		return err
+	}
+
+	return response.NewSuccessResponse(ctx, fiber.StatusOK, summary)
+}
+
+func (c FeedbackController) GetLecturerSummaries(ctx *fiber.Ctx) error {
+	user := middleware.GetUserFromCtx(ctx)
+	semesterId := ctx.Query("semester_id")
+
+	summaries, err := c.FeedbackUseCase.GetLecturerSummaries(*user, semesterId)
+	if err != nil {
+		return err
+	}
+
+	return response.NewSuccessResponse(ctx, fiber.StatusOK, summaries)
+}
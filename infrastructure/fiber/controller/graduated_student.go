@@ -0,0 +1,113 @@
+package controller
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/team-inu/inu-backyard/entity"
+	"github.com/team-inu/inu-backyard/infrastructure/fiber/response"
+	"github.com/team-inu/inu-backyard/internal/validator"
+)
+
+type GraduatedStudentController struct {
+	GraduatedStudentUseCase entity.GraduatedStudentUseCase
+	Validator               validator.PayloadValidator
+}
+
+func NewGraduatedStudentController(validator validator.PayloadValidator, graduatedStudentUseCase entity.GraduatedStudentUseCase) *GraduatedStudentController {
+	return &GraduatedStudentController{
+		GraduatedStudentUseCase: graduatedStudentUseCase,
+		Validator:               validator,
+	}
+}
+
+func (c GraduatedStudentController) GetByParams(ctx *fiber.Ctx) error {
+	programmeId := ctx.Query("programme_id")
+	year := ctx.Query("year")
+
+	graduatedStudents, err := c.GraduatedStudentUseCase.GetByParams(programmeId, year)
+	if err != nil {
+		return err
+	}
+
+	return response.NewSuccessResponse(ctx, fiber.StatusOK, graduatedStudents)
+}
+
+func (c GraduatedStudentController) GetById(ctx *fiber.Ctx) error {
+	graduateId := ctx.Params("graduateId")
+
+	graduatedStudent, err := c.GraduatedStudentUseCase.GetById(graduateId)
+	if err != nil {
+		return err
+	}
+
+	if graduatedStudent == nil {
+		return response.NewSuccessResponse(ctx, fiber.StatusNotFound, graduatedStudent)
+	}
+
+	return response.NewSuccessResponse(ctx, fiber.StatusOK, graduatedStudent)
+}
+
+func (c GraduatedStudentController) Create(ctx *fiber.Ctx) error {
+	var payload entity.CreateGraduatedStudentPayload
+	if ok, err := c.Validator.Validate(&payload, ctx); !ok {
+		return err
+	}
+
+	graduatedStudent, err := c.GraduatedStudentUseCase.Create(payload)
+	if err != nil {
+		return err
+	}
+
+	return response.NewSuccessResponse(ctx, fiber.StatusCreated, graduatedStudent)
+}
+
+func (c GraduatedStudentController) GraduateCohort(ctx *fiber.Ctx) error {
+	var payload entity.GraduateCohortPayload
+	if ok, err := c.Validator.Validate(&payload, ctx); !ok {
+		return err
+	}
+
+	result, err := c.GraduatedStudentUseCase.GraduateCohort(payload)
+	if err != nil {
+		return err
+	}
+
+	return response.NewSuccessResponse(ctx, fiber.StatusCreated, result)
+}
+
+func (c GraduatedStudentController) Update(ctx *fiber.Ctx) error {
+	var payload entity.UpdateGraduatedStudentPayload
+	if ok, err := c.Validator.Validate(&payload, ctx); !ok {
+		return err
+	}
+
+	graduateId := ctx.Params("graduateId")
+
+	err := c.GraduatedStudentUseCase.Update(graduateId, payload)
+	if err != nil {
+		return err
+	}
+
+	return response.NewSuccessResponse(ctx, fiber.StatusOK, nil)
+}
+
+func (c GraduatedStudentController) Delete(ctx *fiber.Ctx) error {
+	graduateId := ctx.Params("graduateId")
+
+	err := c.GraduatedStudentUseCase.Delete(graduateId)
+	if err != nil {
+		return err
+	}
+
+	return response.NewSuccessResponse(ctx, fiber.StatusOK, nil)
+}
+
+func (c GraduatedStudentController) GetAlumniSummary(ctx *fiber.Ctx) error {
+	programmeId := ctx.Params("programmeId")
+
+	summary, err := c.GraduatedStudentUseCase.GetAlumniSummary(programmeId)
+	if err != nil {
+		return err
+	}
+
+	return response.NewSuccessResponse(ctx, fiber.StatusOK, summary)
+}
@@ -0,0 +1,73 @@
+package controller
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/team-inu/inu-backyard/entity"
+	errs "github.com/team-inu/inu-backyard/entity/error"
+	"github.com/team-inu/inu-backyard/infrastructure/fiber/middleware"
+	"github.com/team-inu/inu-backyard/infrastructure/fiber/response"
+	"github.com/team-inu/inu-backyard/internal/validator"
+)
+
+type MailController struct {
+	MailUseCase entity.MailUseCase
+	Validator   validator.PayloadValidator
+}
+
+func NewMailController(validator validator.PayloadValidator, mailUseCase entity.MailUseCase) *MailController {
+	return &MailController{
+		MailUseCase: mailUseCase,
+		Validator:   validator,
+	}
+}
+
+func (c MailController) GetOutbox(ctx *fiber.Ctx) error {
+	err := checkMailPermission(ctx)
+	if err != nil {
+		return err
+	}
+
+	mails, err := c.MailUseCase.GetOutbox(ctx.Query("status"), ctx.Query("pageIndex"), ctx.Query("pageSize"))
+	if err != nil {
+		return err
+	}
+
+	return response.NewSuccessResponse(ctx, fiber.StatusOK, mails)
+}
+
+func (c MailController) GetOutboxById(ctx *fiber.Ctx) error {
+	err := checkMailPermission(ctx)
+	if err != nil {
+		return err
+	}
+
+	mail, err := c.MailUseCase.GetOutboxById(ctx.Params("mailId"))
+	if err != nil {
+		return err
+	}
+
+	return response.NewSuccessResponse(ctx, fiber.StatusOK, mail)
+}
+
+func (c MailController) RetryOutbox(ctx *fiber.Ctx) error {
+	err := checkMailPermission(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = c.MailUseCase.RetryOutbox(ctx.Params("mailId"))
+	if err != nil {
+		return err
+	}
+
+	return response.NewSuccessResponse(ctx, fiber.StatusOK, nil)
+}
+
+func checkMailPermission(ctx *fiber.Ctx) error {
+	user := middleware.GetUserFromCtx(ctx)
+	if !user.IsRoles([]entity.UserRole{entity.UserRoleHeadOfCurriculum}) {
+		return errs.New(errs.ErrMailPermission, "no permission to view mails")
+	}
+
+	return nil
+}
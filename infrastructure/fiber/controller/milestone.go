@@ -0,0 +1,94 @@
+package controller
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/team-inu/inu-backyard/entity"
+	errs "github.com/team-inu/inu-backyard/entity/error"
+	"github.com/team-inu/inu-backyard/infrastructure/fiber/middleware"
+	"github.com/team-inu/inu-backyard/infrastructure/fiber/response"
+	"github.com/team-inu/inu-backyard/internal/validator"
+)
+
+type MilestoneController struct {
+	MilestoneUseCase entity.MilestoneUseCase
+	Validator        validator.PayloadValidator
+}
+
+func NewMilestoneController(validator validator.PayloadValidator, milestoneUseCase entity.MilestoneUseCase) *MilestoneController {
+	return &MilestoneController{
+		MilestoneUseCase: milestoneUseCase,
+		Validator:        validator,
+	}
+}
+
+func (c MilestoneController) GetBySemesterId(ctx *fiber.Ctx) error {
+	milestones, err := c.MilestoneUseCase.GetBySemesterId(ctx.Params("semesterId"))
+	if err != nil {
+		return err
+	}
+
+	return response.NewSuccessResponse(ctx, fiber.StatusOK, milestones)
+}
+
+func (c MilestoneController) Create(ctx *fiber.Ctx) error {
+	err := checkMilestonePermission(ctx)
+	if err != nil {
+		return err
+	}
+
+	var payload entity.CreateMilestonePayload
+
+	if ok, err := c.Validator.Validate(&payload, ctx); !ok {
+		return err
+	}
+
+	err = c.MilestoneUseCase.Create(ctx.Params("semesterId"), payload)
+	if err != nil {
+		return err
+	}
+
+	return response.NewSuccessResponse(ctx, fiber.StatusCreated, nil)
+}
+
+func (c MilestoneController) Update(ctx *fiber.Ctx) error {
+	err := checkMilestonePermission(ctx)
+	if err != nil {
+		return err
+	}
+
+	var payload entity.UpdateMilestonePayload
+
+	if ok, err := c.Validator.Validate(&payload, ctx); !ok {
+		return err
+	}
+
+	err = c.MilestoneUseCase.Update(ctx.Params("milestoneId"), payload)
+	if err != nil {
+		return err
+	}
+
+	return response.NewSuccessResponse(ctx, fiber.StatusOK, nil)
+}
+
+func (c MilestoneController) Delete(ctx *fiber.Ctx) error {
+	err := checkMilestonePermission(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = c.MilestoneUseCase.Delete(ctx.Params("milestoneId"))
+	if err != nil {
+		return err
+	}
+
+	return response.NewSuccessResponse(ctx, fiber.StatusOK, nil)
+}
+
+func checkMilestonePermission(ctx *fiber.Ctx) error {
+	user := middleware.GetUserFromCtx(ctx)
+	if !user.IsRoles([]entity.UserRole{entity.UserRoleHeadOfCurriculum}) {
+		return errs.New(errs.ErrMilestonePermission, "no permission to manage milestones")
+	}
+
+	return nil
+}
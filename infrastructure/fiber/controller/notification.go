@@ -0,0 +1,99 @@
+package controller
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/team-inu/inu-backyard/entity"
+	"github.com/team-inu/inu-backyard/infrastructure/fiber/middleware"
+	"github.com/team-inu/inu-backyard/infrastructure/fiber/response"
+	"github.com/team-inu/inu-backyard/internal/validator"
+)
+
+type NotificationController struct {
+	NotificationUseCase entity.NotificationUseCase
+	Validator           validator.PayloadValidator
+}
+
+func NewNotificationController(validator validator.PayloadValidator, notificationUseCase entity.NotificationUseCase) *NotificationController {
+	return &NotificationController{
+		NotificationUseCase: notificationUseCase,
+		Validator:           validator,
+	}
+}
+
+func (c NotificationController) GetAll(ctx *fiber.Ctx) error {
+	user := middleware.GetUserFromCtx(ctx)
+
+	notifications, err := c.NotificationUseCase.GetByUserId(user.Id, ctx.QueryBool("unread"), ctx.Query("pageIndex"), ctx.Query("pageSize"))
+	if err != nil {
+		return err
+	}
+
+	return response.NewSuccessResponse(ctx, fiber.StatusOK, notifications)
+}
+
+func (c NotificationController) CountUnread(ctx *fiber.Ctx) error {
+	user := middleware.GetUserFromCtx(ctx)
+
+	count, err := c.NotificationUseCase.CountUnread(user.Id)
+	if err != nil {
+		return err
+	}
+
+	return response.NewSuccessResponse(ctx, fiber.StatusOK, fiber.Map{"count": count})
+}
+
+func (c NotificationController) MarkRead(ctx *fiber.Ctx) error {
+	var payload entity.MarkNotificationsReadPayload
+
+	if ok, err := c.Validator.Validate(&payload, ctx); !ok {
+		return err
+	}
+
+	user := middleware.GetUserFromCtx(ctx)
+
+	err := c.NotificationUseCase.MarkRead(user.Id, payload.Ids)
+	if err != nil {
+		return err
+	}
+
+	return response.NewSuccessResponse(ctx, fiber.StatusOK, nil)
+}
+
+func (c NotificationController) MarkAllRead(ctx *fiber.Ctx) error {
+	user := middleware.GetUserFromCtx(ctx)
+
+	err := c.NotificationUseCase.MarkAllRead(user.Id)
+	if err != nil {
+		return err
+	}
+
+	return response.NewSuccessResponse(ctx, fiber.StatusOK, nil)
+}
+
+func (c NotificationController) GetPreferences(ctx *fiber.Ctx) error {
+	user := middleware.GetUserFromCtx(ctx)
+
+	preferences, err := c.NotificationUseCase.GetPreferences(user.Id)
+	if err != nil {
+		return err
+	}
+
+	return response.NewSuccessResponse(ctx, fiber.StatusOK, preferences)
+}
+
+func (c NotificationController) UpdatePreferences(ctx *fiber.Ctx) error {
+	var payload entity.UpdateNotificationPreferencesPayload
+
+	if ok, err := c.Validator.Validate(&payload, ctx); !ok {
+		return err
+	}
+
+	user := middleware.GetUserFromCtx(ctx)
+
+	err := c.NotificationUseCase.UpdatePreferences(user.Id, payload)
+	if err != nil {
+		return err
+	}
+
+	return response.NewSuccessResponse(ctx, fiber.StatusOK, nil)
+}
@@ -0,0 +1,152 @@
+package controller
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/team-inu/inu-backyard/entity"
+	"github.com/team-inu/inu-backyard/infrastructure/fiber/response"
+	"github.com/team-inu/inu-backyard/internal/validator"
+)
+
+type ProgramEducationalObjectiveController struct {
+	ProgramEducationalObjectiveUseCase entity.ProgramEducationalObjectiveUseCase
+	Validator                          validator.PayloadValidator
+}
+
+func NewProgramEducationalObjectiveController(validator validator.PayloadValidator, programEducationalObjectiveUseCase entity.ProgramEducationalObjectiveUseCase) *ProgramEducationalObjectiveController {
+	return &ProgramEducationalObjectiveController{
+		ProgramEducationalObjectiveUseCase: programEducationalObjectiveUseCase,
+		Validator:                          validator,
+	}
+}
+
+func (c ProgramEducationalObjectiveController) GetByProgrammeId(ctx *fiber.Ctx) error {
+	programmeId := ctx.Params("programmeId")
+
+	peos, err := c.ProgramEducationalObjectiveUseCase.GetByProgrammeId(programmeId)
+	if err != nil {
+		return err
+	}
+
+	return response.NewSuccessResponse(ctx, fiber.StatusOK, peos)
+}
+
+func (c ProgramEducationalObjectiveController) GetById(ctx *fiber.Ctx) error {
+	programmeId := ctx.Params("programmeId")
+	peoId := ctx.Params("peoId")
+
+	peo, err := c.ProgramEducationalObjectiveUseCase.GetById(programmeId, peoId)
+	if err != nil {
+		return err
+	}
+
+	if peo == nil {
+		return response.NewSuccessResponse(ctx, fiber.StatusNotFound, peo)
+	}
+
+	return response.NewSuccessResponse(ctx, fiber.StatusOK, peo)
+}
+
+func (c ProgramEducationalObjectiveController) Create(ctx *fiber.Ctx) error {
+	var payload entity.CreateProgramEducationalObjectivePayload
+	if ok, err := c.Validator.Validate(&payload, ctx); !ok {
+		return err
+	}
+
+	programmeId := ctx.Params("programmeId")
+
+	err := c.ProgramEducationalObjectiveUseCase.Create(programmeId, payload)
+	if err != nil {
+		return err
+	}
+
+	return response.NewSuccessResponse(ctx, fiber.StatusCreated, nil)
+}
+
+func (c ProgramEducationalObjectiveController) Update(ctx *fiber.Ctx) error {
+	var payload entity.UpdateProgramEducationalObjectivePayload
+	if ok, err := c.Validator.Validate(&payload, ctx); !ok {
+		return err
+	}
+
+	programmeId := ctx.Params("programmeId")
+	peoId := ctx.Params("peoId")
+
+	err := c.ProgramEducationalObjectiveUseCase.Update(programmeId, peoId, payload)
+	if err != nil {
+		return err
+	}
+
+	return response.NewSuccessResponse(ctx, fiber.StatusOK, nil)
+}
+
+func (c ProgramEducationalObjectiveController) Delete(ctx *fiber.Ctx) error {
+	programmeId := ctx.Params("programmeId")
+	peoId := ctx.Params("peoId")
+
+	err := c.ProgramEducationalObjectiveUseCase.Delete(programmeId, peoId)
+	if err != nil {
+		return err
+	}
+
+	return response.NewSuccessResponse(ctx, fiber.StatusOK, nil)
+}
+
+func (c ProgramEducationalObjectiveController) CreateLinkProgramLearningOutcome(ctx *fiber.Ctx) error {
+	var payload entity.CreateLinkPEOProgramLearningOutcomePayload
+	if ok, err := c.Validator.Validate(&payload, ctx); !ok {
+		return err
+	}
+
+	programmeId := ctx.Params("programmeId")
+	peoId := ctx.Params("peoId")
+
+	err := c.ProgramEducationalObjectiveUseCase.CreateLinkProgramLearningOutcome(programmeId, peoId, payload.ProgramLearningOutcomeIds)
+	if err != nil {
+		return err
+	}
+
+	return response.NewSuccessResponse(ctx, fiber.StatusCreated, nil)
+}
+
+func (c ProgramEducationalObjectiveController) CreateLinkStudentOutcome(ctx *fiber.Ctx) error {
+	var payload entity.CreateLinkPEOStudentOutcomePayload
+	if ok, err := c.Validator.Validate(&payload, ctx); !ok {
+		return err
+	}
+
+	programmeId := ctx.Params("programmeId")
+	peoId := ctx.Params("peoId")
+
+	err := c.ProgramEducationalObjectiveUseCase.CreateLinkStudentOutcome(programmeId, peoId, payload.StudentOutcomeIds)
+	if err != nil {
+		return err
+	}
+
+	return response.NewSuccessResponse(ctx, fiber.StatusCreated, nil)
+}
+
+func (c ProgramEducationalObjectiveController) DeleteLinkProgramLearningOutcome(ctx *fiber.Ctx) error {
+	programmeId := ctx.Params("programmeId")
+	peoId := ctx.Params("peoId")
+	ploId := ctx.Params("ploId")
+
+	err := c.ProgramEducationalObjectiveUseCase.DeleteLinkProgramLearningOutcome(programmeId, peoId, ploId)
+	if err != nil {
+		return err
+	}
+
+	return response.NewSuccessResponse(ctx, fiber.StatusOK, nil)
+}
+
+func (c ProgramEducationalObjectiveController) DeleteLinkStudentOutcome(ctx *fiber.Ctx) error {
+	programmeId := ctx.Params("programmeId")
+	peoId := ctx.Params("peoId")
+	soId := ctx.Params("soId")
+
+	err := c.ProgramEducationalObjectiveUseCase.DeleteLinkStudentOutcome(programmeId, peoId, soId)
+	if err != nil {
+		return err
+	}
+
+	return response.NewSuccessResponse(ctx, fiber.StatusOK, nil)
+}
@@ -0,0 +1,110 @@
+package controller
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/team-inu/inu-backyard/entity"
+	"github.com/team-inu/inu-backyard/infrastructure/fiber/middleware"
+	"github.com/team-inu/inu-backyard/infrastructure/fiber/response"
+	"github.com/team-inu/inu-backyard/internal/validator"
+)
+
+type ProgramImprovementController struct {
+	ProgramImprovementUseCase entity.ProgramImprovementUseCase
+	ReportJobUseCase          entity.ReportJobUseCase
+	Validator                 validator.PayloadValidator
+}
+
+func NewProgramImprovementController(validator validator.PayloadValidator, programImprovementUseCase entity.ProgramImprovementUseCase, reportJobUseCase entity.ReportJobUseCase) *ProgramImprovementController {
+	return &ProgramImprovementController{
+		ProgramImprovementUseCase: programImprovementUseCase,
+		ReportJobUseCase:          reportJobUseCase,
+		Validator:                 validator,
+	}
+}
+
+func (c ProgramImprovementController) GetByProgrammeId(ctx *fiber.Ctx) error {
+	programmeId := ctx.Params("programmeId")
+	status := entity.ProgramImprovementStatus(ctx.Query("status"))
+
+	improvements, err := c.ProgramImprovementUseCase.GetByProgrammeId(programmeId, status)
+	if err != nil {
+		return err
+	}
+
+	return response.NewSuccessResponse(ctx, fiber.StatusOK, improvements)
+}
+
+func (c ProgramImprovementController) GetById(ctx *fiber.Ctx) error {
+	programmeId := ctx.Params("programmeId")
+	improvementId := ctx.Params("improvementId")
+
+	improvement, err := c.ProgramImprovementUseCase.GetById(programmeId, improvementId)
+	if err != nil {
+		return err
+	}
+
+	if improvement == nil {
+		return response.NewSuccessResponse(ctx, fiber.StatusNotFound, improvement)
+	}
+
+	return response.NewSuccessResponse(ctx, fiber.StatusOK, improvement)
+}
+
+func (c ProgramImprovementController) Create(ctx *fiber.Ctx) error {
+	var payload entity.CreateProgramImprovementRequestPayload
+	if ok, err := c.Validator.Validate(&payload, ctx); !ok {
+		return err
+	}
+
+	user := middleware.GetUserFromCtx(ctx)
+	programmeId := ctx.Params("programmeId")
+
+	err := c.ProgramImprovementUseCase.Create(*user, programmeId, payload)
+	if err != nil {
+		return err
+	}
+
+	return response.NewSuccessResponse(ctx, fiber.StatusCreated, nil)
+}
+
+func (c ProgramImprovementController) Update(ctx *fiber.Ctx) error {
+	var payload entity.UpdateProgramImprovementRequestPayload
+	if ok, err := c.Validator.Validate(&payload, ctx); !ok {
+		return err
+	}
+
+	programmeId := ctx.Params("programmeId")
+	improvementId := ctx.Params("improvementId")
+
+	err := c.ProgramImprovementUseCase.Update(programmeId, improvementId, payload)
+	if err != nil {
+		return err
+	}
+
+	return response.NewSuccessResponse(ctx, fiber.StatusOK, nil)
+}
+
+func (c ProgramImprovementController) Delete(ctx *fiber.Ctx) error {
+	programmeId := ctx.Params("programmeId")
+	improvementId := ctx.Params("improvementId")
+
+	err := c.ProgramImprovementUseCase.Delete(programmeId, improvementId)
+	if err != nil {
+		return err
+	}
+
+	return response.NewSuccessResponse(ctx, fiber.StatusOK, nil)
+}
+
+// GetLogFile queues the export, the file is downloaded through the report job
+func (c ProgramImprovementController) GetLogFile(ctx *fiber.Ctx) error {
+	user := middleware.GetUserFromCtx(ctx)
+	programmeId := ctx.Params("programmeId")
+
+	job, err := c.ReportJobUseCase.Enqueue(*user, entity.ReportTypeProgramImprovementLog, programmeId, 0, 0)
+	if err != nil {
+		return err
+	}
+
+	return response.NewSuccessResponse(ctx, fiber.StatusAccepted, job)
+}
@@ -260,3 +260,23 @@ func (c ProgrammeController) GetAllCourseLinkedSO(ctx *fiber.Ctx) error {
 
 	return response.NewSuccessResponse(ctx, fiber.StatusOK, allCourseLinkedSO)
 }
+
+func (c ProgrammeController) GetStakeholderFeedback(ctx *fiber.Ctx) error {
+	programmeId := ctx.Params("programmeId")
+
+	programme, err := c.ProgrammeUseCase.GetById(programmeId)
+	if err != nil {
+		return err
+	}
+
+	if programme == nil {
+		return response.NewSuccessResponse(ctx, fiber.StatusNotFound, programme)
+	}
+
+	feedback, err := c.ProgrammeUseCase.GetStakeholderFeedback(programmeId)
+	if err != nil {
+		return err
+	}
+
+	return response.NewSuccessResponse(ctx, fiber.StatusOK, feedback)
+}
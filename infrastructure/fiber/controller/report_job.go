@@ -0,0 +1,48 @@
+package controller
+
+import (
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/team-inu/inu-backyard/entity"
+	"github.com/team-inu/inu-backyard/infrastructure/fiber/middleware"
+	"github.com/team-inu/inu-backyard/infrastructure/fiber/response"
+	"github.com/team-inu/inu-backyard/internal/validator"
+)
+
+type ReportJobController struct {
+	ReportJobUseCase entity.ReportJobUseCase
+	Validator        validator.PayloadValidator
+}
+
+func NewReportJobController(validator validator.PayloadValidator, reportJobUseCase entity.ReportJobUseCase) *ReportJobController {
+	return &ReportJobController{
+		ReportJobUseCase: reportJobUseCase,
+		Validator:        validator,
+	}
+}
+
+func (c ReportJobController) GetById(ctx *fiber.Ctx) error {
+	user := middleware.GetUserFromCtx(ctx)
+
+	job, err := c.ReportJobUseCase.GetById(*user, ctx.Params("jobId"))
+	if err != nil {
+		return err
+	}
+
+	return response.NewSuccessResponse(ctx, fiber.StatusOK, job)
+}
+
+// Download needs no session, the signed link is the credential
+func (c ReportJobController) Download(ctx *fiber.Ctx) error {
+	file, content, err := c.ReportJobUseCase.OpenDownload(ctx.Params("jobId"), ctx.Query("expires"), ctx.Query("signature"))
+	if err != nil {
+		return err
+	}
+
+	ctx.Set("Content-Type", file.ContentType)
+	ctx.Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, file.Name))
+
+	// the content is closed once it is sent
+	return ctx.SendStream(content, int(file.Size))
+}
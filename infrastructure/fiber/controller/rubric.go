@@ -0,0 +1,137 @@
+package controller
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/team-inu/inu-backyard/entity"
+	"github.com/team-inu/inu-backyard/infrastructure/fiber/middleware"
+	"github.com/team-inu/inu-backyard/infrastructure/fiber/response"
+	"github.com/team-inu/inu-backyard/internal/validator"
+)
+
+type RubricController struct {
+	RubricUseCase entity.RubricUseCase
+	Validator     validator.PayloadValidator
+}
+
+func NewRubricController(validator validator.PayloadValidator, rubricUseCase entity.RubricUseCase) *RubricController {
+	return &RubricController{
+		RubricUseCase: rubricUseCase,
+		Validator:     validator,
+	}
+}
+
+func (c RubricController) GetByProgrammeId(ctx *fiber.Ctx) error {
+	programmeId := ctx.Params("programmeId")
+
+	rubrics, err := c.RubricUseCase.GetByProgrammeId(programmeId)
+	if err != nil {
+		return err
+	}
+
+	return response.NewSuccessResponse(ctx, fiber.StatusOK, rubrics)
+}
+
+func (c RubricController) GetById(ctx *fiber.Ctx) error {
+	programmeId := ctx.Params("programmeId")
+	rubricId := ctx.Params("rubricId")
+
+	rubric, err := c.RubricUseCase.GetById(programmeId, rubricId)
+	if err != nil {
+		return err
+	}
+
+	if rubric == nil {
+		return response.NewSuccessResponse(ctx, fiber.StatusNotFound, rubric)
+	}
+
+	return response.NewSuccessResponse(ctx, fiber.StatusOK, rubric)
+}
+
+func (c RubricController) Create(ctx *fiber.Ctx) error {
+	var payload entity.CreateRubricPayload
+	if ok, err := c.Validator.Validate(&payload, ctx); !ok {
+		return err
+	}
+
+	programmeId := ctx.Params("programmeId")
+
+	err := c.RubricUseCase.Create(programmeId, payload)
+	if err != nil {
+		return err
+	}
+
+	return response.NewSuccessResponse(ctx, fiber.StatusCreated, nil)
+}
+
+func (c RubricController) Update(ctx *fiber.Ctx) error {
+	var payload entity.UpdateRubricPayload
+	if ok, err := c.Validator.Validate(&payload, ctx); !ok {
+		return err
+	}
+
+	programmeId := ctx.Params("programmeId")
+	rubricId := ctx.Params("rubricId")
+
+	err := c.RubricUseCase.Update(programmeId, rubricId, payload)
+	if err != nil {
+		return err
+	}
+
+	return response.NewSuccessResponse(ctx, fiber.StatusOK, nil)
+}
+
+func (c RubricController) Delete(ctx *fiber.Ctx) error {
+	programmeId := ctx.Params("programmeId")
+	rubricId := ctx.Params("rubricId")
+
+	err := c.RubricUseCase.Delete(programmeId, rubricId)
+	if err != nil {
+		return err
+	}
+
+	return response.NewSuccessResponse(ctx, fiber.StatusOK, nil)
+}
+
+func (c RubricController) Attach(ctx *fiber.Ctx) error {
+	var payload entity.AttachRubricPayload
+	if ok, err := c.Validator.Validate(&payload, ctx); !ok {
+		return err
+	}
+
+	assignmentId := ctx.Params("assignmentId")
+
+	err := c.RubricUseCase.Attach(assignmentId, payload)
+	if err != nil {
+		return err
+	}
+
+	return response.NewSuccessResponse(ctx, fiber.StatusCreated, nil)
+}
+
+func (c RubricController) Detach(ctx *fiber.Ctx) error {
+	assignmentId := ctx.Params("assignmentId")
+
+	err := c.RubricUseCase.Detach(assignmentId)
+	if err != nil {
+		return err
+	}
+
+	return response.NewSuccessResponse(ctx, fiber.StatusOK, nil)
+}
+
+func (c RubricController) SaveGrades(ctx *fiber.Ctx) error {
+	var payload entity.SaveRubricGradesPayload
+	if ok, err := c.Validator.Validate(&payload, ctx); !ok {
+		return err
+	}
+
+	user := middleware.GetUserFromCtx(ctx)
+	assignmentId := ctx.Params("assignmentId")
+
+	err := c.RubricUseCase.SaveGrades(*user, assignmentId, payload.StudentGrades)
+	if err != nil {
+		return err
+	}
+
+	return response.NewSuccessResponse(ctx, fiber.StatusOK, nil)
+}
@@ -0,0 +1,81 @@
+package controller
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/team-inu/inu-backyard/entity"
+	errs "github.com/team-inu/inu-backyard/entity/error"
+	"github.com/team-inu/inu-backyard/infrastructure/fiber/middleware"
+	"github.com/team-inu/inu-backyard/infrastructure/fiber/response"
+	"github.com/team-inu/inu-backyard/internal/validator"
+)
+
+type SessionController struct {
+	SessionUseCase entity.SessionUseCase
+	Validator      validator.PayloadValidator
+}
+
+func NewSessionController(validator validator.PayloadValidator, sessionUseCase entity.SessionUseCase) *SessionController {
+	return &SessionController{
+		SessionUseCase: sessionUseCase,
+		Validator:      validator,
+	}
+}
+
+func (c SessionController) GetActive(ctx *fiber.Ctx) error {
+	user := middleware.GetUserFromCtx(ctx)
+	session := middleware.GetSessionFromCtx(ctx)
+
+	sessions, err := c.SessionUseCase.GetActiveByUserId(user.Id, session.Id)
+	if err != nil {
+		return err
+	}
+
+	return response.NewSuccessResponse(ctx, fiber.StatusOK, sessions)
+}
+
+func (c SessionController) Revoke(ctx *fiber.Ctx) error {
+	user := middleware.GetUserFromCtx(ctx)
+	session := middleware.GetSessionFromCtx(ctx)
+
+	sessionId := ctx.Params("sessionId")
+
+	cookie, err := c.SessionUseCase.Revoke(user.Id, sessionId)
+	if err != nil {
+		return err
+	}
+
+	// revoking the session of this request is the same as signing out
+	if sessionId == session.Id {
+		ctx.Cookie(cookie)
+	}
+
+	return response.NewSuccessResponse(ctx, fiber.StatusOK, nil)
+}
+
+func (c SessionController) RevokeOthers(ctx *fiber.Ctx) error {
+	user := middleware.GetUserFromCtx(ctx)
+	session := middleware.GetSessionFromCtx(ctx)
+
+	err := c.SessionUseCase.DestroyOthers(user.Id, session.Id)
+	if err != nil {
+		return err
+	}
+
+	return response.NewSuccessResponse(ctx, fiber.StatusOK, nil)
+}
+
+func (c SessionController) ForceSignOut(ctx *fiber.Ctx) error {
+	user := middleware.GetUserFromCtx(ctx)
+	if !user.IsRoles([]entity.UserRole{entity.UserRoleHeadOfCurriculum}) {
+		return errs.New(errs.ErrSessionPermission, "no permission to sign out other users")
+	}
+
+	targetUserId := ctx.Params("userId")
+
+	_, err := c.SessionUseCase.DestroyByUserId(targetUserId)
+	if err != nil {
+		return errs.New(errs.ErrDeleteSession, "cannot sign out user id %s", targetUserId, err)
+	}
+
+	return response.NewSuccessResponse(ctx, fiber.StatusOK, nil)
+}
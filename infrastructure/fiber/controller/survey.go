@@ -183,3 +183,70 @@ func (c SurveyController) GetSurveysWithCourseAndOutcomes(ctx *fiber.Ctx) error
 
 	return response.NewSuccessResponse(ctx, fiber.StatusOK, surveys)
 }
+
+func (c SurveyController) GetByProgrammeId(ctx *fiber.Ctx) error {
+	programmeId := ctx.Params("programmeId")
+
+	surveys, err := c.SurveyUseCase.GetByProgrammeId(programmeId)
+	if err != nil {
+		return err
+	}
+
+	return response.NewSuccessResponse(ctx, fiber.StatusOK, surveys)
+}
+
+// CreateInvitations sends one-time survey links to alumni or employers
+func (c SurveyController) CreateInvitations(ctx *fiber.Ctx) error {
+	var payload entity.CreateSurveyInvitationPayload
+	if ok, err := c.Validator.Validate(&payload, ctx); !ok {
+		return err
+	}
+
+	surveyId := ctx.Params("surveyId")
+
+	result, err := c.SurveyUseCase.CreateInvitations(surveyId, payload)
+	if err != nil {
+		return err
+	}
+
+	return response.NewSuccessResponse(ctx, fiber.StatusCreated, result)
+}
+
+func (c SurveyController) GetInvitations(ctx *fiber.Ctx) error {
+	surveyId := ctx.Params("surveyId")
+
+	invitations, err := c.SurveyUseCase.GetInvitationsBySurveyId(surveyId)
+	if err != nil {
+		return err
+	}
+
+	return response.NewSuccessResponse(ctx, fiber.StatusOK, invitations)
+}
+
+// GetFormByToken returns the questions of the survey an invitation token belongs to
+func (c SurveyController) GetFormByToken(ctx *fiber.Ctx) error {
+	token := ctx.Params("token")
+
+	form, err := c.SurveyUseCase.GetFormByToken(token)
+	if err != nil {
+		return err
+	}
+
+	return response.NewSuccessResponse(ctx, fiber.StatusOK, form)
+}
+
+func (c SurveyController) SubmitResponse(ctx *fiber.Ctx) error {
+	var payload entity.SubmitSurveyResponsePayload
+	if ok, err := c.Validator.Validate(&payload, ctx); !ok {
+		return err
+	}
+
+	token := ctx.Params("token")
+
+	err := c.SurveyUseCase.SubmitResponse(token, payload)
+	if err != nil {
+		return err
+	}
+
+	return response.NewSuccessResponse(ctx, fiber.StatusCreated, nil)
+}
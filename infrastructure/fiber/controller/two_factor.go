@@ -0,0 +1,142 @@
+package controller
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/team-inu/inu-backyard/entity"
+	errs "github.com/team-inu/inu-backyard/entity/error"
+	"github.com/team-inu/inu-backyard/infrastructure/fiber/middleware"
+	"github.com/team-inu/inu-backyard/infrastructure/fiber/response"
+	"github.com/team-inu/inu-backyard/internal/validator"
+)
+
+type TwoFactorController struct {
+	TwoFactorUseCase entity.TwoFactorUseCase
+	Validator        validator.PayloadValidator
+}
+
+func NewTwoFactorController(validator validator.PayloadValidator, twoFactorUseCase entity.TwoFactorUseCase) *TwoFactorController {
+	return &TwoFactorController{
+		TwoFactorUseCase: twoFactorUseCase,
+		Validator:        validator,
+	}
+}
+
+func (c TwoFactorController) GetStatus(ctx *fiber.Ctx) error {
+	user := middleware.GetUserFromCtx(ctx)
+
+	status, err := c.TwoFactorUseCase.GetStatus(*user)
+	if err != nil {
+		return err
+	}
+
+	return response.NewSuccessResponse(ctx, fiber.StatusOK, status)
+}
+
+func (c TwoFactorController) BeginEnrollment(ctx *fiber.Ctx) error {
+	user := middleware.GetUserFromCtx(ctx)
+
+	enrollment, err := c.TwoFactorUseCase.BeginEnrollment(*user)
+	if err != nil {
+		return err
+	}
+
+	return response.NewSuccessResponse(ctx, fiber.StatusOK, enrollment)
+}
+
+func (c TwoFactorController) ConfirmEnrollment(ctx *fiber.Ctx) error {
+	var payload entity.TwoFactorCodePayload
+	if ok, err := c.Validator.Validate(&payload, ctx); !ok {
+		return err
+	}
+
+	user := middleware.GetUserFromCtx(ctx)
+
+	recoveryCodes, err := c.TwoFactorUseCase.ConfirmEnrollment(*user, payload.Code)
+	if err != nil {
+		return err
+	}
+
+	return response.NewSuccessResponse(ctx, fiber.StatusOK, fiber.Map{
+		"recovery_codes": recoveryCodes,
+	})
+}
+
+func (c TwoFactorController) RegenerateRecoveryCodes(ctx *fiber.Ctx) error {
+	var payload entity.TwoFactorCodePayload
+	if ok, err := c.Validator.Validate(&payload, ctx); !ok {
+		return err
+	}
+
+	user := middleware.GetUserFromCtx(ctx)
+
+	recoveryCodes, err := c.TwoFactorUseCase.RegenerateRecoveryCodes(*user, payload.Code)
+	if err != nil {
+		return err
+	}
+
+	return response.NewSuccessResponse(ctx, fiber.StatusOK, fiber.Map{
+		"recovery_codes": recoveryCodes,
+	})
+}
+
+func (c TwoFactorController) Disable(ctx *fiber.Ctx) error {
+	var payload entity.TwoFactorCodePayload
+	if ok, err := c.Validator.Validate(&payload, ctx); !ok {
+		return err
+	}
+
+	user := middleware.GetUserFromCtx(ctx)
+
+	err := c.TwoFactorUseCase.Disable(*user, payload.Code)
+	if err != nil {
+		return err
+	}
+
+	return response.NewSuccessResponse(ctx, fiber.StatusOK, nil)
+}
+
+func (c TwoFactorController) Reset(ctx *fiber.Ctx) error {
+	user := middleware.GetUserFromCtx(ctx)
+	if !user.IsRoles([]entity.UserRole{entity.UserRoleHeadOfCurriculum}) {
+		return errs.New(errs.ErrTwoFactorPermission, "no permission to reset two-factor")
+	}
+
+	targetUserId := ctx.Params("userId")
+
+	err := c.TwoFactorUseCase.Reset(targetUserId)
+	if err != nil {
+		return err
+	}
+
+	return response.NewSuccessResponse(ctx, fiber.StatusOK, nil)
+}
+
+func (c TwoFactorController) GetPolicy(ctx *fiber.Ctx) error {
+	roles, err := c.TwoFactorUseCase.GetPolicy()
+	if err != nil {
+		return err
+	}
+
+	return response.NewSuccessResponse(ctx, fiber.StatusOK, fiber.Map{
+		"roles": roles,
+	})
+}
+
+func (c TwoFactorController) UpdatePolicy(ctx *fiber.Ctx) error {
+	var payload entity.UpdateTwoFactorPolicyPayload
+	if ok, err := c.Validator.Validate(&payload, ctx); !ok {
+		return err
+	}
+
+	user := middleware.GetUserFromCtx(ctx)
+	if !user.IsRoles([]entity.UserRole{entity.UserRoleHeadOfCurriculum}) {
+		return errs.New(errs.ErrTwoFactorPermission, "no permission to update two-factor policy")
+	}
+
+	err := c.TwoFactorUseCase.UpdatePolicy(payload.Roles)
+	if err != nil {
+		return err
+	}
+
+	return response.NewSuccessResponse(ctx, fiber.StatusOK, nil)
+}
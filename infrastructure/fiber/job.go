@@ -0,0 +1,168 @@
+package fiber
+
+import (
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	defaultCleanupInterval    = time.Hour
+	defaultMailPollInterval   = 10 * time.Second
+	defaultSchedulerInterval  = 5 * time.Minute
+	defaultReportWorkers      = 2
+	defaultReportPollInterval = 2 * time.Second
+
+	defaultAttainmentPollInterval = 2 * time.Second
+)
+
+// startCleanup deletes expired sessions, password reset tokens, login throttles, old mails, read notifications and reports in the background
+func (f *fiberServer) startCleanup() {
+	interval := time.Duration(f.config.Client.Auth.Session.CleanupInterval) * time.Second
+	if interval <= 0 {
+		interval = defaultCleanupInterval
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for ; true; <-ticker.C {
+			deleted, err := f.sessionUseCase.DeleteExpired()
+			if err != nil {
+				f.logger.Error("Cannot delete expired sessions", zap.Error(err))
+			} else if deleted > 0 {
+				f.logger.Info("Deleted expired sessions", zap.Int64("count", deleted))
+			}
+
+			deleted, err = f.mailUseCase.DeleteExpiredTokens()
+			if err != nil {
+				f.logger.Error("Cannot delete expired reset password tokens", zap.Error(err))
+			} else if deleted > 0 {
+				f.logger.Info("Deleted expired reset password tokens", zap.Int64("count", deleted))
+			}
+
+			deleted, err = f.loginThrottleUseCase.DeleteStale()
+			if err != nil {
+				f.logger.Error("Cannot delete stale login throttles", zap.Error(err))
+			} else if deleted > 0 {
+				f.logger.Info("Deleted stale login throttles", zap.Int64("count", deleted))
+			}
+
+			deleted, err = f.mailUseCase.DeleteOldOutbox()
+			if err != nil {
+				f.logger.Error("Cannot delete old mails", zap.Error(err))
+			} else if deleted > 0 {
+				f.logger.Info("Deleted old mails", zap.Int64("count", deleted))
+			}
+
+			deleted, err = f.notificationUseCase.DeleteOld()
+			if err != nil {
+				f.logger.Error("Cannot delete old notifications", zap.Error(err))
+			} else if deleted > 0 {
+				f.logger.Info("Deleted old notifications", zap.Int64("count", deleted))
+			}
+
+			deleted, err = f.reportJobUseCase.DeleteExpired()
+			if err != nil {
+				f.logger.Error("Cannot delete expired reports", zap.Error(err))
+			} else if deleted > 0 {
+				f.logger.Info("Deleted expired reports", zap.Int64("count", deleted))
+			}
+		}
+	}()
+}
+
+// startMailWorker sends queued mails in the background, failed mails are retried with backoff
+func (f *fiberServer) startMailWorker() {
+	interval := time.Duration(f.config.Mail.PollInterval) * time.Second
+	if interval <= 0 {
+		interval = defaultMailPollInterval
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for ; true; <-ticker.C {
+			sent, err := f.mailUseCase.ProcessOutbox()
+			if err != nil {
+				f.logger.Error("Cannot process mail outbox", zap.Error(err))
+			} else if sent > 0 {
+				f.logger.Info("Sent queued mails", zap.Int("count", sent))
+			}
+		}
+	}()
+}
+
+// startScheduler sends deadline reminders in the background, only the replica holding the scheduler lease runs them
+func (f *fiberServer) startScheduler() {
+	interval := time.Duration(f.config.Scheduler.Interval) * time.Second
+	if interval <= 0 {
+		interval = defaultSchedulerInterval
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for ; true; <-ticker.C {
+			reminded, err := f.schedulerUseCase.Run()
+			if err != nil {
+				f.logger.Error("Cannot run scheduler", zap.Error(err))
+			}
+			if reminded > 0 {
+				f.logger.Info("Sent deadline reminders", zap.Int("milestones", reminded))
+			}
+		}
+	}()
+}
+
+// startAttainmentWorker recomputes stale outcome attainments in the background, one course at a time
+func (f *fiberServer) startAttainmentWorker() {
+	interval := time.Duration(f.config.Attainment.PollInterval) * time.Second
+	if interval <= 0 {
+		interval = defaultAttainmentPollInterval
+	}
+
+	go func() {
+		for {
+			isRefreshed, err := f.attainmentUseCase.ProcessNext()
+			if err != nil {
+				f.logger.Error("Cannot refresh attainments", zap.Error(err))
+			}
+
+			if !isRefreshed || err != nil {
+				time.Sleep(interval)
+			}
+		}
+	}()
+}
+
+// startReportWorkers generates queued reports in the background, an idle worker waits before looking again
+func (f *fiberServer) startReportWorkers() {
+	workers := f.config.Report.Workers
+	if workers <= 0 {
+		workers = defaultReportWorkers
+	}
+
+	interval := time.Duration(f.config.Report.PollInterval) * time.Second
+	if interval <= 0 {
+		interval = defaultReportPollInterval
+	}
+
+	for i := 0; i < workers; i++ {
+		go func() {
+			for {
+				isProcessed, err := f.reportJobUseCase.ProcessNext()
+				if err != nil {
+					f.logger.Error("Cannot process report job", zap.Error(err))
+				}
+
+				if !isProcessed || err != nil {
+					time.Sleep(interval)
+				}
+			}
+		}()
+	}
+}
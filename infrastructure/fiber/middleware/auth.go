@@ -1,11 +1,21 @@
 package middleware
 
 import (
+	"strings"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/team-inu/inu-backyard/entity"
+	errs "github.com/team-inu/inu-backyard/entity/error"
 	"github.com/team-inu/inu-backyard/internal/validator"
 )
 
+// paths still available to a user who must change the password first
+var passwordChangePaths = map[string]bool{
+	"/auth/me":       true,
+	"/auth/logout":   true,
+	"/auth/password": true,
+}
+
 func NewAuthMiddleware(
 	validator validator.PayloadValidator,
 	authUseCase entity.AuthUseCase,
@@ -16,12 +26,21 @@ func NewAuthMiddleware(
 			return err
 		}
 
-		user, err := authUseCase.Authenticate(sid)
+		authentication, err := authUseCase.Authenticate(sid)
 		if err != nil {
 			return err
 		}
 
-		ctx.Locals("user", user)
+		if authentication.Cookie != nil {
+			ctx.Cookie(authentication.Cookie)
+		}
+
+		if authentication.PasswordChangeRequired && !passwordChangePaths[strings.TrimSuffix(ctx.Path(), "/")] {
+			return errs.New(errs.ErrPasswordChangeRequired, "password of user id %s must be changed", authentication.Session.UserId)
+		}
+
+		ctx.Locals("user", authentication.User)
+		ctx.Locals("session", authentication.Session)
 
 		return ctx.Next()
 	}
@@ -31,3 +50,8 @@ func GetUserFromCtx(ctx *fiber.Ctx) *entity.User {
 	user, _ := ctx.Locals("user").(*entity.User)
 	return user
 }
+
+func GetSessionFromCtx(ctx *fiber.Ctx) *entity.Session {
+	session, _ := ctx.Locals("session").(*entity.Session)
+	return session
+}
@@ -51,4 +51,144 @@ var DomainErrCodeToHttpStatus = map[int]int{
 	errs.ErrCreateSubPLO:   fiber.StatusInternalServerError,
 	errs.ErrUpdateSubPLO:   fiber.StatusInternalServerError,
 	errs.ErrDeleteSubPLO:   fiber.StatusInternalServerError,
+
+	errs.ErrCurriculumMapLevelNotFound: fiber.StatusNotFound,
+	errs.ErrCreateCurriculumMapLevel:   fiber.StatusBadRequest,
+	errs.ErrUpdateCurriculumMapLevel:   fiber.StatusInternalServerError,
+	errs.ErrDeleteCurriculumMapLevel:   fiber.StatusInternalServerError,
+	errs.ErrQueryCurriculumMap:         fiber.StatusInternalServerError,
+
+	errs.ErrPEONotFound: fiber.StatusNotFound,
+	errs.ErrCreatePEO:   fiber.StatusBadRequest,
+	errs.ErrUpdatePEO:   fiber.StatusInternalServerError,
+	errs.ErrDeletePEO:   fiber.StatusInternalServerError,
+	errs.ErrQueryPEO:    fiber.StatusInternalServerError,
+
+	errs.ErrProgramImprovementNotFound:      fiber.StatusNotFound,
+	errs.ErrCreateProgramImprovement:        fiber.StatusBadRequest,
+	errs.ErrUpdateProgramImprovement:        fiber.StatusInternalServerError,
+	errs.ErrDeleteProgramImprovement:        fiber.StatusInternalServerError,
+	errs.ErrQueryProgramImprovement:         fiber.StatusInternalServerError,
+	errs.ErrInvalidProgramImprovementStatus: fiber.StatusBadRequest,
+
+	errs.ErrGraduatedStudentNotFound: fiber.StatusNotFound,
+	errs.ErrCreateGraduatedStudent:   fiber.StatusBadRequest,
+	errs.ErrUpdateGraduatedStudent:   fiber.StatusInternalServerError,
+	errs.ErrDeleteGraduatedStudent:   fiber.StatusInternalServerError,
+	errs.ErrQueryGraduatedStudent:    fiber.StatusInternalServerError,
+	errs.ErrDupGraduatedStudent:      fiber.StatusConflict,
+
+	errs.ErrSurveyInvitationNotFound: fiber.StatusNotFound,
+	errs.ErrCreateSurveyInvitation:   fiber.StatusBadRequest,
+	errs.ErrQuerySurveyInvitation:    fiber.StatusInternalServerError,
+	errs.ErrSurveyInvitationUsed:     fiber.StatusConflict,
+	errs.ErrSurveyInvitationExpired:  fiber.StatusGone,
+	errs.ErrSubmitSurveyResponse:     fiber.StatusBadRequest,
+
+	errs.ErrCreateFeedback:      fiber.StatusBadRequest,
+	errs.ErrQueryFeedback:       fiber.StatusInternalServerError,
+	errs.ErrDupFeedback:         fiber.StatusConflict,
+	errs.ErrFeedbackNotEnrolled: fiber.StatusForbidden,
+	errs.ErrFeedbackPermission:  fiber.StatusForbidden,
+
+	errs.ErrFeedbackTokenNotFound: fiber.StatusNotFound,
+	errs.ErrFeedbackTokenUsed:     fiber.StatusConflict,
+	errs.ErrFeedbackTokenExpired:  fiber.StatusGone,
+
+	errs.ErrSsoDisabled: fiber.StatusNotFound,
+	errs.ErrSsoSignIn:   fiber.StatusUnauthorized,
+	errs.ErrSsoProvider: fiber.StatusBadGateway,
+
+	errs.ErrLdap: fiber.StatusBadGateway,
+
+	errs.ErrTwoFactorInvalidCode:    fiber.StatusUnauthorized,
+	errs.ErrTwoFactorNotEnrolled:    fiber.StatusBadRequest,
+	errs.ErrTwoFactorAlreadyEnabled: fiber.StatusConflict,
+	errs.ErrTwoFactorRequired:       fiber.StatusForbidden,
+	errs.ErrUpdateTwoFactor:         fiber.StatusInternalServerError,
+	errs.ErrQueryTwoFactor:          fiber.StatusInternalServerError,
+	errs.ErrTwoFactorPermission:     fiber.StatusForbidden,
+
+	errs.ErrSessionNotFound:   fiber.StatusNotFound,
+	errs.ErrSessionPermission: fiber.StatusForbidden,
+
+	errs.ErrResetTokenInvalid:      fiber.StatusBadRequest,
+	errs.ErrResetTokenLocked:       fiber.StatusTooManyRequests,
+	errs.ErrResetTokenRequestLimit: fiber.StatusTooManyRequests,
+	errs.ErrCreateResetToken:       fiber.StatusInternalServerError,
+	errs.ErrQueryResetToken:        fiber.StatusInternalServerError,
+	errs.ErrUpdateResetToken:       fiber.StatusInternalServerError,
+
+	errs.ErrTooManyAttempts:    fiber.StatusTooManyRequests,
+	errs.ErrUpdateThrottle:     fiber.StatusInternalServerError,
+	errs.ErrThrottlePermission: fiber.StatusForbidden,
+
+	errs.ErrPasswordPolicy:         fiber.StatusBadRequest,
+	errs.ErrPasswordChangeRequired: fiber.StatusForbidden,
+	errs.ErrUpdatePassword:         fiber.StatusInternalServerError,
+	errs.ErrQueryPassword:          fiber.StatusInternalServerError,
+
+	errs.ErrMailTemplate:      fiber.StatusInternalServerError,
+	errs.ErrCreateMail:        fiber.StatusInternalServerError,
+	errs.ErrQueryMail:         fiber.StatusInternalServerError,
+	errs.ErrUpdateMail:        fiber.StatusInternalServerError,
+	errs.ErrMailNotFound:      fiber.StatusNotFound,
+	errs.ErrMailPermission:    fiber.StatusForbidden,
+	errs.ErrMailNotRetryable:  fiber.StatusBadRequest,
+	errs.ErrInvalidMailStatus: fiber.StatusBadRequest,
+
+	errs.ErrCreateNotification:      fiber.StatusInternalServerError,
+	errs.ErrQueryNotification:       fiber.StatusInternalServerError,
+	errs.ErrUpdateNotification:      fiber.StatusInternalServerError,
+	errs.ErrInvalidNotificationType: fiber.StatusBadRequest,
+
+	errs.ErrMilestoneNotFound:   fiber.StatusNotFound,
+	errs.ErrCreateMilestone:     fiber.StatusInternalServerError,
+	errs.ErrUpdateMilestone:     fiber.StatusInternalServerError,
+	errs.ErrDeleteMilestone:     fiber.StatusInternalServerError,
+	errs.ErrQueryMilestone:      fiber.StatusInternalServerError,
+	errs.ErrInvalidMilestone:    fiber.StatusBadRequest,
+	errs.ErrDupMilestone:        fiber.StatusConflict,
+	errs.ErrMilestonePermission: fiber.StatusForbidden,
+	errs.ErrScoreEntryLocked:    fiber.StatusForbidden,
+	errs.ErrSchedulerLease:      fiber.StatusInternalServerError,
+
+	errs.ErrReportJobNotFound:   fiber.StatusNotFound,
+	errs.ErrCreateReportJob:     fiber.StatusInternalServerError,
+	errs.ErrQueryReportJob:      fiber.StatusInternalServerError,
+	errs.ErrUpdateReportJob:     fiber.StatusInternalServerError,
+	errs.ErrInvalidReportJob:    fiber.StatusBadRequest,
+	errs.ErrReportJobPermission: fiber.StatusForbidden,
+	errs.ErrReportJobNotReady:   fiber.StatusConflict,
+	errs.ErrReportLinkInvalid:   fiber.StatusForbidden,
+
+	errs.ErrFileNotFound: fiber.StatusNotFound,
+	errs.ErrCreateFile:   fiber.StatusInternalServerError,
+	errs.ErrQueryFile:    fiber.StatusInternalServerError,
+	errs.ErrDeleteFile:   fiber.StatusInternalServerError,
+	errs.ErrFileTooLarge: fiber.StatusRequestEntityTooLarge,
+	errs.ErrInvalidFile:  fiber.StatusBadRequest,
+
+	errs.ErrRefreshAttainment:    fiber.StatusInternalServerError,
+	errs.ErrAttainmentPermission: fiber.StatusForbidden,
+
+	errs.ErrAssessmentItemNotFound:     fiber.StatusNotFound,
+	errs.ErrCreateAssessmentItem:       fiber.StatusInternalServerError,
+	errs.ErrUpdateAssessmentItem:       fiber.StatusInternalServerError,
+	errs.ErrDeleteAssessmentItem:       fiber.StatusInternalServerError,
+	errs.ErrQueryAssessmentItem:        fiber.StatusInternalServerError,
+	errs.ErrInvalidAssessmentItem:      fiber.StatusBadRequest,
+	errs.ErrSaveAssessmentItemScore:    fiber.StatusInternalServerError,
+	errs.ErrInvalidAssessmentItemScore: fiber.StatusBadRequest,
+	errs.ErrAssessmentItemPermission:   fiber.StatusForbidden,
+	errs.ErrScoreByAssessmentItems:     fiber.StatusConflict,
+
+	errs.ErrRubricNotFound: fiber.StatusNotFound,
+	errs.ErrCreateRubric:   fiber.StatusInternalServerError,
+	errs.ErrUpdateRubric:   fiber.StatusInternalServerError,
+	errs.ErrDeleteRubric:   fiber.StatusInternalServerError,
+	errs.ErrQueryRubric:    fiber.StatusInternalServerError,
+	errs.ErrInvalidRubric:  fiber.StatusBadRequest,
+	errs.ErrRubricInUse:    fiber.StatusConflict,
+	errs.ErrAttachRubric:   fiber.StatusInternalServerError,
 }
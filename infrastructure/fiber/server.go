@@ -8,8 +8,12 @@ import (
 	"github.com/team-inu/inu-backyard/infrastructure/captcha"
 	"github.com/team-inu/inu-backyard/infrastructure/fiber/controller"
 	"github.com/team-inu/inu-backyard/infrastructure/fiber/middleware"
+	"github.com/team-inu/inu-backyard/infrastructure/ldap"
+	"github.com/team-inu/inu-backyard/infrastructure/mail"
+	"github.com/team-inu/inu-backyard/infrastructure/sso"
+	"github.com/team-inu/inu-backyard/infrastructure/storage"
 	"github.com/team-inu/inu-backyard/internal/config"
-	"github.com/team-inu/inu-backyard/internal/utils/session"
+	"github.com/team-inu/inu-backyard/internal/utils/password"
 	"github.com/team-inu/inu-backyard/internal/validator"
 	"github.com/team-inu/inu-backyard/repository"
 	"github.com/team-inu/inu-backyard/usecase"
@@ -22,7 +26,6 @@ type fiberServer struct {
 	gorm      *gorm.DB
 	turnstile *captcha.Turnstile
 	logger    *zap.Logger
-	session   session.SessionM
 
 	studentRepository                entity.StudentRepository
 	courseRepository                 entity.CourseRepository
@@ -45,6 +48,22 @@ type fiberServer struct {
 	importerRepository               repository.ImporterRepositoryGorm
 	mailRepository                   entity.MailRepository
 	surveyRepository                 entity.SurveyRepository
+	curriculumMapRepository          entity.CurriculumMapRepository
+	peoRepository                    entity.ProgramEducationalObjectiveRepository
+	programImprovementRepository     entity.ProgramImprovementRepository
+	graduatedStudentRepository       entity.GraduatedStudentRepository
+	feedbackRepository               entity.FeedbackRepository
+	twoFactorRepository              entity.TwoFactorRepository
+	loginThrottleRepository          entity.LoginThrottleRepository
+	passwordRepository               entity.PasswordRepository
+	notificationRepository           entity.NotificationRepository
+	milestoneRepository              entity.MilestoneRepository
+	schedulerLeaseRepository         entity.SchedulerLeaseRepository
+	reportJobRepository              entity.ReportJobRepository
+	fileRepository                   entity.FileRepository
+	attainmentRepository             entity.AttainmentRepository
+	assessmentItemRepository         entity.AssessmentItemRepository
+	rubricRepository                 entity.RubricRepository
 
 	studentUseCase                entity.StudentUseCase
 	courseUseCase                 entity.CourseUseCase
@@ -68,8 +87,24 @@ type fiberServer struct {
 	courseStreamUseCase           entity.CourseStreamsUseCase
 	importerUseCase               usecase.ImporterUseCase
 	surveyUseCase                 entity.SurveyUseCase
-
-	mailUseCase entity.MailUseCase
+	curriculumMapUseCase          entity.CurriculumMapUseCase
+	peoUseCase                    entity.ProgramEducationalObjectiveUseCase
+	programImprovementUseCase     entity.ProgramImprovementUseCase
+	graduatedStudentUseCase       entity.GraduatedStudentUseCase
+	feedbackUseCase               entity.FeedbackUseCase
+	twoFactorUseCase              entity.TwoFactorUseCase
+	loginThrottleUseCase          entity.LoginThrottleUseCase
+	passwordUseCase               entity.PasswordUseCase
+	assessmentItemUseCase         entity.AssessmentItemUseCase
+	rubricUseCase                 entity.RubricUseCase
+
+	mailUseCase         entity.MailUseCase
+	notificationUseCase entity.NotificationUseCase
+	milestoneUseCase    entity.MilestoneUseCase
+	schedulerUseCase    entity.SchedulerUseCase
+	reportJobUseCase    entity.ReportJobUseCase
+	fileUseCase         entity.FileUseCase
+	attainmentUseCase   entity.AttainmentUseCase
 }
 
 func NewFiberServer(
@@ -77,20 +112,23 @@ func NewFiberServer(
 	gorm *gorm.DB,
 	turnstile *captcha.Turnstile,
 	logger *zap.Logger,
-	session session.SessionM,
 ) *fiberServer {
 	return &fiberServer{
 		config:    config,
 		gorm:      gorm,
 		turnstile: turnstile,
 		logger:    logger,
-		session:   session,
 	}
 }
 
 func (f *fiberServer) Run() {
 	f.initRepository()
 	f.initUseCase()
+	f.startCleanup()
+	f.startMailWorker()
+	f.startScheduler()
+	f.startReportWorkers()
+	f.startAttainmentWorker()
 
 	err := f.initController()
 	if err != nil {
@@ -118,8 +156,24 @@ func (f *fiberServer) initRepository() {
 	f.coursePortfolioRepository = repository.NewCoursePortfolioRepositoryGorm(f.gorm)
 	f.courseStreamRepository = repository.NewCourseStreamRepository(f.gorm)
 	f.importerRepository = repository.NewImporterRepositoryGorm(f.gorm)
-	f.mailRepository = repository.NewMailRepository(f.session)
+	f.mailRepository = repository.NewMailRepositoryGorm(f.gorm)
 	f.surveyRepository = repository.NewSurveyRepositoryGorm(f.gorm)
+	f.curriculumMapRepository = repository.NewCurriculumMapRepositoryGorm(f.gorm)
+	f.peoRepository = repository.NewProgramEducationalObjectiveRepositoryGorm(f.gorm)
+	f.programImprovementRepository = repository.NewProgramImprovementRepositoryGorm(f.gorm)
+	f.graduatedStudentRepository = repository.NewGraduatedStudentRepositoryGorm(f.gorm)
+	f.feedbackRepository = repository.NewFeedbackRepositoryGorm(f.gorm)
+	f.twoFactorRepository = repository.NewTwoFactorRepositoryGorm(f.gorm)
+	f.loginThrottleRepository = repository.NewLoginThrottleRepositoryGorm(f.gorm)
+	f.passwordRepository = repository.NewPasswordRepositoryGorm(f.gorm)
+	f.notificationRepository = repository.NewNotificationRepositoryGorm(f.gorm)
+	f.milestoneRepository = repository.NewMilestoneRepositoryGorm(f.gorm)
+	f.schedulerLeaseRepository = repository.NewSchedulerLeaseRepositoryGorm(f.gorm)
+	f.reportJobRepository = repository.NewReportJobRepositoryGorm(f.gorm)
+	f.fileRepository = repository.NewFileRepositoryGorm(f.gorm)
+	f.attainmentRepository = repository.NewAttainmentRepositoryGorm(f.gorm)
+	f.assessmentItemRepository = repository.NewAssessmentItemRepositoryGorm(f.gorm)
+	f.rubricRepository = repository.NewRubricRepositoryGorm(f.gorm)
 }
 
 func (f *fiberServer) initUseCase() {
@@ -129,32 +183,126 @@ func (f *fiberServer) initUseCase() {
 	f.studentUseCase = usecase.NewStudentUseCase(f.studentRepository, f.departmentUseCase, f.programmeUseCase)
 
 	f.programLearningOutcomeUseCase = usecase.NewProgramLearningOutcomeUseCase(f.programLearningOutcomeRepository, f.programmeUseCase)
-	f.userUseCase = usecase.NewUserUseCase(f.userRepository)
+
+	passwordConfig := f.config.Client.Auth.Password
+	passwordPolicy, err := password.NewPolicy(
+		passwordConfig.MinLength,
+		passwordConfig.RequireUpper,
+		passwordConfig.RequireLower,
+		passwordConfig.RequireDigit,
+		passwordConfig.RequireSymbol,
+		passwordConfig.BreachedListPath,
+	)
+	if err != nil {
+		panic(err)
+	}
+
+	f.passwordUseCase = usecase.NewPasswordUseCase(f.passwordRepository, passwordPolicy, passwordConfig)
+	f.userUseCase = usecase.NewUserUseCase(f.userRepository, f.passwordUseCase)
 	f.semesterUseCase = usecase.NewSemesterUseCase(f.semesterRepository)
 	f.courseUseCase = usecase.NewCourseUseCase(f.courseRepository, f.semesterUseCase, f.userUseCase)
 	f.enrollmentUseCase = usecase.NewEnrollmentUseCase(f.enrollmentRepository, f.studentUseCase, f.courseUseCase)
 	f.gradeUseCase = usecase.NewGradeUseCase(f.gradeRepository, f.studentUseCase, f.semesterUseCase)
 	f.sessionUseCase = usecase.NewSessionUseCase(f.sessionRepository, f.config.Client.Auth)
-	f.mailUseCase = usecase.NewMailUseCase(f.mailRepository)
-	f.authUseCase = usecase.NewAuthUseCase(f.sessionUseCase, f.userUseCase, f.mailUseCase)
+
+	mailer, err := mail.NewMailer(f.config.Mail)
+	if err != nil {
+		panic(err)
+	}
+
+	mailRenderer, err := mail.NewRenderer()
+	if err != nil {
+		panic(err)
+	}
+
+	f.mailUseCase, err = usecase.NewMailUseCase(f.mailRepository, mailer, mailRenderer, f.config.Mail, f.config.Client)
+	if err != nil {
+		panic(err)
+	}
+
+	f.notificationUseCase = usecase.NewNotificationUseCase(f.notificationRepository, f.userUseCase, f.mailUseCase)
+	f.milestoneUseCase = usecase.NewMilestoneUseCase(f.milestoneRepository, f.semesterUseCase, f.notificationUseCase)
+	f.schedulerUseCase = usecase.NewSchedulerUseCase(f.schedulerLeaseRepository, f.milestoneUseCase, f.config.Scheduler)
+
+	var ssoProvider entity.SsoProvider
+	if f.config.Client.Auth.Oidc.Enabled {
+		ssoProvider = sso.NewOidcProvider(f.config.Client.Auth.Oidc)
+	}
+
+	credentialVerifiers := []entity.CredentialVerifier{}
+	if f.config.Client.Auth.Ldap.Enabled {
+		ldapVerifier, err := ldap.NewVerifier(f.config.Client.Auth.Ldap)
+		if err != nil {
+			panic(err)
+		}
+		credentialVerifiers = append(credentialVerifiers, ldapVerifier)
+	}
+	credentialVerifiers = append(credentialVerifiers, usecase.NewPasswordCredentialVerifier(f.userUseCase))
+
+	f.twoFactorUseCase = usecase.NewTwoFactorUseCase(f.twoFactorRepository, f.userUseCase, f.config.Client.Auth.TwoFactor)
+	f.loginThrottleUseCase = usecase.NewLoginThrottleUseCase(f.loginThrottleRepository, f.userUseCase, f.mailUseCase, f.config.Client.Auth.Throttle)
+	f.authUseCase = usecase.NewAuthUseCase(f.sessionUseCase, f.userUseCase, f.mailUseCase, ssoProvider, f.config.Client.Auth, credentialVerifiers, f.twoFactorUseCase, f.loginThrottleUseCase, f.passwordUseCase)
+
 	f.programOutcomeUseCase = usecase.NewProgramOutcomeUseCase(f.programOutcomeRepository, f.semesterUseCase)
 	f.studentOutcomeUseCase = usecase.NewStudentOutcomeUseCase(f.studentOutcomeRepository, f.programmeUseCase)
 	f.courseLearningOutcomeUseCase = usecase.NewCourseLearningOutcomeUseCase(f.courseLearningOutcomeRepository, f.courseUseCase, f.programmeUseCase, f.programOutcomeUseCase, f.programLearningOutcomeUseCase, f.studentOutcomeUseCase)
 
 	f.assignmentUseCase = usecase.NewAssignmentUseCase(f.assignmentRepository, f.courseLearningOutcomeUseCase, f.courseUseCase)
-	f.scoreUseCase = usecase.NewScoreUseCase(f.scoreRepository, f.enrollmentUseCase, f.assignmentUseCase, f.courseUseCase, f.userUseCase, f.studentUseCase)
-	f.courseStreamUseCase = usecase.NewCourseStreamUseCase(f.courseStreamRepository, f.courseUseCase)
-	f.coursePortfolioUseCase = usecase.NewCoursePortfolioUseCase(f.coursePortfolioRepository, f.courseUseCase, f.userUseCase, f.enrollmentUseCase, f.assignmentUseCase, f.scoreUseCase, f.studentUseCase, f.courseLearningOutcomeUseCase, f.courseStreamUseCase)
-	f.importerUseCase = usecase.NewImporterUseCase(f.importerRepository, f.courseUseCase, f.enrollmentUseCase, f.assignmentUseCase, f.programOutcomeUseCase, f.programLearningOutcomeUseCase, f.courseLearningOutcomeUseCase, f.userUseCase)
+	f.assessmentItemUseCase = usecase.NewAssessmentItemUseCase(f.assessmentItemRepository, f.assignmentUseCase, f.courseUseCase, f.courseLearningOutcomeUseCase, f.enrollmentUseCase, f.milestoneUseCase)
+	f.rubricUseCase = usecase.NewRubricUseCase(f.rubricRepository, f.programmeUseCase, f.assignmentUseCase, f.courseUseCase, f.courseLearningOutcomeUseCase, f.assessmentItemUseCase)
+	f.scoreUseCase = usecase.NewScoreUseCase(f.scoreRepository, f.enrollmentUseCase, f.assignmentUseCase, f.courseUseCase, f.userUseCase, f.studentUseCase, f.milestoneUseCase, f.assessmentItemUseCase)
+	f.courseStreamUseCase = usecase.NewCourseStreamUseCase(f.courseStreamRepository, f.courseUseCase, f.notificationUseCase, f.logger)
+	f.feedbackUseCase = usecase.NewFeedbackUseCase(f.feedbackRepository, f.courseUseCase, f.studentUseCase, f.enrollmentUseCase, f.mailUseCase, f.loginThrottleUseCase, f.config.Client)
+	f.coursePortfolioUseCase = usecase.NewCoursePortfolioUseCase(f.coursePortfolioRepository, f.courseUseCase, f.userUseCase, f.enrollmentUseCase, f.assignmentUseCase, f.scoreUseCase, f.studentUseCase, f.courseLearningOutcomeUseCase, f.courseStreamUseCase, f.programmeUseCase, f.feedbackUseCase, f.notificationUseCase, f.logger)
+
+	fileStore, err := storage.NewFileStore(f.config.Storage)
+	if err != nil {
+		panic(err)
+	}
+
+	f.fileUseCase = usecase.NewFileUseCase(f.fileRepository, fileStore, f.config.Storage)
+	f.attainmentUseCase, err = usecase.NewAttainmentUseCase(f.attainmentRepository, f.config.Attainment)
+	if err != nil {
+		panic(err)
+	}
+
+	f.importerUseCase = usecase.NewImporterUseCase(f.importerRepository, f.courseUseCase, f.enrollmentUseCase, f.assignmentUseCase, f.programOutcomeUseCase, f.programLearningOutcomeUseCase, f.courseLearningOutcomeUseCase, f.userUseCase, f.notificationUseCase, f.milestoneUseCase, f.logger)
 	f.predictionUseCase = usecase.NewPredictionUseCase(f.config)
-	f.surveyUseCase = usecase.NewSurveyUseCase(f.surveyRepository)
+	f.graduatedStudentUseCase = usecase.NewGraduatedStudentUseCase(f.graduatedStudentRepository, f.studentUseCase, f.programmeUseCase)
+	f.surveyUseCase = usecase.NewSurveyUseCase(f.surveyRepository, f.programmeUseCase, f.graduatedStudentUseCase, f.mailUseCase, f.courseUseCase, f.notificationUseCase, f.config.Client, f.logger)
+	f.curriculumMapUseCase = usecase.NewCurriculumMapUseCase(f.curriculumMapRepository, f.programmeUseCase)
+	f.peoUseCase = usecase.NewProgramEducationalObjectiveUseCase(f.peoRepository, f.programmeUseCase)
+	f.programImprovementUseCase = usecase.NewProgramImprovementUseCase(f.programImprovementRepository, f.programmeUseCase, f.courseUseCase, f.surveyUseCase, f.userUseCase)
+
+	f.reportJobUseCase, err = usecase.NewReportJobUseCase(f.reportJobRepository, f.coursePortfolioUseCase, f.curriculumMapUseCase, f.programImprovementUseCase, f.fileUseCase, f.config.Report)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// newAppConfig reads the client address from the proxy header of requests forwarded by the trusted proxies, so the
+// login throttle counts each client instead of the proxy
+func newAppConfig(proxy config.ProxyConfig, logger *zap.Logger) fiber.Config {
+	proxyHeader := ""
+	if len(proxy.TrustedProxies) > 0 {
+		proxyHeader = proxy.Header
+		if proxyHeader == "" {
+			proxyHeader = fiber.HeaderXForwardedFor
+		}
+	}
+
+	return fiber.Config{
+		AppName:                 "inu-backyard",
+		ErrorHandler:            errorHandler(logger),
+		ProxyHeader:             proxyHeader,
+		EnableTrustedProxyCheck: true,
+		TrustedProxies:          proxy.TrustedProxies,
+		EnableIPValidation:      true,
+	}
 }
 
 func (f *fiberServer) initController() error {
-	app := fiber.New(fiber.Config{
-		AppName:      "inu-backyard",
-		ErrorHandler: errorHandler(f.logger),
-	})
+	app := fiber.New(newAppConfig(f.config.Client.Proxy, f.logger))
 
 	app.Use(middleware.NewCorsMiddleware(f.config.Client.Cors.AllowOrigins))
 	app.Use(middleware.NewLogger(fiberzap.Config{
@@ -178,16 +326,30 @@ func (f *fiberServer) initController() error {
 	scoreController := controller.NewScoreController(validator, f.scoreUseCase)
 	userController := controller.NewUserController(validator, f.userUseCase, f.authUseCase)
 	assignmentController := controller.NewAssignmentController(validator, f.assignmentUseCase)
+	assessmentItemController := controller.NewAssessmentItemController(validator, f.assessmentItemUseCase)
+	rubricController := controller.NewRubricController(validator, f.rubricUseCase)
 	programmeController := controller.NewProgrammeController(validator, f.programmeUseCase)
 	semesterController := controller.NewSemesterController(validator, f.semesterUseCase)
 	enrollmentController := controller.NewEnrollmentController(validator, f.enrollmentUseCase)
 	gradeController := controller.NewGradeController(validator, f.gradeUseCase)
 	predictionController := controller.NewPredictionController(validator, f.predictionUseCase)
-	coursePortfolioController := controller.NewCoursePortfolioController(validator, f.coursePortfolioUseCase)
+	coursePortfolioController := controller.NewCoursePortfolioController(validator, f.coursePortfolioUseCase, f.reportJobUseCase)
+	reportJobController := controller.NewReportJobController(validator, f.reportJobUseCase)
+	attainmentController := controller.NewAttainmentController(validator, f.attainmentUseCase)
 	courseStreamController := controller.NewCourseStreamController(validator, f.courseStreamUseCase)
 	importerController := controller.NewImporterController(validator, f.importerUseCase)
 	surveyController := controller.NewSurveyController(validator, f.surveyUseCase)
+	curriculumMapController := controller.NewCurriculumMapController(validator, f.curriculumMapUseCase, f.reportJobUseCase)
+	peoController := controller.NewProgramEducationalObjectiveController(validator, f.peoUseCase)
+	programImprovementController := controller.NewProgramImprovementController(validator, f.programImprovementUseCase, f.reportJobUseCase)
+	graduatedStudentController := controller.NewGraduatedStudentController(validator, f.graduatedStudentUseCase)
+	feedbackController := controller.NewFeedbackController(validator, f.feedbackUseCase)
 	authController := controller.NewAuthController(validator, f.config.Client.Auth, *f.turnstile, f.authUseCase, f.userUseCase)
+	twoFactorController := controller.NewTwoFactorController(validator, f.twoFactorUseCase)
+	sessionController := controller.NewSessionController(validator, f.sessionUseCase)
+	mailController := controller.NewMailController(validator, f.mailUseCase)
+	notificationController := controller.NewNotificationController(validator, f.notificationUseCase)
+	milestoneController := controller.NewMilestoneController(validator, f.milestoneUseCase)
 
 	api := app.Group("/")
 
@@ -227,6 +389,7 @@ func (f *fiberServer) initController() error {
 	course.Get("/:courseId/assignments", assignmentController.GetByCourseId)
 	course.Get("/:courseId/assignment-groups", assignmentController.GetGroupByCourseId)
 	course.Get("/:courseId/survey", surveyController.GetByCourseId)
+	course.Get("/:courseId/feedback_summary", feedbackController.GetCourseSummary)
 	course.Get("/:courseId", courseController.GetById)
 	course.Get("/:courseId/portfolio/outcomes", coursePortfolioController.GetCourseOutcomesSuccessRateByCourseId)
 
@@ -261,6 +424,7 @@ func (f *fiberServer) initController() error {
 	so := api.Group("/sos", authMiddleware)
 
 	so.Get("/", studentOutcomeController.GetAll)
+	so.Get("/courses", coursePortfolioController.GetAllStudentOutcomeCourses)
 	so.Post("/", studentOutcomeController.Create)
 	so.Get("/:soId", studentOutcomeController.GetById)
 	so.Patch("/:soId", studentOutcomeController.Update)
@@ -339,6 +503,9 @@ func (f *fiberServer) initController() error {
 	user.Get("/:userId", userController.GetById)
 	user.Patch("/:userId", userController.Update)
 	user.Delete("/:userId", userController.Delete)
+	user.Delete("/:userId/2fa", twoFactorController.Reset)
+	user.Delete("/:userId/sessions", sessionController.ForceSignOut)
+	user.Delete("/:userId/lockout", userController.Unlock)
 	user.Post("/:userId/password", userController.ChangePassword)
 	user.Post("/bulk", userController.CreateMany)
 
@@ -369,6 +536,25 @@ func (f *fiberServer) initController() error {
 	cloByAssignment.Post("/", assignmentController.CreateLinkCourseLearningOutcome)
 	cloByAssignment.Delete("/:cloId", assignmentController.DeleteLinkCourseLearningOutcome)
 
+	// assessment item route
+	assignment.Get("/:assignmentId/items", assessmentItemController.GetByAssignmentId)
+	assignment.Post("/:assignmentId/items", assessmentItemController.Create)
+	assignment.Get("/:assignmentId/item_scores", assessmentItemController.GetScoresByAssignmentId)
+	assignment.Post("/:assignmentId/item_scores", assessmentItemController.SaveScores)
+
+	assessmentItem := api.Group("/assessment_items", authMiddleware)
+
+	assessmentItem.Get("/:itemId", assessmentItemController.GetById)
+	assessmentItem.Patch("/:itemId", assessmentItemController.Update)
+	assessmentItem.Delete("/:itemId", assessmentItemController.Delete)
+	assessmentItem.Post("/:itemId/clos", assessmentItemController.CreateLinkCourseLearningOutcome)
+	assessmentItem.Delete("/:itemId/clos/:cloId", assessmentItemController.DeleteLinkCourseLearningOutcome)
+
+	// rubric by assignment route
+	assignment.Post("/:assignmentId/rubric", rubricController.Attach)
+	assignment.Delete("/:assignmentId/rubric", rubricController.Detach)
+	assignment.Post("/:assignmentId/rubric_grades", rubricController.SaveGrades)
+
 	// programme route
 	programme := api.Group("/programmes", authMiddleware)
 
@@ -388,6 +574,36 @@ func (f *fiberServer) initController() error {
 	programme.Get("/:programmeId/clo_assessment", coursePortfolioController.GetCourseCloAssessment)
 	programme.Get("/:programmeId/liked_outcomes", coursePortfolioController.GetCourseLinkedOutcomes)
 	programme.Get("/:programmeId/outcomes_success_rate", coursePortfolioController.GetCourseOutcomesSuccessRate)
+	programme.Get("/:programmeId/so_attainment", coursePortfolioController.GetProgrammeStudentOutcomeAttainment)
+	programme.Get("/:programmeId/outcomes_trend", coursePortfolioController.GetOutcomeAttainmentTrend)
+	programme.Get("/:programmeId/outcomes_trend/export", coursePortfolioController.GetOutcomeAttainmentTrendFile)
+	programme.Get("/:programmeId/curriculum_map", curriculumMapController.Get)
+	programme.Get("/:programmeId/curriculum_map/export", curriculumMapController.GetFile)
+	programme.Post("/:programmeId/curriculum_map/levels", curriculumMapController.SetLevel)
+	programme.Delete("/:programmeId/curriculum_map/levels/:levelId", curriculumMapController.DeleteLevel)
+	programme.Get("/:programmeId/peos", peoController.GetByProgrammeId)
+	programme.Get("/:programmeId/peos/:peoId", peoController.GetById)
+	programme.Post("/:programmeId/peos", peoController.Create)
+	programme.Patch("/:programmeId/peos/:peoId", peoController.Update)
+	programme.Delete("/:programmeId/peos/:peoId", peoController.Delete)
+	programme.Post("/:programmeId/peos/:peoId/plos", peoController.CreateLinkProgramLearningOutcome)
+	programme.Delete("/:programmeId/peos/:peoId/plos/:ploId", peoController.DeleteLinkProgramLearningOutcome)
+	programme.Post("/:programmeId/peos/:peoId/sos", peoController.CreateLinkStudentOutcome)
+	programme.Delete("/:programmeId/peos/:peoId/sos/:soId", peoController.DeleteLinkStudentOutcome)
+	programme.Get("/:programmeId/rubrics", rubricController.GetByProgrammeId)
+	programme.Get("/:programmeId/rubrics/:rubricId", rubricController.GetById)
+	programme.Post("/:programmeId/rubrics", rubricController.Create)
+	programme.Patch("/:programmeId/rubrics/:rubricId", rubricController.Update)
+	programme.Delete("/:programmeId/rubrics/:rubricId", rubricController.Delete)
+	programme.Get("/:programmeId/improvements", programImprovementController.GetByProgrammeId)
+	programme.Get("/:programmeId/improvements/export", programImprovementController.GetLogFile)
+	programme.Get("/:programmeId/improvements/:improvementId", programImprovementController.GetById)
+	programme.Post("/:programmeId/improvements", programImprovementController.Create)
+	programme.Patch("/:programmeId/improvements/:improvementId", programImprovementController.Update)
+	programme.Delete("/:programmeId/improvements/:improvementId", programImprovementController.Delete)
+	programme.Get("/:programmeId/alumni_summary", graduatedStudentController.GetAlumniSummary)
+	programme.Get("/:programmeId/surveys", surveyController.GetByProgrammeId)
+	programme.Get("/:programmeId/stakeholder_feedback", programmeController.GetStakeholderFeedback)
 	programme.Get("/outcomes/po", programmeController.GetAllCourseLinkedPO)
 	programme.Get("/outcomes/plo", programmeController.GetAllCourseLinkedPLO)
 	programme.Get("/outcomes/so", programmeController.GetAllCourseLinkedSO)
@@ -409,6 +625,14 @@ func (f *fiberServer) initController() error {
 	semester.Post("/", semesterController.Create)
 	semester.Patch("/:semesterId", semesterController.Update)
 	semester.Delete("/:semesterId", semesterController.Delete)
+	semester.Get("/:semesterId/milestones", milestoneController.GetBySemesterId)
+	semester.Post("/:semesterId/milestones", milestoneController.Create)
+
+	// deadlines of a semester, reminders are sent by the scheduler
+	milestones := api.Group("/milestones", authMiddleware)
+
+	milestones.Patch("/:milestoneId", milestoneController.Update)
+	milestones.Delete("/:milestoneId", milestoneController.Delete)
 
 	// grade route
 	grade := api.Group("/grades", authMiddleware)
@@ -437,6 +661,8 @@ func (f *fiberServer) initController() error {
 	survey.Post("/", surveyController.Create)
 	survey.Get("/:surveyId", surveyController.GetById)
 	survey.Get("/:surveyId/questions", surveyController.GetQuestionBySurveyId)
+	survey.Get("/:surveyId/invitations", surveyController.GetInvitations)
+	survey.Post("/:surveyId/invitations", surveyController.CreateInvitations)
 	survey.Patch("/:surveyId", surveyController.Update)
 	survey.Delete("/:surveyId", surveyController.Delete)
 
@@ -446,16 +672,89 @@ func (f *fiberServer) initController() error {
 	question.Patch("/:questionId", surveyController.UpdateQuestion)
 	question.Delete("/:questionId", surveyController.DeleteQuestion)
 
+	// survey answered by alumni and employers through a one-time token
+	surveyResponse := api.Group("/survey_responses")
+	surveyResponse.Get("/:token", surveyController.GetFormByToken)
+	surveyResponse.Post("/:token", surveyController.SubmitResponse)
+
+	// graduate
+	graduate := api.Group("/graduates", authMiddleware)
+
+	graduate.Get("/", graduatedStudentController.GetByParams)
+	graduate.Post("/", graduatedStudentController.Create)
+	graduate.Post("/bulk", graduatedStudentController.GraduateCohort)
+	graduate.Get("/:graduateId", graduatedStudentController.GetById)
+	graduate.Patch("/:graduateId", graduatedStudentController.Update)
+	graduate.Delete("/:graduateId", graduatedStudentController.Delete)
+
+	// student course feedback, submitted without an account through a one-time link sent to the student email
+	courseFeedback := api.Group("/course_feedbacks")
+
+	courseFeedback.Post("/links", feedbackController.RequestLink)
+	courseFeedback.Post("/", feedbackController.Create)
+
+	feedback := api.Group("/feedbacks", authMiddleware)
+
+	feedback.Get("/", feedbackController.GetLecturerSummaries)
+
+	// delivery status of queued emails, for admins
+	mails := api.Group("/mails", authMiddleware)
+
+	mails.Get("/", mailController.GetOutbox)
+	mails.Get("/:mailId", mailController.GetOutboxById)
+	mails.Post("/:mailId/retry", mailController.RetryOutbox)
+
+	// notifications of the signed in user
+	notifications := api.Group("/notifications", authMiddleware)
+
+	notifications.Get("/", notificationController.GetAll)
+	notifications.Get("/unread_count", notificationController.CountUnread)
+	notifications.Patch("/read", notificationController.MarkRead)
+	notifications.Patch("/read_all", notificationController.MarkAllRead)
+	notifications.Get("/preferences", notificationController.GetPreferences)
+	notifications.Put("/preferences", notificationController.UpdatePreferences)
+
+	// reports generated in the background, the download is authorized by its signed link
+	jobs := api.Group("/jobs")
+
+	jobs.Get("/:jobId", authMiddleware, reportJobController.GetById)
+	jobs.Get("/:jobId/download", reportJobController.Download)
+
+	// outcome attainments are kept up to date by a background worker, a refresh recomputes all of them
+	attainments := api.Group("/attainments", authMiddleware)
+
+	attainments.Post("/refresh", attainmentController.RefreshAll)
+
 	// authentication route
 	auth := app.Group("/auth")
 
 	auth.Post("/login", authController.SignIn)
+	auth.Post("/login/2fa", authController.VerifyTwoFactor)
+	auth.Post("/login/2fa/enroll", authController.BeginChallengeEnrollment)
+	auth.Get("/sso/login", authController.SsoSignIn)
+	auth.Get("/sso/callback", authController.SsoCallback)
 	auth.Get("/logout", authMiddleware, authController.SignOut)
 	auth.Get("/me", authMiddleware, authController.Me)
+	auth.Post("/password", authMiddleware, authController.ChangePassword)
+
+	twoFactor := auth.Group("/2fa", authMiddleware)
+
+	twoFactor.Get("/", twoFactorController.GetStatus)
+	twoFactor.Delete("/", twoFactorController.Disable)
+	twoFactor.Post("/enroll", twoFactorController.BeginEnrollment)
+	twoFactor.Post("/confirm", twoFactorController.ConfirmEnrollment)
+	twoFactor.Post("/recovery_codes", twoFactorController.RegenerateRecoveryCodes)
+	twoFactor.Get("/policy", twoFactorController.GetPolicy)
+	twoFactor.Put("/policy", twoFactorController.UpdatePolicy)
+
+	sessions := auth.Group("/sessions", authMiddleware)
+
+	sessions.Get("/", sessionController.GetActive)
+	sessions.Delete("/", sessionController.RevokeOthers)
+	sessions.Delete("/:sessionId", sessionController.Revoke)
 
 	auth.Post("/forgot-password", authController.ForgotPassword)
 	auth.Post("/reset-password", authController.ResetPassword)
-	auth.Get("/:email", authController.GetSessionData)
 
 	app.Get("/metrics", monitor.New())
 
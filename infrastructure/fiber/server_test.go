@@ -0,0 +1,99 @@
+package fiber
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/team-inu/inu-backyard/entity"
+	"github.com/team-inu/inu-backyard/internal/config"
+	"github.com/team-inu/inu-backyard/usecase"
+	"go.uber.org/zap"
+)
+
+type memoryLoginThrottleRepository struct {
+	entity.LoginThrottleRepository
+	throttles map[string]*entity.LoginThrottle
+}
+
+func (r *memoryLoginThrottleRepository) GetByKeys(keys []string) ([]entity.LoginThrottle, error) {
+	throttles := []entity.LoginThrottle{}
+	for _, key := range keys {
+		if throttle, ok := r.throttles[key]; ok {
+			throttles = append(throttles, *throttle)
+		}
+	}
+
+	return throttles, nil
+}
+
+func (r *memoryLoginThrottleRepository) Update(key string, update func(throttle *entity.LoginThrottle)) (*entity.LoginThrottle, error) {
+	throttle, ok := r.throttles[key]
+	if !ok {
+		throttle = &entity.LoginThrottle{Key: key}
+		r.throttles[key] = throttle
+	}
+
+	update(throttle)
+	return throttle, nil
+}
+
+// newThrottledApp fails a sign in of a new email for each request and answers 429 once the client address is locked
+func newThrottledApp(proxy config.ProxyConfig) *fiber.App {
+	loginThrottleUseCase := usecase.NewLoginThrottleUseCase(
+		&memoryLoginThrottleRepository{throttles: map[string]*entity.LoginThrottle{}},
+		nil,
+		nil,
+		config.LoginThrottleConfig{MaxFailures: 100, IpMaxFailures: 2},
+	)
+
+	app := fiber.New(newAppConfig(proxy, zap.NewNop()))
+	app.Post("/sign-in", func(ctx *fiber.Ctx) error {
+		email := time.Now().Format(time.RFC3339Nano) + "@example.com"
+		err := loginThrottleUseCase.Check(entity.ThrottleActionSignIn, email, ctx.IP())
+		if err != nil {
+			return ctx.SendStatus(fiber.StatusTooManyRequests)
+		}
+
+		err = loginThrottleUseCase.Fail(entity.ThrottleActionSignIn, email, ctx.IP())
+		if err != nil {
+			return err
+		}
+
+		return ctx.SendStatus(fiber.StatusUnauthorized)
+	})
+
+	return app
+}
+
+func signIn(t *testing.T, app *fiber.App, clientAddress string) int {
+	req := httptest.NewRequest(fiber.MethodPost, "/sign-in", nil)
+	req.Header.Set(fiber.HeaderXForwardedFor, clientAddress)
+
+	res, err := app.Test(req)
+	assert.Nil(t, err)
+
+	return res.StatusCode
+}
+
+func TestAppConfig(t *testing.T) {
+	t.Run("TestProxyClientsThrottledSeparately", func(t *testing.T) {
+		// requests of app.Test come from 0.0.0.0
+		app := newThrottledApp(config.ProxyConfig{TrustedProxies: []string{"0.0.0.0/32"}})
+
+		assert.Equal(t, fiber.StatusUnauthorized, signIn(t, app, "203.0.113.1"))
+		assert.Equal(t, fiber.StatusUnauthorized, signIn(t, app, "203.0.113.1"))
+		assert.Equal(t, fiber.StatusTooManyRequests, signIn(t, app, "203.0.113.1"), "Expected the client to be locked after its failures")
+		assert.Equal(t, fiber.StatusUnauthorized, signIn(t, app, "203.0.113.2"), "Expected another client behind the same proxy to be counted on its own")
+	})
+
+	t.Run("TestUntrustedProxyHeaderIgnored", func(t *testing.T) {
+		app := newThrottledApp(config.ProxyConfig{TrustedProxies: []string{"10.0.0.0/8"}})
+
+		assert.Equal(t, fiber.StatusUnauthorized, signIn(t, app, "203.0.113.1"))
+		assert.Equal(t, fiber.StatusUnauthorized, signIn(t, app, "203.0.113.2"))
+		assert.Equal(t, fiber.StatusTooManyRequests, signIn(t, app, "203.0.113.3"), "Expected the header of an untrusted peer to be ignored")
+	})
+}
@@ -0,0 +1,340 @@
+// Package ldaptest provides an embedded LDAP server for tests, it answers StartTLS, simple binds and searches over in-memory entries.
+package ldaptest
+
+import (
+	"bufio"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+	"github.com/go-ldap/ldap/v3"
+)
+
+const startTlsOid = "1.3.6.1.4.1.1466.20037"
+
+type Entry struct {
+	Dn         string
+	Password   string
+	Attributes map[string][]string
+}
+
+type Server struct {
+	// searches are refused until the connection is bound, as Active Directory does
+	RequireBind bool
+
+	listener    net.Listener
+	tlsConfig   *tls.Config
+	certificate []byte
+	mutex       sync.Mutex
+	entries     []Entry
+	wait        sync.WaitGroup
+}
+
+// NewServer listens on a random local port with a self-signed certificate for StartTLS
+func NewServer(entries ...Entry) (*Server, error) {
+	certificate, tlsCertificate, err := newCertificate()
+	if err != nil {
+		return nil, err
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+
+	server := &Server{
+		RequireBind: true,
+		listener:    listener,
+		tlsConfig:   &tls.Config{Certificates: []tls.Certificate{tlsCertificate}},
+		certificate: certificate,
+		entries:     entries,
+	}
+
+	server.wait.Add(1)
+	go server.serve()
+
+	return server, nil
+}
+
+// newCertificate returns the PEM and the key pair of a certificate for 127.0.0.1
+func newCertificate() ([]byte, tls.Certificate, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, tls.Certificate{}, err
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "ldaptest"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, tls.Certificate{}, err
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}, nil
+}
+
+// Url is an ldap:// url, clients must upgrade the connection with StartTLS
+func (s *Server) Url() string {
+	return "ldap://" + s.listener.Addr().String()
+}
+
+// CaCertPem is the self-signed certificate of the server to trust
+func (s *Server) CaCertPem() []byte {
+	return s.certificate
+}
+
+func (s *Server) AddEntry(entry Entry) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.entries = append(s.entries, entry)
+}
+
+func (s *Server) Close() {
+	s.listener.Close()
+	s.wait.Wait()
+}
+
+func (s *Server) serve() {
+	defer s.wait.Done()
+
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		go s.handle(conn)
+	}
+}
+
+func (s *Server) handle(conn net.Conn) {
+	defer func() { conn.Close() }()
+
+	reader := bufio.NewReader(conn)
+	bound := false
+	encrypted := false
+
+	for {
+		message, err := ber.ReadPacket(reader)
+		if err != nil || len(message.Children) < 2 {
+			return
+		}
+
+		messageId, _ := message.Children[0].Value.(int64)
+		op := message.Children[1]
+		if op.ClassType != ber.ClassApplication {
+			return
+		}
+
+		var responses []*ber.Packet
+		switch op.Tag {
+		case ldap.ApplicationExtendedRequest:
+			if len(op.Children) == 0 || op.Children[0].Data.String() != startTlsOid || encrypted {
+				responses = append(responses, newResult(ldap.ApplicationExtendedResponse, ldap.LDAPResultProtocolError, "unsupported extended operation"))
+				break
+			}
+
+			if !write(conn, messageId, newResult(ldap.ApplicationExtendedResponse, ldap.LDAPResultSuccess, "")) {
+				return
+			}
+
+			tlsConn := tls.Server(conn, s.tlsConfig)
+			if tlsConn.Handshake() != nil {
+				return
+			}
+
+			conn = tlsConn
+			reader = bufio.NewReader(conn)
+			encrypted = true
+			continue
+		case ldap.ApplicationBindRequest:
+			var resultCode uint16
+			bound, resultCode = s.bind(op, encrypted)
+			responses = append(responses, newResult(ldap.ApplicationBindResponse, resultCode, ""))
+		case ldap.ApplicationSearchRequest:
+			if s.RequireBind && !bound {
+				responses = append(responses, newResult(ldap.ApplicationSearchResultDone, ldap.LDAPResultOperationsError, "bind required"))
+				break
+			}
+			responses = append(responses, s.search(op)...)
+		case ldap.ApplicationUnbindRequest:
+			return
+		default:
+			responses = append(responses, newResult(ldap.ApplicationSearchResultDone, ldap.LDAPResultProtocolError, "unsupported operation"))
+		}
+
+		for _, response := range responses {
+			if !write(conn, messageId, response) {
+				return
+			}
+		}
+	}
+}
+
+func write(conn net.Conn, messageId int64, response *ber.Packet) bool {
+	message := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "LDAP Response")
+	message.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, messageId, "MessageID"))
+	message.AppendChild(response)
+
+	_, err := conn.Write(message.Bytes())
+	return err == nil
+}
+
+func newResult(tag ber.Tag, resultCode uint16, message string) *ber.Packet {
+	result := ber.Encode(ber.ClassApplication, ber.TypeConstructed, tag, nil, "Result")
+	result.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, int64(resultCode), "Result Code"))
+	result.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "Matched DN"))
+	result.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, message, "Diagnostic Message"))
+	return result
+}
+
+// bind refuses passwords sent before StartTLS
+func (s *Server) bind(op *ber.Packet, encrypted bool) (bool, uint16) {
+	if len(op.Children) < 3 {
+		return false, ldap.LDAPResultProtocolError
+	}
+
+	dn := op.Children[1].Data.String()
+	password := op.Children[2].Data.String()
+
+	// anonymous bind
+	if dn == "" && password == "" {
+		return false, ldap.LDAPResultSuccess
+	} else if !encrypted {
+		return false, ldap.LDAPResultConfidentialityRequired
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for _, entry := range s.entries {
+		if strings.EqualFold(entry.Dn, dn) && entry.Password != "" && entry.Password == password {
+			return true, ldap.LDAPResultSuccess
+		}
+	}
+
+	return false, ldap.LDAPResultInvalidCredentials
+}
+
+func (s *Server) search(op *ber.Packet) []*ber.Packet {
+	if len(op.Children) < 8 {
+		return []*ber.Packet{newResult(ldap.ApplicationSearchResultDone, ldap.LDAPResultProtocolError, "malformed search")}
+	}
+
+	baseDn := strings.ToLower(op.Children[0].Data.String())
+	filter := op.Children[6]
+
+	requested := map[string]bool{}
+	for _, attribute := range op.Children[7].Children {
+		requested[strings.ToLower(attribute.Data.String())] = true
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	responses := []*ber.Packet{}
+	for _, entry := range s.entries {
+		dn := strings.ToLower(entry.Dn)
+		if dn != baseDn && !strings.HasSuffix(dn, ","+baseDn) {
+			continue
+		}
+
+		if !matches(filter, entry) {
+			continue
+		}
+
+		result := ber.Encode(ber.ClassApplication, ber.TypeConstructed, ldap.ApplicationSearchResultEntry, nil, "Search Result Entry")
+		result.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, entry.Dn, "Object Name"))
+
+		attributes := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "Attributes")
+		for name, values := range entry.Attributes {
+			if len(requested) > 0 && !requested[strings.ToLower(name)] {
+				continue
+			}
+
+			attribute := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "Attribute")
+			attribute.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, name, "Type"))
+			set := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSet, nil, "Values")
+			for _, value := range values {
+				set.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, value, "Value"))
+			}
+			attribute.AppendChild(set)
+			attributes.AppendChild(attribute)
+		}
+		result.AppendChild(attributes)
+
+		responses = append(responses, result)
+	}
+
+	return append(responses, newResult(ldap.ApplicationSearchResultDone, ldap.LDAPResultSuccess, ""))
+}
+
+func matches(filter *ber.Packet, entry Entry) bool {
+	if filter.ClassType != ber.ClassContext {
+		return false
+	}
+
+	switch filter.Tag {
+	case ldap.FilterAnd:
+		for _, child := range filter.Children {
+			if !matches(child, entry) {
+				return false
+			}
+		}
+		return true
+	case ldap.FilterOr:
+		for _, child := range filter.Children {
+			if matches(child, entry) {
+				return true
+			}
+		}
+		return false
+	case ldap.FilterNot:
+		return len(filter.Children) == 1 && !matches(filter.Children[0], entry)
+	case ldap.FilterPresent:
+		return len(attributeValues(entry, filter.Data.String())) > 0
+	case ldap.FilterEqualityMatch:
+		if len(filter.Children) < 2 {
+			return false
+		}
+
+		for _, value := range attributeValues(entry, filter.Children[0].Data.String()) {
+			if strings.EqualFold(value, filter.Children[1].Data.String()) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return false
+}
+
+func attributeValues(entry Entry, attribute string) []string {
+	for name, values := range entry.Attributes {
+		if strings.EqualFold(name, attribute) {
+			return values
+		}
+	}
+	return nil
+}
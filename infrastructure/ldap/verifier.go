@@ -0,0 +1,182 @@
+package ldap
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/team-inu/inu-backyard/entity"
+	errs "github.com/team-inu/inu-backyard/entity/error"
+	"github.com/team-inu/inu-backyard/internal/config"
+)
+
+// Verifier checks passwords by binding as the user entry found by email
+type Verifier struct {
+	config    config.LdapConfig
+	tlsConfig *tls.Config
+	timeout   time.Duration
+}
+
+// NewVerifier refuses an ldap:// url without StartTLS since the passwords would be sent in clear text
+func NewVerifier(config config.LdapConfig) (*Verifier, error) {
+	parsedUrl, err := url.Parse(config.Url)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse ldap url: %w", err)
+	}
+
+	switch parsedUrl.Scheme {
+	case "ldaps":
+	case "ldap":
+		if !config.StartTls {
+			return nil, fmt.Errorf("ldap url %s must use ldaps or enable startTls", config.Url)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported ldap url scheme %s", parsedUrl.Scheme)
+	}
+
+	tlsConfig := &tls.Config{ServerName: parsedUrl.Hostname()}
+	if config.CaCertPath != "" {
+		caCert, err := os.ReadFile(config.CaCertPath)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read ldap ca certificate: %w", err)
+		}
+
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(caCert) {
+			return nil, fmt.Errorf("no certificate found in ldap ca certificate %s", config.CaCertPath)
+		}
+	}
+
+	timeout := time.Duration(config.Timeout) * time.Second
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+
+	return &Verifier{
+		config:    config,
+		tlsConfig: tlsConfig,
+		timeout:   timeout,
+	}, nil
+}
+
+func (v Verifier) Verify(email string, password string) (*entity.VerifiedCredential, error) {
+	// a simple bind with an empty password is an anonymous bind and always succeeds
+	if password == "" {
+		return nil, errs.New(errs.ErrUserPassword, "password is incorrect")
+	}
+
+	conn, err := v.dial()
+	if err != nil {
+		return nil, errs.New(errs.ErrLdap, "cannot connect to ldap server", err)
+	}
+	defer conn.Close()
+
+	if v.config.BindDn != "" {
+		err = conn.Bind(v.config.BindDn, v.config.BindPassword)
+		if err != nil {
+			return nil, errs.New(errs.ErrLdap, "cannot bind ldap service account", err)
+		}
+	}
+
+	entry, err := v.findUser(conn, email)
+	if err != nil {
+		return nil, err
+	} else if entry == nil {
+		return nil, nil
+	}
+
+	err = conn.Bind(entry.DN, password)
+	if ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials) {
+		return nil, errs.New(errs.ErrUserPassword, "password is incorrect")
+	} else if err != nil {
+		return nil, errs.New(errs.ErrLdap, "cannot bind ldap user %s", entry.DN, err)
+	}
+
+	return &entity.VerifiedCredential{
+		Email:       email,
+		FirstNameEN: entry.GetEqualFoldAttributeValue(v.config.FirstNameAttribute),
+		LastNameEN:  entry.GetEqualFoldAttributeValue(v.config.LastNameAttribute),
+		Roles:       v.mapRoles(entry.GetEqualFoldAttributeValues(v.config.GroupAttribute)),
+		CreateUser:  v.config.CreateUsers,
+	}, nil
+}
+
+func (v Verifier) dial() (*ldap.Conn, error) {
+	conn, err := ldap.DialURL(v.config.Url, ldap.DialWithDialer(&net.Dialer{Timeout: v.timeout}), ldap.DialWithTLSConfig(v.tlsConfig))
+	if err != nil {
+		return nil, err
+	}
+	conn.SetTimeout(v.timeout)
+
+	if v.config.StartTls {
+		err = conn.StartTLS(v.tlsConfig)
+		if err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+
+	return conn, nil
+}
+
+func (v Verifier) findUser(conn *ldap.Conn, email string) (*ldap.Entry, error) {
+	filter := strings.ReplaceAll(v.config.UserFilter, "%s", ldap.EscapeFilter(email))
+
+	attributes := []string{}
+	for _, attribute := range []string{v.config.FirstNameAttribute, v.config.LastNameAttribute, v.config.GroupAttribute} {
+		if attribute != "" {
+			attributes = append(attributes, attribute)
+		}
+	}
+
+	result, err := conn.Search(ldap.NewSearchRequest(
+		v.config.BaseDn,
+		ldap.ScopeWholeSubtree,
+		ldap.NeverDerefAliases,
+		0,
+		int(v.timeout.Seconds()),
+		false,
+		filter,
+		attributes,
+		nil,
+	))
+	if err != nil {
+		return nil, errs.New(errs.ErrLdap, "cannot search ldap user %s", email, err)
+	}
+
+	if len(result.Entries) == 0 {
+		return nil, nil
+	} else if len(result.Entries) > 1 {
+		return nil, errs.New(errs.ErrLdap, "cannot sign in", fmt.Errorf("%d ldap entries match email %s", len(result.Entries), email))
+	}
+
+	return result.Entries[0], nil
+}
+
+// mapRoles compares group names case insensitively as distinguished names are
+func (v Verifier) mapRoles(groups []string) []entity.UserRole {
+	roles := []entity.UserRole{}
+	mapped := map[entity.UserRole]bool{}
+	for _, groupRole := range v.config.GroupRoles {
+		role := entity.UserRole(groupRole.Role)
+		if mapped[role] {
+			continue
+		}
+
+		for _, group := range groups {
+			if strings.EqualFold(strings.TrimSpace(group), strings.TrimSpace(groupRole.Group)) {
+				roles = append(roles, role)
+				mapped[role] = true
+				break
+			}
+		}
+	}
+
+	return roles
+}
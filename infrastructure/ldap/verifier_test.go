@@ -0,0 +1,171 @@
+package ldap
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/team-inu/inu-backyard/entity"
+	errs "github.com/team-inu/inu-backyard/entity/error"
+	"github.com/team-inu/inu-backyard/infrastructure/ldap/ldaptest"
+	"github.com/team-inu/inu-backyard/internal/config"
+)
+
+const (
+	serviceDn      = "cn=service,ou=accounts,dc=example,dc=com"
+	curriculumHead = "cn=curriculum-heads,ou=groups,dc=example,dc=com"
+)
+
+func setupTestServer(t *testing.T) *ldaptest.Server {
+	server, err := ldaptest.NewServer(
+		ldaptest.Entry{
+			Dn:       serviceDn,
+			Password: "service-secret",
+		},
+		ldaptest.Entry{
+			Dn:       "cn=somchai,ou=people,dc=example,dc=com",
+			Password: "somchai-secret",
+			Attributes: map[string][]string{
+				"objectClass": {"user"},
+				"mail":        {"somchai@example.com"},
+				"givenName":   {"Somchai"},
+				"sn":          {"Jaidee"},
+				"memberOf":    {"CN=Curriculum-Heads,OU=Groups,DC=example,DC=com", "cn=staff,ou=groups,dc=example,dc=com"},
+			},
+		},
+		ldaptest.Entry{
+			Dn:       "cn=malee,ou=people,dc=example,dc=com",
+			Password: "malee-secret",
+			Attributes: map[string][]string{
+				"objectClass": {"user"},
+				"mail":        {"malee@example.com"},
+				"givenName":   {"Malee"},
+				"sn":          {"Sukjai"},
+			},
+		},
+	)
+	if err != nil {
+		t.Fatalf("Failed to start ldap server: %v", err)
+	}
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func testConfig(t *testing.T, server *ldaptest.Server) config.LdapConfig {
+	caCertPath := filepath.Join(t.TempDir(), "ca.pem")
+	err := os.WriteFile(caCertPath, server.CaCertPem(), 0600)
+	if err != nil {
+		t.Fatalf("Failed to write ldap ca certificate: %v", err)
+	}
+
+	return config.LdapConfig{
+		Enabled:            true,
+		Url:                server.Url(),
+		StartTls:           true,
+		CaCertPath:         caCertPath,
+		BindDn:             serviceDn,
+		BindPassword:       "service-secret",
+		BaseDn:             "dc=example,dc=com",
+		UserFilter:         "(&(objectClass=user)(mail=%s))",
+		FirstNameAttribute: "givenName",
+		LastNameAttribute:  "sn",
+		GroupAttribute:     "memberOf",
+		GroupRoles: []config.LdapGroupRole{
+			{Group: curriculumHead, Role: string(entity.UserRoleHeadOfCurriculum)},
+		},
+		CreateUsers: true,
+		Timeout:     5,
+	}
+}
+
+func errorCode(err error) int {
+	if domainErr, ok := err.(*errs.DomainError); ok {
+		return domainErr.Code
+	}
+	return 0
+}
+
+func TestVerifier(t *testing.T) {
+	server := setupTestServer(t)
+	verifier, err := NewVerifier(testConfig(t, server))
+	if err != nil {
+		t.Fatalf("Failed to create verifier: %v", err)
+	}
+
+	t.Run("TestVerify", func(t *testing.T) {
+		credential, err := verifier.Verify("somchai@example.com", "somchai-secret")
+		assert.Nil(t, err, "Expected no error while verifying, got %v", err)
+		assert.NotNil(t, credential, "Expected a credential")
+		assert.Equal(t, "Somchai", credential.FirstNameEN, "Expected first name from directory")
+		assert.Equal(t, "Jaidee", credential.LastNameEN, "Expected last name from directory")
+		assert.Equal(t, []entity.UserRole{entity.UserRoleHeadOfCurriculum}, credential.Roles, "Expected group to map to role")
+		assert.True(t, credential.CreateUser, "Expected user creation to follow config")
+	})
+
+	t.Run("TestVerify_NoMappedGroup", func(t *testing.T) {
+		credential, err := verifier.Verify("malee@example.com", "malee-secret")
+		assert.Nil(t, err, "Expected no error while verifying, got %v", err)
+		assert.Empty(t, credential.Roles, "Expected no role without mapped group")
+	})
+
+	t.Run("TestVerify_WrongPassword", func(t *testing.T) {
+		credential, err := verifier.Verify("somchai@example.com", "wrong")
+		assert.Nil(t, credential)
+		assert.Equal(t, errs.ErrUserPassword, errorCode(err), "Expected password error, got %v", err)
+	})
+
+	t.Run("TestVerify_EmptyPassword", func(t *testing.T) {
+		credential, err := verifier.Verify("somchai@example.com", "")
+		assert.Nil(t, credential)
+		assert.Equal(t, errs.ErrUserPassword, errorCode(err), "Expected empty password to be refused, got %v", err)
+	})
+
+	t.Run("TestVerify_UnknownEmail", func(t *testing.T) {
+		credential, err := verifier.Verify("nobody@example.com", "whatever")
+		assert.Nil(t, err, "Expected no error for unknown email, got %v", err)
+		assert.Nil(t, credential, "Expected no credential for unknown email")
+	})
+
+	t.Run("TestVerify_FilterInjection", func(t *testing.T) {
+		credential, err := verifier.Verify("*)(mail=*", "somchai-secret")
+		assert.Nil(t, err, "Expected no error, got %v", err)
+		assert.Nil(t, credential, "Expected escaped email to match nothing")
+	})
+
+	t.Run("TestVerify_WrongServiceAccount", func(t *testing.T) {
+		config := testConfig(t, server)
+		config.BindPassword = "wrong"
+
+		verifier, _ := NewVerifier(config)
+		_, err := verifier.Verify("somchai@example.com", "somchai-secret")
+		assert.Equal(t, errs.ErrLdap, errorCode(err), "Expected ldap error, got %v", err)
+	})
+
+	t.Run("TestVerify_ServerDown", func(t *testing.T) {
+		config := testConfig(t, server)
+		config.Url = "ldap://127.0.0.1:1"
+
+		verifier, _ := NewVerifier(config)
+		_, err := verifier.Verify("somchai@example.com", "somchai-secret")
+		assert.Equal(t, errs.ErrLdap, errorCode(err), "Expected ldap error, got %v", err)
+	})
+
+	t.Run("TestVerify_UntrustedCertificate", func(t *testing.T) {
+		config := testConfig(t, server)
+		config.CaCertPath = ""
+
+		verifier, _ := NewVerifier(config)
+		_, err := verifier.Verify("somchai@example.com", "somchai-secret")
+		assert.Equal(t, errs.ErrLdap, errorCode(err), "Expected a self-signed certificate to be refused, got %v", err)
+	})
+
+	t.Run("TestNewVerifier_PlainLdap", func(t *testing.T) {
+		config := testConfig(t, server)
+		config.StartTls = false
+
+		_, err := NewVerifier(config)
+		assert.NotNil(t, err, "Expected ldap:// without StartTLS to be refused")
+	})
+}
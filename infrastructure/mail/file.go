@@ -0,0 +1,47 @@
+package mail
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/team-inu/inu-backyard/entity"
+)
+
+// FileMailer writes each message as an .eml file, it lets developers read emails without an SMTP server
+type FileMailer struct {
+	directory string
+	from      string
+}
+
+func NewFileMailer(directory string, from string) (*FileMailer, error) {
+	if directory == "" {
+		return nil, fmt.Errorf("mail directory is not set")
+	}
+
+	err := os.MkdirAll(directory, 0o755)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create mail directory: %w", err)
+	}
+
+	return &FileMailer{
+		directory: directory,
+		from:      from,
+	}, nil
+}
+
+func (m FileMailer) Send(message entity.MailMessage) error {
+	file, err := os.Create(filepath.Join(m.directory, ulid.Make().String()+".eml"))
+	if err != nil {
+		return fmt.Errorf("cannot create mail file: %w", err)
+	}
+	defer file.Close()
+
+	_, err = newMessage(m.from, message).WriteTo(file)
+	if err != nil {
+		return fmt.Errorf("cannot write mail file: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,86 @@
+package mail
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/team-inu/inu-backyard/entity"
+	"github.com/team-inu/inu-backyard/internal/config"
+)
+
+func TestRenderer(t *testing.T) {
+	renderer, err := NewRenderer()
+	if err != nil {
+		t.Fatalf("Failed to parse mail templates: %v", err)
+	}
+
+	expiresAt := time.Date(2025, time.January, 2, 10, 0, 0, 0, time.UTC)
+	invitation := map[string]interface{}{
+		"Name":        "<b>Somchai</b>",
+		"SurveyTitle": "Alumni & Employer Survey",
+		"Link":        "http://localhost:3000/surveys/respond?token=abc",
+		"ExpiresAt":   expiresAt,
+	}
+
+	t.Run("TestRenderEnglish", func(t *testing.T) {
+		subject, body, err := renderer.Render(entity.MailTemplateSurveyInvitation, "en", invitation)
+		assert.Nil(t, err, "Expected no error while rendering, got %v", err)
+		assert.Equal(t, "Alumni & Employer Survey", subject, "Expected subject not to be html escaped")
+		assert.Contains(t, body, "2 January 2025")
+		assert.Contains(t, body, `lang="en"`)
+		assert.Contains(t, body, "&lt;b&gt;Somchai&lt;/b&gt;", "Expected data to be html escaped")
+	})
+
+	t.Run("TestRenderThai", func(t *testing.T) {
+		_, body, err := renderer.Render(entity.MailTemplateSurveyInvitation, "th", invitation)
+		assert.Nil(t, err, "Expected no error while rendering, got %v", err)
+		assert.Contains(t, body, "2 มกราคม 2568", "Expected Thai date in the Buddhist era")
+	})
+
+	t.Run("TestRenderAllTemplates", func(t *testing.T) {
+		data := map[string]interface{}{"Code": "123456", "ExpireIn": 15, "ResetUrl": "http://localhost:3000/reset-password", "LockedUntil": expiresAt}
+		for _, language := range languages {
+			for _, name := range []entity.MailTemplate{entity.MailTemplateForgotPassword, entity.MailTemplateAccountLocked} {
+				subject, _, err := renderer.Render(name, language, data)
+				assert.Nil(t, err, "Expected no error while rendering %s in %s, got %v", name, language, err)
+				assert.NotEmpty(t, subject, "Expected subject of %s in %s", name, language)
+			}
+		}
+	})
+
+	t.Run("TestUnknownLanguage", func(t *testing.T) {
+		_, _, err := renderer.Render(entity.MailTemplateForgotPassword, "fr", nil)
+		assert.NotNil(t, err, "Expected error for unknown language")
+	})
+}
+
+func TestMailer(t *testing.T) {
+	t.Run("TestFileMailer", func(t *testing.T) {
+		directory := t.TempDir()
+		mailer, err := NewMailer(config.MailConfig{Driver: "file", Directory: directory, From: "no-reply@example.com"})
+		assert.Nil(t, err, "Expected no error while creating mailer, got %v", err)
+
+		err = mailer.Send(entity.MailMessage{To: "lecturer@example.com", Subject: "Hello", Html: "<p>Hi</p>"})
+		assert.Nil(t, err, "Expected no error while sending, got %v", err)
+
+		files, _ := filepath.Glob(filepath.Join(directory, "*.eml"))
+		assert.Len(t, files, 1, "Expected one mail file")
+
+		content, _ := os.ReadFile(files[0])
+		assert.True(t, strings.Contains(string(content), "To: lecturer@example.com"), "Expected recipient header in file")
+	})
+
+	t.Run("TestSmtpWithoutHost", func(t *testing.T) {
+		_, err := NewMailer(config.MailConfig{Driver: "smtp", From: "no-reply@example.com"})
+		assert.NotNil(t, err, "Expected error when smtp host is not set")
+	})
+
+	t.Run("TestUnknownDriver", func(t *testing.T) {
+		_, err := NewMailer(config.MailConfig{Driver: "pigeon"})
+		assert.NotNil(t, err, "Expected error for unknown driver")
+	})
+}
@@ -0,0 +1,39 @@
+package mail
+
+import (
+	"sync"
+
+	"github.com/team-inu/inu-backyard/entity"
+)
+
+// MemoryMailer keeps sent messages, it is used in tests and local development
+type MemoryMailer struct {
+	// Send returns this error when it is set
+	Err error
+
+	mutex    sync.Mutex
+	messages []entity.MailMessage
+}
+
+func NewMemoryMailer() *MemoryMailer {
+	return &MemoryMailer{}
+}
+
+func (m *MemoryMailer) Send(message entity.MailMessage) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.Err != nil {
+		return m.Err
+	}
+
+	m.messages = append(m.messages, message)
+	return nil
+}
+
+func (m *MemoryMailer) Messages() []entity.MailMessage {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	return append([]entity.MailMessage{}, m.messages...)
+}
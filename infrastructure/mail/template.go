@@ -0,0 +1,109 @@
+package mail
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"html"
+	"html/template"
+	"strings"
+	"time"
+
+	"github.com/team-inu/inu-backyard/entity"
+)
+
+//go:embed templates
+var templateFS embed.FS
+
+var (
+	languages = []string{"th", "en"}
+	templates = []entity.MailTemplate{
+		entity.MailTemplateForgotPassword,
+		entity.MailTemplateAccountLocked,
+		entity.MailTemplateSurveyInvitation,
+		entity.MailTemplateNotification,
+		entity.MailTemplateFeedbackLink,
+	}
+)
+
+// dates in emails are shown in Thailand time, which has no daylight saving
+var thailandTime = time.FixedZone("ICT", 7*60*60)
+
+var thaiMonths = []string{
+	"มกราคม", "กุมภาพันธ์", "มีนาคม", "เมษายน", "พฤษภาคม", "มิถุนายน",
+	"กรกฎาคม", "สิงหาคม", "กันยายน", "ตุลาคม", "พฤศจิกายน", "ธันวาคม",
+}
+
+// Renderer renders the templates under templates/<language>/<template>.html inside layout.html,
+// each template defines a "subject" and a "content" block
+type Renderer struct {
+	templates map[string]*template.Template
+}
+
+func NewRenderer() (*Renderer, error) {
+	renderer := &Renderer{templates: map[string]*template.Template{}}
+
+	for _, language := range languages {
+		for _, name := range templates {
+			path := fmt.Sprintf("templates/%s/%s.html", language, name)
+
+			parsed, err := template.New(string(name)).Funcs(templateFuncs(language)).ParseFS(templateFS, "templates/layout.html", path)
+			if err != nil {
+				return nil, fmt.Errorf("cannot parse mail template %s: %w", path, err)
+			}
+
+			renderer.templates[templateKey(name, language)] = parsed
+		}
+	}
+
+	return renderer, nil
+}
+
+func (r Renderer) Render(name entity.MailTemplate, language string, data interface{}) (string, string, error) {
+	parsed, ok := r.templates[templateKey(name, language)]
+	if !ok {
+		return "", "", fmt.Errorf("mail template %s in language %s not found", name, language)
+	}
+
+	var subject bytes.Buffer
+	err := parsed.ExecuteTemplate(&subject, "subject", data)
+	if err != nil {
+		return "", "", fmt.Errorf("cannot render subject of mail template %s: %w", name, err)
+	}
+
+	var body bytes.Buffer
+	err = parsed.ExecuteTemplate(&body, "layout", map[string]interface{}{
+		"Language": language,
+		"Data":     data,
+	})
+	if err != nil {
+		return "", "", fmt.Errorf("cannot render mail template %s: %w", name, err)
+	}
+
+	// the subject is a header, not html, so the escaping done by the template is undone
+	return html.UnescapeString(strings.TrimSpace(subject.String())), body.String(), nil
+}
+
+func templateKey(name entity.MailTemplate, language string) string {
+	return language + "/" + string(name)
+}
+
+func templateFuncs(language string) template.FuncMap {
+	return template.FuncMap{
+		"date": func(t time.Time) string {
+			return formatDate(t, language)
+		},
+		"datetime": func(t time.Time) string {
+			return formatDate(t, language) + " " + t.In(thailandTime).Format("15:04")
+		},
+	}
+}
+
+// formatDate writes Thai dates in the Buddhist era, e.g. 2 มกราคม 2568
+func formatDate(t time.Time, language string) string {
+	t = t.In(thailandTime)
+	if language == "th" {
+		return fmt.Sprintf("%d %s %d", t.Day(), thaiMonths[t.Month()-1], t.Year()+543)
+	}
+	return t.Format("2 January 2006")
+}
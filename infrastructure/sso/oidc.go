@@ -0,0 +1,123 @@
+package sso
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/team-inu/inu-backyard/entity"
+	"github.com/team-inu/inu-backyard/internal/config"
+	"golang.org/x/oauth2"
+)
+
+// OidcProvider signs in with the authorization code flow and PKCE, id tokens are verified by go-oidc
+type OidcProvider struct {
+	config config.OidcConfig
+	client *http.Client
+
+	mutex    sync.Mutex
+	provider *oidc.Provider
+}
+
+func NewOidcProvider(config config.OidcConfig) *OidcProvider {
+	return &OidcProvider{
+		config: config,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (p *OidcProvider) AuthCodeUrl(state string, nonce string, codeChallenge string) (string, error) {
+	oauth2Config, _, err := p.getOauth2Config()
+	if err != nil {
+		return "", err
+	}
+
+	return oauth2Config.AuthCodeURL(
+		state,
+		oidc.Nonce(nonce),
+		oauth2.SetAuthURLParam("code_challenge", codeChallenge),
+		oauth2.SetAuthURLParam("code_challenge_method", "S256"),
+	), nil
+}
+
+func (p *OidcProvider) Exchange(code string, codeVerifier string, nonce string) (*entity.SsoIdentity, error) {
+	oauth2Config, provider, err := p.getOauth2Config()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := oidc.ClientContext(context.Background(), p.client)
+
+	token, err := oauth2Config.Exchange(ctx, code, oauth2.VerifierOption(codeVerifier))
+	if err != nil {
+		return nil, fmt.Errorf("cannot exchange code: %w", err)
+	}
+
+	rawIdToken, ok := token.Extra("id_token").(string)
+	if !ok || rawIdToken == "" {
+		return nil, fmt.Errorf("token response has no id token")
+	}
+
+	idToken, err := provider.Verifier(&oidc.Config{ClientID: p.config.ClientId}).Verify(ctx, rawIdToken)
+	if err != nil {
+		return nil, fmt.Errorf("cannot verify id token: %w", err)
+	} else if idToken.Nonce != nonce {
+		return nil, fmt.Errorf("id token nonce mismatch")
+	}
+
+	claims := map[string]interface{}{}
+	err = idToken.Claims(&claims)
+	if err != nil {
+		return nil, fmt.Errorf("cannot decode id token claims: %w", err)
+	}
+
+	emailClaim := p.config.EmailClaim
+	if emailClaim == "" {
+		emailClaim = "email"
+	}
+
+	email, _ := claims[emailClaim].(string)
+	if email == "" {
+		return nil, fmt.Errorf("id token has no %s claim", emailClaim)
+	}
+
+	if verified, ok := claims["email_verified"].(bool); ok && !verified {
+		return nil, fmt.Errorf("email %s is not verified by the identity provider", email)
+	}
+
+	return &entity.SsoIdentity{
+		Subject: idToken.Subject,
+		Email:   email,
+	}, nil
+}
+
+// getOauth2Config discovers the provider on first use, a failed discovery is tried again on the next sign in
+func (p *OidcProvider) getOauth2Config() (*oauth2.Config, *oidc.Provider, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.provider == nil {
+		provider, err := oidc.NewProvider(oidc.ClientContext(context.Background(), p.client), p.config.Issuer)
+		if err != nil {
+			return nil, nil, fmt.Errorf("cannot get openid configuration: %w", err)
+		}
+
+		p.provider = provider
+	}
+
+	scopes := p.config.Scopes
+	if len(scopes) == 0 {
+		scopes = []string{oidc.ScopeOpenID, "email"}
+	}
+
+	return &oauth2.Config{
+		ClientID:     p.config.ClientId,
+		ClientSecret: p.config.ClientSecret,
+		Endpoint:     p.provider.Endpoint(),
+		RedirectURL:  p.config.RedirectUrl,
+		Scopes:       scopes,
+	}, p.provider, nil
+}
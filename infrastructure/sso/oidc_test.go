@@ -0,0 +1,143 @@
+package sso
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/team-inu/inu-backyard/infrastructure/sso/ssotest"
+	"github.com/team-inu/inu-backyard/internal/config"
+)
+
+const testRedirectUrl = "http://localhost:3001/auth/sso/callback"
+
+// login follows the provider login page and returns the code and state sent back to the callback
+func login(t *testing.T, authUrl string) (string, string) {
+	client := &http.Client{
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	resp, err := client.Get(authUrl)
+	if err != nil {
+		t.Fatalf("Failed to open login page: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusFound {
+		t.Fatalf("Expected redirect from login page, got status %d", resp.StatusCode)
+	}
+
+	location, err := url.Parse(resp.Header.Get("Location"))
+	if err != nil {
+		t.Fatalf("Failed to parse callback address: %v", err)
+	}
+
+	return location.Query().Get("code"), location.Query().Get("state")
+}
+
+func TestOidcProvider(t *testing.T) {
+	idp, err := ssotest.NewIdentityProvider("inu-backyard", "secret")
+	if err != nil {
+		t.Fatalf("Failed to start identity provider: %v", err)
+	}
+	defer idp.Close()
+
+	provider := NewOidcProvider(config.OidcConfig{
+		Enabled:      true,
+		Issuer:       idp.Issuer(),
+		ClientId:     "inu-backyard",
+		ClientSecret: "secret",
+		RedirectUrl:  testRedirectUrl,
+	})
+
+	t.Run("TestExchange", func(t *testing.T) {
+		idp.Email = "lecturer@example.com"
+		codeVerifier, _ := GenerateRandomString()
+
+		authUrl, err := provider.AuthCodeUrl("state", "nonce", CodeChallenge(codeVerifier))
+		assert.Nil(t, err, "Expected no error while building login address, got %v", err)
+
+		code, state := login(t, authUrl)
+		assert.Equal(t, "state", state, "Expected state to be sent back")
+
+		identity, err := provider.Exchange(code, codeVerifier, "nonce")
+		assert.Nil(t, err, "Expected no error while exchanging code, got %v", err)
+		assert.NotNil(t, identity, "Expected an identity")
+		assert.Equal(t, "lecturer@example.com", identity.Email, "Expected email claim to match")
+	})
+
+	t.Run("TestExchange_WrongCodeVerifier", func(t *testing.T) {
+		codeVerifier, _ := GenerateRandomString()
+		authUrl, _ := provider.AuthCodeUrl("state", "nonce", CodeChallenge(codeVerifier))
+		code, _ := login(t, authUrl)
+
+		identity, err := provider.Exchange(code, "not-the-verifier", "nonce")
+		assert.NotNil(t, err, "Expected error when code verifier does not match the challenge")
+		assert.Nil(t, identity)
+	})
+
+	t.Run("TestExchange_CodeReused", func(t *testing.T) {
+		codeVerifier, _ := GenerateRandomString()
+		authUrl, _ := provider.AuthCodeUrl("state", "nonce", CodeChallenge(codeVerifier))
+		code, _ := login(t, authUrl)
+
+		_, err := provider.Exchange(code, codeVerifier, "nonce")
+		assert.Nil(t, err, "Expected no error on first exchange, got %v", err)
+
+		_, err = provider.Exchange(code, codeVerifier, "nonce")
+		assert.NotNil(t, err, "Expected error when the code is exchanged twice")
+	})
+
+	t.Run("TestExchange_NonceMismatch", func(t *testing.T) {
+		codeVerifier, _ := GenerateRandomString()
+		authUrl, _ := provider.AuthCodeUrl("state", "nonce", CodeChallenge(codeVerifier))
+		code, _ := login(t, authUrl)
+
+		_, err := provider.Exchange(code, codeVerifier, "other-nonce")
+		assert.NotNil(t, err, "Expected error when nonce does not match")
+	})
+
+	t.Run("TestExchange_ExpiredToken", func(t *testing.T) {
+		idp.TokenTTL = -time.Hour
+		defer func() { idp.TokenTTL = time.Hour }()
+
+		codeVerifier, _ := GenerateRandomString()
+		authUrl, _ := provider.AuthCodeUrl("state", "nonce", CodeChallenge(codeVerifier))
+		code, _ := login(t, authUrl)
+
+		_, err := provider.Exchange(code, codeVerifier, "nonce")
+		assert.NotNil(t, err, "Expected error when id token is expired")
+	})
+
+	t.Run("TestExchange_UnverifiedEmail", func(t *testing.T) {
+		idp.EmailVerified = false
+		defer func() { idp.EmailVerified = true }()
+
+		codeVerifier, _ := GenerateRandomString()
+		authUrl, _ := provider.AuthCodeUrl("state", "nonce", CodeChallenge(codeVerifier))
+		code, _ := login(t, authUrl)
+
+		_, err := provider.Exchange(code, codeVerifier, "nonce")
+		assert.NotNil(t, err, "Expected error when email is not verified")
+	})
+
+	t.Run("TestExchange_WrongClientSecret", func(t *testing.T) {
+		otherProvider := NewOidcProvider(config.OidcConfig{
+			Issuer:       idp.Issuer(),
+			ClientId:     "inu-backyard",
+			ClientSecret: "wrong",
+			RedirectUrl:  testRedirectUrl,
+		})
+
+		codeVerifier, _ := GenerateRandomString()
+		authUrl, _ := otherProvider.AuthCodeUrl("state", "nonce", CodeChallenge(codeVerifier))
+		code, _ := login(t, authUrl)
+
+		_, err := otherProvider.Exchange(code, codeVerifier, "nonce")
+		assert.NotNil(t, err, "Expected error when client secret is wrong")
+	})
+}
@@ -0,0 +1,226 @@
+// Package ssotest provides an in-process OpenID Connect identity provider for tests.
+package ssotest
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/go-jose/go-jose/v4"
+)
+
+const keyId = "ssotest"
+
+type authorization struct {
+	clientId      string
+	redirectUrl   string
+	nonce         string
+	codeChallenge string
+	email         string
+}
+
+// IdentityProvider signs in whoever Email is set to, the same way a real provider would after its login page
+type IdentityProvider struct {
+	Server       *httptest.Server
+	ClientId     string
+	ClientSecret string
+
+	Email         string
+	EmailVerified bool
+	// lifetime of issued id tokens, negative to issue expired tokens
+	TokenTTL time.Duration
+
+	key            *rsa.PrivateKey
+	mutex          sync.Mutex
+	authorizations map[string]authorization
+}
+
+func NewIdentityProvider(clientId string, clientSecret string) (*IdentityProvider, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, err
+	}
+
+	idp := &IdentityProvider{
+		ClientId:       clientId,
+		ClientSecret:   clientSecret,
+		EmailVerified:  true,
+		TokenTTL:       time.Hour,
+		key:            key,
+		authorizations: map[string]authorization{},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", idp.discovery)
+	mux.HandleFunc("/authorize", idp.authorize)
+	mux.HandleFunc("/token", idp.token)
+	mux.HandleFunc("/jwks", idp.jwks)
+	idp.Server = httptest.NewServer(mux)
+
+	return idp, nil
+}
+
+func (idp *IdentityProvider) Issuer() string {
+	return idp.Server.URL
+}
+
+func (idp *IdentityProvider) Close() {
+	idp.Server.Close()
+}
+
+func (idp *IdentityProvider) discovery(w http.ResponseWriter, r *http.Request) {
+	writeJson(w, http.StatusOK, map[string]interface{}{
+		"issuer":                                idp.Issuer(),
+		"authorization_endpoint":                idp.Issuer() + "/authorize",
+		"token_endpoint":                        idp.Issuer() + "/token",
+		"jwks_uri":                              idp.Issuer() + "/jwks",
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+		"token_endpoint_auth_methods_supported": []string{"client_secret_basic", "client_secret_post"},
+	})
+}
+
+func (idp *IdentityProvider) authorize(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	if query.Get("response_type") != "code" || query.Get("client_id") != idp.ClientId {
+		http.Error(w, "invalid client or response type", http.StatusBadRequest)
+		return
+	} else if query.Get("code_challenge") == "" || query.Get("code_challenge_method") != "S256" {
+		http.Error(w, "PKCE with S256 is required", http.StatusBadRequest)
+		return
+	}
+
+	redirectUrl, err := url.Parse(query.Get("redirect_uri"))
+	if err != nil || redirectUrl.Scheme == "" {
+		http.Error(w, "invalid redirect uri", http.StatusBadRequest)
+		return
+	}
+
+	code := randomString()
+
+	idp.mutex.Lock()
+	idp.authorizations[code] = authorization{
+		clientId:      query.Get("client_id"),
+		redirectUrl:   query.Get("redirect_uri"),
+		nonce:         query.Get("nonce"),
+		codeChallenge: query.Get("code_challenge"),
+		email:         idp.Email,
+	}
+	idp.mutex.Unlock()
+
+	callbackQuery := redirectUrl.Query()
+	callbackQuery.Set("code", code)
+	callbackQuery.Set("state", query.Get("state"))
+	redirectUrl.RawQuery = callbackQuery.Encode()
+
+	http.Redirect(w, r, redirectUrl.String(), http.StatusFound)
+}
+
+func (idp *IdentityProvider) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeTokenError(w, "invalid_request")
+		return
+	}
+
+	code := r.PostForm.Get("code")
+	clientId, clientSecret, ok := r.BasicAuth()
+	if !ok {
+		clientId, clientSecret = r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+	}
+
+	// a code can be exchanged only once
+	idp.mutex.Lock()
+	auth, ok := idp.authorizations[code]
+	delete(idp.authorizations, code)
+	idp.mutex.Unlock()
+
+	if !ok || r.PostForm.Get("grant_type") != "authorization_code" {
+		writeTokenError(w, "invalid_grant")
+		return
+	} else if clientId != auth.clientId || clientSecret != idp.ClientSecret {
+		writeTokenError(w, "invalid_client")
+		return
+	} else if r.PostForm.Get("redirect_uri") != auth.redirectUrl {
+		writeTokenError(w, "invalid_grant")
+		return
+	}
+
+	challenge := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if base64.RawURLEncoding.EncodeToString(challenge[:]) != auth.codeChallenge {
+		writeTokenError(w, "invalid_grant")
+		return
+	}
+
+	now := time.Now()
+	idToken, err := idp.sign(map[string]interface{}{
+		"iss":            idp.Issuer(),
+		"sub":            auth.email,
+		"aud":            auth.clientId,
+		"iat":            now.Unix(),
+		"exp":            now.Add(idp.TokenTTL).Unix(),
+		"nonce":          auth.nonce,
+		"email":          auth.email,
+		"email_verified": idp.EmailVerified,
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	writeJson(w, http.StatusOK, map[string]string{
+		"access_token": randomString(),
+		"token_type":   "Bearer",
+		"id_token":     idToken,
+	})
+}
+
+func (idp *IdentityProvider) jwks(w http.ResponseWriter, r *http.Request) {
+	writeJson(w, http.StatusOK, jose.JSONWebKeySet{
+		Keys: []jose.JSONWebKey{{Key: &idp.key.PublicKey, KeyID: keyId, Algorithm: string(jose.RS256), Use: "sig"}},
+	})
+}
+
+func (idp *IdentityProvider) sign(claims map[string]interface{}) (string, error) {
+	signer, err := jose.NewSigner(
+		jose.SigningKey{Algorithm: jose.RS256, Key: jose.JSONWebKey{Key: idp.key, KeyID: keyId}},
+		(&jose.SignerOptions{}).WithType("JWT"),
+	)
+	if err != nil {
+		return "", err
+	}
+
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	signature, err := signer.Sign(payload)
+	if err != nil {
+		return "", err
+	}
+
+	return signature.CompactSerialize()
+}
+
+func writeJson(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+func writeTokenError(w http.ResponseWriter, code string) {
+	writeJson(w, http.StatusBadRequest, map[string]string{"error": code})
+}
+
+func randomString() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
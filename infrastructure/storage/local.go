@@ -0,0 +1,86 @@
+package storage
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// LocalStore keeps files under a directory, the key is the path relative to it
+type LocalStore struct {
+	directory string
+}
+
+func NewLocalStore(directory string) (*LocalStore, error) {
+	if directory == "" {
+		directory = filepath.Join("output", "files")
+	}
+
+	err := os.MkdirAll(directory, os.ModePerm)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create storage directory %s: %w", directory, err)
+	}
+
+	return &LocalStore{directory: directory}, nil
+}
+
+// Put writes to a temporary file first so a reader never sees a partial file
+func (s LocalStore) Put(key string, contentType string, content io.Reader, size int64) error {
+	if err := validateKey(key); err != nil {
+		return err
+	}
+
+	path := filepath.Join(s.directory, filepath.FromSlash(key))
+	err := os.MkdirAll(filepath.Dir(path), os.ModePerm)
+	if err != nil {
+		return fmt.Errorf("cannot create directory of file %s: %w", key, err)
+	}
+
+	temp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("cannot create file %s: %w", key, err)
+	}
+	defer os.Remove(temp.Name())
+
+	_, err = io.Copy(temp, content)
+	if closeErr := temp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("cannot write file %s: %w", key, err)
+	}
+
+	err = os.Rename(temp.Name(), path)
+	if err != nil {
+		return fmt.Errorf("cannot move file %s into place: %w", key, err)
+	}
+
+	return nil
+}
+
+func (s LocalStore) Open(key string) (io.ReadCloser, error) {
+	if err := validateKey(key); err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(filepath.Join(s.directory, filepath.FromSlash(key)))
+	if err != nil {
+		return nil, fmt.Errorf("cannot open file %s: %w", key, err)
+	}
+
+	return file, nil
+}
+
+func (s LocalStore) Delete(key string) error {
+	if err := validateKey(key); err != nil {
+		return err
+	}
+
+	err := os.Remove(filepath.Join(s.directory, filepath.FromSlash(key)))
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("cannot delete file %s: %w", key, err)
+	}
+
+	return nil
+}
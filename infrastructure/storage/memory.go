@@ -0,0 +1,74 @@
+package storage
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// MemoryStore keeps files in memory, it is used in tests and local development
+type MemoryStore struct {
+	// Put returns this error when it is set
+	Err error
+
+	mutex sync.Mutex
+	files map[string][]byte
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{files: map[string][]byte{}}
+}
+
+func (s *MemoryStore) Put(key string, contentType string, content io.Reader, size int64) error {
+	if err := validateKey(key); err != nil {
+		return err
+	}
+
+	data, err := io.ReadAll(content)
+	if err != nil {
+		return fmt.Errorf("cannot read content of file %s: %w", key, err)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.Err != nil {
+		return s.Err
+	}
+
+	s.files[key] = data
+	return nil
+}
+
+func (s *MemoryStore) Open(key string) (io.ReadCloser, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	data, ok := s.files[key]
+	if !ok {
+		return nil, fmt.Errorf("file %s: %w", key, os.ErrNotExist)
+	}
+
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (s *MemoryStore) Delete(key string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	delete(s.files, key)
+	return nil
+}
+
+func (s *MemoryStore) Keys() []string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	keys := make([]string, 0, len(s.files))
+	for key := range s.files {
+		keys = append(keys, key)
+	}
+	return keys
+}
@@ -0,0 +1,264 @@
+package storage
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/team-inu/inu-backyard/internal/config"
+)
+
+// the body is streamed so its hash is not part of the signature, the connection is protected by tls instead
+const unsignedPayload = "UNSIGNED-PAYLOAD"
+
+// S3Store keeps files in a bucket of an S3 compatible service, requests are signed with AWS signature version 4
+type S3Store struct {
+	endpoint  *url.URL
+	region    string
+	bucket    string
+	accessKey string
+	secretKey string
+	pathStyle bool
+	client    *http.Client
+	now       func() time.Time
+}
+
+// zero values in config fall back to the us-east-1 region and 60 second requests
+func NewS3Store(config config.S3Config) (*S3Store, error) {
+	if config.Endpoint == "" {
+		return nil, fmt.Errorf("s3 endpoint is not set")
+	} else if config.Bucket == "" {
+		return nil, fmt.Errorf("s3 bucket is not set")
+	} else if config.AccessKey == "" || config.SecretKey == "" {
+		return nil, fmt.Errorf("s3 credentials are not set")
+	}
+
+	endpoint, err := url.Parse(strings.TrimSuffix(config.Endpoint, "/"))
+	if err != nil || endpoint.Host == "" {
+		return nil, fmt.Errorf("s3 endpoint %s is not valid", config.Endpoint)
+	}
+
+	region := config.Region
+	if region == "" {
+		region = "us-east-1"
+	}
+
+	timeout := config.Timeout
+	if timeout <= 0 {
+		timeout = 60
+	}
+
+	return &S3Store{
+		endpoint:  endpoint,
+		region:    region,
+		bucket:    config.Bucket,
+		accessKey: config.AccessKey,
+		secretKey: config.SecretKey,
+		pathStyle: config.PathStyle,
+		client:    &http.Client{Timeout: time.Duration(timeout) * time.Second},
+		now:       time.Now,
+	}, nil
+}
+
+func (s S3Store) Put(key string, contentType string, content io.Reader, size int64) error {
+	request, err := s.newRequest(http.MethodPut, key, content)
+	if err != nil {
+		return err
+	}
+
+	request.ContentLength = size
+	if contentType != "" {
+		request.Header.Set("Content-Type", contentType)
+	}
+
+	response, err := s.do(request)
+	if err != nil {
+		return fmt.Errorf("cannot put object %s: %w", key, err)
+	}
+	response.Body.Close()
+
+	return nil
+}
+
+func (s S3Store) Open(key string) (io.ReadCloser, error) {
+	request, err := s.newRequest(http.MethodGet, key, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := s.do(request)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get object %s: %w", key, err)
+	}
+
+	return response.Body, nil
+}
+
+// Delete succeeds for missing objects, as S3 does
+func (s S3Store) Delete(key string) error {
+	request, err := s.newRequest(http.MethodDelete, key, nil)
+	if err != nil {
+		return err
+	}
+
+	response, err := s.do(request)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("cannot delete object %s: %w", key, err)
+	} else if err == nil {
+		response.Body.Close()
+	}
+
+	return nil
+}
+
+func (s S3Store) newRequest(method string, key string, body io.Reader) (*http.Request, error) {
+	if err := validateKey(key); err != nil {
+		return nil, err
+	}
+
+	segments := strings.Split(key, "/")
+	if s.pathStyle {
+		segments = append([]string{s.bucket}, segments...)
+	}
+
+	// the path is escaped by hand since the signature needs stricter escaping than net/url does
+	escaped := make([]string, 0, len(segments))
+	for _, segment := range segments {
+		escaped = append(escaped, uriEncode(segment))
+	}
+
+	objectUrl := *s.endpoint
+	if !s.pathStyle {
+		objectUrl.Host = s.bucket + "." + objectUrl.Host
+	}
+	objectUrl.Path = s.endpoint.Path + "/" + strings.Join(segments, "/")
+	objectUrl.RawPath = s.endpoint.EscapedPath() + "/" + strings.Join(escaped, "/")
+
+	request, err := http.NewRequest(method, objectUrl.String(), body)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create request for object %s: %w", key, err)
+	}
+
+	return request, nil
+}
+
+// do signs and sends the request, a 404 is returned as an error wrapping os.ErrNotExist
+func (s S3Store) do(request *http.Request) (*http.Response, error) {
+	request.Header.Set("X-Amz-Content-Sha256", unsignedPayload)
+	s.sign(request, unsignedPayload, s.now())
+
+	response, err := s.client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.StatusCode >= 200 && response.StatusCode < 300 {
+		return response, nil
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode == http.StatusNotFound {
+		return nil, os.ErrNotExist
+	}
+
+	var s3Error struct {
+		Code    string `xml:"Code"`
+		Message string `xml:"Message"`
+	}
+	body, _ := io.ReadAll(io.LimitReader(response.Body, 4096))
+	if xml.Unmarshal(body, &s3Error) == nil && s3Error.Code != "" {
+		return nil, fmt.Errorf("s3 responded %d %s: %s", response.StatusCode, s3Error.Code, s3Error.Message)
+	}
+
+	return nil, fmt.Errorf("s3 responded %d", response.StatusCode)
+}
+
+// sign adds the Authorization and X-Amz-Date headers, the host and every header already set are signed
+func (s S3Store) sign(request *http.Request, payloadHash string, now time.Time) {
+	amzDate := now.UTC().Format("20060102T150405Z")
+	date := amzDate[:8]
+	request.Header.Set("X-Amz-Date", amzDate)
+
+	headers := map[string]string{"host": request.URL.Host}
+	for name, values := range request.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		request.Method,
+		request.URL.EscapedPath(),
+		canonicalQuery(request.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + s.region + "/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hashHex([]byte(canonicalRequest))
+
+	key := hmacSha256([]byte("AWS4"+s.secretKey), date)
+	key = hmacSha256(key, s.region)
+	key = hmacSha256(key, "s3")
+	key = hmacSha256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSha256(key, stringToSign))
+
+	request.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.accessKey, scope, signedHeaders, signature,
+	))
+}
+
+func canonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := []string{}
+	for _, key := range keys {
+		values := append([]string{}, query[key]...)
+		sort.Strings(values)
+		for _, value := range values {
+			pairs = append(pairs, uriEncode(key)+"="+uriEncode(value))
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+// uriEncode escapes everything but the unreserved characters of RFC 3986, as the signature requires
+func uriEncode(value string) string {
+	return strings.ReplaceAll(url.QueryEscape(value), "+", "%20")
+}
+
+func hmacSha256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func hashHex(data []byte) string {
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,39 @@
+// Package storage keeps file contents on the local disk, in an S3 compatible bucket or in memory.
+package storage
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/team-inu/inu-backyard/entity"
+	"github.com/team-inu/inu-backyard/internal/config"
+)
+
+// NewFileStore returns the store of the configured driver, local when it is empty
+func NewFileStore(config config.StorageConfig) (entity.FileStore, error) {
+	switch config.Driver {
+	case "", "local":
+		return NewLocalStore(config.Directory)
+	case "s3":
+		return NewS3Store(config.S3)
+	case "memory":
+		return NewMemoryStore(), nil
+	default:
+		return nil, fmt.Errorf("unsupported storage driver %s", config.Driver)
+	}
+}
+
+// validateKey refuses keys that could escape the directory or bucket prefix they are put in
+func validateKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return fmt.Errorf("file key %q is not valid", key)
+	}
+
+	for _, segment := range strings.Split(key, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return fmt.Errorf("file key %q is not valid", key)
+		}
+	}
+
+	return nil
+}
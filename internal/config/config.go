@@ -7,11 +7,101 @@ type SessionConfig struct {
 	Secret     string
 	Prefix     string
 	CookieName string
+	// renew the session on use once half of max age has passed
+	Sliding bool
+	// seconds between deleting expired sessions, password reset tokens, login throttles and old mails
+	CleanupInterval int
+}
+
+// OpenID Connect provider used for single sign-on, the login uses authorization code flow with PKCE
+type OidcConfig struct {
+	Enabled      bool
+	Issuer       string
+	ClientId     string
+	ClientSecret string
+	// backend callback address registered at the provider
+	RedirectUrl string
+	// frontend address to land on after a successful login
+	PostLoginRedirectUrl string
+	Scopes               []string
+	// claim holding the user email, "email" when empty
+	EmailClaim string
+}
+
+type LdapGroupRole struct {
+	// distinguished name of the directory group
+	Group string
+	Role  string
+}
+
+// LDAP or Active Directory server checked before the local password on sign in
+type LdapConfig struct {
+	Enabled bool
+	// ldaps://host:636, or ldap://host:389 with StartTls
+	Url string
+	// upgrade an ldap:// connection before binding, ldap:// is refused without it
+	StartTls bool
+	// PEM file of the CA that signed the server certificate, the system roots are used when empty
+	CaCertPath string
+	// service account used to look up the user entry, anonymous when empty
+	BindDn       string
+	BindPassword string
+	BaseDn       string
+	// %s is replaced by the escaped email, e.g. (&(objectClass=user)(mail=%s))
+	UserFilter         string
+	FirstNameAttribute string
+	LastNameAttribute  string
+	GroupAttribute     string
+	GroupRoles         []LdapGroupRole
+	// create the user on first sign in when the directory accepts the password
+	CreateUsers bool
+	// in seconds
+	Timeout int
+}
+
+type TwoFactorConfig struct {
+	// name shown in authenticator apps
+	Issuer string
+}
+
+// failed attempts allowed before sign in, forgot and reset password are locked, durations are in seconds
+type LoginThrottleConfig struct {
+	MaxFailures   int
+	IpMaxFailures int
+	// failures older than the window are forgotten
+	Window int
+	// the first lockout, doubled on each following lockout up to MaxLockout
+	BaseLockout int
+	MaxLockout  int
+}
+
+type PasswordExpiryConfig struct {
+	Role string
+	Days int
+}
+
+type PasswordConfig struct {
+	MinLength     int
+	RequireUpper  bool
+	RequireLower  bool
+	RequireDigit  bool
+	RequireSymbol bool
+	// file with one breached password per line, the list shipped with the server is used when empty
+	BreachedListPath string
+	// previous passwords that cannot be used again
+	HistorySize int
+	// days a password can be used for each role, the shortest applies to users with several roles
+	Expiry []PasswordExpiryConfig
 }
 
 type AuthConfig struct {
 	Session   SessionConfig
 	Turnstile TurnstileConfig
+	Oidc      OidcConfig
+	Ldap      LdapConfig
+	TwoFactor TwoFactorConfig
+	Throttle  LoginThrottleConfig
+	Password  PasswordConfig
 }
 
 type CorsConfig struct {
@@ -23,11 +113,113 @@ type TurnstileConfig struct {
 }
 
 type ClientConfig struct {
-	Auth AuthConfig
-	Cors CorsConfig
+	// frontend address used for links sent by email
+	BaseUrl string
+	Auth    AuthConfig
+	Cors    CorsConfig
+	Proxy   ProxyConfig
+}
+
+// reverse proxies in front of the server, the client address is read from Header only on requests coming from them,
+// the proxies must overwrite the header rather than append to one sent by the client
+type ProxyConfig struct {
+	// X-Forwarded-For when empty
+	Header string
+	// addresses or CIDR ranges of the proxies such as the ingress controller pods, the header is ignored when empty
+	TrustedProxies []string
+}
+
+type SmtpConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	// only for servers with self-signed certificates during development
+	InsecureSkipVerify bool
+}
+
+// emails are queued in the outbox and sent by a background worker, durations are in seconds
+type MailConfig struct {
+	// smtp, file or memory
+	Driver string
+	From   string
+	// th or en, the language of the templates
+	Language string
+	Smtp     SmtpConfig
+	// directory the file driver writes emails to
+	Directory string
+	// attempts before a mail is marked failed
+	MaxAttempts int
+	// wait before the first retry, doubled on each following retry
+	RetryBackoff int
+	PollInterval int
+	BatchSize    int
+	// days sent and failed mails are kept for admins
+	Retention int
+	// encrypts the queued bodies, required by the smtp driver, the file and memory drivers use a random key when empty
+	EncryptionKey string
+}
+
+// deadline reminders are sent by one replica at a time, the interval is in seconds
+type SchedulerConfig struct {
+	Interval int
+}
+
+// reports are generated by background workers and downloaded through signed links
+type ReportConfig struct {
+	Workers int
+	// seconds an idle worker waits before looking for a job again
+	PollInterval int
+	// minutes a download link is valid
+	LinkExpiry int
+	// hours finished jobs and their files are kept
+	Retention int
+	// signs download links, required, replicas must share it for links to work on any of them
+	SigningKey string
+}
+
+// stale outcome attainments are recomputed by a background worker, a refresh claims its course so replicas can all run it
+type AttainmentConfig struct {
+	// seconds an idle worker waits before looking for stale attainments again
+	PollInterval int
+	// method of the programmes and courses without their own, THRESHOLD when empty
+	Method string
+	// minimum score percentages of the rubric levels from the lowest, used by the RUBRIC_LEVEL method
+	RubricLevels []float64
+	// level counted from 1 an outcome must reach to pass with the RUBRIC_LEVEL method
+	PassingRubricLevel int
+}
+
+type S3Config struct {
+	// base url of the service, e.g. https://s3.ap-southeast-1.amazonaws.com or a MinIO server
+	Endpoint  string
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	// addresses the bucket in the path instead of the host name, MinIO needs it
+	PathStyle bool
+	// seconds a request may take
+	Timeout int
+}
+
+// generated and uploaded files are kept where every replica can read them
+type StorageConfig struct {
+	// local or s3, local only works with a single replica or a shared volume
+	Driver string
+	// directory the local driver writes to
+	Directory string
+	S3        S3Config
+	// bytes, larger files are refused
+	MaxFileSize int64
 }
 
 type FiberServerConfig struct {
-	Database database.GormConfig
-	Client   ClientConfig
+	Database   database.GormConfig
+	Client     ClientConfig
+	Mail       MailConfig
+	Scheduler  SchedulerConfig
+	Report     ReportConfig
+	Storage    StorageConfig
+	Attainment AttainmentConfig
 }
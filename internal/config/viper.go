@@ -16,6 +16,20 @@ func SetConfig(class interface{}) {
 	viper.SetConfigName(name)
 	viper.AddConfigPath(".")
 
+	// secrets passed to containers as environment variables
+	for key, env := range map[string]string{
+		"mail.smtp.host":       "SMTP_HOST",
+		"mail.smtp.port":       "SMTP_PORT",
+		"mail.smtp.username":   "SMTP_USERNAME",
+		"mail.smtp.password":   "SMTP_PASSWORD",
+		"mail.encryptionKey":   "MAIL_ENCRYPTION_KEY",
+		"report.signingKey":    "REPORT_SIGNING_KEY",
+		"storage.s3.accessKey": "S3_ACCESS_KEY",
+		"storage.s3.secretKey": "S3_SECRET_KEY",
+	} {
+		viper.BindEnv(key, env)
+	}
+
 	if err := viper.ReadInConfig(); err != nil {
 		log.Fatalf("Error reading config file, %s", err)
 	}
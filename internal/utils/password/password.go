@@ -0,0 +1,116 @@
+// Package password checks new passwords against a policy and a list of breached passwords.
+package password
+
+import (
+	"bufio"
+	_ "embed"
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+)
+
+//go:embed breached.txt
+var defaultBreachedList string
+
+// violations returned by Validate
+const (
+	ViolationMinLength = "min_length"
+	ViolationUpper     = "upper"
+	ViolationLower     = "lower"
+	ViolationDigit     = "digit"
+	ViolationSymbol    = "symbol"
+	ViolationBreached  = "breached"
+)
+
+type Policy struct {
+	MinLength     int
+	RequireUpper  bool
+	RequireLower  bool
+	RequireDigit  bool
+	RequireSymbol bool
+
+	breached map[string]bool
+}
+
+// NewPolicy reads the breached password list at breachedListPath, the list shipped with the package is used when the path is empty
+func NewPolicy(minLength int, requireUpper bool, requireLower bool, requireDigit bool, requireSymbol bool, breachedListPath string) (*Policy, error) {
+	list := defaultBreachedList
+	if breachedListPath != "" {
+		content, err := os.ReadFile(breachedListPath)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read breached password list: %w", err)
+		}
+		list = string(content)
+	}
+
+	policy := &Policy{
+		MinLength:     minLength,
+		RequireUpper:  requireUpper,
+		RequireLower:  requireLower,
+		RequireDigit:  requireDigit,
+		RequireSymbol: requireSymbol,
+		breached:      map[string]bool{},
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(list))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		policy.breached[strings.ToLower(line)] = true
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("cannot parse breached password list: %w", err)
+	}
+
+	return policy, nil
+}
+
+// Validate returns the rules the password breaks, it is empty when the password is acceptable
+func (p Policy) Validate(password string) []string {
+	violations := []string{}
+
+	if len([]rune(password)) < p.MinLength {
+		violations = append(violations, ViolationMinLength)
+	}
+
+	var hasUpper, hasLower, hasDigit, hasSymbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		// letters without case, like Thai, count as lower case
+		case unicode.IsLetter(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			hasSymbol = true
+		}
+	}
+
+	if p.RequireUpper && !hasUpper {
+		violations = append(violations, ViolationUpper)
+	}
+	if p.RequireLower && !hasLower {
+		violations = append(violations, ViolationLower)
+	}
+	if p.RequireDigit && !hasDigit {
+		violations = append(violations, ViolationDigit)
+	}
+	if p.RequireSymbol && !hasSymbol {
+		violations = append(violations, ViolationSymbol)
+	}
+
+	if p.IsBreached(password) {
+		violations = append(violations, ViolationBreached)
+	}
+
+	return violations
+}
+
+func (p Policy) IsBreached(password string) bool {
+	return p.breached[strings.ToLower(password)]
+}
@@ -0,0 +1,43 @@
+package password
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPolicy(t *testing.T) {
+	policy, err := NewPolicy(10, true, true, true, true, "")
+	if err != nil {
+		t.Fatalf("Failed to create policy: %v", err)
+	}
+
+	t.Run("TestValidate", func(t *testing.T) {
+		assert.Empty(t, policy.Validate("Correct-Horse-9"), "Expected strong password to pass")
+		assert.Equal(t, []string{ViolationMinLength, ViolationUpper, ViolationDigit, ViolationSymbol}, policy.Validate("short"))
+		assert.Equal(t, []string{ViolationSymbol}, policy.Validate("NoSymbolsHere1"))
+		assert.Empty(t, policy.Validate("รหัสผ่านยาวพอ-A1"), "Expected Thai letters to count toward length by character")
+	})
+
+	t.Run("TestBreached", func(t *testing.T) {
+		assert.Contains(t, policy.Validate("Password123!"), ViolationBreached, "Expected listed password to be refused")
+		assert.True(t, policy.IsBreached("PASSWORD"), "Expected breached check to ignore case")
+	})
+
+	t.Run("TestBreachedListPath", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "breached.txt")
+		os.WriteFile(path, []byte("# comment\nCorrect-Horse-9\n"), 0o600)
+
+		customPolicy, err := NewPolicy(0, false, false, false, false, path)
+		assert.Nil(t, err, "Expected no error while reading list, got %v", err)
+		assert.True(t, customPolicy.IsBreached("correct-horse-9"), "Expected custom list to be used")
+		assert.False(t, customPolicy.IsBreached("password"), "Expected custom list to replace the default")
+	})
+
+	t.Run("TestMissingList", func(t *testing.T) {
+		_, err := NewPolicy(0, false, false, false, false, filepath.Join(t.TempDir(), "missing.txt"))
+		assert.NotNil(t, err, "Expected error for missing list")
+	})
+}
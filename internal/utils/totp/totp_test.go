@@ -0,0 +1,60 @@
+package totp
+
+import (
+	"encoding/base32"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// RFC 6238 appendix B, SHA1 with the 20-byte ASCII key, truncated to 6 digits
+var rfcSecret = base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+func TestGenerateCode(t *testing.T) {
+	vectors := map[int64]string{
+		59:          "287082",
+		1111111109:  "081804",
+		1111111111:  "050471",
+		1234567890:  "005924",
+		2000000000:  "279037",
+		20000000000: "353130",
+	}
+
+	for unix, expected := range vectors {
+		code, err := GenerateCode(rfcSecret, Step(time.Unix(unix, 0)))
+		assert.Nil(t, err, "Expected no error while generating code, got %v", err)
+		assert.Equal(t, expected, code, "Expected code at %d to match RFC 6238", unix)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	code, _ := GenerateCode(rfcSecret, Step(now))
+
+	t.Run("TestValidate_CurrentStep", func(t *testing.T) {
+		step, err := Validate(rfcSecret, code, now)
+		assert.Nil(t, err)
+		assert.Equal(t, Step(now), step, "Expected current step to match")
+	})
+
+	t.Run("TestValidate_ClockDrift", func(t *testing.T) {
+		step, _ := Validate(rfcSecret, code, now.Add(Period*time.Second))
+		assert.Equal(t, Step(now), step, "Expected previous step to be accepted")
+	})
+
+	t.Run("TestValidate_TooOld", func(t *testing.T) {
+		step, _ := Validate(rfcSecret, code, now.Add(3*Period*time.Second))
+		assert.Equal(t, int64(-1), step, "Expected old code to be refused")
+	})
+
+	t.Run("TestValidate_WrongCode", func(t *testing.T) {
+		step, _ := Validate(rfcSecret, "000000", now)
+		assert.Equal(t, int64(-1), step, "Expected wrong code to be refused")
+	})
+}
+
+func TestProvisioningUri(t *testing.T) {
+	uri := ProvisioningUri("inu-backyard", "somchai@example.com", "JBSWY3DPEHPK3PXP")
+	assert.Equal(t, "otpauth://totp/inu-backyard:somchai@example.com?algorithm=SHA1&digits=6&issuer=inu-backyard&period=30&secret=JBSWY3DPEHPK3PXP", uri)
+}
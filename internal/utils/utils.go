@@ -1,7 +1,8 @@
 package utils
 
 import (
-	"math/rand"
+	"crypto/rand"
+	"math/big"
 	"strconv"
 
 	errs "github.com/team-inu/inu-backyard/entity/error"
@@ -42,11 +43,16 @@ func ValidatePagination(pageIndex string, pageSize string) (int, int, error) {
 	return offset, size, nil
 }
 
-func GenerateRandomInt(length int) string {
+// GenerateRandomInt returns a string of random digits, it is used for codes sent to users
+func GenerateRandomInt(length int) (string, error) {
 	const charset = "0123456789"
 	b := make([]byte, length)
 	for i := range b {
-		b[i] = charset[rand.Intn(len(charset))]
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(charset))))
+		if err != nil {
+			return "", err
+		}
+		b[i] = charset[n.Int64()]
 	}
-	return string(b)
+	return string(b), nil
 }
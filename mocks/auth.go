@@ -16,9 +16,9 @@ func (m *MockAuthUseCase) ChangePassword(userId, oldPassword, newPassword string
 	return args.Error(0)
 }
 
-func (m *MockAuthUseCase) Authenticate(sessionId string) (*entity.User, error) {
+func (m *MockAuthUseCase) Authenticate(sessionId string) (*entity.Authentication, error) {
 	args := m.Called(sessionId)
-	return args.Get(0).(*entity.User), args.Error(1)
+	return args.Get(0).(*entity.Authentication), args.Error(1)
 }
 
 func (m *MockAuthUseCase) SignIn(email string, password string, ipAddress string, userAgent string) (*fiber.Cookie, error) {
@@ -30,3 +30,28 @@ func (m *MockAuthUseCase) SignOut(header string) (*fiber.Cookie, error) {
 	args := m.Called(header)
 	return args.Get(0).(*fiber.Cookie), args.Error(1)
 }
+
+func (m *MockAuthUseCase) BeginSsoSignIn() (string, *fiber.Cookie, error) {
+	args := m.Called()
+	return args.String(0), args.Get(1).(*fiber.Cookie), args.Error(2)
+}
+
+func (m *MockAuthUseCase) SsoSignIn(payload entity.SsoCallbackPayload, stateCookie string, ipAddress string, userAgent string) (*entity.SignInResult, error) {
+	args := m.Called(payload, stateCookie, ipAddress, userAgent)
+	return args.Get(0).(*entity.SignInResult), args.Error(1)
+}
+
+func (m *MockAuthUseCase) VerifyTwoFactor(payload entity.VerifyTwoFactorPayload, ipAddress string) (*entity.TwoFactorSignInResult, error) {
+	args := m.Called(payload, ipAddress)
+	return args.Get(0).(*entity.TwoFactorSignInResult), args.Error(1)
+}
+
+func (m *MockAuthUseCase) BeginChallengeEnrollment(challenge string) (*entity.TotpEnrollment, error) {
+	args := m.Called(challenge)
+	return args.Get(0).(*entity.TotpEnrollment), args.Error(1)
+}
+
+func (m *MockAuthUseCase) UnlockUser(userId string) error {
+	args := m.Called(userId)
+	return args.Error(0)
+}
@@ -0,0 +1,265 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/team-inu/inu-backyard/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type assessmentItemRepositoryGorm struct {
+	gorm *gorm.DB
+}
+
+func NewAssessmentItemRepositoryGorm(gorm *gorm.DB) entity.AssessmentItemRepository {
+	return &assessmentItemRepositoryGorm{gorm: gorm}
+}
+
+func (r assessmentItemRepositoryGorm) GetById(id string) (*entity.AssessmentItem, error) {
+	var item entity.AssessmentItem
+	err := r.gorm.Preload("CourseLearningOutcomes").Preload("RubricCriterion.Levels").Where("id = ?", id).First(&item).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("cannot query to get assessment item by id: %w", err)
+	}
+
+	return &item, nil
+}
+
+func (r assessmentItemRepositoryGorm) GetByAssignmentId(assignmentId string) ([]entity.AssessmentItem, error) {
+	var items []entity.AssessmentItem
+	err := r.gorm.Preload("CourseLearningOutcomes").Preload("RubricCriterion.Levels").Where("assignment_id = ?", assignmentId).Order("sequence").Find(&items).Error
+	if err != nil {
+		return nil, fmt.Errorf("cannot query to get assessment items by assignment id: %w", err)
+	}
+
+	return items, nil
+}
+
+func (r assessmentItemRepositoryGorm) Create(item *entity.AssessmentItem) error {
+	err := r.gorm.Transaction(func(tx *gorm.DB) error {
+		err := tx.Omit("CourseLearningOutcomes.*").Create(item).Error
+		if err != nil {
+			return err
+		}
+
+		// the CLOs of the assignment are evaluated from its items from now on
+		return queueAttainmentRefreshByAssignment(tx, []string{item.AssignmentId})
+	})
+	if err != nil {
+		return fmt.Errorf("cannot create assessment item: %w", err)
+	}
+
+	return nil
+}
+
+func (r assessmentItemRepositoryGorm) Update(id string, item *entity.AssessmentItem) error {
+	err := r.gorm.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&entity.AssessmentItem{}).Where("id = ?", id).Updates(item).Error
+		if err != nil {
+			return err
+		}
+
+		return queueAttainmentRefreshByAssessmentItem(tx, id)
+	})
+	if err != nil {
+		return fmt.Errorf("cannot update assessment item: %w", err)
+	}
+
+	return nil
+}
+
+func (r assessmentItemRepositoryGorm) Delete(id string) error {
+	err := r.gorm.Transaction(func(tx *gorm.DB) error {
+		var assignmentIds []string
+		err := tx.Model(&entity.AssessmentItem{}).Where("id = ?", id).Pluck("assignment_id", &assignmentIds).Error
+		if err != nil || len(assignmentIds) == 0 {
+			return err
+		}
+
+		err = deleteAssessmentItems(tx, []string{id})
+		if err != nil {
+			return err
+		}
+
+		return updateAssessmentItemTotals(tx, assignmentIds[0], "")
+	})
+	if err != nil {
+		return fmt.Errorf("cannot delete assessment item: %w", err)
+	}
+
+	return nil
+}
+
+func (r assessmentItemRepositoryGorm) CreateLinkCourseLearningOutcome(id string, courseLearningOutcomeIds []string) error {
+	err := r.gorm.Transaction(func(tx *gorm.DB) error {
+		for _, cloId := range courseLearningOutcomeIds {
+			err := tx.Exec("INSERT INTO `clo_assessment_item` (assessment_item_id, course_learning_outcome_id) VALUES (?, ?)", id, cloId).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		return queueAttainmentRefreshByAssessmentItem(tx, id)
+	})
+	if err != nil {
+		return fmt.Errorf("cannot create link between assessment item and clo: %w", err)
+	}
+
+	return nil
+}
+
+func (r assessmentItemRepositoryGorm) DeleteLinkCourseLearningOutcome(id string, courseLearningOutcomeId string) error {
+	err := r.gorm.Transaction(func(tx *gorm.DB) error {
+		err := tx.Exec("DELETE FROM `clo_assessment_item` WHERE assessment_item_id = ? AND course_learning_outcome_id = ?", id, courseLearningOutcomeId).Error
+		if err != nil {
+			return err
+		}
+
+		return queueAttainmentRefreshByAssessmentItem(tx, id)
+	})
+	if err != nil {
+		return fmt.Errorf("cannot delete link between assessment item and clo: %w", err)
+	}
+
+	return nil
+}
+
+func (r assessmentItemRepositoryGorm) GetScoresByAssignmentId(assignmentId string) ([]entity.AssessmentItemScore, error) {
+	var scores []entity.AssessmentItemScore
+	err := r.gorm.
+		Joins("JOIN assessment_item ON assessment_item.id = assessment_item_score.assessment_item_id").
+		Where("assessment_item.assignment_id = ?", assignmentId).
+		Find(&scores).Error
+	if err != nil {
+		return nil, fmt.Errorf("cannot query to get assessment item scores by assignment id: %w", err)
+	}
+
+	return scores, nil
+}
+
+func (r assessmentItemRepositoryGorm) SaveScores(assignmentId string, userId string, scores []entity.AssessmentItemScore) error {
+	studentIds := []string{}
+	isAdded := map[string]bool{}
+	for _, score := range scores {
+		if !isAdded[score.StudentId] {
+			isAdded[score.StudentId] = true
+			studentIds = append(studentIds, score.StudentId)
+		}
+	}
+
+	err := r.gorm.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.OnConflict{DoUpdates: clause.AssignmentColumns([]string{"score", "rubric_level_id", "user_id", "updated_at"})}).
+			CreateInBatches(scores, attainmentBatchSize).Error
+		if err != nil {
+			return err
+		}
+
+		return updateAssessmentItemTotals(tx, assignmentId, userId, studentIds...)
+	})
+	if err != nil {
+		return fmt.Errorf("cannot save assessment item scores: %w", err)
+	}
+
+	return nil
+}
+
+// deleteAssessmentItems removes the items with their scores and CLO links
+func deleteAssessmentItems(tx *gorm.DB, itemIds []string) error {
+	if len(itemIds) == 0 {
+		return nil
+	}
+
+	err := tx.Where("assessment_item_id IN ?", itemIds).Delete(&entity.AssessmentItemScore{}).Error
+	if err != nil {
+		return err
+	}
+
+	err = tx.Exec("DELETE FROM `clo_assessment_item` WHERE assessment_item_id IN ?", itemIds).Error
+	if err != nil {
+		return err
+	}
+
+	return tx.Where("id IN ?", itemIds).Delete(&entity.AssessmentItem{}).Error
+}
+
+// updateAssessmentItemTotals sets the scores of the assignment to the totals of the item scores of the students, every student
+// when none is given, a score is removed once the student has no item score left and created by the user when missing
+func updateAssessmentItemTotals(tx *gorm.DB, assignmentId string, userId string, studentIds ...string) error {
+	var totals []struct {
+		StudentId string
+		Score     float64
+	}
+	totalQuery := tx.Table("assessment_item_score").
+		Select("assessment_item_score.student_id, SUM(assessment_item_score.score) AS score").
+		Joins("JOIN assessment_item ON assessment_item.id = assessment_item_score.assessment_item_id").
+		Where("assessment_item.assignment_id = ?", assignmentId).
+		Group("assessment_item_score.student_id")
+	if len(studentIds) > 0 {
+		totalQuery = totalQuery.Where("assessment_item_score.student_id IN ?", studentIds)
+	}
+	err := totalQuery.Scan(&totals).Error
+	if err != nil {
+		return err
+	}
+
+	var existScores []entity.Score
+	scoreQuery := tx.Select("id", "student_id").Where("assignment_id = ?", assignmentId)
+	if len(studentIds) > 0 {
+		scoreQuery = scoreQuery.Where("student_id IN ?", studentIds)
+	}
+	err = scoreQuery.Find(&existScores).Error
+	if err != nil {
+		return err
+	}
+
+	scoreIdByStudent := make(map[string]string, len(existScores))
+	for _, score := range existScores {
+		scoreIdByStudent[score.StudentId] = score.Id
+	}
+
+	newScores := []entity.Score{}
+	for _, total := range totals {
+		scoreId, ok := scoreIdByStudent[total.StudentId]
+		if !ok {
+			newScores = append(newScores, entity.Score{
+				Id:           ulid.Make().String(),
+				Score:        total.Score,
+				StudentId:    total.StudentId,
+				UserId:       userId,
+				AssignmentId: assignmentId,
+			})
+			continue
+		}
+
+		delete(scoreIdByStudent, total.StudentId)
+		err = tx.Model(&entity.Score{}).Where("id = ?", scoreId).Update("score", total.Score).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(newScores) > 0 {
+		err = tx.CreateInBatches(newScores, attainmentBatchSize).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(scoreIdByStudent) > 0 {
+		staleScoreIds := make([]string, 0, len(scoreIdByStudent))
+		for _, scoreId := range scoreIdByStudent {
+			staleScoreIds = append(staleScoreIds, scoreId)
+		}
+
+		err = tx.Where("id IN ?", staleScoreIds).Delete(&entity.Score{}).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	return queueAttainmentRefreshByAssignment(tx, []string{assignmentId}, studentIds...)
+}
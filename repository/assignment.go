@@ -145,7 +145,14 @@ func (r assignmentRepositoryGorm) Update(id string, assignment *entity.Assignmen
 	}
 
 	//update old assignment with new name
-	err = r.gorm.Model(&oldAssignment).Updates(assignment).Error
+	err = r.gorm.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&oldAssignment).Updates(assignment).Error
+		if err != nil {
+			return err
+		}
+
+		return queueAttainmentRefreshByAssignment(tx, []string{id})
+	})
 	if err != nil {
 		return fmt.Errorf("cannot update assignment by id: %w", err)
 	}
@@ -154,7 +161,25 @@ func (r assignmentRepositoryGorm) Update(id string, assignment *entity.Assignmen
 }
 
 func (r assignmentRepositoryGorm) Delete(id string) error {
-	err := r.gorm.Where("id = ?", id).Delete(&entity.Assignment{}).Error
+	err := r.gorm.Transaction(func(tx *gorm.DB) error {
+		err := queueAttainmentRefreshByAssignment(tx, []string{id})
+		if err != nil {
+			return err
+		}
+
+		var itemIds []string
+		err = tx.Model(&entity.AssessmentItem{}).Where("assignment_id = ?", id).Pluck("id", &itemIds).Error
+		if err != nil {
+			return err
+		}
+
+		err = deleteAssessmentItems(tx, itemIds)
+		if err != nil {
+			return err
+		}
+
+		return tx.Where("id = ?", id).Delete(&entity.Assignment{}).Error
+	})
 	if err != nil {
 		return fmt.Errorf("cannot delete assignment by id: %w", err)
 	}
@@ -170,7 +195,14 @@ func (r assignmentRepositoryGorm) CreateLinkCourseLearningOutcome(assignmentId s
 
 	query = query[:len(query)-1]
 
-	err := r.gorm.Exec(fmt.Sprintf("INSERT INTO `clo_assignment` (assignment_id, course_learning_outcome_id) VALUES %s", query)).Error
+	err := r.gorm.Transaction(func(tx *gorm.DB) error {
+		err := tx.Exec(fmt.Sprintf("INSERT INTO `clo_assignment` (assignment_id, course_learning_outcome_id) VALUES %s", query)).Error
+		if err != nil {
+			return err
+		}
+
+		return queueAttainmentRefreshByAssignment(tx, []string{assignmentId})
+	})
 
 	if err != nil {
 		return fmt.Errorf("cannot create link between assignment and clo: %w", err)
@@ -180,7 +212,14 @@ func (r assignmentRepositoryGorm) CreateLinkCourseLearningOutcome(assignmentId s
 }
 
 func (r assignmentRepositoryGorm) DeleteLinkCourseLearningOutcome(assignmentId string, courseLearningOutcomeId string) error {
-	err := r.gorm.Exec("DELETE FROM `clo_assignment` WHERE course_learning_outcome_id = ? AND assignment_id = ?", courseLearningOutcomeId, assignmentId).Error
+	err := r.gorm.Transaction(func(tx *gorm.DB) error {
+		err := tx.Exec("DELETE FROM `clo_assignment` WHERE course_learning_outcome_id = ? AND assignment_id = ?", courseLearningOutcomeId, assignmentId).Error
+		if err != nil {
+			return err
+		}
+
+		return queueAttainmentRefreshByAssignment(tx, []string{assignmentId})
+	})
 
 	if err != nil {
 		return fmt.Errorf("cannot delete link between assignment and clo: %w", err)
@@ -70,7 +70,27 @@ func (r assignmentRepositoryGorm) UpdateGroup(assignmentGroupId string, assignme
 }
 
 func (r assignmentRepositoryGorm) DeleteGroup(assignmentGroupId string) error {
-	err := r.gorm.Delete(&entity.AssignmentGroup{Id: assignmentGroupId}).Error
+	err := r.gorm.Transaction(func(tx *gorm.DB) error {
+		var courseIds []string
+		err := tx.Model(&entity.AssignmentGroup{}).Where("id = ?", assignmentGroupId).Pluck("course_id", &courseIds).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Delete(&entity.AssignmentGroup{Id: assignmentGroupId}).Error
+		if err != nil {
+			return err
+		}
+
+		for _, courseId := range courseIds {
+			err = queueAttainmentRefresh(tx, courseId)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
 
 	if err != nil {
 		return fmt.Errorf("cannot delete assignment group: %w", err)
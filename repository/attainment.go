@@ -0,0 +1,417 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/team-inu/inu-backyard/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const attainmentBatchSize = 500
+
+type attainmentRepositoryGorm struct {
+	gorm *gorm.DB
+}
+
+func NewAttainmentRepositoryGorm(gorm *gorm.DB) entity.AttainmentRepository {
+	return &attainmentRepositoryGorm{gorm: gorm}
+}
+
+func (r attainmentRepositoryGorm) Queue(courseId string, studentIds []string) error {
+	err := queueAttainmentRefresh(r.gorm, courseId, studentIds...)
+	if err != nil {
+		return fmt.Errorf("cannot query to queue attainment refresh: %w", err)
+	}
+
+	return nil
+}
+
+func (r attainmentRepositoryGorm) QueueAll() (int64, error) {
+	var count int64
+	err := r.gorm.Transaction(func(tx *gorm.DB) error {
+		err := tx.Exec(`
+			INSERT INTO attainment_refresh (course_id, student_id, requested_at)
+			SELECT id, '', ? FROM course
+			ON DUPLICATE KEY UPDATE requested_at = VALUES(requested_at)
+		`, time.Now()).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Model(&entity.Course{}).Count(&count).Error
+	})
+	if err != nil {
+		return 0, fmt.Errorf("cannot query to queue attainment refresh of all courses: %w", err)
+	}
+
+	return count, nil
+}
+
+func (r attainmentRepositoryGorm) RefreshNext(evaluate func(source entity.AttainmentSource) entity.CourseAttainment) (bool, error) {
+	isRefreshed := false
+	err := r.gorm.Transaction(func(tx *gorm.DB) error {
+		var next []entity.AttainmentRefresh
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Order("requested_at").
+			Limit(1).
+			Find(&next).Error
+		if err != nil || len(next) == 0 {
+			return err
+		}
+
+		// changes queued while the course is refreshed wait for the lock and are queued again once it is released
+		var claimed []entity.AttainmentRefresh
+		err = tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("course_id = ?", next[0].CourseId).
+			Find(&claimed).Error
+		if err != nil {
+			return err
+		}
+
+		courseId := next[0].CourseId
+		isWholeCourse := false
+		studentIds := make([]string, 0, len(claimed))
+		for _, refresh := range claimed {
+			if refresh.StudentId == "" {
+				isWholeCourse = true
+			}
+			studentIds = append(studentIds, refresh.StudentId)
+		}
+
+		source, err := getAttainmentSource(tx, courseId, studentIds, isWholeCourse)
+		if err != nil {
+			return err
+		}
+
+		attainment := entity.CourseAttainment{}
+		if source != nil {
+			attainment = evaluate(*source)
+		}
+
+		err = replaceAttainments(tx, courseId, studentIds, isWholeCourse, attainment)
+		if err != nil {
+			return err
+		}
+
+		err = tx.Where("course_id = ? AND student_id IN ?", courseId, studentIds).Delete(&entity.AttainmentRefresh{}).Error
+		if err != nil {
+			return err
+		}
+
+		isRefreshed = true
+		return nil
+	})
+	if err != nil {
+		return false, fmt.Errorf("cannot query to refresh attainments: %w", err)
+	}
+
+	return isRefreshed, nil
+}
+
+// getAttainmentSource returns nil when the course no longer exists
+func getAttainmentSource(tx *gorm.DB, courseId string, studentIds []string, isWholeCourse bool) (*entity.AttainmentSource, error) {
+	var courses []struct {
+		ExpectedPassingCloPercentage float64
+		Method                       entity.AttainmentMethod
+	}
+	err := tx.Raw(`
+		SELECT course.expected_passing_clo_percentage, COALESCE(NULLIF(course.attainment_method, ''), programme.attainment_method, '') AS method
+		FROM course
+		LEFT JOIN programme ON programme.id = course.programme_id
+		WHERE course.id = ?
+	`, courseId).Scan(&courses).Error
+	if err != nil {
+		return nil, err
+	} else if len(courses) == 0 {
+		return nil, nil
+	}
+
+	source := entity.AttainmentSource{
+		CourseId:                     courseId,
+		ExpectedPassingCloPercentage: courses[0].ExpectedPassingCloPercentage,
+		Method:                       courses[0].Method,
+	}
+
+	studentQuery := tx.Table("enrollment").
+		Distinct("enrollment.student_id").
+		Joins("JOIN score ON score.student_id = enrollment.student_id").
+		Joins("JOIN assignment ON assignment.id = score.assignment_id").
+		Joins("JOIN assignment_group ON assignment_group.id = assignment.assignment_group_id AND assignment_group.course_id = enrollment.course_id").
+		Where("enrollment.course_id = ? AND enrollment.status != ?", courseId, entity.EnrollmentStatusWithdraw)
+	if !isWholeCourse {
+		studentQuery = studentQuery.Where("enrollment.student_id IN ?", studentIds)
+	}
+	err = studentQuery.Scan(&source.StudentIds).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var clos []entity.CourseLearningOutcome
+	err = tx.Select("id", "expected_passing_assignment_percentage").Where("course_id = ?", courseId).Find(&clos).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var cloAssignments []struct {
+		CourseLearningOutcomeId string
+		entity.AttainmentAssignment
+	}
+	err = tx.Raw(`
+		SELECT clo_assignment.course_learning_outcome_id, assignment.id, assignment.max_score, assignment.expected_score_percentage
+		FROM clo_assignment
+		JOIN course_learning_outcome ON course_learning_outcome.id = clo_assignment.course_learning_outcome_id
+		JOIN assignment ON assignment.id = clo_assignment.assignment_id
+		WHERE course_learning_outcome.course_id = ? AND assignment.is_included_in_clo IS TRUE
+			AND NOT EXISTS (SELECT 1 FROM assessment_item WHERE assessment_item.assignment_id = assignment.id)
+		UNION ALL
+		SELECT clo_assessment_item.course_learning_outcome_id, assessment_item.id, assessment_item.max_score, assessment_item.expected_score_percentage
+		FROM clo_assessment_item
+		JOIN course_learning_outcome ON course_learning_outcome.id = clo_assessment_item.course_learning_outcome_id
+		JOIN assessment_item ON assessment_item.id = clo_assessment_item.assessment_item_id
+		JOIN assignment ON assignment.id = assessment_item.assignment_id
+		WHERE course_learning_outcome.course_id = ? AND assignment.is_included_in_clo IS TRUE
+	`, courseId, courseId).Scan(&cloAssignments).Error
+	if err != nil {
+		return nil, err
+	}
+
+	isAdded := map[string]bool{}
+	assignmentIdsByClo := map[string][]string{}
+	for _, cloAssignment := range cloAssignments {
+		assignmentIdsByClo[cloAssignment.CourseLearningOutcomeId] = append(assignmentIdsByClo[cloAssignment.CourseLearningOutcomeId], cloAssignment.Id)
+		if !isAdded[cloAssignment.Id] {
+			isAdded[cloAssignment.Id] = true
+			source.Assignments = append(source.Assignments, cloAssignment.AttainmentAssignment)
+		}
+	}
+	for _, clo := range clos {
+		source.Clos = append(source.Clos, entity.AttainmentClo{
+			Id:                                  clo.Id,
+			ExpectedPassingAssignmentPercentage: clo.ExpectedPassingAssignmentPercentage,
+			AssignmentIds:                       assignmentIdsByClo[clo.Id],
+		})
+	}
+
+	if len(source.StudentIds) > 0 {
+		err = tx.Raw(`
+			SELECT score.student_id, score.assignment_id, score.score
+			FROM score
+			JOIN assignment ON assignment.id = score.assignment_id
+			JOIN assignment_group ON assignment_group.id = assignment.assignment_group_id
+			WHERE assignment_group.course_id = ? AND assignment.is_included_in_clo IS TRUE AND score.student_id IN ?
+			UNION ALL
+			SELECT assessment_item_score.student_id, assessment_item_score.assessment_item_id AS assignment_id, assessment_item_score.score
+			FROM assessment_item_score
+			JOIN assessment_item ON assessment_item.id = assessment_item_score.assessment_item_id
+			JOIN assignment ON assignment.id = assessment_item.assignment_id
+			JOIN assignment_group ON assignment_group.id = assignment.assignment_group_id
+			WHERE assignment_group.course_id = ? AND assignment.is_included_in_clo IS TRUE AND assessment_item_score.student_id IN ?
+		`, courseId, source.StudentIds, courseId, source.StudentIds).Scan(&source.Scores).Error
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	links := []struct {
+		query  string
+		target *[]entity.AttainmentOutcomeLink
+	}{
+		{`
+			SELECT clo_subplo.course_learning_outcome_id, sub_program_learning_outcome.program_learning_outcome_id AS outcome_id, MAX(clo_subplo.weight) AS weight
+			FROM clo_subplo
+			JOIN sub_program_learning_outcome ON sub_program_learning_outcome.id = clo_subplo.sub_program_learning_outcome_id
+			JOIN course_learning_outcome ON course_learning_outcome.id = clo_subplo.course_learning_outcome_id
+			WHERE course_learning_outcome.course_id = ?
+			GROUP BY clo_subplo.course_learning_outcome_id, sub_program_learning_outcome.program_learning_outcome_id
+		`, &source.PloLinks},
+		{`
+			SELECT clo_subplo.course_learning_outcome_id, clo_subplo.sub_program_learning_outcome_id AS outcome_id, clo_subplo.weight
+			FROM clo_subplo
+			JOIN course_learning_outcome ON course_learning_outcome.id = clo_subplo.course_learning_outcome_id
+			WHERE course_learning_outcome.course_id = ?
+		`, &source.SubPloLinks},
+		{`
+			SELECT clo_po.course_learning_outcome_id, clo_po.program_outcome_id AS outcome_id, clo_po.weight
+			FROM clo_po
+			JOIN course_learning_outcome ON course_learning_outcome.id = clo_po.course_learning_outcome_id
+			WHERE course_learning_outcome.course_id = ?
+		`, &source.PoLinks},
+		{`
+			SELECT clo_subso.course_learning_outcome_id, sub_student_outcome.student_outcome_id AS outcome_id, MAX(clo_subso.weight) AS weight
+			FROM clo_subso
+			JOIN sub_student_outcome ON sub_student_outcome.id = clo_subso.sub_student_outcome_id
+			JOIN course_learning_outcome ON course_learning_outcome.id = clo_subso.course_learning_outcome_id
+			WHERE course_learning_outcome.course_id = ?
+			GROUP BY clo_subso.course_learning_outcome_id, sub_student_outcome.student_outcome_id
+		`, &source.SoLinks},
+		{`
+			SELECT clo_subso.course_learning_outcome_id, clo_subso.sub_student_outcome_id AS outcome_id, clo_subso.weight
+			FROM clo_subso
+			JOIN course_learning_outcome ON course_learning_outcome.id = clo_subso.course_learning_outcome_id
+			WHERE course_learning_outcome.course_id = ?
+		`, &source.SubSoLinks},
+	}
+	for _, link := range links {
+		err = tx.Raw(link.query, courseId).Scan(link.target).Error
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &source, nil
+}
+
+func replaceAttainments(tx *gorm.DB, courseId string, studentIds []string, isWholeCourse bool, attainment entity.CourseAttainment) error {
+	for _, model := range []interface{}{
+		&entity.StudentCloAttainment{},
+		&entity.StudentPloAttainment{},
+		&entity.StudentSubPloAttainment{},
+		&entity.StudentPoAttainment{},
+		&entity.StudentSoAttainment{},
+		&entity.StudentSubSoAttainment{},
+	} {
+		query := tx.Where("course_id = ?", courseId)
+		if !isWholeCourse {
+			query = query.Where("student_id IN ?", studentIds)
+		}
+
+		err := query.Delete(model).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, rows := range []struct {
+		count int
+		value interface{}
+	}{
+		{len(attainment.Clos), attainment.Clos},
+		{len(attainment.Plos), attainment.Plos},
+		{len(attainment.SubPlos), attainment.SubPlos},
+		{len(attainment.Pos), attainment.Pos},
+		{len(attainment.Sos), attainment.Sos},
+		{len(attainment.SubSos), attainment.SubSos},
+	} {
+		if rows.count == 0 {
+			continue
+		}
+
+		err := tx.CreateInBatches(rows.value, attainmentBatchSize).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// queueAttainmentRefresh marks the students of a course stale in the same transaction as the change, the whole course when no student is given
+func queueAttainmentRefresh(tx *gorm.DB, courseId string, studentIds ...string) error {
+	if courseId == "" {
+		return nil
+	} else if len(studentIds) == 0 {
+		studentIds = []string{""}
+	}
+
+	now := time.Now()
+	refreshes := make([]entity.AttainmentRefresh, 0, len(studentIds))
+	for _, studentId := range studentIds {
+		refreshes = append(refreshes, entity.AttainmentRefresh{CourseId: courseId, StudentId: studentId, RequestedAt: now})
+	}
+
+	return tx.Clauses(clause.OnConflict{DoUpdates: clause.AssignmentColumns([]string{"requested_at"})}).
+		CreateInBatches(refreshes, attainmentBatchSize).Error
+}
+
+// queueAttainmentRefreshByAssignment marks the courses of the assignments stale, only the given students when there are any
+func queueAttainmentRefreshByAssignment(tx *gorm.DB, assignmentIds []string, studentIds ...string) error {
+	var courseIds []string
+	err := tx.Table("assignment").
+		Distinct("assignment_group.course_id").
+		Joins("JOIN assignment_group ON assignment_group.id = assignment.assignment_group_id").
+		Where("assignment.id IN ?", assignmentIds).
+		Scan(&courseIds).Error
+	if err != nil {
+		return err
+	}
+
+	for _, courseId := range courseIds {
+		err = queueAttainmentRefresh(tx, courseId, studentIds...)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func queueAttainmentRefreshByAssessmentItem(tx *gorm.DB, assessmentItemId string) error {
+	var assignmentIds []string
+	err := tx.Model(&entity.AssessmentItem{}).Where("id = ?", assessmentItemId).Pluck("assignment_id", &assignmentIds).Error
+	if err != nil || len(assignmentIds) == 0 {
+		return err
+	}
+
+	return queueAttainmentRefreshByAssignment(tx, assignmentIds)
+}
+
+func queueAttainmentRefreshByClo(tx *gorm.DB, cloId string) error {
+	var courseIds []string
+	err := tx.Model(&entity.CourseLearningOutcome{}).Where("id = ?", cloId).Pluck("course_id", &courseIds).Error
+	if err != nil {
+		return err
+	}
+
+	for _, courseId := range courseIds {
+		err = queueAttainmentRefresh(tx, courseId)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// queueAttainmentRefreshByProgramme marks stale the courses of the programme using its attainment method
+func queueAttainmentRefreshByProgramme(tx *gorm.DB, programmeId string) error {
+	var courseIds []string
+	err := tx.Model(&entity.Course{}).
+		Where("programme_id = ? AND (attainment_method IS NULL OR attainment_method = '')", programmeId).
+		Pluck("id", &courseIds).Error
+	if err != nil {
+		return err
+	}
+
+	for _, courseId := range courseIds {
+		err = queueAttainmentRefresh(tx, courseId)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// queueAttainmentRefreshByOutcomeLink marks stale the courses with CLOs linked to the outcome through the join table
+func queueAttainmentRefreshByOutcomeLink(tx *gorm.DB, joinTable string, outcomeColumn string, outcomeId string) error {
+	var courseIds []string
+	err := tx.Table(joinTable).
+		Distinct("course_learning_outcome.course_id").
+		Joins(fmt.Sprintf("JOIN course_learning_outcome ON course_learning_outcome.id = %s.course_learning_outcome_id", joinTable)).
+		Where(fmt.Sprintf("%s.%s = ?", joinTable, outcomeColumn), outcomeId).
+		Scan(&courseIds).Error
+	if err != nil {
+		return err
+	}
+
+	for _, courseId := range courseIds {
+		err = queueAttainmentRefresh(tx, courseId)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -89,7 +89,15 @@ func (r courseRepositoryGorm) Create(course *entity.Course) error {
 }
 
 func (r courseRepositoryGorm) Update(id string, course *entity.Course) error {
-	err := r.gorm.Model(&entity.Course{}).Where("id = ?", id).Updates(course).Error
+	err := r.gorm.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&entity.Course{}).Where("id = ?", id).Updates(course).Error
+		if err != nil {
+			return err
+		}
+
+		// the expected passing CLO percentage or the attainment method may have changed
+		return queueAttainmentRefresh(tx, id)
+	})
 	if err != nil {
 		return fmt.Errorf("cannot update course: %w", err)
 	}
@@ -98,7 +106,15 @@ func (r courseRepositoryGorm) Update(id string, course *entity.Course) error {
 }
 
 func (r courseRepositoryGorm) Delete(id string) error {
-	err := r.gorm.Delete(&entity.Course{Id: id}).Error
+	err := r.gorm.Transaction(func(tx *gorm.DB) error {
+		err := tx.Delete(&entity.Course{Id: id}).Error
+		if err != nil {
+			return err
+		}
+
+		// the attainments of a course that is gone are removed by the refresh
+		return queueAttainmentRefresh(tx, id)
+	})
 
 	if err != nil {
 		return fmt.Errorf("cannot delete course: %w", err)
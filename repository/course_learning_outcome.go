@@ -5,6 +5,7 @@ import (
 
 	"github.com/team-inu/inu-backyard/entity"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type courseLearningOutcomeRepositoryGorm struct {
@@ -55,69 +56,63 @@ func (r courseLearningOutcomeRepositoryGorm) GetByCourseId(courseId string) ([]e
 }
 
 func (r courseLearningOutcomeRepositoryGorm) Create(courseLearningOutcome *entity.CourseLearningOutcome) error {
-	// go cacheOutcomes(r.gorm, TabeeSelectorAllPloCourses)
-	// go cacheOutcomes(r.gorm, TabeeSelectorAllPoCourses)
 	return r.gorm.Create(&courseLearningOutcome).Error
 }
 
-func (r courseLearningOutcomeRepositoryGorm) CreateLinkProgramOutcome(id string, programOutcomeIds []string) error {
-	var query string
+func (r courseLearningOutcomeRepositoryGorm) CreateLinkProgramOutcome(id string, programOutcomeIds []string, weights map[string]float64) error {
+	links := make([]entity.CloPo, 0, len(programOutcomeIds))
 	for _, poId := range programOutcomeIds {
-		query += fmt.Sprintf("('%s', '%s'),", id, poId)
+		links = append(links, entity.CloPo{CourseLearningOutcomeId: id, ProgramOutcomeId: poId, Weight: weights[poId]})
 	}
 
-	query = query[:len(query)-1]
-
-	err := r.gorm.Exec(fmt.Sprintf("INSERT INTO `clo_po` (course_learning_outcome_id, program_outcome_id) VALUES %s", query)).Error
-
+	err := r.createOutcomeLinks(id, &links)
 	if err != nil {
 		return fmt.Errorf("cannot create link between CLO and PO: %w", err)
 	}
-	// go cacheOutcomes(r.gorm, TabeeSelectorAllPloCourses)
-	// go cacheOutcomes(r.gorm, TabeeSelectorAllPoCourses)
 
 	return nil
 }
 
-func (r courseLearningOutcomeRepositoryGorm) CreateLinkSubProgramLearningOutcome(id string, subProgramLearningOutcomeIds []string) error {
-
-	var query string
+func (r courseLearningOutcomeRepositoryGorm) CreateLinkSubProgramLearningOutcome(id string, subProgramLearningOutcomeIds []string, weights map[string]float64) error {
+	links := make([]entity.CloSubplo, 0, len(subProgramLearningOutcomeIds))
 	for _, sploId := range subProgramLearningOutcomeIds {
-		query += fmt.Sprintf("('%s', '%s'),", id, sploId)
+		links = append(links, entity.CloSubplo{CourseLearningOutcomeId: id, SubProgramLearningOutcomeId: sploId, Weight: weights[sploId]})
 	}
 
-	query = query[:len(query)-1]
-
-	err := r.gorm.Exec(fmt.Sprintf("INSERT INTO `clo_subplo` (course_learning_outcome_id, sub_program_learning_outcome_id) VALUES %s", query)).Error
-
+	err := r.createOutcomeLinks(id, &links)
 	if err != nil {
 		return fmt.Errorf("cannot create link between CLO and SPLO: %w", err)
 	}
-	// go cacheOutcomes(r.gorm, TabeeSelectorAllPloCourses)
-	// go cacheOutcomes(r.gorm, TabeeSelectorAllPoCourses)
 
 	return nil
 }
 
-func (r courseLearningOutcomeRepositoryGorm) CreateLinkSubStudentOutcome(id string, subStudentOutcomeIds []string) error {
-	var query string
+func (r courseLearningOutcomeRepositoryGorm) CreateLinkSubStudentOutcome(id string, subStudentOutcomeIds []string, weights map[string]float64) error {
+	links := make([]entity.CloSubso, 0, len(subStudentOutcomeIds))
 	for _, ssoId := range subStudentOutcomeIds {
-		query += fmt.Sprintf("('%s', '%s'),", id, ssoId)
+		links = append(links, entity.CloSubso{CourseLearningOutcomeId: id, SubStudentOutcomeId: ssoId, Weight: weights[ssoId]})
 	}
 
-	query = query[:len(query)-1]
-
-	err := r.gorm.Exec(fmt.Sprintf("INSERT INTO `clo_subso` (course_learning_outcome_id, sub_student_outcome_id) VALUES %s", query)).Error
-
+	err := r.createOutcomeLinks(id, &links)
 	if err != nil {
 		return fmt.Errorf("cannot create link between CLO and SSO: %w", err)
 	}
-	// go cacheOutcomes(r.gorm, TabeeSelectorAllPloCourses)
-	// go cacheOutcomes(r.gorm, TabeeSelectorAllPoCourses)
 
 	return nil
 }
 
+// createOutcomeLinks upserts the links of the CLO, an existing link gets the new weight
+func (r courseLearningOutcomeRepositoryGorm) createOutcomeLinks(id string, links interface{}) error {
+	return r.gorm.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.OnConflict{DoUpdates: clause.AssignmentColumns([]string{"weight"})}).Create(links).Error
+		if err != nil {
+			return err
+		}
+
+		return queueAttainmentRefreshByClo(tx, id)
+	})
+}
+
 func (r courseLearningOutcomeRepositoryGorm) CreateMany(courseLeaningOutcome []entity.CourseLearningOutcome) error {
 	return nil
 }
@@ -127,15 +122,24 @@ func (r courseLearningOutcomeRepositoryGorm) Update(id string, courseLearningOut
 	if err != nil {
 		return fmt.Errorf("cannot update courseLearningOutcome: %w", err)
 	}
-	// go cacheOutcomes(r.gorm, TabeeSelectorAllPloCourses)
-	// go cacheOutcomes(r.gorm, TabeeSelectorAllPoCourses)
+
+	err = queueAttainmentRefreshByClo(r.gorm, id)
+	if err != nil {
+		return fmt.Errorf("cannot queue attainment refresh of CLO: %w", err)
+	}
 
 	return nil
 }
 
 func (r courseLearningOutcomeRepositoryGorm) Delete(id string) error {
+	// the course is found through the CLO so it is queued before the CLO is gone
+	err := queueAttainmentRefreshByClo(r.gorm, id)
+	if err != nil {
+		return fmt.Errorf("cannot queue attainment refresh of CLO: %w", err)
+	}
+
 	// Delete the courseLearningOutcome by ID in clo_po, clo_subplo, and clo_subso tables
-	err := r.gorm.Exec("DELETE FROM `clo_po` WHERE course_learning_outcome_id = ?", id).Error
+	err = r.gorm.Exec("DELETE FROM `clo_po` WHERE course_learning_outcome_id = ?", id).Error
 	if err != nil {
 		return fmt.Errorf("cannot delete link between CLO and PO: %w", err)
 	}
@@ -153,9 +157,6 @@ func (r courseLearningOutcomeRepositoryGorm) Delete(id string) error {
 		return fmt.Errorf("cannot delete courseLearningOutcome: %w", err)
 	}
 
-	// go cacheOutcomes(r.gorm, TabeeSelectorAllPloCourses)
-	// go cacheOutcomes(r.gorm, TabeeSelectorAllPoCourses)
-
 	return nil
 }
 
@@ -165,8 +166,10 @@ func (r courseLearningOutcomeRepositoryGorm) DeleteLinkProgramOutcome(id string,
 		return fmt.Errorf("cannot delete link between CLO and PO: %w", err)
 	}
 
-	// go cacheOutcomes(r.gorm, TabeeSelectorAllPloCourses)
-	// go cacheOutcomes(r.gorm, TabeeSelectorAllPoCourses)
+	err = queueAttainmentRefreshByClo(r.gorm, id)
+	if err != nil {
+		return fmt.Errorf("cannot queue attainment refresh of CLO: %w", err)
+	}
 
 	return nil
 }
@@ -178,8 +181,11 @@ func (r courseLearningOutcomeRepositoryGorm) DeleteLinkSubProgramLearningOutcome
 	if err != nil {
 		return fmt.Errorf("cannot delete link between CLO and SPLO: %w", err)
 	}
-	// go cacheOutcomes(r.gorm, TabeeSelectorAllPloCourses)
-	// go cacheOutcomes(r.gorm, TabeeSelectorAllPoCourses)
+
+	err = queueAttainmentRefreshByClo(r.gorm, id)
+	if err != nil {
+		return fmt.Errorf("cannot queue attainment refresh of CLO: %w", err)
+	}
 
 	return nil
 }
@@ -190,8 +196,11 @@ func (r courseLearningOutcomeRepositoryGorm) DeleteLinkSubStudentOutcome(id stri
 	if err != nil {
 		return fmt.Errorf("cannot delete link between CLO and SSO: %w", err)
 	}
-	// go cacheOutcomes(r.gorm, TabeeSelectorAllPloCourses)
-	// go cacheOutcomes(r.gorm, TabeeSelectorAllPoCourses)
+
+	err = queueAttainmentRefreshByClo(r.gorm, id)
+	if err != nil {
+		return fmt.Errorf("cannot queue attainment refresh of CLO: %w", err)
+	}
 
 	return nil
 }
@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/team-inu/inu-backyard/entity"
+	"gorm.io/gorm"
+)
+
+type curriculumMapRepositoryGorm struct {
+	gorm *gorm.DB
+}
+
+func NewCurriculumMapRepositoryGorm(gorm *gorm.DB) entity.CurriculumMapRepository {
+	return &curriculumMapRepositoryGorm{gorm: gorm}
+}
+
+func (r curriculumMapRepositoryGorm) GetCourseOutcomeLinks(programmeId string) ([]entity.CurriculumCourseOutcomeLink, error) {
+	var links []entity.CurriculumCourseOutcomeLink
+
+	err := r.gorm.Raw(`
+	SELECT
+		c.code AS course_code,
+		c.name AS course_name,
+		clo.id AS clo_id,
+		splo.program_learning_outcome_id AS plo_id,
+		splo.id AS splo_id,
+		sso.student_outcome_id AS so_id
+	FROM
+		course c
+	LEFT JOIN course_learning_outcome clo ON
+		clo.course_id = c.id
+	LEFT JOIN clo_subplo ON
+		clo_subplo.course_learning_outcome_id = clo.id
+	LEFT JOIN sub_program_learning_outcome splo ON
+		splo.id = clo_subplo.sub_program_learning_outcome_id
+	LEFT JOIN clo_subso ON
+		clo_subso.course_learning_outcome_id = clo.id
+	LEFT JOIN sub_student_outcome sso ON
+		sso.id = clo_subso.sub_student_outcome_id
+	WHERE
+		c.programme_id = ?
+	ORDER BY
+		c.code;
+	`, programmeId).Scan(&links).Error
+	if err != nil {
+		return nil, fmt.Errorf("cannot query to get course outcome links: %w", err)
+	}
+
+	return links, nil
+}
+
+func (r curriculumMapRepositoryGorm) GetLevels(programmeId string) ([]entity.CurriculumMapLevel, error) {
+	var levels []entity.CurriculumMapLevel
+
+	err := r.gorm.Where("programme_id = ?", programmeId).Find(&levels).Error
+	if err != nil {
+		return nil, fmt.Errorf("cannot query to get curriculum map levels: %w", err)
+	}
+
+	return levels, nil
+}
+
+func (r curriculumMapRepositoryGorm) GetLevelById(id string) (*entity.CurriculumMapLevel, error) {
+	var level entity.CurriculumMapLevel
+
+	err := r.gorm.Where("id = ?", id).First(&level).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("cannot query to get curriculum map level by id: %w", err)
+	}
+
+	return &level, nil
+}
+
+func (r curriculumMapRepositoryGorm) GetLevel(programmeId string, courseCode string, outcomeType entity.OutcomeType, outcomeId string) (*entity.CurriculumMapLevel, error) {
+	var level entity.CurriculumMapLevel
+
+	err := r.gorm.Where(&entity.CurriculumMapLevel{
+		ProgrammeId: programmeId,
+		CourseCode:  courseCode,
+		OutcomeType: outcomeType,
+		OutcomeId:   outcomeId,
+	}).First(&level).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("cannot query to get curriculum map level: %w", err)
+	}
+
+	return &level, nil
+}
+
+func (r curriculumMapRepositoryGorm) CreateLevel(level *entity.CurriculumMapLevel) error {
+	err := r.gorm.Create(level).Error
+	if err != nil {
+		return fmt.Errorf("cannot create curriculum map level: %w", err)
+	}
+
+	return nil
+}
+
+func (r curriculumMapRepositoryGorm) UpdateLevel(id string, level *entity.CurriculumMapLevel) error {
+	err := r.gorm.Model(&entity.CurriculumMapLevel{}).Where("id = ?", id).Updates(level).Error
+	if err != nil {
+		return fmt.Errorf("cannot update curriculum map level: %w", err)
+	}
+
+	return nil
+}
+
+func (r curriculumMapRepositoryGorm) DeleteLevel(id string) error {
+	err := r.gorm.Delete(&entity.CurriculumMapLevel{Id: id}).Error
+	if err != nil {
+		return fmt.Errorf("cannot delete curriculum map level: %w", err)
+	}
+
+	return nil
+}
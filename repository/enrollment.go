@@ -91,15 +91,25 @@ func (r enrollmentRepositoryGorm) GetByStudentId(studentId string) ([]entity.Enr
 }
 
 func (r enrollmentRepositoryGorm) CreateMany(enrollments []entity.Enrollment) error {
-	go cacheOutcomes(r.gorm, TabeeSelectorAllPloCourses)
-	go cacheOutcomes(r.gorm, TabeeSelectorAllPoCourses)
-	return r.gorm.Create(&enrollments).Error
+	return r.gorm.Transaction(func(tx *gorm.DB) error {
+		err := tx.Create(&enrollments).Error
+		if err != nil {
+			return err
+		}
+
+		return queueEnrollmentAttainmentRefresh(tx, enrollments)
+	})
 }
 
 func (r enrollmentRepositoryGorm) Create(enrollment *entity.Enrollment) error {
-	go cacheOutcomes(r.gorm, TabeeSelectorAllPloCourses)
-	go cacheOutcomes(r.gorm, TabeeSelectorAllPoCourses)
-	return r.gorm.Create(&enrollment).Error
+	return r.gorm.Transaction(func(tx *gorm.DB) error {
+		err := tx.Create(&enrollment).Error
+		if err != nil {
+			return err
+		}
+
+		return queueAttainmentRefresh(tx, enrollment.CourseId, enrollment.StudentId)
+	})
 }
 
 func (r enrollmentRepositoryGorm) Update(id string, enrollment *entity.Enrollment) error {
@@ -110,24 +120,59 @@ func (r enrollmentRepositoryGorm) Update(id string, enrollment *entity.Enrollmen
 		return fmt.Errorf("cannot get enrollment while updating enrollment: %w", err)
 	}
 
+	// the old course and student are kept aside since Updates writes the new values into oldEnrollment
+	stale := []entity.Enrollment{*oldEnrollment, *enrollment}
+
 	//update old enrollment with new name
-	err = r.gorm.Model(&oldEnrollment).Updates(enrollment).Error
+	err = r.gorm.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&oldEnrollment).Updates(enrollment).Error
+		if err != nil {
+			return err
+		}
+
+		return queueEnrollmentAttainmentRefresh(tx, stale)
+	})
 	if err != nil {
 		return fmt.Errorf("cannot update enrollment by id: %w", err)
 	}
-	go cacheOutcomes(r.gorm, TabeeSelectorAllPloCourses)
-	go cacheOutcomes(r.gorm, TabeeSelectorAllPoCourses)
 
 	return nil
 }
 
 func (r enrollmentRepositoryGorm) Delete(id string) error {
-	err := r.gorm.Where("id = ?", id).Delete(&entity.Enrollment{}).Error
+	err := r.gorm.Transaction(func(tx *gorm.DB) error {
+		var enrollments []entity.Enrollment
+		err := tx.Where("id = ?", id).Find(&enrollments).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Where("id = ?", id).Delete(&entity.Enrollment{}).Error
+		if err != nil {
+			return err
+		}
+
+		return queueEnrollmentAttainmentRefresh(tx, enrollments)
+	})
 	if err != nil {
 		return fmt.Errorf("cannot delete enrollment by id: %w", err)
 	}
-	go cacheOutcomes(r.gorm, TabeeSelectorAllPloCourses)
-	go cacheOutcomes(r.gorm, TabeeSelectorAllPoCourses)
+
+	return nil
+}
+
+// queueEnrollmentAttainmentRefresh marks the enrolled students stale in their courses, missing fields are skipped
+func queueEnrollmentAttainmentRefresh(tx *gorm.DB, enrollments []entity.Enrollment) error {
+	for _, enrollment := range enrollments {
+		if enrollment.CourseId == "" || enrollment.StudentId == "" {
+			continue
+		}
+
+		err := queueAttainmentRefresh(tx, enrollment.CourseId, enrollment.StudentId)
+		if err != nil {
+			return err
+		}
+	}
 
 	return nil
 }
@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/team-inu/inu-backyard/entity"
+	"gorm.io/gorm"
+)
+
+type feedbackRepositoryGorm struct {
+	gorm *gorm.DB
+}
+
+func NewFeedbackRepositoryGorm(gorm *gorm.DB) entity.FeedbackRepository {
+	return &feedbackRepositoryGorm{gorm: gorm}
+}
+
+func (r feedbackRepositoryGorm) GetByCourseId(courseId string) ([]entity.Feedback, error) {
+	var feedbacks []entity.Feedback
+
+	err := r.gorm.Where("course_id = ?", courseId).Find(&feedbacks).Error
+	if err != nil {
+		return nil, fmt.Errorf("cannot query to get feedbacks by course id: %w", err)
+	}
+
+	return feedbacks, nil
+}
+
+func (r feedbackRepositoryGorm) GetByLecturerId(lecturerId string, semesterId string) ([]entity.Feedback, error) {
+	var feedbacks []entity.Feedback
+
+	db := r.gorm.
+		Joins("JOIN course_lecturer ON course_lecturer.course_id = feedback.course_id").
+		Where("course_lecturer.user_id = ?", lecturerId)
+	if semesterId != "" {
+		db = db.Joins("JOIN course ON course.id = feedback.course_id").Where("course.semester_id = ?", semesterId)
+	}
+
+	err := db.Preload("Course.Semester").Find(&feedbacks).Error
+	if err != nil {
+		return nil, fmt.Errorf("cannot query to get feedbacks by lecturer id: %w", err)
+	}
+
+	return feedbacks, nil
+}
+
+func (r feedbackRepositoryGorm) GetByCourseIdAndStudentId(courseId string, studentId string) (*entity.Feedback, error) {
+	var feedback entity.Feedback
+
+	err := r.gorm.Where("course_id = ? AND student_id = ?", courseId, studentId).First(&feedback).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("cannot query to get feedback: %w", err)
+	}
+
+	return &feedback, nil
+}
+
+func (r feedbackRepositoryGorm) Create(feedback *entity.Feedback) error {
+	err := r.gorm.Omit("Course", "Student").Create(feedback).Error
+	if err != nil {
+		return fmt.Errorf("cannot create feedback: %w", err)
+	}
+
+	return nil
+}
+
+func (r feedbackRepositoryGorm) CreateToken(token *entity.FeedbackToken) error {
+	err := r.gorm.Create(token).Error
+	if err != nil {
+		return fmt.Errorf("cannot create feedback token: %w", err)
+	}
+
+	return nil
+}
+
+func (r feedbackRepositoryGorm) GetTokenByHash(tokenHash string) (*entity.FeedbackToken, error) {
+	var token entity.FeedbackToken
+	err := r.gorm.Where("token_hash = ?", tokenHash).First(&token).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("cannot query feedback token by hash: %w", err)
+	}
+
+	return &token, nil
+}
+
+func (r feedbackRepositoryGorm) CreateWithToken(tokenId string, feedback *entity.Feedback) (bool, error) {
+	accepted := false
+
+	err := r.gorm.Transaction(func(tx *gorm.DB) error {
+		// consuming the token first keeps concurrent submissions of one link from both succeeding
+		result := tx.Model(&entity.FeedbackToken{}).
+			Where("id = ? AND used_at IS NULL", tokenId).
+			Update("used_at", time.Now())
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		err := tx.Omit("Course", "Student").Create(feedback).Error
+		if err != nil {
+			return err
+		}
+
+		accepted = true
+		return nil
+	})
+	if err != nil {
+		return false, fmt.Errorf("cannot create feedback with token: %w", err)
+	}
+
+	return accepted, nil
+}
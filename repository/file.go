@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/team-inu/inu-backyard/entity"
+	"gorm.io/gorm"
+)
+
+type fileRepositoryGorm struct {
+	gorm *gorm.DB
+}
+
+func NewFileRepositoryGorm(gorm *gorm.DB) entity.FileRepository {
+	return &fileRepositoryGorm{gorm: gorm}
+}
+
+func (r fileRepositoryGorm) Create(file *entity.StoredFile) error {
+	err := r.gorm.Create(file).Error
+	if err != nil {
+		return fmt.Errorf("cannot query to create file: %w", err)
+	}
+
+	return nil
+}
+
+func (r fileRepositoryGorm) GetById(id string) (*entity.StoredFile, error) {
+	var file entity.StoredFile
+	err := r.gorm.Where("id = ?", id).First(&file).Error
+
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("cannot query to get file: %w", err)
+	}
+
+	return &file, nil
+}
+
+func (r fileRepositoryGorm) GetByIds(ids []string) ([]entity.StoredFile, error) {
+	var files []entity.StoredFile
+	err := r.gorm.Where("id IN ?", ids).Find(&files).Error
+	if err != nil {
+		return nil, fmt.Errorf("cannot query to get files: %w", err)
+	}
+
+	return files, nil
+}
+
+func (r fileRepositoryGorm) Delete(ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	err := r.gorm.Where("id IN ?", ids).Delete(&entity.StoredFile{}).Error
+	if err != nil {
+		return fmt.Errorf("cannot query to delete files: %w", err)
+	}
+
+	return nil
+}
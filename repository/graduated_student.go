@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/team-inu/inu-backyard/entity"
+	"gorm.io/gorm"
+)
+
+type graduatedStudentRepositoryGorm struct {
+	gorm *gorm.DB
+}
+
+func NewGraduatedStudentRepositoryGorm(gorm *gorm.DB) entity.GraduatedStudentRepository {
+	return &graduatedStudentRepositoryGorm{gorm: gorm}
+}
+
+func (r graduatedStudentRepositoryGorm) GetAll() ([]entity.GraduatedStudent, error) {
+	var graduatedStudents []entity.GraduatedStudent
+
+	err := r.gorm.Joins("Student").Find(&graduatedStudents).Error
+	if err != nil {
+		return nil, fmt.Errorf("cannot query to get graduated students: %w", err)
+	}
+
+	return graduatedStudents, nil
+}
+
+func (r graduatedStudentRepositoryGorm) GetByParams(programmeId string, year string) ([]entity.GraduatedStudent, error) {
+	var graduatedStudents []entity.GraduatedStudent
+
+	db := r.gorm.Joins("Student")
+	if programmeId != "" {
+		db = db.Where("`Student`.`programme_id` = ?", programmeId)
+	}
+	if year != "" {
+		db = db.Where("`graduated_student`.`year` = ?", year)
+	}
+
+	err := db.Order("`graduated_student`.`year`").Find(&graduatedStudents).Error
+	if err != nil {
+		return nil, fmt.Errorf("cannot query to get graduated students by params: %w", err)
+	}
+
+	return graduatedStudents, nil
+}
+
+func (r graduatedStudentRepositoryGorm) GetById(id string) (*entity.GraduatedStudent, error) {
+	var graduatedStudent entity.GraduatedStudent
+
+	err := r.gorm.Joins("Student").Where("`graduated_student`.`id` = ?", id).First(&graduatedStudent).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("cannot query to get graduated student by id: %w", err)
+	}
+
+	return &graduatedStudent, nil
+}
+
+func (r graduatedStudentRepositoryGorm) Create(graduatedStudent *entity.GraduatedStudent) error {
+	err := r.gorm.Omit("Student").Create(graduatedStudent).Error
+	if err != nil {
+		return fmt.Errorf("cannot create graduated student: %w", err)
+	}
+
+	return nil
+}
+
+func (r graduatedStudentRepositoryGorm) CreateMany(graduatedStudents []entity.GraduatedStudent) error {
+	err := r.gorm.Omit("Student").Create(&graduatedStudents).Error
+	if err != nil {
+		return fmt.Errorf("cannot create graduated students: %w", err)
+	}
+
+	return nil
+}
+
+func (r graduatedStudentRepositoryGorm) Update(id string, graduatedStudent *entity.GraduatedStudent) error {
+	err := r.gorm.Model(&entity.GraduatedStudent{}).Omit("Student").Where("id = ?", id).Updates(graduatedStudent).Error
+	if err != nil {
+		return fmt.Errorf("cannot update graduated student: %w", err)
+	}
+
+	return nil
+}
+
+func (r graduatedStudentRepositoryGorm) Delete(id string) error {
+	err := r.gorm.Delete(&entity.GraduatedStudent{Id: id}).Error
+	if err != nil {
+		return fmt.Errorf("cannot delete graduated student: %w", err)
+	}
+
+	return nil
+}
+
+func (r graduatedStudentRepositoryGorm) FilterExisted(studentIds []string) ([]string, error) {
+	var existedIds []string
+
+	err := r.gorm.Raw("SELECT student_id FROM `graduated_student` WHERE student_id in ?", studentIds).Scan(&existedIds).Error
+	if err != nil {
+		return nil, fmt.Errorf("cannot query graduated student: %w", err)
+	}
+
+	return existedIds, nil
+}
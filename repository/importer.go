@@ -31,6 +31,10 @@ func (r ImporterRepositoryGorm) UpdateOrCreate(
 	isDelete bool,
 ) error {
 	err := r.gorm.Transaction(func(tx *gorm.DB) error {
+		if err := queueAttainmentRefresh(tx, courseId); err != nil {
+			return fmt.Errorf("cannot queue attainment refresh while import course: %w", err)
+		}
+
 		if err := tx.Exec("DELETE FROM score WHERE assignment_id IN ?", oldAssignmentIds).Error; err != nil {
 			return fmt.Errorf("cannot clear old score while import course: %w", err)
 		}
@@ -93,14 +97,16 @@ func (r ImporterRepositoryGorm) UpdateOrCreate(
 
 		return nil
 	})
-	go cacheOutcomes(r.gorm, TabeeSelectorAllPloCourses)
-	go cacheOutcomes(r.gorm, TabeeSelectorAllPoCourses)
 
 	return err
 }
 
 func (r ImporterRepositoryGorm) Delete(courseId string, oldAssignmentGroupIds []string, oldAssignmentIds []string, oldCloIds []string) error {
 	err := r.gorm.Transaction(func(tx *gorm.DB) error {
+		if err := queueAttainmentRefresh(tx, courseId); err != nil {
+			return fmt.Errorf("cannot queue attainment refresh: %w", err)
+		}
+
 		if err := tx.Exec("DELETE FROM score WHERE assignment_id IN ?", oldAssignmentIds).Error; err != nil {
 			return fmt.Errorf("cannot clear old score: %w", err)
 		}
@@ -139,8 +145,5 @@ func (r ImporterRepositoryGorm) Delete(courseId string, oldAssignmentGroupIds []
 		return fmt.Errorf("cannot delete course: %w", err)
 	}
 
-	go cacheOutcomes(r.gorm, TabeeSelectorAllPloCourses)
-	go cacheOutcomes(r.gorm, TabeeSelectorAllPoCourses)
-
 	return nil
 }
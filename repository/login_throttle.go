@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/team-inu/inu-backyard/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type loginThrottleRepositoryGorm struct {
+	gorm *gorm.DB
+}
+
+func NewLoginThrottleRepositoryGorm(gorm *gorm.DB) entity.LoginThrottleRepository {
+	return &loginThrottleRepositoryGorm{gorm: gorm}
+}
+
+func (r loginThrottleRepositoryGorm) GetByKeys(keys []string) ([]entity.LoginThrottle, error) {
+	var throttles []entity.LoginThrottle
+	err := r.gorm.Where("throttle_key IN ?", keys).Find(&throttles).Error
+	if err != nil {
+		return nil, fmt.Errorf("cannot query to get login throttles: %w", err)
+	}
+
+	return throttles, nil
+}
+
+func (r loginThrottleRepositoryGorm) Update(key string, update func(throttle *entity.LoginThrottle)) (*entity.LoginThrottle, error) {
+	var throttle entity.LoginThrottle
+	err := r.gorm.Transaction(func(tx *gorm.DB) error {
+		// replicas may insert the same key at once, the row lock below serializes the update
+		err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&entity.LoginThrottle{Key: key, LastFailedAt: time.Now()}).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("throttle_key = ?", key).First(&throttle).Error
+		if err != nil {
+			return err
+		}
+
+		update(&throttle)
+
+		return tx.Save(&throttle).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot query to update login throttle: %w", err)
+	}
+
+	return &throttle, nil
+}
+
+func (r loginThrottleRepositoryGorm) DeleteByKeys(keys []string) error {
+	err := r.gorm.Where("throttle_key IN ?", keys).Delete(&entity.LoginThrottle{}).Error
+	if err != nil {
+		return fmt.Errorf("cannot query to delete login throttles: %w", err)
+	}
+
+	return nil
+}
+
+func (r loginThrottleRepositoryGorm) DeleteStale(before time.Time) (int64, error) {
+	result := r.gorm.Where("last_failed_at < ? AND (locked_until IS NULL OR locked_until < ?)", before, before).Delete(&entity.LoginThrottle{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("cannot query to delete stale login throttles: %w", result.Error)
+	}
+
+	return result.RowsAffected, nil
+}
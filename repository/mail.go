@@ -1,36 +1,179 @@
 package repository
 
 import (
+	"fmt"
+	"math"
 	"time"
 
 	"github.com/team-inu/inu-backyard/entity"
-	errs "github.com/team-inu/inu-backyard/entity/error"
-	"github.com/team-inu/inu-backyard/internal/utils/session"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
-type MailRepository struct {
-	Session session.SessionM
+type mailRepositoryGorm struct {
+	gorm *gorm.DB
 }
 
-func NewMailRepository(session session.SessionM) entity.MailRepository {
-	return &MailRepository{Session: session}
+func NewMailRepositoryGorm(gorm *gorm.DB) entity.MailRepository {
+	return &mailRepositoryGorm{gorm: gorm}
 }
 
-func (r MailRepository) GetToken(email string) (string, error) {
-	token, valid := r.Session.GetSessionData(email)
-	if !valid {
-		return "", errs.New(errs.SameCode, "token not found")
+func (r mailRepositoryGorm) CreateToken(token *entity.PasswordResetToken) error {
+	err := r.gorm.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&entity.PasswordResetToken{}).Where("email = ? AND used_at IS NULL", token.Email).Update("used_at", token.CreatedAt).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Create(token).Error
+	})
+	if err != nil {
+		return fmt.Errorf("cannot query to create reset password token: %w", err)
+	}
+
+	return nil
+}
+
+func (r mailRepositoryGorm) GetToken(email string) (*entity.PasswordResetToken, error) {
+	var token entity.PasswordResetToken
+	err := r.gorm.Where("email = ? AND used_at IS NULL", email).Order("created_at desc").First(&token).Error
+
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("cannot query to get reset password token: %w", err)
+	}
+
+	return &token, nil
+}
+
+func (r mailRepositoryGorm) CountTokensSince(email string, since time.Time) (int64, error) {
+	var count int64
+	err := r.gorm.Model(&entity.PasswordResetToken{}).Where("email = ? AND created_at >= ?", email, since).Count(&count).Error
+	if err != nil {
+		return 0, fmt.Errorf("cannot query to count reset password tokens: %w", err)
+	}
+
+	return count, nil
+}
+
+func (r mailRepositoryGorm) IncreaseAttempts(id string, maxAttempts int) (bool, error) {
+	result := r.gorm.Model(&entity.PasswordResetToken{}).
+		Where("id = ? AND attempts < ?", id, maxAttempts).
+		Update("attempts", gorm.Expr("attempts + 1"))
+	if result.Error != nil {
+		return false, fmt.Errorf("cannot query to increase reset password token attempts: %w", result.Error)
 	}
 
-	return token, nil
+	return result.RowsAffected > 0, nil
 }
 
-func (r MailRepository) SaveToken(email string, token string) error {
-	r.Session.CreateSession(email, time.Duration(time.Minute*15), token)
+func (r mailRepositoryGorm) UseToken(id string) (bool, error) {
+	result := r.gorm.Model(&entity.PasswordResetToken{}).Where("id = ? AND used_at IS NULL", id).Update("used_at", time.Now())
+	if result.Error != nil {
+		return false, fmt.Errorf("cannot query to use reset password token: %w", result.Error)
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+func (r mailRepositoryGorm) DeleteExpiredTokens(before time.Time) (int64, error) {
+	result := r.gorm.Where("expired_at <= ?", before).Delete(&entity.PasswordResetToken{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("cannot query to delete expired reset password tokens: %w", result.Error)
+	}
+
+	return result.RowsAffected, nil
+}
+
+func (r mailRepositoryGorm) CreateOutbox(mail *entity.MailOutbox) error {
+	err := r.gorm.Create(mail).Error
+	if err != nil {
+		return fmt.Errorf("cannot query to create mail outbox: %w", err)
+	}
+
 	return nil
 }
 
-func (r MailRepository) DeleteToken(email string) error {
-	r.Session.RemoveSession(email)
+func (r mailRepositoryGorm) ClaimOutbox(now time.Time, lease time.Duration, limit int) ([]entity.MailOutbox, error) {
+	var mails []entity.MailOutbox
+	err := r.gorm.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", entity.MailStatusPending, now).
+			Order("next_attempt_at").
+			Limit(limit).
+			Find(&mails).Error
+		if err != nil || len(mails) == 0 {
+			return err
+		}
+
+		ids := make([]string, 0, len(mails))
+		for _, mail := range mails {
+			ids = append(ids, mail.Id)
+		}
+
+		return tx.Model(&entity.MailOutbox{}).Where("id IN ?", ids).Update("next_attempt_at", now.Add(lease)).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot query to claim mail outbox: %w", err)
+	}
+
+	return mails, nil
+}
+
+func (r mailRepositoryGorm) UpdateOutbox(mail *entity.MailOutbox) error {
+	err := r.gorm.Save(mail).Error
+	if err != nil {
+		return fmt.Errorf("cannot query to update mail outbox: %w", err)
+	}
+
 	return nil
 }
+
+func (r mailRepositoryGorm) GetOutbox(status entity.MailStatus, offset int, limit int) (*entity.Pagination, error) {
+	var mails []entity.MailOutbox
+	var total int64
+
+	queryBuilder := r.gorm.Model(&entity.MailOutbox{})
+	if status != "" {
+		queryBuilder = queryBuilder.Where("status = ?", status)
+	}
+
+	if err := queryBuilder.Count(&total).Error; err != nil {
+		return nil, fmt.Errorf("cannot count mail outbox: %w", err)
+	}
+
+	if err := queryBuilder.Order("created_at desc").Offset(offset).Limit(limit).Find(&mails).Error; err != nil {
+		return nil, fmt.Errorf("cannot query mail outbox: %w", err)
+	}
+
+	return &entity.Pagination{
+		Total:     total,
+		Size:      limit,
+		Page:      offset/limit + 1,
+		TotalPage: int(math.Ceil(float64(total) / float64(limit))),
+		Data:      mails,
+	}, nil
+}
+
+func (r mailRepositoryGorm) GetOutboxById(id string) (*entity.MailOutbox, error) {
+	var mail entity.MailOutbox
+	err := r.gorm.Where("id = ?", id).First(&mail).Error
+
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("cannot query to get mail outbox: %w", err)
+	}
+
+	return &mail, nil
+}
+
+func (r mailRepositoryGorm) DeleteOutboxBefore(before time.Time) (int64, error) {
+	result := r.gorm.Where("status IN ? AND updated_at < ?", []entity.MailStatus{entity.MailStatusSent, entity.MailStatusFailed}, before).Delete(&entity.MailOutbox{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("cannot query to delete old mail outbox: %w", result.Error)
+	}
+
+	return result.RowsAffected, nil
+}
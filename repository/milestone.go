@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/team-inu/inu-backyard/entity"
+	"gorm.io/gorm"
+)
+
+type milestoneRepositoryGorm struct {
+	gorm *gorm.DB
+}
+
+func NewMilestoneRepositoryGorm(gorm *gorm.DB) entity.MilestoneRepository {
+	return &milestoneRepositoryGorm{gorm: gorm}
+}
+
+func (r milestoneRepositoryGorm) GetById(id string) (*entity.SemesterMilestone, error) {
+	var milestone entity.SemesterMilestone
+	err := r.gorm.Where("id = ?", id).First(&milestone).Error
+
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("cannot query to get milestone by id: %w", err)
+	}
+
+	return &milestone, nil
+}
+
+func (r milestoneRepositoryGorm) GetBySemesterId(semesterId string) ([]entity.SemesterMilestone, error) {
+	var milestones []entity.SemesterMilestone
+	err := r.gorm.Where("semester_id = ?", semesterId).Order("due_at").Find(&milestones).Error
+	if err != nil {
+		return nil, fmt.Errorf("cannot query to get milestones by semester id: %w", err)
+	}
+
+	return milestones, nil
+}
+
+func (r milestoneRepositoryGorm) GetUnreminded(now time.Time) ([]entity.SemesterMilestone, error) {
+	var milestones []entity.SemesterMilestone
+	err := r.gorm.Where("reminded_at IS NULL AND due_at > ?", now).Order("due_at").Find(&milestones).Error
+	if err != nil {
+		return nil, fmt.Errorf("cannot query to get unreminded milestones: %w", err)
+	}
+
+	return milestones, nil
+}
+
+func (r milestoneRepositoryGorm) Create(milestone *entity.SemesterMilestone) error {
+	err := r.gorm.Create(milestone).Error
+	if err != nil {
+		return fmt.Errorf("cannot query to create milestone: %w", err)
+	}
+
+	return nil
+}
+
+func (r milestoneRepositoryGorm) Update(milestone *entity.SemesterMilestone) error {
+	err := r.gorm.Save(milestone).Error
+	if err != nil {
+		return fmt.Errorf("cannot query to update milestone: %w", err)
+	}
+
+	return nil
+}
+
+func (r milestoneRepositoryGorm) MarkReminded(id string, remindedAt time.Time) (bool, error) {
+	result := r.gorm.Model(&entity.SemesterMilestone{}).Where("id = ? AND reminded_at IS NULL", id).Update("reminded_at", remindedAt)
+	if result.Error != nil {
+		return false, fmt.Errorf("cannot query to mark milestone reminded: %w", result.Error)
+	}
+
+	return result.RowsAffected == 1, nil
+}
+
+func (r milestoneRepositoryGorm) Delete(id string) error {
+	err := r.gorm.Delete(&entity.SemesterMilestone{Id: id}).Error
+	if err != nil {
+		return fmt.Errorf("cannot query to delete milestone: %w", err)
+	}
+
+	return nil
+}
+
+func (r milestoneRepositoryGorm) GetIncompleteCourses(semesterId string, milestoneType entity.MilestoneType) ([]entity.Course, error) {
+	queryBuilder := r.gorm.Preload("Lecturers").Where("semester_id = ?", semesterId)
+
+	switch milestoneType {
+	case entity.MilestoneTypeScoreEntryClose:
+		// an enrolled student without a score in one of the assignments of the course
+		queryBuilder = queryBuilder.Where(`EXISTS (
+			SELECT 1 FROM enrollment
+			JOIN assignment_group ON assignment_group.course_id = enrollment.course_id
+			JOIN assignment ON assignment.assignment_group_id = assignment_group.id
+			LEFT JOIN score ON score.assignment_id = assignment.id AND score.student_id = enrollment.student_id
+			WHERE enrollment.course_id = course.id AND enrollment.status = ? AND score.id IS NULL
+		)`, entity.EnrollmentStatusEnroll)
+	case entity.MilestoneTypePortfolioDue:
+		queryBuilder = queryBuilder.Where("is_portfolio_completed = ?", false)
+	case entity.MilestoneTypeSurveyClose:
+		queryBuilder = queryBuilder.Where("EXISTS (SELECT 1 FROM survey WHERE survey.course_id = course.id AND survey.is_complete = ?)", false)
+	default:
+		return nil, fmt.Errorf("milestone type %s has no incomplete items", milestoneType)
+	}
+
+	var courses []entity.Course
+	err := queryBuilder.Find(&courses).Error
+	if err != nil {
+		return nil, fmt.Errorf("cannot query to get incomplete courses of milestone: %w", err)
+	}
+
+	return courses, nil
+}
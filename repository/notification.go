@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/team-inu/inu-backyard/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type notificationRepositoryGorm struct {
+	gorm *gorm.DB
+}
+
+func NewNotificationRepositoryGorm(gorm *gorm.DB) entity.NotificationRepository {
+	return &notificationRepositoryGorm{gorm: gorm}
+}
+
+func (r notificationRepositoryGorm) Create(notification *entity.Notification) (bool, error) {
+	isCreated := false
+	err := r.gorm.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&entity.Notification{}).
+			Where("user_id = ? AND type = ? AND reference_id = ? AND read_at IS NULL", notification.UserId, notification.Type, notification.ReferenceId).
+			Updates(map[string]interface{}{
+				"title":      notification.Title,
+				"message":    notification.Message,
+				"link":       notification.Link,
+				"count":      gorm.Expr("count + 1"),
+				"updated_at": notification.CreatedAt,
+			})
+		if result.Error != nil {
+			return result.Error
+		} else if result.RowsAffected > 0 {
+			return nil
+		}
+
+		isCreated = true
+		return tx.Create(notification).Error
+	})
+	if err != nil {
+		return false, fmt.Errorf("cannot query to create notification: %w", err)
+	}
+
+	return isCreated, nil
+}
+
+func (r notificationRepositoryGorm) GetByUserId(userId string, isUnreadOnly bool, offset int, limit int) (*entity.Pagination, error) {
+	var notifications []entity.Notification
+	var total int64
+
+	queryBuilder := r.gorm.Model(&entity.Notification{}).Where("user_id = ?", userId)
+	if isUnreadOnly {
+		queryBuilder = queryBuilder.Where("read_at IS NULL")
+	}
+
+	if err := queryBuilder.Count(&total).Error; err != nil {
+		return nil, fmt.Errorf("cannot count notifications: %w", err)
+	}
+
+	if err := queryBuilder.Order("updated_at desc").Offset(offset).Limit(limit).Find(&notifications).Error; err != nil {
+		return nil, fmt.Errorf("cannot query notifications: %w", err)
+	}
+
+	return &entity.Pagination{
+		Total:     total,
+		Size:      limit,
+		Page:      offset/limit + 1,
+		TotalPage: int(math.Ceil(float64(total) / float64(limit))),
+		Data:      notifications,
+	}, nil
+}
+
+func (r notificationRepositoryGorm) CountUnread(userId string) (int64, error) {
+	var count int64
+	err := r.gorm.Model(&entity.Notification{}).Where("user_id = ? AND read_at IS NULL", userId).Count(&count).Error
+	if err != nil {
+		return 0, fmt.Errorf("cannot query to count unread notifications: %w", err)
+	}
+
+	return count, nil
+}
+
+func (r notificationRepositoryGorm) MarkRead(userId string, ids []string, readAt time.Time) error {
+	queryBuilder := r.gorm.Model(&entity.Notification{}).Where("user_id = ? AND read_at IS NULL", userId)
+	if len(ids) > 0 {
+		queryBuilder = queryBuilder.Where("id IN ?", ids)
+	}
+
+	err := queryBuilder.Update("read_at", readAt).Error
+	if err != nil {
+		return fmt.Errorf("cannot query to mark notifications read: %w", err)
+	}
+
+	return nil
+}
+
+func (r notificationRepositoryGorm) GetPreferences(userId string) ([]entity.NotificationPreference, error) {
+	var preferences []entity.NotificationPreference
+	err := r.gorm.Where("user_id = ?", userId).Find(&preferences).Error
+	if err != nil {
+		return nil, fmt.Errorf("cannot query to get notification preferences: %w", err)
+	}
+
+	return preferences, nil
+}
+
+func (r notificationRepositoryGorm) UpdatePreferences(preferences []entity.NotificationPreference) error {
+	if len(preferences) == 0 {
+		return nil
+	}
+
+	err := r.gorm.Clauses(clause.OnConflict{UpdateAll: true}).Create(&preferences).Error
+	if err != nil {
+		return fmt.Errorf("cannot query to update notification preferences: %w", err)
+	}
+
+	return nil
+}
+
+func (r notificationRepositoryGorm) GetUserIdsByRole(role entity.UserRole) ([]string, error) {
+	var userIds []string
+	// roles are saved as a comma separated list
+	err := r.gorm.Model(&entity.User{}).Where("FIND_IN_SET(?, role) > 0", role).Pluck("id", &userIds).Error
+	if err != nil {
+		return nil, fmt.Errorf("cannot query to get user ids by role: %w", err)
+	}
+
+	return userIds, nil
+}
+
+func (r notificationRepositoryGorm) DeleteReadBefore(before time.Time) (int64, error) {
+	result := r.gorm.Where("read_at < ?", before).Delete(&entity.Notification{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("cannot query to delete read notifications: %w", result.Error)
+	}
+
+	return result.RowsAffected, nil
+}
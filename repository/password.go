@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/team-inu/inu-backyard/entity"
+	"gorm.io/gorm"
+)
+
+type passwordRepositoryGorm struct {
+	gorm *gorm.DB
+}
+
+func NewPasswordRepositoryGorm(gorm *gorm.DB) entity.PasswordRepository {
+	return &passwordRepositoryGorm{gorm: gorm}
+}
+
+func (r passwordRepositoryGorm) GetHistory(userId string, limit int) ([]entity.PasswordHistory, error) {
+	var histories []entity.PasswordHistory
+	err := r.gorm.Where("user_id = ?", userId).Order("created_at desc").Limit(limit).Find(&histories).Error
+	if err != nil {
+		return nil, fmt.Errorf("cannot query to get password history: %w", err)
+	}
+
+	return histories, nil
+}
+
+func (r passwordRepositoryGorm) UpdatePassword(userId string, passwordHash string, changedAt time.Time, historySize int) error {
+	err := r.gorm.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&entity.User{}).Where("id = ?", userId).Updates(map[string]interface{}{
+			"password":             passwordHash,
+			"password_changed_at":  changedAt,
+			"must_change_password": false,
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		if historySize <= 0 {
+			return tx.Where("user_id = ?", userId).Delete(&entity.PasswordHistory{}).Error
+		}
+
+		err = tx.Create(&entity.PasswordHistory{
+			Id:           ulid.Make().String(),
+			UserId:       userId,
+			PasswordHash: passwordHash,
+			CreatedAt:    changedAt,
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		var expiredIds []string
+		err = tx.Model(&entity.PasswordHistory{}).Where("user_id = ?", userId).Order("created_at desc").Offset(historySize).Limit(1000).Pluck("id", &expiredIds).Error
+		if err != nil || len(expiredIds) == 0 {
+			return err
+		}
+
+		return tx.Where("id IN ?", expiredIds).Delete(&entity.PasswordHistory{}).Error
+	})
+	if err != nil {
+		return fmt.Errorf("cannot query to update password: %w", err)
+	}
+
+	return nil
+}
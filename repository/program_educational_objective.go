@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/team-inu/inu-backyard/entity"
+	"gorm.io/gorm"
+)
+
+type programEducationalObjectiveRepositoryGorm struct {
+	gorm *gorm.DB
+}
+
+func NewProgramEducationalObjectiveRepositoryGorm(gorm *gorm.DB) entity.ProgramEducationalObjectiveRepository {
+	return &programEducationalObjectiveRepositoryGorm{gorm: gorm}
+}
+
+func (r programEducationalObjectiveRepositoryGorm) GetByProgrammeId(programmeId string) ([]entity.ProgramEducationalObjective, error) {
+	var peos []entity.ProgramEducationalObjective
+
+	err := r.gorm.Where("programme_id = ?", programmeId).
+		Preload("ProgramLearningOutcomes").
+		Preload("StudentOutcomes").
+		Order("code").
+		Find(&peos).Error
+	if err != nil {
+		return nil, fmt.Errorf("cannot query to get program educational objectives: %w", err)
+	}
+
+	return peos, nil
+}
+
+func (r programEducationalObjectiveRepositoryGorm) GetById(id string) (*entity.ProgramEducationalObjective, error) {
+	var peo entity.ProgramEducationalObjective
+
+	err := r.gorm.Where("id = ?", id).
+		Preload("ProgramLearningOutcomes").
+		Preload("StudentOutcomes").
+		First(&peo).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("cannot query to get program educational objective by id: %w", err)
+	}
+
+	return &peo, nil
+}
+
+func (r programEducationalObjectiveRepositoryGorm) Create(peo *entity.ProgramEducationalObjective) error {
+	err := r.gorm.Create(peo).Error
+	if err != nil {
+		return fmt.Errorf("cannot create program educational objective: %w", err)
+	}
+
+	return nil
+}
+
+func (r programEducationalObjectiveRepositoryGorm) Update(id string, peo *entity.ProgramEducationalObjective) error {
+	err := r.gorm.Model(&entity.ProgramEducationalObjective{}).Where("id = ?", id).Updates(peo).Error
+	if err != nil {
+		return fmt.Errorf("cannot update program educational objective: %w", err)
+	}
+
+	return nil
+}
+
+func (r programEducationalObjectiveRepositoryGorm) Delete(id string) error {
+	err := r.gorm.Exec("DELETE FROM `peo_plo` WHERE program_educational_objective_id = ?", id).Error
+	if err != nil {
+		return fmt.Errorf("cannot delete link between PEO and PLO: %w", err)
+	}
+
+	err = r.gorm.Exec("DELETE FROM `peo_so` WHERE program_educational_objective_id = ?", id).Error
+	if err != nil {
+		return fmt.Errorf("cannot delete link between PEO and SO: %w", err)
+	}
+
+	err = r.gorm.Delete(&entity.ProgramEducationalObjective{Id: id}).Error
+	if err != nil {
+		return fmt.Errorf("cannot delete program educational objective: %w", err)
+	}
+
+	return nil
+}
+
+func (r programEducationalObjectiveRepositoryGorm) CreateLinkProgramLearningOutcome(id string, ploIds []string) error {
+	var query string
+	for _, ploId := range ploIds {
+		query += fmt.Sprintf("('%s', '%s'),", id, ploId)
+	}
+
+	query = query[:len(query)-1]
+
+	err := r.gorm.Exec(fmt.Sprintf("INSERT INTO `peo_plo` (program_educational_objective_id, program_learning_outcome_id) VALUES %s", query)).Error
+	if err != nil {
+		return fmt.Errorf("cannot create link between PEO and PLO: %w", err)
+	}
+
+	return nil
+}
+
+func (r programEducationalObjectiveRepositoryGorm) CreateLinkStudentOutcome(id string, soIds []string) error {
+	var query string
+	for _, soId := range soIds {
+		query += fmt.Sprintf("('%s', '%s'),", id, soId)
+	}
+
+	query = query[:len(query)-1]
+
+	err := r.gorm.Exec(fmt.Sprintf("INSERT INTO `peo_so` (program_educational_objective_id, student_outcome_id) VALUES %s", query)).Error
+	if err != nil {
+		return fmt.Errorf("cannot create link between PEO and SO: %w", err)
+	}
+
+	return nil
+}
+
+func (r programEducationalObjectiveRepositoryGorm) DeleteLinkProgramLearningOutcome(id string, ploId string) error {
+	err := r.gorm.Exec("DELETE FROM `peo_plo` WHERE program_educational_objective_id = ? AND program_learning_outcome_id = ?", id, ploId).Error
+	if err != nil {
+		return fmt.Errorf("cannot delete link between PEO and PLO: %w", err)
+	}
+
+	return nil
+}
+
+func (r programEducationalObjectiveRepositoryGorm) DeleteLinkStudentOutcome(id string, soId string) error {
+	err := r.gorm.Exec("DELETE FROM `peo_so` WHERE program_educational_objective_id = ? AND student_outcome_id = ?", id, soId).Error
+	if err != nil {
+		return fmt.Errorf("cannot delete link between PEO and SO: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,143 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/team-inu/inu-backyard/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type programImprovementRepositoryGorm struct {
+	gorm *gorm.DB
+}
+
+func NewProgramImprovementRepositoryGorm(gorm *gorm.DB) entity.ProgramImprovementRepository {
+	return &programImprovementRepositoryGorm{gorm: gorm}
+}
+
+func (r programImprovementRepositoryGorm) preload(db *gorm.DB) *gorm.DB {
+	return db.
+		Preload("Owner", func(db *gorm.DB) *gorm.DB {
+			return db.Omit("Password")
+		}).
+		Preload("Courses").
+		Preload("ProgramLearningOutcomes").
+		Preload("ProgramOutcomes").
+		Preload("Surveys")
+}
+
+func (r programImprovementRepositoryGorm) GetByProgrammeId(programmeId string, status entity.ProgramImprovementStatus) ([]entity.ProgramImprovement, error) {
+	var improvements []entity.ProgramImprovement
+
+	query := r.gorm.Where("programme_id = ?", programmeId)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	err := r.preload(query).Order("date").Find(&improvements).Error
+	if err != nil {
+		return nil, fmt.Errorf("cannot query to get program improvements: %w", err)
+	}
+
+	return improvements, nil
+}
+
+func (r programImprovementRepositoryGorm) GetById(id string) (*entity.ProgramImprovement, error) {
+	var improvement entity.ProgramImprovement
+
+	err := r.preload(r.gorm.Where("id = ?", id)).First(&improvement).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("cannot query to get program improvement by id: %w", err)
+	}
+
+	return &improvement, nil
+}
+
+func (r programImprovementRepositoryGorm) Create(improvement *entity.ProgramImprovement) error {
+	err := r.gorm.Omit(clause.Associations).Create(improvement).Error
+	if err != nil {
+		return fmt.Errorf("cannot create program improvement: %w", err)
+	}
+
+	return nil
+}
+
+func (r programImprovementRepositoryGorm) Update(id string, improvement *entity.ProgramImprovement) error {
+	err := r.gorm.Model(&entity.ProgramImprovement{}).Omit(clause.Associations).Where("id = ?", id).Updates(improvement).Error
+	if err != nil {
+		return fmt.Errorf("cannot update program improvement: %w", err)
+	}
+
+	return nil
+}
+
+func (r programImprovementRepositoryGorm) Delete(id string) error {
+	err := r.gorm.Transaction(func(tx *gorm.DB) error {
+		for _, table := range []string{"improvement_course", "improvement_plo", "improvement_po", "improvement_survey"} {
+			err := tx.Exec(fmt.Sprintf("DELETE FROM `%s` WHERE program_improvement_id = ?", table), id).Error
+			if err != nil {
+				return fmt.Errorf("cannot delete links from %s: %w", table, err)
+			}
+		}
+
+		return tx.Delete(&entity.ProgramImprovement{Id: id}).Error
+	})
+	if err != nil {
+		return fmt.Errorf("cannot delete program improvement: %w", err)
+	}
+
+	return nil
+}
+
+// ReplaceLinks overwrites the links of each kind that is not nil in links
+func (r programImprovementRepositoryGorm) ReplaceLinks(id string, links entity.ProgramImprovementLinks) error {
+	linkTables := []struct {
+		table  string
+		column string
+		ids    []string
+	}{
+		{"improvement_course", "course_id", links.CourseIds},
+		{"improvement_plo", "program_learning_outcome_id", links.ProgramLearningOutcomeIds},
+		{"improvement_po", "program_outcome_id", links.ProgramOutcomeIds},
+		{"improvement_survey", "survey_id", links.SurveyIds},
+	}
+
+	err := r.gorm.Transaction(func(tx *gorm.DB) error {
+		for _, link := range linkTables {
+			if link.ids == nil {
+				continue
+			}
+
+			err := tx.Exec(fmt.Sprintf("DELETE FROM `%s` WHERE program_improvement_id = ?", link.table), id).Error
+			if err != nil {
+				return fmt.Errorf("cannot delete links from %s: %w", link.table, err)
+			}
+
+			if len(link.ids) == 0 {
+				continue
+			}
+
+			var query string
+			for _, linkId := range link.ids {
+				query += fmt.Sprintf("('%s', '%s'),", id, linkId)
+			}
+
+			query = query[:len(query)-1]
+
+			err = tx.Exec(fmt.Sprintf("INSERT INTO `%s` (program_improvement_id, %s) VALUES %s", link.table, link.column, query)).Error
+			if err != nil {
+				return fmt.Errorf("cannot create links in %s: %w", link.table, err)
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("cannot replace program improvement links: %w", err)
+	}
+
+	return nil
+}
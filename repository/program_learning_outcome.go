@@ -51,8 +51,6 @@ func (r programLearningOutcomeRepositoryGorm) Create(programLearningOutcome *ent
 	if err != nil {
 		return fmt.Errorf("cannot create programLearningOutcome: %w", err)
 	}
-	go cacheOutcomes(r.gorm, TabeeSelectorAllPloCourses)
-	go cacheOutcomes(r.gorm, TabeeSelectorAllPoCourses)
 
 	return nil
 }
@@ -62,8 +60,6 @@ func (r programLearningOutcomeRepositoryGorm) CreateMany(programLearningOutcome
 	if err != nil {
 		return fmt.Errorf("cannot create programLearningOutcome: %w", err)
 	}
-	go cacheOutcomes(r.gorm, TabeeSelectorAllPloCourses)
-	go cacheOutcomes(r.gorm, TabeeSelectorAllPoCourses)
 
 	return nil
 }
@@ -78,8 +74,6 @@ func (r programLearningOutcomeRepositoryGorm) Update(id string, programLearningO
 	if err != nil {
 		return fmt.Errorf("cannot update programLearningOutcome: %w", err)
 	}
-	go cacheOutcomes(r.gorm, TabeeSelectorAllPloCourses)
-	go cacheOutcomes(r.gorm, TabeeSelectorAllPoCourses)
 
 	return nil
 }
@@ -90,8 +84,6 @@ func (r programLearningOutcomeRepositoryGorm) Delete(id string) error {
 	if err != nil {
 		return fmt.Errorf("cannot delete programLearningOutcome: %w", err)
 	}
-	go cacheOutcomes(r.gorm, TabeeSelectorAllPloCourses)
-	go cacheOutcomes(r.gorm, TabeeSelectorAllPoCourses)
 
 	return nil
 }
@@ -64,8 +64,6 @@ func (r programOutcomeRepositoryGorm) Create(programOutcome *entity.ProgramOutco
 	if err != nil {
 		return fmt.Errorf("cannot create programOutcome: %w", err)
 	}
-	go cacheOutcomes(r.gorm, TabeeSelectorAllPloCourses)
-	go cacheOutcomes(r.gorm, TabeeSelectorAllPoCourses)
 
 	return nil
 }
@@ -75,8 +73,6 @@ func (r programOutcomeRepositoryGorm) CreateMany(programOutcome []entity.Program
 	if err != nil {
 		return fmt.Errorf("cannot create programOutcome: %w", err)
 	}
-	go cacheOutcomes(r.gorm, TabeeSelectorAllPloCourses)
-	go cacheOutcomes(r.gorm, TabeeSelectorAllPoCourses)
 
 	return nil
 }
@@ -86,8 +82,6 @@ func (r programOutcomeRepositoryGorm) Update(id string, programOutcome *entity.P
 	if err != nil {
 		return fmt.Errorf("cannot update programOutcome: %w", err)
 	}
-	go cacheOutcomes(r.gorm, TabeeSelectorAllPloCourses)
-	go cacheOutcomes(r.gorm, TabeeSelectorAllPoCourses)
 
 	return nil
 }
@@ -98,8 +92,6 @@ func (r programOutcomeRepositoryGorm) Delete(id string) error {
 	if err != nil {
 		return fmt.Errorf("cannot delete programOutcome: %w", err)
 	}
-	go cacheOutcomes(r.gorm, TabeeSelectorAllPloCourses)
-	go cacheOutcomes(r.gorm, TabeeSelectorAllPoCourses)
 
 	return nil
 }
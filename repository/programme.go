@@ -104,7 +104,15 @@ func (r programmeRepositoryGorm) Create(programme *entity.Programme) error {
 }
 
 func (r programmeRepositoryGorm) Update(id string, programme *entity.Programme) error {
-	err := r.gorm.Model(&entity.Programme{}).Where("id = ?", id).Updates(programme).Error
+	err := r.gorm.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&entity.Programme{}).Where("id = ?", id).Updates(programme).Error
+		if err != nil {
+			return err
+		}
+
+		// the attainment method may have changed for the courses without their own
+		return queueAttainmentRefreshByProgramme(tx, id)
+	})
 	if err != nil {
 		return fmt.Errorf("cannot update programme: %w", err)
 	}
@@ -569,6 +577,66 @@ func (r programmeRepositoryGorm) GetAllSO(programmeId string) ([]entity.StudentO
 	return sos, nil
 }
 
+func (r programmeRepositoryGorm) GetAllPEO(programmeId string) ([]entity.ProgramEducationalObjective, error) {
+	var peos []entity.ProgramEducationalObjective
+
+	err := r.gorm.Where("programme_id = ?", programmeId).Preload("ProgramLearningOutcomes").Preload("StudentOutcomes").Order("code").Find(&peos).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return peos, nil
+}
+
+func (r programmeRepositoryGorm) GetStakeholderFeedback(programmeId string) ([]entity.StakeholderFeedbackRecord, error) {
+	var records []entity.StakeholderFeedbackRecord
+
+	err := r.gorm.Raw(`
+	SELECT
+		'PLO' AS outcome_type,
+		s.audience,
+		q.plo_id AS outcome_id,
+		COUNT(qs.id) AS response_count,
+		AVG(qs.score) AS average_score
+	FROM
+		survey s
+	JOIN question q ON
+		q.survey_id = s.id
+	JOIN q_score qs ON
+		qs.question_id = q.id
+	WHERE
+		s.programme_id = ?
+		AND q.plo_id <> ''
+	GROUP BY
+		s.audience,
+		q.plo_id
+	UNION ALL
+	SELECT
+		'PEO' AS outcome_type,
+		s.audience,
+		q.peo_id AS outcome_id,
+		COUNT(qs.id) AS response_count,
+		AVG(qs.score) AS average_score
+	FROM
+		survey s
+	JOIN question q ON
+		q.survey_id = s.id
+	JOIN q_score qs ON
+		qs.question_id = q.id
+	WHERE
+		s.programme_id = ?
+		AND q.peo_id <> ''
+	GROUP BY
+		s.audience,
+		q.peo_id;
+	`, programmeId, programmeId).Scan(&records).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return records, nil
+}
+
 func (r programmeRepositoryGorm) GetAllPO(programmeId string) ([]entity.ProgramOutcome, error) {
 	var pos []entity.ProgramOutcome
 
@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/team-inu/inu-backyard/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type reportJobRepositoryGorm struct {
+	gorm *gorm.DB
+}
+
+func NewReportJobRepositoryGorm(gorm *gorm.DB) entity.ReportJobRepository {
+	return &reportJobRepositoryGorm{gorm: gorm}
+}
+
+func (r reportJobRepositoryGorm) Create(job *entity.ReportJob) error {
+	err := r.gorm.Create(job).Error
+	if err != nil {
+		return fmt.Errorf("cannot query to create report job: %w", err)
+	}
+
+	return nil
+}
+
+func (r reportJobRepositoryGorm) GetById(id string) (*entity.ReportJob, error) {
+	var job entity.ReportJob
+	err := r.gorm.Where("id = ?", id).First(&job).Error
+
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("cannot query to get report job: %w", err)
+	}
+
+	return &job, nil
+}
+
+func (r reportJobRepositoryGorm) ClaimNext(now time.Time, lease time.Duration) (*entity.ReportJob, error) {
+	var jobs []entity.ReportJob
+	err := r.gorm.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? OR (status = ? AND started_at < ?)", entity.ReportJobStatusPending, entity.ReportJobStatusRunning, now.Add(-lease)).
+			Order("created_at").
+			Limit(1).
+			Find(&jobs).Error
+		if err != nil || len(jobs) == 0 {
+			return err
+		}
+
+		jobs[0].Status = entity.ReportJobStatusRunning
+		jobs[0].StartedAt = &now
+		jobs[0].Attempts++
+
+		return tx.Model(&entity.ReportJob{}).Where("id = ?", jobs[0].Id).Updates(map[string]interface{}{
+			"status":     jobs[0].Status,
+			"started_at": now,
+			"attempts":   jobs[0].Attempts,
+		}).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot query to claim report job: %w", err)
+	} else if len(jobs) == 0 {
+		return nil, nil
+	}
+
+	return &jobs[0], nil
+}
+
+func (r reportJobRepositoryGorm) Update(job *entity.ReportJob) error {
+	err := r.gorm.Save(job).Error
+	if err != nil {
+		return fmt.Errorf("cannot query to update report job: %w", err)
+	}
+
+	return nil
+}
+
+func (r reportJobRepositoryGorm) GetFinishedBefore(before time.Time) ([]entity.ReportJob, error) {
+	var jobs []entity.ReportJob
+	err := r.gorm.Where("status IN ? AND finished_at < ?", []entity.ReportJobStatus{entity.ReportJobStatusCompleted, entity.ReportJobStatusFailed}, before).Find(&jobs).Error
+	if err != nil {
+		return nil, fmt.Errorf("cannot query to get finished report jobs: %w", err)
+	}
+
+	return jobs, nil
+}
+
+func (r reportJobRepositoryGorm) Delete(ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	err := r.gorm.Where("id IN ?", ids).Delete(&entity.ReportJob{}).Error
+	if err != nil {
+		return fmt.Errorf("cannot query to delete report jobs: %w", err)
+	}
+
+	return nil
+}
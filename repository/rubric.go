@@ -0,0 +1,167 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/team-inu/inu-backyard/entity"
+	"gorm.io/gorm"
+)
+
+type rubricRepositoryGorm struct {
+	gorm *gorm.DB
+}
+
+func NewRubricRepositoryGorm(gorm *gorm.DB) entity.RubricRepository {
+	return &rubricRepositoryGorm{gorm: gorm}
+}
+
+func preloadRubricCriteria(db *gorm.DB) *gorm.DB {
+	return db.
+		Preload("Criteria", func(db *gorm.DB) *gorm.DB { return db.Order("sequence") }).
+		Preload("Criteria.Levels", func(db *gorm.DB) *gorm.DB { return db.Order("points") })
+}
+
+func (r rubricRepositoryGorm) GetById(id string) (*entity.Rubric, error) {
+	var rubric entity.Rubric
+	err := preloadRubricCriteria(r.gorm).Where("id = ?", id).First(&rubric).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("cannot query to get rubric by id: %w", err)
+	}
+
+	return &rubric, nil
+}
+
+func (r rubricRepositoryGorm) GetByProgrammeId(programmeId string) ([]entity.Rubric, error) {
+	var rubrics []entity.Rubric
+	err := preloadRubricCriteria(r.gorm).Where("programme_id = ?", programmeId).Find(&rubrics).Error
+	if err != nil {
+		return nil, fmt.Errorf("cannot query to get rubrics by programme id: %w", err)
+	}
+
+	return rubrics, nil
+}
+
+func (r rubricRepositoryGorm) Create(rubric *entity.Rubric) error {
+	err := r.gorm.Create(rubric).Error
+	if err != nil {
+		return fmt.Errorf("cannot create rubric: %w", err)
+	}
+
+	return nil
+}
+
+func (r rubricRepositoryGorm) Update(id string, rubric *entity.Rubric) error {
+	err := r.gorm.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&entity.Rubric{}).Where("id = ?", id).Updates(&entity.Rubric{Name: rubric.Name, Description: rubric.Description}).Error
+		if err != nil || len(rubric.Criteria) == 0 {
+			return err
+		}
+
+		err = deleteRubricCriteria(tx, id)
+		if err != nil {
+			return err
+		}
+
+		for i := range rubric.Criteria {
+			rubric.Criteria[i].RubricId = id
+		}
+
+		return tx.Create(&rubric.Criteria).Error
+	})
+	if err != nil {
+		return fmt.Errorf("cannot update rubric: %w", err)
+	}
+
+	return nil
+}
+
+func (r rubricRepositoryGorm) Delete(id string) error {
+	err := r.gorm.Transaction(func(tx *gorm.DB) error {
+		err := deleteRubricCriteria(tx, id)
+		if err != nil {
+			return err
+		}
+
+		return tx.Where("id = ?", id).Delete(&entity.Rubric{}).Error
+	})
+	if err != nil {
+		return fmt.Errorf("cannot delete rubric: %w", err)
+	}
+
+	return nil
+}
+
+func (r rubricRepositoryGorm) IsAttached(id string) (bool, error) {
+	var count int64
+	err := r.gorm.Model(&entity.Assignment{}).Where("rubric_id = ?", id).Count(&count).Error
+	if err != nil {
+		return false, fmt.Errorf("cannot query to count assignments of rubric: %w", err)
+	}
+
+	return count > 0, nil
+}
+
+func (r rubricRepositoryGorm) Attach(assignmentId string, rubricId string, items []entity.AssessmentItem) error {
+	err := r.gorm.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&entity.Assignment{}).Where("id = ?", assignmentId).Update("rubric_id", rubricId).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Omit("CourseLearningOutcomes.*", "RubricCriterion").Create(&items).Error
+		if err != nil {
+			return err
+		}
+
+		return queueAttainmentRefreshByAssignment(tx, []string{assignmentId})
+	})
+	if err != nil {
+		return fmt.Errorf("cannot attach rubric to assignment: %w", err)
+	}
+
+	return nil
+}
+
+func (r rubricRepositoryGorm) Detach(assignmentId string) error {
+	err := r.gorm.Transaction(func(tx *gorm.DB) error {
+		var itemIds []string
+		err := tx.Model(&entity.AssessmentItem{}).Where("assignment_id = ? AND rubric_criterion_id IS NOT NULL", assignmentId).Pluck("id", &itemIds).Error
+		if err != nil {
+			return err
+		}
+
+		err = deleteAssessmentItems(tx, itemIds)
+		if err != nil {
+			return err
+		}
+
+		err = tx.Model(&entity.Assignment{}).Where("id = ?", assignmentId).Update("rubric_id", nil).Error
+		if err != nil {
+			return err
+		}
+
+		return updateAssessmentItemTotals(tx, assignmentId, "")
+	})
+	if err != nil {
+		return fmt.Errorf("cannot detach rubric from assignment: %w", err)
+	}
+
+	return nil
+}
+
+func deleteRubricCriteria(tx *gorm.DB, rubricId string) error {
+	var criterionIds []string
+	err := tx.Model(&entity.RubricCriterion{}).Where("rubric_id = ?", rubricId).Pluck("id", &criterionIds).Error
+	if err != nil || len(criterionIds) == 0 {
+		return err
+	}
+
+	err = tx.Where("rubric_criterion_id IN ?", criterionIds).Delete(&entity.RubricLevel{}).Error
+	if err != nil {
+		return err
+	}
+
+	return tx.Where("id IN ?", criterionIds).Delete(&entity.RubricCriterion{}).Error
+}
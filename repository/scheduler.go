@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/team-inu/inu-backyard/entity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type schedulerLeaseRepositoryGorm struct {
+	gorm *gorm.DB
+}
+
+func NewSchedulerLeaseRepositoryGorm(gorm *gorm.DB) entity.SchedulerLeaseRepository {
+	return &schedulerLeaseRepositoryGorm{gorm: gorm}
+}
+
+func (r schedulerLeaseRepositoryGorm) Acquire(name string, holder string, now time.Time, expiresAt time.Time) (bool, error) {
+	result := r.gorm.Clauses(clause.OnConflict{DoNothing: true}).Create(&entity.SchedulerLease{
+		Name:      name,
+		Holder:    holder,
+		ExpiresAt: expiresAt,
+	})
+	if result.Error != nil {
+		return false, fmt.Errorf("cannot query to create scheduler lease: %w", result.Error)
+	} else if result.RowsAffected == 1 {
+		return true, nil
+	}
+
+	// the row is updated only when this replica holds the lease or the lease of another one expired
+	result = r.gorm.Model(&entity.SchedulerLease{}).
+		Where("name = ? AND (holder = ? OR expires_at < ?)", name, holder, now).
+		Updates(map[string]interface{}{
+			"holder":     holder,
+			"expires_at": expiresAt,
+		})
+	if result.Error != nil {
+		return false, fmt.Errorf("cannot query to acquire scheduler lease: %w", result.Error)
+	}
+
+	return result.RowsAffected == 1, nil
+}
@@ -96,46 +96,98 @@ func (r scoreRepository) GetByStudentId(studentId string) ([]entity.Score, error
 }
 
 func (r scoreRepository) Create(score *entity.Score) error {
-	err := r.gorm.Create(&score).Error
+	err := r.gorm.Transaction(func(tx *gorm.DB) error {
+		err := tx.Create(&score).Error
+		if err != nil {
+			return err
+		}
+
+		return queueAttainmentRefreshByAssignment(tx, []string{score.AssignmentId}, score.StudentId)
+	})
 	if err != nil {
 		return fmt.Errorf("cannot create score: %w", err)
 	}
-	go cacheOutcomes(r.gorm, TabeeSelectorAllPloCourses)
-	go cacheOutcomes(r.gorm, TabeeSelectorAllPoCourses)
 
 	return nil
 }
 
 func (r scoreRepository) CreateMany(scores []entity.Score) error {
-	err := r.gorm.Create(&scores).Error
+	err := r.gorm.Transaction(func(tx *gorm.DB) error {
+		err := tx.Create(&scores).Error
+		if err != nil {
+			return err
+		}
+
+		return queueScoreAttainmentRefresh(tx, scores)
+	})
 	if err != nil {
 		return fmt.Errorf("cannot create scores: %w", err)
 	}
-	go cacheOutcomes(r.gorm, TabeeSelectorAllPloCourses)
-	go cacheOutcomes(r.gorm, TabeeSelectorAllPoCourses)
 
 	return nil
 }
 
 func (r scoreRepository) Update(id string, score *entity.Score) error {
-	err := r.gorm.Model(&entity.Score{}).Where("id = ?", id).Updates(score).Error
+	err := r.gorm.Transaction(func(tx *gorm.DB) error {
+		var scores []entity.Score
+		err := tx.Where("id = ?", id).Find(&scores).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Model(&entity.Score{}).Where("id = ?", id).Updates(score).Error
+		if err != nil {
+			return err
+		}
+
+		return queueScoreAttainmentRefresh(tx, append(scores, *score))
+	})
 	if err != nil {
 		return fmt.Errorf("cannot update score: %w", err)
 	}
-	go cacheOutcomes(r.gorm, TabeeSelectorAllPloCourses)
-	go cacheOutcomes(r.gorm, TabeeSelectorAllPoCourses)
 
 	return nil
 }
 
 func (r scoreRepository) Delete(id string) error {
-	err := r.gorm.Delete(&entity.Score{Id: id}).Error
+	err := r.gorm.Transaction(func(tx *gorm.DB) error {
+		var scores []entity.Score
+		err := tx.Where("id = ?", id).Find(&scores).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Delete(&entity.Score{Id: id}).Error
+		if err != nil {
+			return err
+		}
+
+		return queueScoreAttainmentRefresh(tx, scores)
+	})
 
 	if err != nil {
 		return fmt.Errorf("cannot delete score: %w", err)
 	}
-	go cacheOutcomes(r.gorm, TabeeSelectorAllPloCourses)
-	go cacheOutcomes(r.gorm, TabeeSelectorAllPoCourses)
+
+	return nil
+}
+
+// queueScoreAttainmentRefresh marks the students of the scores stale in the courses of their assignments
+func queueScoreAttainmentRefresh(tx *gorm.DB, scores []entity.Score) error {
+	studentIdsByAssignment := map[string][]string{}
+	for _, score := range scores {
+		if score.AssignmentId == "" || score.StudentId == "" {
+			continue
+		}
+		studentIdsByAssignment[score.AssignmentId] = append(studentIdsByAssignment[score.AssignmentId], score.StudentId)
+	}
+
+	for assignmentId, studentIds := range studentIdsByAssignment {
+		err := queueAttainmentRefreshByAssignment(tx, []string{assignmentId}, studentIds...)
+		if err != nil {
+			return err
+		}
+	}
 
 	return nil
 }
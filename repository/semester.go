@@ -39,7 +39,7 @@ func (r *SemesterRepository) Get(year int, semesterSequence string) (*entity.Sem
 func (r *SemesterRepository) GetById(id string) (*entity.Semester, error) {
 	var semester entity.Semester
 
-	err := r.gorm.First(&semester, "id = ?", id).Error
+	err := r.gorm.Preload("Milestones").First(&semester, "id = ?", id).Error
 	if err == gorm.ErrRecordNotFound {
 		return nil, nil
 	} else if err != nil {
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/team-inu/inu-backyard/entity"
 	"gorm.io/gorm"
@@ -46,13 +47,21 @@ func (r *sessionRepository) Delete(id string) error {
 }
 
 func (r *sessionRepository) DeleteByUserId(userId string) error {
-	err := r.gorm.Delete(&entity.Session{UserId: userId}).Error
+	err := r.gorm.Where("user_id = ?", userId).Delete(&entity.Session{}).Error
 	if err != nil {
 		return fmt.Errorf("cannot query to delete session: %w", err)
 	}
 	return nil
 }
 
+func (r *sessionRepository) DeleteByUserIdExcept(userId string, id string) error {
+	err := r.gorm.Where("user_id = ? AND id <> ?", userId, id).Delete(&entity.Session{}).Error
+	if err != nil {
+		return fmt.Errorf("cannot query to delete other sessions: %w", err)
+	}
+	return nil
+}
+
 func (r *sessionRepository) DeleteDuplicates(userId string, ipAddress string, userAgent string) error {
 	result := r.gorm.Where("user_id = ? AND ip_address = ? AND user_agent = ?", userId, ipAddress, userAgent).Delete(&entity.Session{})
 
@@ -66,3 +75,50 @@ func (r *sessionRepository) DeleteDuplicates(userId string, ipAddress string, us
 
 	return nil
 }
+
+func (r *sessionRepository) IncreaseTwoFactorAttempts(id string, maxAttempts int) (bool, error) {
+	result := r.gorm.Model(&entity.Session{}).
+		Where("id = ? AND two_factor_attempts < ?", id, maxAttempts).
+		Update("two_factor_attempts", gorm.Expr("two_factor_attempts + 1"))
+	if result.Error != nil {
+		return false, fmt.Errorf("cannot query to increase two-factor attempts: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *sessionRepository) Activate(id string, newId string, expiredAt time.Time) (bool, error) {
+	result := r.gorm.Model(&entity.Session{}).Where("id = ? AND two_factor_pending = ?", id, true).Updates(map[string]interface{}{
+		"id":                 newId,
+		"two_factor_pending": false,
+		"expired_at":         expiredAt,
+	})
+	if result.Error != nil {
+		return false, fmt.Errorf("cannot query to activate session: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *sessionRepository) GetActiveByUserId(userId string) ([]entity.Session, error) {
+	var sessions []entity.Session
+	err := r.gorm.Where("user_id = ? AND two_factor_pending = ? AND expired_at > ?", userId, false, time.Now()).Order("created_at desc").Find(&sessions).Error
+	if err != nil {
+		return nil, fmt.Errorf("cannot query to get sessions by user id: %w", err)
+	}
+	return sessions, nil
+}
+
+func (r *sessionRepository) UpdateExpiredAt(id string, expiredAt time.Time) error {
+	err := r.gorm.Model(&entity.Session{}).Where("id = ?", id).Update("expired_at", expiredAt).Error
+	if err != nil {
+		return fmt.Errorf("cannot query to update session expiry: %w", err)
+	}
+	return nil
+}
+
+func (r *sessionRepository) DeleteExpired(before time.Time) (int64, error) {
+	result := r.gorm.Where("expired_at <= ?", before).Delete(&entity.Session{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("cannot query to delete expired sessions: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
@@ -69,8 +69,6 @@ func (r programLearningOutcomeRepositoryGorm) CreateSubPLO(subProgramLearningOut
 	if err != nil {
 		return fmt.Errorf("cannot create subProgramLearningOutcome: %w", err)
 	}
-	go cacheOutcomes(r.gorm, TabeeSelectorAllPloCourses)
-	go cacheOutcomes(r.gorm, TabeeSelectorAllPoCourses)
 
 	return nil
 }
@@ -87,13 +85,21 @@ func (r programLearningOutcomeRepositoryGorm) UpdateSubPLO(id string, subProgram
 		return fmt.Errorf("subProgramLearningOutcome not found")
 	}
 
-	go cacheOutcomes(r.gorm, TabeeSelectorAllPloCourses)
-	go cacheOutcomes(r.gorm, TabeeSelectorAllPoCourses)
+	// the sub PLO may have moved to another PLO
+	err := queueAttainmentRefreshByOutcomeLink(r.gorm, "clo_subplo", "sub_program_learning_outcome_id", id)
+	if err != nil {
+		return fmt.Errorf("cannot queue attainment refresh of subProgramLearningOutcome: %w", err)
+	}
 
 	return nil
 }
 
 func (r programLearningOutcomeRepositoryGorm) DeleteSubPLO(id string) error {
+	err := queueAttainmentRefreshByOutcomeLink(r.gorm, "clo_subplo", "sub_program_learning_outcome_id", id)
+	if err != nil {
+		return fmt.Errorf("cannot queue attainment refresh of subProgramLearningOutcome: %w", err)
+	}
+
 	tx := r.gorm.Delete(&entity.SubProgramLearningOutcome{Id: id})
 
 	if tx.Error != nil {
@@ -103,9 +109,6 @@ func (r programLearningOutcomeRepositoryGorm) DeleteSubPLO(id string) error {
 		return fmt.Errorf("subProgramLearningOutcome not found")
 	}
 
-	go cacheOutcomes(r.gorm, TabeeSelectorAllPloCourses)
-	go cacheOutcomes(r.gorm, TabeeSelectorAllPoCourses)
-
 	return nil
 }
 
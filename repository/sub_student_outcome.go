@@ -42,24 +42,27 @@ func (r StudentOutcomeRepositoryGorm) UpdateSubSO(id string, subSO *entity.SubSt
 		return fmt.Errorf("cannot update subSO: %w", tx.Error)
 	}
 
-	//TODO: abet
-	go cacheOutcomes(r.gorm, TabeeSelectorAllPloCourses)
-	go cacheOutcomes(r.gorm, TabeeSelectorAllPoCourses)
+	// the sub SO may have moved to another SO
+	err := queueAttainmentRefreshByOutcomeLink(r.gorm, "clo_subso", "sub_student_outcome_id", id)
+	if err != nil {
+		return fmt.Errorf("cannot queue attainment refresh of subSO: %w", err)
+	}
 
 	return nil
 }
 
 func (r StudentOutcomeRepositoryGorm) DeleteSubSO(id string) error {
+	err := queueAttainmentRefreshByOutcomeLink(r.gorm, "clo_subso", "sub_student_outcome_id", id)
+	if err != nil {
+		return fmt.Errorf("cannot queue attainment refresh of subSO: %w", err)
+	}
+
 	tx := r.gorm.Delete(&entity.SubStudentOutcome{Id: id})
 
 	if tx.Error != nil {
 		return fmt.Errorf("cannot delete subSO: %w", tx.Error)
 	}
 
-	//TODO: abet
-	go cacheOutcomes(r.gorm, TabeeSelectorAllPloCourses)
-	go cacheOutcomes(r.gorm, TabeeSelectorAllPoCourses)
-
 	return nil
 }
 
@@ -69,9 +72,6 @@ func (r StudentOutcomeRepositoryGorm) CreateSubSO(subStudentOutcome *entity.SubS
 		return fmt.Errorf("cannot create subStudentOutcome: %w", err)
 	}
 
-	go cacheOutcomes(r.gorm, TabeeSelectorAllPloCourses)
-	go cacheOutcomes(r.gorm, TabeeSelectorAllPoCourses)
-
 	return nil
 }
 
@@ -80,8 +80,6 @@ func (r StudentOutcomeRepositoryGorm) CreateManySubSO(subStudentOutcome []*entit
 	if err != nil {
 		return fmt.Errorf("cannot create subStudentOutcome: %w", err)
 	}
-	go cacheOutcomes(r.gorm, TabeeSelectorAllPloCourses)
-	go cacheOutcomes(r.gorm, TabeeSelectorAllPoCourses)
 
 	return nil
 }
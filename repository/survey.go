@@ -3,6 +3,7 @@ package repository
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/team-inu/inu-backyard/entity"
 	"gorm.io/gorm"
@@ -207,3 +208,75 @@ func splitAndFilter(s string) []string {
 	}
 	return result
 }
+
+func (r *SurveyRepositoryGorm) GetByProgrammeId(programmeId string) ([]entity.Survey, error) {
+	var surveys []entity.Survey
+	err := r.gorm.Preload("Questions.Scores").Where("programme_id = ?", programmeId).Find(&surveys).Error
+	if err != nil {
+		return nil, fmt.Errorf("cannot query surveys by programme id: %w", err)
+	}
+
+	return surveys, nil
+}
+
+func (r *SurveyRepositoryGorm) CreateInvitations(invitations []entity.SurveyInvitation) error {
+	err := r.gorm.Create(&invitations).Error
+	if err != nil {
+		return fmt.Errorf("cannot create survey invitations: %w", err)
+	}
+
+	return nil
+}
+
+func (r *SurveyRepositoryGorm) GetInvitationsBySurveyId(surveyId string) ([]entity.SurveyInvitation, error) {
+	var invitations []entity.SurveyInvitation
+	err := r.gorm.Where("survey_id = ?", surveyId).Order("created_at").Find(&invitations).Error
+	if err != nil {
+		return nil, fmt.Errorf("cannot query survey invitations: %w", err)
+	}
+
+	return invitations, nil
+}
+
+func (r *SurveyRepositoryGorm) GetInvitationByTokenHash(tokenHash string) (*entity.SurveyInvitation, error) {
+	var invitation entity.SurveyInvitation
+	err := r.gorm.Where("token_hash = ?", tokenHash).First(&invitation).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("cannot query survey invitation by token: %w", err)
+	}
+
+	return &invitation, nil
+}
+
+func (r *SurveyRepositoryGorm) SubmitResponse(invitationId string, scores []entity.QScore) (bool, error) {
+	accepted := false
+
+	err := r.gorm.Transaction(func(tx *gorm.DB) error {
+		// consuming the invitation first keeps concurrent submissions of one token from both succeeding
+		result := tx.Model(&entity.SurveyInvitation{}).
+			Where("id = ? AND responded_at IS NULL", invitationId).
+			Update("responded_at", time.Now())
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		if len(scores) > 0 {
+			if err := tx.Create(&scores).Error; err != nil {
+				return err
+			}
+		}
+
+		accepted = true
+		return nil
+	})
+	if err != nil {
+		return false, fmt.Errorf("cannot submit survey response: %w", err)
+	}
+
+	return accepted, nil
+}
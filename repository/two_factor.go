@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/team-inu/inu-backyard/entity"
+	"gorm.io/gorm"
+)
+
+type twoFactorRepositoryGorm struct {
+	gorm *gorm.DB
+}
+
+func NewTwoFactorRepositoryGorm(gorm *gorm.DB) entity.TwoFactorRepository {
+	return &twoFactorRepositoryGorm{gorm: gorm}
+}
+
+func (r twoFactorRepositoryGorm) UpdateTotp(userId string, secret string, enabled bool) error {
+	err := r.gorm.Model(&entity.User{}).Where("id = ?", userId).Updates(map[string]interface{}{
+		"totp_secret":         secret,
+		"totp_enabled":        enabled,
+		"totp_last_used_step": 0,
+	}).Error
+	if err != nil {
+		return fmt.Errorf("cannot update totp of user: %w", err)
+	}
+
+	return nil
+}
+
+func (r twoFactorRepositoryGorm) UpdateTotpLastUsedStep(userId string, step int64) (bool, error) {
+	result := r.gorm.Model(&entity.User{}).
+		Where("id = ? AND totp_last_used_step < ?", userId, step).
+		Update("totp_last_used_step", step)
+	if result.Error != nil {
+		return false, fmt.Errorf("cannot update totp last used step of user: %w", result.Error)
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+func (r twoFactorRepositoryGorm) ReplaceRecoveryCodes(userId string, codes []entity.RecoveryCode) error {
+	return r.gorm.Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("user_id = ?", userId).Delete(&entity.RecoveryCode{}).Error
+		if err != nil {
+			return fmt.Errorf("cannot delete recovery codes: %w", err)
+		}
+
+		if len(codes) == 0 {
+			return nil
+		}
+
+		err = tx.Omit("User").Create(&codes).Error
+		if err != nil {
+			return fmt.Errorf("cannot create recovery codes: %w", err)
+		}
+
+		return nil
+	})
+}
+
+func (r twoFactorRepositoryGorm) UseRecoveryCode(userId string, codeHash string) (bool, error) {
+	result := r.gorm.Model(&entity.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userId, codeHash).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return false, fmt.Errorf("cannot use recovery code: %w", result.Error)
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+func (r twoFactorRepositoryGorm) CountUnusedRecoveryCodes(userId string) (int64, error) {
+	var count int64
+
+	err := r.gorm.Model(&entity.RecoveryCode{}).Where("user_id = ? AND used_at IS NULL", userId).Count(&count).Error
+	if err != nil {
+		return 0, fmt.Errorf("cannot count recovery codes: %w", err)
+	}
+
+	return count, nil
+}
+
+func (r twoFactorRepositoryGorm) GetPolicies() ([]entity.TwoFactorPolicy, error) {
+	var policies []entity.TwoFactorPolicy
+
+	err := r.gorm.Find(&policies).Error
+	if err != nil {
+		return nil, fmt.Errorf("cannot query to get two-factor policies: %w", err)
+	}
+
+	return policies, nil
+}
+
+func (r twoFactorRepositoryGorm) ReplacePolicies(policies []entity.TwoFactorPolicy) error {
+	return r.gorm.Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("1 = 1").Delete(&entity.TwoFactorPolicy{}).Error
+		if err != nil {
+			return fmt.Errorf("cannot delete two-factor policies: %w", err)
+		}
+
+		if len(policies) == 0 {
+			return nil
+		}
+
+		err = tx.Create(&policies).Error
+		if err != nil {
+			return fmt.Errorf("cannot create two-factor policies: %w", err)
+		}
+
+		return nil
+	})
+}
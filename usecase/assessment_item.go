@@ -0,0 +1,411 @@
+package usecase
+
+import (
+	"slices"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/team-inu/inu-backyard/entity"
+	errs "github.com/team-inu/inu-backyard/entity/error"
+	slice "github.com/team-inu/inu-backyard/internal/utils/slice"
+)
+
+type assessmentItemUseCase struct {
+	assessmentItemRepo           entity.AssessmentItemRepository
+	assignmentUseCase            entity.AssignmentUseCase
+	courseUseCase                entity.CourseUseCase
+	courseLearningOutcomeUseCase entity.CourseLearningOutcomeUseCase
+	enrollmentUseCase            entity.EnrollmentUseCase
+	milestoneUseCase             entity.MilestoneUseCase
+}
+
+func NewAssessmentItemUseCase(
+	assessmentItemRepo entity.AssessmentItemRepository,
+	assignmentUseCase entity.AssignmentUseCase,
+	courseUseCase entity.CourseUseCase,
+	courseLearningOutcomeUseCase entity.CourseLearningOutcomeUseCase,
+	enrollmentUseCase entity.EnrollmentUseCase,
+	milestoneUseCase entity.MilestoneUseCase,
+) entity.AssessmentItemUseCase {
+	return &assessmentItemUseCase{
+		assessmentItemRepo:           assessmentItemRepo,
+		assignmentUseCase:            assignmentUseCase,
+		courseUseCase:                courseUseCase,
+		courseLearningOutcomeUseCase: courseLearningOutcomeUseCase,
+		enrollmentUseCase:            enrollmentUseCase,
+		milestoneUseCase:             milestoneUseCase,
+	}
+}
+
+func (u assessmentItemUseCase) GetById(id string) (*entity.AssessmentItem, error) {
+	item, err := u.assessmentItemRepo.GetById(id)
+	if err != nil {
+		return nil, errs.New(errs.ErrQueryAssessmentItem, "cannot get assessment item by id %s", id, err)
+	}
+
+	return item, nil
+}
+
+func (u assessmentItemUseCase) GetByAssignmentId(assignmentId string) ([]entity.AssessmentItem, error) {
+	items, err := u.assessmentItemRepo.GetByAssignmentId(assignmentId)
+	if err != nil {
+		return nil, errs.New(errs.ErrQueryAssessmentItem, "cannot get assessment items by assignment id %s", assignmentId, err)
+	}
+
+	return items, nil
+}
+
+func (u assessmentItemUseCase) Create(assignmentId string, payload entity.CreateAssessmentItemPayload) error {
+	assignment, courseId, err := u.getAssignment(assignmentId)
+	if err != nil {
+		return err
+	}
+
+	err = u.checkMaxScores(*assignment, "", *payload.MaxScore)
+	if err != nil {
+		return err
+	}
+
+	err = u.checkCourseLearningOutcomes(courseId, payload.CourseLearningOutcomeIds)
+	if err != nil {
+		return err
+	}
+
+	courseLearningOutcomes := []*entity.CourseLearningOutcome{}
+	for _, id := range payload.CourseLearningOutcomeIds {
+		courseLearningOutcomes = append(courseLearningOutcomes, &entity.CourseLearningOutcome{Id: id})
+	}
+
+	expectedScorePercentage := assignment.ExpectedScorePercentage
+	if payload.ExpectedScorePercentage != nil {
+		expectedScorePercentage = *payload.ExpectedScorePercentage
+	}
+
+	item := entity.AssessmentItem{
+		Id:                      ulid.Make().String(),
+		AssignmentId:            assignmentId,
+		Name:                    payload.Name,
+		Description:             payload.Description,
+		MaxScore:                *payload.MaxScore,
+		ExpectedScorePercentage: expectedScorePercentage,
+		Sequence:                payload.Sequence,
+		CourseLearningOutcomes:  courseLearningOutcomes,
+	}
+
+	err = u.assessmentItemRepo.Create(&item)
+	if err != nil {
+		return errs.New(errs.ErrCreateAssessmentItem, "cannot create assessment item", err)
+	}
+
+	return nil
+}
+
+func (u assessmentItemUseCase) Update(id string, payload entity.UpdateAssessmentItemPayload) error {
+	existItem, err := u.GetById(id)
+	if err != nil {
+		return errs.New(errs.SameCode, "cannot get assessment item id %s to update", id, err)
+	} else if existItem == nil {
+		return errs.New(errs.ErrAssessmentItemNotFound, "cannot get assessment item id %s to update", id)
+	}
+
+	item := entity.AssessmentItem{
+		Name:        payload.Name,
+		Description: payload.Description,
+	}
+
+	if payload.MaxScore != nil {
+		if existItem.RubricCriterionId != nil {
+			return errs.New(errs.ErrInvalidAssessmentItem, "max score of assessment item id %s comes from its rubric criterion", id)
+		}
+
+		assignment, _, err := u.getAssignment(existItem.AssignmentId)
+		if err != nil {
+			return err
+		}
+
+		err = u.checkMaxScores(*assignment, id, *payload.MaxScore)
+		if err != nil {
+			return err
+		}
+
+		item.MaxScore = *payload.MaxScore
+	}
+	if payload.ExpectedScorePercentage != nil {
+		item.ExpectedScorePercentage = *payload.ExpectedScorePercentage
+	}
+	if payload.Sequence != nil {
+		item.Sequence = *payload.Sequence
+	}
+
+	err = u.assessmentItemRepo.Update(id, &item)
+	if err != nil {
+		return errs.New(errs.ErrUpdateAssessmentItem, "cannot update assessment item by id %s", id, err)
+	}
+
+	return nil
+}
+
+func (u assessmentItemUseCase) Delete(id string) error {
+	item, err := u.GetById(id)
+	if err != nil {
+		return errs.New(errs.SameCode, "cannot get assessment item id %s to delete", id, err)
+	} else if item == nil {
+		return errs.New(errs.ErrAssessmentItemNotFound, "cannot get assessment item id %s to delete", id)
+	} else if item.RubricCriterionId != nil {
+		return errs.New(errs.ErrInvalidAssessmentItem, "assessment item id %s is removed with the rubric of its assignment", id)
+	}
+
+	err = u.assessmentItemRepo.Delete(id)
+	if err != nil {
+		return errs.New(errs.ErrDeleteAssessmentItem, "cannot delete assessment item by id %s", id, err)
+	}
+
+	return nil
+}
+
+func (u assessmentItemUseCase) CreateLinkCourseLearningOutcome(id string, courseLearningOutcomeIds []string) error {
+	item, err := u.GetById(id)
+	if err != nil {
+		return errs.New(errs.SameCode, "cannot get assessment item id %s while link clo", id, err)
+	} else if item == nil {
+		return errs.New(errs.ErrAssessmentItemNotFound, "assessment item id %s not found while link clo", id)
+	}
+
+	_, courseId, err := u.getAssignment(item.AssignmentId)
+	if err != nil {
+		return err
+	}
+
+	alreadyLinkedCloIds := []string{}
+	for _, clo := range item.CourseLearningOutcomes {
+		if slices.Contains(courseLearningOutcomeIds, clo.Id) {
+			alreadyLinkedCloIds = append(alreadyLinkedCloIds, clo.Id)
+		}
+	}
+	if len(alreadyLinkedCloIds) != 0 {
+		return errs.New(errs.ErrInvalidAssessmentItem, "clo ids %v are already linked to assessment item id %s", alreadyLinkedCloIds, id)
+	}
+
+	err = u.checkCourseLearningOutcomes(courseId, courseLearningOutcomeIds)
+	if err != nil {
+		return err
+	}
+
+	err = u.assessmentItemRepo.CreateLinkCourseLearningOutcome(id, courseLearningOutcomeIds)
+	if err != nil {
+		return errs.New(errs.ErrCreateAssessmentItem, "cannot link clo to assessment item id %s", id, err)
+	}
+
+	return nil
+}
+
+func (u assessmentItemUseCase) DeleteLinkCourseLearningOutcome(id string, courseLearningOutcomeId string) error {
+	item, err := u.GetById(id)
+	if err != nil {
+		return errs.New(errs.SameCode, "cannot get assessment item id %s while unlink clo", id, err)
+	} else if item == nil {
+		return errs.New(errs.ErrAssessmentItemNotFound, "assessment item id %s not found while unlink clo", id)
+	}
+
+	err = u.assessmentItemRepo.DeleteLinkCourseLearningOutcome(id, courseLearningOutcomeId)
+	if err != nil {
+		return errs.New(errs.ErrDeleteAssessmentItem, "cannot unlink clo from assessment item id %s", id, err)
+	}
+
+	return nil
+}
+
+func (u assessmentItemUseCase) GetScoresByAssignmentId(assignmentId string) ([]entity.AssessmentItemScore, error) {
+	scores, err := u.assessmentItemRepo.GetScoresByAssignmentId(assignmentId)
+	if err != nil {
+		return nil, errs.New(errs.ErrQueryAssessmentItem, "cannot get assessment item scores by assignment id %s", assignmentId, err)
+	}
+
+	return scores, nil
+}
+
+func (u assessmentItemUseCase) SaveScores(user entity.User, assignmentId string, studentScores []entity.StudentItemScores) error {
+	if len(studentScores) == 0 {
+		return errs.New(errs.ErrInvalidAssessmentItemScore, "student scores must not be empty")
+	}
+
+	_, courseId, err := u.getAssignment(assignmentId)
+	if err != nil {
+		return err
+	}
+
+	course, err := u.courseUseCase.GetById(courseId)
+	if err != nil {
+		return errs.New(errs.SameCode, "cannot get course id %s to save assessment item scores", courseId, err)
+	} else if course == nil {
+		return errs.New(errs.ErrCourseNotFound, "cannot get course id %s to save assessment item scores", courseId)
+	}
+
+	if !user.IsRoles([]entity.UserRole{entity.UserRoleHeadOfCurriculum}) {
+		isLecturer := false
+		for _, lecturer := range course.Lecturers {
+			isLecturer = isLecturer || lecturer.Id == user.Id
+		}
+		if !isLecturer {
+			return errs.New(errs.ErrAssessmentItemPermission, "no permission to save assessment item scores of course id %s", courseId)
+		}
+
+		isLocked, err := u.milestoneUseCase.IsScoreEntryLocked(course.SemesterId)
+		if err != nil {
+			return errs.New(errs.SameCode, "cannot check score entry lock of semester id %s", course.SemesterId, err)
+		} else if isLocked {
+			return errs.New(errs.ErrScoreEntryLocked, "score entry of semester id %s is closed", course.SemesterId)
+		}
+	}
+
+	items, err := u.GetByAssignmentId(assignmentId)
+	if err != nil {
+		return err
+	}
+
+	itemById := make(map[string]entity.AssessmentItem, len(items))
+	for _, item := range items {
+		itemById[item.Id] = item
+	}
+
+	studentIds := []string{}
+	scores := []entity.AssessmentItemScore{}
+	for _, studentScore := range studentScores {
+		studentIds = append(studentIds, studentScore.StudentId)
+		for itemId, score := range studentScore.Scores {
+			item, ok := itemById[itemId]
+			if !ok {
+				return errs.New(errs.ErrInvalidAssessmentItemScore, "assessment item id %s is not an item of assignment id %s", itemId, assignmentId)
+			} else if item.RubricCriterion != nil {
+				return errs.New(errs.ErrInvalidAssessmentItemScore, "assessment item id %s is scored by a rubric level", itemId)
+			} else if score > item.MaxScore {
+				return errs.New(errs.ErrInvalidAssessmentItemScore, "score %.2f of student id %s is more than max score %.2f of assessment item id %s", score, studentScore.StudentId, item.MaxScore, itemId)
+			}
+
+			scores = append(scores, entity.AssessmentItemScore{
+				AssessmentItemId: itemId,
+				StudentId:        studentScore.StudentId,
+				Score:            score,
+				UserId:           user.Id,
+			})
+		}
+
+		for itemId, levelId := range studentScore.Levels {
+			item, ok := itemById[itemId]
+			if !ok {
+				return errs.New(errs.ErrInvalidAssessmentItemScore, "assessment item id %s is not an item of assignment id %s", itemId, assignmentId)
+			} else if item.RubricCriterion == nil {
+				return errs.New(errs.ErrInvalidAssessmentItemScore, "assessment item id %s is not scored by a rubric level", itemId)
+			}
+
+			level := findRubricLevel(*item.RubricCriterion, levelId)
+			if level == nil {
+				return errs.New(errs.ErrInvalidAssessmentItemScore, "rubric level id %s is not a level of assessment item id %s", levelId, itemId)
+			}
+
+			scores = append(scores, entity.AssessmentItemScore{
+				AssessmentItemId: itemId,
+				StudentId:        studentScore.StudentId,
+				Score:            level.Points,
+				UserId:           user.Id,
+				RubricLevelId:    &level.Id,
+			})
+		}
+	}
+
+	duplicateStudentIds := slice.GetDuplicateValue(studentIds)
+	if len(duplicateStudentIds) != 0 {
+		return errs.New(errs.ErrInvalidAssessmentItemScore, "duplicate student ids %v", duplicateStudentIds)
+	}
+
+	withStatus := entity.EnrollmentStatusEnroll
+	joinedStudentIds, err := u.enrollmentUseCase.FilterJoinedStudent(studentIds, courseId, &withStatus)
+	if err != nil {
+		return errs.New(errs.SameCode, "cannot get existed student ids while saving assessment item scores", err)
+	}
+
+	nonJoinedStudentIds := slice.Subtraction(studentIds, joinedStudentIds)
+	if len(nonJoinedStudentIds) > 0 {
+		return errs.New(errs.ErrInvalidAssessmentItemScore, "there are non joined student ids %v", nonJoinedStudentIds)
+	}
+
+	if len(scores) == 0 {
+		return nil
+	}
+
+	err = u.assessmentItemRepo.SaveScores(assignmentId, user.Id, scores)
+	if err != nil {
+		return errs.New(errs.ErrSaveAssessmentItemScore, "cannot save assessment item scores of assignment id %s", assignmentId, err)
+	}
+
+	return nil
+}
+
+func findRubricLevel(criterion entity.RubricCriterion, levelId string) *entity.RubricLevel {
+	for i := range criterion.Levels {
+		if criterion.Levels[i].Id == levelId {
+			return &criterion.Levels[i]
+		}
+	}
+
+	return nil
+}
+
+// getAssignment returns the assignment with the id of its course
+func (u assessmentItemUseCase) getAssignment(assignmentId string) (*entity.Assignment, string, error) {
+	assignment, err := u.assignmentUseCase.GetById(assignmentId)
+	if err != nil {
+		return nil, "", errs.New(errs.SameCode, "cannot get assignment id %s of assessment item", assignmentId, err)
+	} else if assignment == nil {
+		return nil, "", errs.New(errs.ErrAssignmentNotFound, "assignment id %s of assessment item not found", assignmentId)
+	}
+
+	assignmentGroup, err := u.assignmentUseCase.GetGroupByGroupId(assignment.AssignmentGroupId)
+	if err != nil {
+		return nil, "", errs.New(errs.SameCode, "cannot get assignment group id %s of assessment item", assignment.AssignmentGroupId, err)
+	} else if assignmentGroup == nil {
+		return nil, "", errs.New(errs.ErrAssignmentNotFound, "assignment group id %s of assessment item not found", assignment.AssignmentGroupId)
+	}
+
+	return assignment, assignmentGroup.CourseId, nil
+}
+
+// checkMaxScores refuses an item max score taking the items of the assignment over its max score, the item being updated is left out
+func (u assessmentItemUseCase) checkMaxScores(assignment entity.Assignment, itemId string, maxScore float64) error {
+	items, err := u.GetByAssignmentId(assignment.Id)
+	if err != nil {
+		return err
+	}
+
+	totalMaxScore := maxScore
+	for _, item := range items {
+		if item.Id != itemId {
+			totalMaxScore += item.MaxScore
+		}
+	}
+
+	if totalMaxScore > float64(assignment.MaxScore) {
+		return errs.New(errs.ErrInvalidAssessmentItem, "max scores of assessment items add up to %.2f over max score %d of assignment id %s", totalMaxScore, assignment.MaxScore, assignment.Id)
+	}
+
+	return nil
+}
+
+func (u assessmentItemUseCase) checkCourseLearningOutcomes(courseId string, courseLearningOutcomeIds []string) error {
+	duplicateCloIds := slice.GetDuplicateValue(courseLearningOutcomeIds)
+	if len(duplicateCloIds) != 0 {
+		return errs.New(errs.ErrInvalidAssessmentItem, "duplicate clo ids %v", duplicateCloIds)
+	}
+
+	for _, cloId := range courseLearningOutcomeIds {
+		clo, err := u.courseLearningOutcomeUseCase.GetById(cloId)
+		if err != nil {
+			return errs.New(errs.SameCode, "cannot get clo id %s of assessment item", cloId, err)
+		} else if clo == nil {
+			return errs.New(errs.ErrCLONotFound, "clo id %s of assessment item not found", cloId)
+		} else if clo.CourseId != courseId {
+			return errs.New(errs.ErrInvalidAssessmentItem, "clo id %s is not a clo of course id %s", cloId, courseId)
+		}
+	}
+
+	return nil
+}
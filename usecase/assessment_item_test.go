@@ -0,0 +1,114 @@
+package usecase
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/team-inu/inu-backyard/entity"
+	errs "github.com/team-inu/inu-backyard/entity/error"
+)
+
+type stubAssessmentItemRepository struct {
+	entity.AssessmentItemRepository
+	items   []entity.AssessmentItem
+	created *entity.AssessmentItem
+	scores  []entity.AssessmentItemScore
+}
+
+func (r *stubAssessmentItemRepository) GetByAssignmentId(assignmentId string) ([]entity.AssessmentItem, error) {
+	return r.items, nil
+}
+
+func (r *stubAssessmentItemRepository) Create(item *entity.AssessmentItem) error {
+	r.created = item
+	return nil
+}
+
+func (r *stubAssessmentItemRepository) SaveScores(assignmentId string, userId string, scores []entity.AssessmentItemScore) error {
+	r.scores = scores
+	return nil
+}
+
+type stubAssignmentUseCase struct {
+	entity.AssignmentUseCase
+	assignment entity.Assignment
+}
+
+func (u *stubAssignmentUseCase) GetById(id string) (*entity.Assignment, error) {
+	return &u.assignment, nil
+}
+
+func (u *stubAssignmentUseCase) GetGroupByGroupId(assignmentGroupId string) (*entity.AssignmentGroup, error) {
+	return &entity.AssignmentGroup{Id: assignmentGroupId, CourseId: "course"}, nil
+}
+
+type stubCourseUseCase struct {
+	entity.CourseUseCase
+}
+
+func (u *stubCourseUseCase) GetById(id string) (*entity.Course, error) {
+	return &entity.Course{Id: id, ProgrammeId: "programme", Lecturers: []*entity.User{{Id: "lecturer"}}}, nil
+}
+
+type stubEnrollmentUseCase struct {
+	entity.EnrollmentUseCase
+}
+
+func (u *stubEnrollmentUseCase) FilterJoinedStudent(studentIds []string, courseId string, withStatus *entity.EnrollmentStatus) ([]string, error) {
+	return studentIds, nil
+}
+
+// an assignment of 20 with an item of 10
+func newAssessmentItemUseCase() (entity.AssessmentItemUseCase, *stubAssessmentItemRepository) {
+	repository := &stubAssessmentItemRepository{
+		items: []entity.AssessmentItem{{Id: "question-1", AssignmentId: "exam", MaxScore: 10}},
+	}
+	assignmentUseCase := &stubAssignmentUseCase{
+		assignment: entity.Assignment{Id: "exam", MaxScore: 20, ExpectedScorePercentage: 60, AssignmentGroupId: "midterm"},
+	}
+
+	return NewAssessmentItemUseCase(repository, assignmentUseCase, &stubCourseUseCase{}, nil, &stubEnrollmentUseCase{}, nil), repository
+}
+
+func TestAssessmentItem(t *testing.T) {
+	t.Run("TestCreate", func(t *testing.T) {
+		assessmentItemUseCase, repository := newAssessmentItemUseCase()
+
+		maxScore := 10.0
+		err := assessmentItemUseCase.Create("exam", entity.CreateAssessmentItemPayload{Name: "question 2", MaxScore: &maxScore})
+		assert.Nil(t, err)
+		assert.Equal(t, 60.0, repository.created.ExpectedScorePercentage, "Expected the expected score percentage of the assignment by default")
+	})
+
+	t.Run("TestCreate_OverMaxScore", func(t *testing.T) {
+		assessmentItemUseCase, repository := newAssessmentItemUseCase()
+
+		maxScore := 10.5
+		err := assessmentItemUseCase.Create("exam", entity.CreateAssessmentItemPayload{Name: "question 2", MaxScore: &maxScore})
+		assert.Equal(t, errs.ErrInvalidAssessmentItem, errorCode(err), "Expected items adding up over the max score of the assignment to be refused")
+		assert.Nil(t, repository.created)
+	})
+
+	t.Run("TestSaveScores", func(t *testing.T) {
+		assessmentItemUseCase, repository := newAssessmentItemUseCase()
+		user := entity.User{Id: "head", Role: entity.UserRoleHeadOfCurriculum}
+
+		err := assessmentItemUseCase.SaveScores(user, "exam", []entity.StudentItemScores{{StudentId: "student", Scores: map[string]float64{"question-1": 7}}})
+		assert.Nil(t, err)
+		assert.Equal(t, []entity.AssessmentItemScore{{AssessmentItemId: "question-1", StudentId: "student", Score: 7, UserId: "head"}}, repository.scores)
+
+		err = assessmentItemUseCase.SaveScores(user, "exam", []entity.StudentItemScores{{StudentId: "student", Scores: map[string]float64{"question-1": 11}}})
+		assert.Equal(t, errs.ErrInvalidAssessmentItemScore, errorCode(err), "Expected a score over the max score of the item to be refused")
+
+		err = assessmentItemUseCase.SaveScores(user, "exam", []entity.StudentItemScores{{StudentId: "student", Scores: map[string]float64{"question-9": 1}}})
+		assert.Equal(t, errs.ErrInvalidAssessmentItemScore, errorCode(err), "Expected an item of another assignment to be refused")
+	})
+
+	t.Run("TestSaveScores_Permission", func(t *testing.T) {
+		assessmentItemUseCase, repository := newAssessmentItemUseCase()
+
+		err := assessmentItemUseCase.SaveScores(entity.User{Id: "other", Role: entity.UserRoleLecturer}, "exam", []entity.StudentItemScores{{StudentId: "student", Scores: map[string]float64{"question-1": 7}}})
+		assert.Equal(t, errs.ErrAssessmentItemPermission, errorCode(err), "Expected a lecturer outside the course to be refused")
+		assert.Nil(t, repository.scores)
+	})
+}
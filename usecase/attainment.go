@@ -0,0 +1,221 @@
+package usecase
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/team-inu/inu-backyard/entity"
+	errs "github.com/team-inu/inu-backyard/entity/error"
+	"github.com/team-inu/inu-backyard/internal/config"
+)
+
+type attainmentUseCase struct {
+	attainmentRepo entity.AttainmentRepository
+	methods        map[entity.AttainmentMethod]attainmentMethod
+	defaultMethod  entity.AttainmentMethod
+}
+
+// zero values in config fall back to the THRESHOLD method and rubric levels from 0, 50, 65 and 80 percent passing at level 3
+func NewAttainmentUseCase(attainmentRepo entity.AttainmentRepository, config config.AttainmentConfig) (entity.AttainmentUseCase, error) {
+	defaultMethod := entity.AttainmentMethod(config.Method)
+	if defaultMethod == "" {
+		defaultMethod = entity.AttainmentMethodThreshold
+	} else if !defaultMethod.IsValid() {
+		return nil, fmt.Errorf("unknown attainment method %s", config.Method)
+	}
+
+	if len(config.RubricLevels) == 0 {
+		config.RubricLevels = []float64{0, 50, 65, 80}
+		config.PassingRubricLevel = 3
+	}
+
+	methods, err := newAttainmentMethods(config)
+	if err != nil {
+		return nil, fmt.Errorf("cannot configure attainment methods: %w", err)
+	}
+
+	return &attainmentUseCase{
+		attainmentRepo: attainmentRepo,
+		methods:        methods,
+		defaultMethod:  defaultMethod,
+	}, nil
+}
+
+func (u attainmentUseCase) RefreshAll() (int64, error) {
+	count, err := u.attainmentRepo.QueueAll()
+	if err != nil {
+		return 0, errs.New(errs.ErrRefreshAttainment, "cannot queue attainment refresh of all courses", err)
+	}
+
+	return count, nil
+}
+
+func (u attainmentUseCase) ProcessNext() (bool, error) {
+	isRefreshed, err := u.attainmentRepo.RefreshNext(u.evaluate)
+	if err != nil {
+		return false, errs.New(errs.ErrRefreshAttainment, "cannot refresh attainments", err)
+	}
+
+	return isRefreshed, nil
+}
+
+// evaluate uses the method chosen by the course or its programme, the default one when they chose none
+func (u attainmentUseCase) evaluate(source entity.AttainmentSource) entity.CourseAttainment {
+	method, ok := u.methods[source.Method]
+	if !ok {
+		method = u.methods[u.defaultMethod]
+	}
+
+	return evaluateAttainment(source, method)
+}
+
+// evaluateAttainment computes the attainments of the students of the source, a CLO is evaluated from the scores
+// of its assignments and an outcome from the evaluated CLOs linked to it by their link weights, both with the same method
+func evaluateAttainment(source entity.AttainmentSource, method attainmentMethod) entity.CourseAttainment {
+	now := time.Now()
+	attainment := entity.CourseAttainment{}
+
+	assignments := make(map[string]entity.AttainmentAssignment, len(source.Assignments))
+	for _, assignment := range source.Assignments {
+		assignments[assignment.Id] = assignment
+	}
+
+	scores := map[string]map[string]float64{}
+	for _, score := range source.Scores {
+		if scores[score.StudentId] == nil {
+			scores[score.StudentId] = map[string]float64{}
+		}
+		scores[score.StudentId][score.AssignmentId] = score.Score
+	}
+
+	isEvaluatedClo := map[string]bool{}
+	cloResults := map[string]map[string]attainmentItem{}
+	for _, studentId := range source.StudentIds {
+		cloResults[studentId] = map[string]attainmentItem{}
+
+		for _, clo := range source.Clos {
+			if len(clo.AssignmentIds) == 0 {
+				continue
+			}
+			isEvaluatedClo[clo.Id] = true
+
+			items := make([]attainmentItem, 0, len(clo.AssignmentIds))
+			for _, assignmentId := range clo.AssignmentIds {
+				assignment := assignments[assignmentId]
+				item := attainmentItem{weight: 1, points: assignment.MaxScore}
+
+				// a missing score counts as not passed
+				if score, ok := scores[studentId][assignmentId]; ok {
+					item.passed = score*100 >= assignment.ExpectedScorePercentage*assignment.MaxScore
+					if assignment.MaxScore > 0 {
+						item.percentage = score / assignment.MaxScore * 100
+					}
+				}
+
+				items = append(items, item)
+			}
+
+			percentage, passed := method.evaluate(items, clo.ExpectedPassingAssignmentPercentage)
+			cloResults[studentId][clo.Id] = attainmentItem{percentage: percentage, passed: passed, points: 1}
+			attainment.Clos = append(attainment.Clos, entity.StudentCloAttainment{
+				StudentId:               studentId,
+				CourseLearningOutcomeId: clo.Id,
+				CourseId:                source.CourseId,
+				Percentage:              percentage,
+				Passed:                  passed,
+				UpdatedAt:               now,
+			})
+		}
+	}
+
+	rollUp := func(links []entity.AttainmentOutcomeLink, add func(studentId string, outcomeId string, percentage float64, passed bool)) {
+		linksByOutcome := map[string][]entity.AttainmentOutcomeLink{}
+		outcomeIds := []string{}
+		for _, link := range links {
+			if !isEvaluatedClo[link.CourseLearningOutcomeId] {
+				continue
+			}
+			if _, ok := linksByOutcome[link.OutcomeId]; !ok {
+				outcomeIds = append(outcomeIds, link.OutcomeId)
+			}
+			linksByOutcome[link.OutcomeId] = append(linksByOutcome[link.OutcomeId], link)
+		}
+
+		for _, studentId := range source.StudentIds {
+			for _, outcomeId := range outcomeIds {
+				outcomeLinks := linksByOutcome[outcomeId]
+
+				items := make([]attainmentItem, 0, len(outcomeLinks))
+				for _, link := range outcomeLinks {
+					item := cloResults[studentId][link.CourseLearningOutcomeId]
+					item.weight = link.Weight
+					items = append(items, item)
+				}
+
+				percentage, passed := method.evaluate(items, source.ExpectedPassingCloPercentage)
+				add(studentId, outcomeId, percentage, passed)
+			}
+		}
+	}
+
+	rollUp(source.PloLinks, func(studentId string, outcomeId string, percentage float64, passed bool) {
+		attainment.Plos = append(attainment.Plos, entity.StudentPloAttainment{
+			CourseId:                 source.CourseId,
+			StudentId:                studentId,
+			ProgramLearningOutcomeId: outcomeId,
+			Percentage:               percentage,
+			Passed:                   passed,
+			UpdatedAt:                now,
+		})
+	})
+	rollUp(source.SubPloLinks, func(studentId string, outcomeId string, percentage float64, passed bool) {
+		attainment.SubPlos = append(attainment.SubPlos, entity.StudentSubPloAttainment{
+			CourseId:                    source.CourseId,
+			StudentId:                   studentId,
+			SubProgramLearningOutcomeId: outcomeId,
+			Percentage:                  percentage,
+			Passed:                      passed,
+			UpdatedAt:                   now,
+		})
+	})
+	rollUp(source.PoLinks, func(studentId string, outcomeId string, percentage float64, passed bool) {
+		attainment.Pos = append(attainment.Pos, entity.StudentPoAttainment{
+			CourseId:         source.CourseId,
+			StudentId:        studentId,
+			ProgramOutcomeId: outcomeId,
+			Percentage:       percentage,
+			Passed:           passed,
+			UpdatedAt:        now,
+		})
+	})
+	rollUp(source.SoLinks, func(studentId string, outcomeId string, percentage float64, passed bool) {
+		attainment.Sos = append(attainment.Sos, entity.StudentSoAttainment{
+			CourseId:         source.CourseId,
+			StudentId:        studentId,
+			StudentOutcomeId: outcomeId,
+			Percentage:       percentage,
+			Passed:           passed,
+			UpdatedAt:        now,
+		})
+	})
+	rollUp(source.SubSoLinks, func(studentId string, outcomeId string, percentage float64, passed bool) {
+		attainment.SubSos = append(attainment.SubSos, entity.StudentSubSoAttainment{
+			CourseId:            source.CourseId,
+			StudentId:           studentId,
+			SubStudentOutcomeId: outcomeId,
+			Percentage:          percentage,
+			Passed:              passed,
+			UpdatedAt:           now,
+		})
+	})
+
+	return attainment
+}
+
+func percentage(count int, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+
+	return float64(count) / float64(total) * 100
+}
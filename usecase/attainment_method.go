@@ -0,0 +1,152 @@
+package usecase
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/team-inu/inu-backyard/entity"
+	"github.com/team-inu/inu-backyard/internal/config"
+)
+
+// attainmentItem is a result an attainment is made of, an assignment score of a CLO or a CLO of an outcome
+type attainmentItem struct {
+	percentage float64
+	passed     bool
+	// contribution to the attainment, 1 for an assignment and the link weight for a CLO
+	weight float64
+	// max score of an assignment, 1 for a CLO, it weighs the score percentage in averages
+	points float64
+}
+
+type attainmentMethod interface {
+	// evaluate returns the percentage and whether the items attain the expected percentage
+	evaluate(items []attainmentItem, expectedPercentage float64) (float64, bool)
+}
+
+// newAttainmentMethods builds the method of each entity.AttainmentMethod, the rubric levels must ascend
+func newAttainmentMethods(config config.AttainmentConfig) (map[entity.AttainmentMethod]attainmentMethod, error) {
+	for i := 1; i < len(config.RubricLevels); i++ {
+		if config.RubricLevels[i] <= config.RubricLevels[i-1] {
+			return nil, fmt.Errorf("rubric levels must ascend, level %d is %.2f after %.2f", i+1, config.RubricLevels[i], config.RubricLevels[i-1])
+		}
+	}
+	if len(config.RubricLevels) > 0 && (config.PassingRubricLevel < 1 || config.PassingRubricLevel > len(config.RubricLevels)) {
+		return nil, fmt.Errorf("passing rubric level %d is not one of the %d levels", config.PassingRubricLevel, len(config.RubricLevels))
+	}
+
+	return map[entity.AttainmentMethod]attainmentMethod{
+		entity.AttainmentMethodThreshold:       thresholdMethod{},
+		entity.AttainmentMethodWeightedAverage: weightedAverageMethod{},
+		entity.AttainmentMethodBestOf:          bestOfMethod{},
+		entity.AttainmentMethodAllMustPass:     allMustPassMethod{},
+		entity.AttainmentMethodRubricLevel: rubricLevelMethod{
+			levels:       config.RubricLevels,
+			passingLevel: config.PassingRubricLevel,
+		},
+	}, nil
+}
+
+func passedCount(items []attainmentItem) int {
+	count := 0
+	for _, item := range items {
+		if item.passed {
+			count++
+		}
+	}
+
+	return count
+}
+
+// weightedMean weighs the items by weight and points, it falls back to the plain mean when nothing has weight
+func weightedMean(items []attainmentItem, value func(item attainmentItem) float64) float64 {
+	if len(items) == 0 {
+		return 0
+	}
+
+	sum, totalWeight := 0.0, 0.0
+	for _, item := range items {
+		sum += value(item) * item.weight * item.points
+		totalWeight += item.weight * item.points
+	}
+	if totalWeight > 0 {
+		return sum / totalWeight
+	}
+
+	sum = 0
+	for _, item := range items {
+		sum += value(item)
+	}
+
+	return sum / float64(len(items))
+}
+
+type thresholdMethod struct{}
+
+func (thresholdMethod) evaluate(items []attainmentItem, expectedPercentage float64) (float64, bool) {
+	passedWeight, totalWeight := 0.0, 0.0
+	for _, item := range items {
+		totalWeight += item.weight
+		if item.passed {
+			passedWeight += item.weight
+		}
+	}
+
+	if totalWeight == 0 {
+		return 0, false
+	}
+
+	return passedWeight / totalWeight * 100, entity.IsAttained(passedWeight, totalWeight, expectedPercentage)
+}
+
+type weightedAverageMethod struct{}
+
+func (weightedAverageMethod) evaluate(items []attainmentItem, expectedPercentage float64) (float64, bool) {
+	average := weightedMean(items, func(item attainmentItem) float64 { return item.percentage })
+	return average, len(items) > 0 && average >= expectedPercentage
+}
+
+type bestOfMethod struct{}
+
+func (bestOfMethod) evaluate(items []attainmentItem, _ float64) (float64, bool) {
+	best := 0.0
+	for _, item := range items {
+		best = math.Max(best, item.percentage)
+	}
+
+	return best, passedCount(items) > 0
+}
+
+type allMustPassMethod struct{}
+
+func (allMustPassMethod) evaluate(items []attainmentItem, _ float64) (float64, bool) {
+	count := passedCount(items)
+	return percentage(count, len(items)), len(items) > 0 && count == len(items)
+}
+
+type rubricLevelMethod struct {
+	levels       []float64
+	passingLevel int
+}
+
+// level is counted from 1, a percentage below the lowest level is 0
+func (m rubricLevelMethod) level(percentage float64) int {
+	level := 0
+	for i, minPercentage := range m.levels {
+		if percentage >= minPercentage {
+			level = i + 1
+		}
+	}
+
+	return level
+}
+
+func (m rubricLevelMethod) evaluate(items []attainmentItem, _ float64) (float64, bool) {
+	if len(items) == 0 || len(m.levels) == 0 {
+		return 0, false
+	}
+
+	averageLevel := weightedMean(items, func(item attainmentItem) float64 { return float64(m.level(item.percentage)) })
+	average := weightedMean(items, func(item attainmentItem) float64 { return item.percentage })
+
+	return average, int(math.Floor(averageLevel)) >= m.passingLevel
+}
@@ -0,0 +1,230 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/team-inu/inu-backyard/entity"
+	errs "github.com/team-inu/inu-backyard/entity/error"
+	"github.com/team-inu/inu-backyard/internal/config"
+)
+
+type stubAttainmentRepository struct {
+	entity.AttainmentRepository
+	sources []entity.AttainmentSource
+	results []entity.CourseAttainment
+	err     error
+}
+
+func (r *stubAttainmentRepository) RefreshNext(evaluate func(source entity.AttainmentSource) entity.CourseAttainment) (bool, error) {
+	if r.err != nil {
+		return false, r.err
+	} else if len(r.sources) == 0 {
+		return false, nil
+	}
+
+	r.results = append(r.results, evaluate(r.sources[0]))
+	r.sources = r.sources[1:]
+	return true, nil
+}
+
+// two CLOs of two assignments each, CLO-1 needs both assignments and CLO-2 one of them
+func newAttainmentSource() entity.AttainmentSource {
+	return entity.AttainmentSource{
+		CourseId:                     "course",
+		ExpectedPassingCloPercentage: 50,
+		StudentIds:                   []string{"good", "half", "absent"},
+		Assignments: []entity.AttainmentAssignment{
+			{Id: "quiz", MaxScore: 10, ExpectedScorePercentage: 50},
+			{Id: "exam", MaxScore: 100, ExpectedScorePercentage: 60},
+			{Id: "lab", MaxScore: 20, ExpectedScorePercentage: 50},
+		},
+		Clos: []entity.AttainmentClo{
+			{Id: "clo-1", ExpectedPassingAssignmentPercentage: 100, AssignmentIds: []string{"quiz", "exam"}},
+			{Id: "clo-2", ExpectedPassingAssignmentPercentage: 50, AssignmentIds: []string{"exam", "lab"}},
+			{Id: "clo-unassessed", ExpectedPassingAssignmentPercentage: 50},
+		},
+		Scores: []entity.AttainmentScore{
+			{StudentId: "good", AssignmentId: "quiz", Score: 5},
+			{StudentId: "good", AssignmentId: "exam", Score: 60},
+			{StudentId: "good", AssignmentId: "lab", Score: 20},
+			{StudentId: "half", AssignmentId: "quiz", Score: 10},
+			{StudentId: "half", AssignmentId: "exam", Score: 59.5},
+			{StudentId: "half", AssignmentId: "lab", Score: 10},
+		},
+		PloLinks: []entity.AttainmentOutcomeLink{
+			{CourseLearningOutcomeId: "clo-1", OutcomeId: "plo", Weight: 1},
+			{CourseLearningOutcomeId: "clo-2", OutcomeId: "plo", Weight: 1},
+			{CourseLearningOutcomeId: "clo-unassessed", OutcomeId: "plo", Weight: 1},
+		},
+		PoLinks: []entity.AttainmentOutcomeLink{
+			{CourseLearningOutcomeId: "clo-1", OutcomeId: "po", Weight: 1},
+		},
+		SoLinks: []entity.AttainmentOutcomeLink{
+			{CourseLearningOutcomeId: "clo-unassessed", OutcomeId: "so", Weight: 1},
+		},
+	}
+}
+
+func TestAttainment(t *testing.T) {
+	t.Run("TestEvaluateAttainment_Clo", func(t *testing.T) {
+		attainment := evaluateAttainment(newAttainmentSource(), thresholdMethod{})
+
+		passed := map[string]bool{}
+		percentages := map[string]float64{}
+		for _, clo := range attainment.Clos {
+			assert.Equal(t, "course", clo.CourseId)
+			passed[clo.StudentId+"/"+clo.CourseLearningOutcomeId] = clo.Passed
+			percentages[clo.StudentId+"/"+clo.CourseLearningOutcomeId] = clo.Percentage
+		}
+
+		assert.Len(t, attainment.Clos, 6, "Expected a row per student for each CLO with assignments")
+		assert.True(t, passed["good/clo-1"], "Expected scores exactly on the threshold to pass")
+		assert.True(t, passed["good/clo-2"])
+		assert.False(t, passed["half/clo-1"], "Expected a CLO needing every assignment to fail with one missed")
+		assert.True(t, passed["half/clo-2"], "Expected a CLO needing half of the assignments to pass with one of two")
+		assert.Equal(t, 50.0, percentages["half/clo-1"])
+		assert.False(t, passed["absent/clo-1"], "Expected missing scores to count as not passed")
+		assert.Equal(t, 0.0, percentages["absent/clo-2"])
+	})
+
+	t.Run("TestEvaluateAttainment_RollUp", func(t *testing.T) {
+		attainment := evaluateAttainment(newAttainmentSource(), thresholdMethod{})
+
+		plos := map[string]entity.StudentPloAttainment{}
+		for _, plo := range attainment.Plos {
+			plos[plo.StudentId] = plo
+		}
+		assert.Len(t, attainment.Plos, 3)
+		assert.True(t, plos["good"].Passed)
+		assert.Equal(t, 100.0, plos["good"].Percentage, "Expected CLOs without assignments to be left out of the roll up")
+		assert.True(t, plos["half"].Passed, "Expected one of two CLOs to meet the expected 50 percent")
+		assert.False(t, plos["absent"].Passed)
+
+		pos := map[string]bool{}
+		for _, po := range attainment.Pos {
+			pos[po.StudentId] = po.Passed
+		}
+		assert.Equal(t, map[string]bool{"good": true, "half": false, "absent": false}, pos)
+
+		assert.Empty(t, attainment.Sos, "Expected outcomes linked only to unassessed CLOs to have no rows")
+	})
+
+	t.Run("TestEvaluateAttainment_LinkWeight", func(t *testing.T) {
+		source := newAttainmentSource()
+		source.PloLinks[0].Weight = 3
+
+		for _, plo := range evaluateAttainment(source, thresholdMethod{}).Plos {
+			if plo.StudentId == "half" {
+				assert.Equal(t, 25.0, plo.Percentage, "Expected the passed CLO to weigh 1 out of 4")
+				assert.False(t, plo.Passed)
+			} else if plo.StudentId == "good" {
+				assert.True(t, plo.Passed)
+			}
+		}
+	})
+
+	t.Run("TestEvaluateAttainment_NoStudents", func(t *testing.T) {
+		source := newAttainmentSource()
+		source.StudentIds = nil
+
+		attainment := evaluateAttainment(source, thresholdMethod{})
+		assert.Empty(t, attainment.Clos, "Expected nothing for a course without stale students")
+		assert.Empty(t, attainment.Plos)
+	})
+
+	t.Run("TestProcessNext", func(t *testing.T) {
+		repository := &stubAttainmentRepository{sources: []entity.AttainmentSource{newAttainmentSource()}}
+		attainmentUseCase, err := NewAttainmentUseCase(repository, config.AttainmentConfig{})
+		assert.Nil(t, err)
+
+		isRefreshed, err := attainmentUseCase.ProcessNext()
+		assert.Nil(t, err)
+		assert.True(t, isRefreshed)
+		assert.Len(t, repository.results, 1, "Expected the claimed course to be evaluated")
+
+		isRefreshed, err = attainmentUseCase.ProcessNext()
+		assert.Nil(t, err)
+		assert.False(t, isRefreshed, "Expected nothing to refresh once the queue is empty")
+	})
+
+	t.Run("TestProcessNext_Error", func(t *testing.T) {
+		attainmentUseCase, err := NewAttainmentUseCase(&stubAttainmentRepository{err: errors.New("deadlock")}, config.AttainmentConfig{})
+		assert.Nil(t, err)
+
+		_, err = attainmentUseCase.ProcessNext()
+		assert.Equal(t, errs.ErrRefreshAttainment, errorCode(err))
+	})
+
+	t.Run("TestIsAttained", func(t *testing.T) {
+		assert.True(t, entity.IsAttained(2, 3, 66.66))
+		assert.False(t, entity.IsAttained(2, 3, 70))
+		assert.False(t, entity.IsAttained(0, 0, 0), "Expected nothing to pass to not be attained")
+	})
+
+	t.Run("TestAttainmentMethods", func(t *testing.T) {
+		methods, err := newAttainmentMethods(config.AttainmentConfig{RubricLevels: []float64{0, 50, 65, 80}, PassingRubricLevel: 3})
+		assert.Nil(t, err)
+
+		items := []attainmentItem{{percentage: 80, passed: true, weight: 1, points: 10}, {percentage: 40, passed: false, weight: 1, points: 30}}
+		cases := []struct {
+			method             entity.AttainmentMethod
+			expectedPercentage float64
+			percentage         float64
+			passed             bool
+		}{
+			{entity.AttainmentMethodThreshold, 50, 50, true},
+			{entity.AttainmentMethodThreshold, 60, 50, false},
+			{entity.AttainmentMethodWeightedAverage, 50, 50, true},
+			{entity.AttainmentMethodWeightedAverage, 51, 50, false},
+			{entity.AttainmentMethodBestOf, 100, 80, true},
+			{entity.AttainmentMethodAllMustPass, 0, 50, false},
+			{entity.AttainmentMethodRubricLevel, 0, 50, false},
+		}
+		for _, c := range cases {
+			percentage, passed := methods[c.method].evaluate(items, c.expectedPercentage)
+			assert.Equal(t, c.percentage, percentage, "Unexpected percentage of %s", c.method)
+			assert.Equal(t, c.passed, passed, "Unexpected pass of %s expecting %.0f", c.method, c.expectedPercentage)
+		}
+
+		_, passed := methods[entity.AttainmentMethodRubricLevel].evaluate([]attainmentItem{{percentage: 80, weight: 1, points: 1}, {percentage: 70, weight: 1, points: 1}}, 0)
+		assert.True(t, passed, "Expected levels 4 and 3 to reach passing level 3")
+
+		percentage, _ := methods[entity.AttainmentMethodWeightedAverage].evaluate([]attainmentItem{{percentage: 80}, {percentage: 40}}, 0)
+		assert.Equal(t, 60.0, percentage, "Expected items without weight to be averaged evenly")
+
+		for _, method := range entity.AttainmentMethods {
+			_, passed := methods[method].evaluate(nil, 0)
+			assert.False(t, passed, "Expected nothing to evaluate to not be attained with %s", method)
+		}
+	})
+
+	t.Run("TestProcessNext_SourceMethod", func(t *testing.T) {
+		source := newAttainmentSource()
+		source.Method = entity.AttainmentMethodAllMustPass
+		repository := &stubAttainmentRepository{sources: []entity.AttainmentSource{source}}
+		attainmentUseCase, err := NewAttainmentUseCase(repository, config.AttainmentConfig{Method: string(entity.AttainmentMethodThreshold)})
+		assert.Nil(t, err)
+
+		_, err = attainmentUseCase.ProcessNext()
+		assert.Nil(t, err)
+
+		for _, clo := range repository.results[0].Clos {
+			if clo.StudentId == "half" && clo.CourseLearningOutcomeId == "clo-2" {
+				assert.False(t, clo.Passed, "Expected the method of the course over the default one")
+			}
+		}
+	})
+
+	t.Run("TestNewAttainmentUseCase_InvalidConfig", func(t *testing.T) {
+		_, err := NewAttainmentUseCase(&stubAttainmentRepository{}, config.AttainmentConfig{Method: "MEDIAN"})
+		assert.NotNil(t, err, "Expected an unknown method to be refused")
+
+		_, err = NewAttainmentUseCase(&stubAttainmentRepository{}, config.AttainmentConfig{RubricLevels: []float64{0, 60, 50}, PassingRubricLevel: 2})
+		assert.NotNil(t, err, "Expected descending rubric levels to be refused")
+
+		_, err = NewAttainmentUseCase(&stubAttainmentRepository{}, config.AttainmentConfig{RubricLevels: []float64{0, 50}, PassingRubricLevel: 3})
+		assert.NotNil(t, err, "Expected a passing level outside the levels to be refused")
+	})
+}
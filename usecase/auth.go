@@ -1,31 +1,60 @@
 package usecase
 
 import (
+	"errors"
+	"strings"
+	"time"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/team-inu/inu-backyard/entity"
 	errs "github.com/team-inu/inu-backyard/entity/error"
+	"github.com/team-inu/inu-backyard/infrastructure/sso"
+	"github.com/team-inu/inu-backyard/internal/config"
 	"github.com/team-inu/inu-backyard/internal/utils"
 )
 
+// time given to the user to finish logging in at the identity provider
+const ssoStateMaxAge = 10 * time.Minute
+
 type authUseCase struct {
 	mailUseCase    entity.MailUseCase
 	sessionUseCase entity.SessionUseCase
 	userUserCase   entity.UserUseCase
+	ssoProvider    entity.SsoProvider
+	config         config.AuthConfig
+
+	credentialVerifiers  []entity.CredentialVerifier
+	twoFactorUseCase     entity.TwoFactorUseCase
+	loginThrottleUseCase entity.LoginThrottleUseCase
+	passwordUseCase      entity.PasswordUseCase
 }
 
+// ssoProvider is nil when single sign-on is disabled, credentialVerifiers are tried in order on sign in
 func NewAuthUseCase(
 	sessionUseCase entity.SessionUseCase,
 	userUseCase entity.UserUseCase,
 	mailUseCase entity.MailUseCase,
+	ssoProvider entity.SsoProvider,
+	config config.AuthConfig,
+	credentialVerifiers []entity.CredentialVerifier,
+	twoFactorUseCase entity.TwoFactorUseCase,
+	loginThrottleUseCase entity.LoginThrottleUseCase,
+	passwordUseCase entity.PasswordUseCase,
 ) entity.AuthUseCase {
 	return &authUseCase{
-		sessionUseCase: sessionUseCase,
-		userUserCase:   userUseCase,
-		mailUseCase:    mailUseCase,
+		sessionUseCase:       sessionUseCase,
+		userUserCase:         userUseCase,
+		mailUseCase:          mailUseCase,
+		ssoProvider:          ssoProvider,
+		config:               config,
+		credentialVerifiers:  credentialVerifiers,
+		twoFactorUseCase:     twoFactorUseCase,
+		loginThrottleUseCase: loginThrottleUseCase,
+		passwordUseCase:      passwordUseCase,
 	}
 }
 
-func (u authUseCase) Authenticate(header string) (*entity.User, error) {
+func (u authUseCase) Authenticate(header string) (*entity.Authentication, error) {
 	session, err := u.sessionUseCase.Validate(header)
 	if err != nil {
 		return nil, errs.New(errs.SameCode, "cannot authenticate user", err)
@@ -35,27 +64,311 @@ func (u authUseCase) Authenticate(header string) (*entity.User, error) {
 	if err != nil {
 		return nil, errs.New(errs.SameCode, "cannot get user to authenticate", err)
 	}
-	return user, nil
+
+	cookie, err := u.sessionUseCase.Renew(*session)
+	if err != nil {
+		return nil, errs.New(errs.SameCode, "cannot renew session to authenticate", err)
+	}
+
+	return &entity.Authentication{
+		User:                   user,
+		Session:                session,
+		Cookie:                 cookie,
+		PasswordChangeRequired: user != nil && u.passwordUseCase.IsChangeRequired(*user),
+	}, nil
 }
 
-func (u authUseCase) SignIn(payload entity.SignInPayload, ipAddress string, userAgent string) (*fiber.Cookie, error) {
-	user, err := u.userUserCase.GetByEmail(payload.Email)
+func (u authUseCase) SignIn(payload entity.SignInPayload, ipAddress string, userAgent string) (*entity.SignInResult, error) {
+	err := u.loginThrottleUseCase.Check(entity.ThrottleActionSignIn, payload.Email, ipAddress)
 	if err != nil {
-		return nil, errs.New(errs.SameCode, "cannot get user data to sign in", err)
-	} else if user == nil {
+		return nil, errs.New(errs.SameCode, "cannot sign in", err)
+	}
+
+	var credential *entity.VerifiedCredential
+	var verifyErr error
+	for _, verifier := range u.credentialVerifiers {
+		verified, err := verifier.Verify(payload.Email, payload.Password)
+		if err != nil {
+			verifyErr = err
+			continue
+		} else if verified != nil {
+			credential = verified
+			break
+		}
+	}
+
+	if credential == nil {
+		if isCredentialRejected(verifyErr) {
+			err := u.loginThrottleUseCase.Fail(entity.ThrottleActionSignIn, payload.Email, ipAddress)
+			if err != nil {
+				return nil, errs.New(errs.SameCode, "cannot count failed sign in", err)
+			}
+		}
+
+		if verifyErr != nil {
+			return nil, errs.New(errs.SameCode, "cannot verify credential to sign in", verifyErr)
+		}
 		return nil, errs.New(errs.ErrUserNotFound, "password or email is incorrect")
 	}
 
-	err = utils.CheckPassword(user.Password, payload.Password)
+	user, err := u.getOrCreateVerifiedUser(*credential)
 	if err != nil {
 		return nil, err
 	}
 
-	cookie, err := u.sessionUseCase.Create(user.Id, ipAddress, userAgent)
+	result, err := u.startSession(*user, ipAddress, userAgent)
 	if err != nil {
-		return nil, errs.New(errs.SameCode, "cannot create session to sign in", err)
+		return nil, err
 	}
-	return cookie, nil
+
+	// failed sign ins are only reset once the second factor is verified so a known password cannot keep renewing the attempts
+	if result.Cookie != nil {
+		err = u.loginThrottleUseCase.Succeed(entity.ThrottleActionSignIn, payload.Email)
+		if err != nil {
+			return nil, errs.New(errs.SameCode, "cannot reset failed sign in", err)
+		}
+	}
+
+	return result, nil
+}
+
+// startSession signs in a user whose first factor is verified, the session waits for the second factor when the user has one or must have one
+func (u authUseCase) startSession(user entity.User, ipAddress string, userAgent string) (*entity.SignInResult, error) {
+	isTwoFactorRequired, err := u.twoFactorUseCase.IsRequired(user)
+	if err != nil {
+		return nil, errs.New(errs.SameCode, "cannot check two-factor policy to sign in", err)
+	}
+
+	if !user.TotpEnabled && !isTwoFactorRequired {
+		cookie, err := u.sessionUseCase.Create(user.Id, ipAddress, userAgent)
+		if err != nil {
+			return nil, errs.New(errs.SameCode, "cannot create session to sign in", err)
+		}
+		return &entity.SignInResult{
+			Cookie:                 cookie,
+			PasswordChangeRequired: u.passwordUseCase.IsChangeRequired(user),
+		}, nil
+	}
+
+	session, challenge, err := u.sessionUseCase.CreatePending(user.Id, ipAddress, userAgent)
+	if err != nil {
+		return nil, errs.New(errs.SameCode, "cannot create pending session to sign in", err)
+	}
+
+	return &entity.SignInResult{
+		TwoFactorChallenge: &entity.TwoFactorChallenge{
+			Challenge:          challenge,
+			EnrollmentRequired: !user.TotpEnabled,
+			ExpiredAt:          session.ExpiredAt,
+		},
+	}, nil
+}
+
+func (u authUseCase) VerifyTwoFactor(payload entity.VerifyTwoFactorPayload, ipAddress string) (*entity.TwoFactorSignInResult, error) {
+	session, user, err := u.getChallengeUser(payload.Challenge)
+	if err != nil {
+		return nil, err
+	}
+
+	err = u.loginThrottleUseCase.Check(entity.ThrottleActionSignIn, user.Email, ipAddress)
+	if err != nil {
+		return nil, errs.New(errs.SameCode, "cannot verify two-factor code to sign in", err)
+	}
+
+	var recoveryCodes []string
+	if user.TotpEnabled {
+		err = u.twoFactorUseCase.Verify(*user, payload.Code)
+	} else {
+		recoveryCodes, err = u.twoFactorUseCase.ConfirmEnrollment(*user, payload.Code)
+	}
+
+	if err != nil {
+		failErr := u.sessionUseCase.FailPending(*session)
+		if failErr != nil {
+			return nil, errs.New(errs.SameCode, "cannot count failed two-factor attempt", failErr)
+		}
+
+		failErr = u.loginThrottleUseCase.Fail(entity.ThrottleActionSignIn, user.Email, ipAddress)
+		if failErr != nil {
+			return nil, errs.New(errs.SameCode, "cannot count failed sign in", failErr)
+		}
+		return nil, errs.New(errs.SameCode, "cannot verify two-factor code to sign in", err)
+	}
+
+	err = u.loginThrottleUseCase.Succeed(entity.ThrottleActionSignIn, user.Email)
+	if err != nil {
+		return nil, errs.New(errs.SameCode, "cannot reset failed sign in", err)
+	}
+
+	cookie, err := u.sessionUseCase.ActivatePending(session.Id)
+	if err != nil {
+		return nil, errs.New(errs.SameCode, "cannot activate session to sign in", err)
+	}
+
+	return &entity.TwoFactorSignInResult{
+		Cookie:                 cookie,
+		RecoveryCodes:          recoveryCodes,
+		PasswordChangeRequired: u.passwordUseCase.IsChangeRequired(*user),
+	}, nil
+}
+
+func (u authUseCase) BeginChallengeEnrollment(challenge string) (*entity.TotpEnrollment, error) {
+	_, user, err := u.getChallengeUser(challenge)
+	if err != nil {
+		return nil, err
+	}
+
+	enrollment, err := u.twoFactorUseCase.BeginEnrollment(*user)
+	if err != nil {
+		return nil, errs.New(errs.SameCode, "cannot begin two-factor enrollment", err)
+	}
+
+	return enrollment, nil
+}
+
+func (u authUseCase) getChallengeUser(challenge string) (*entity.Session, *entity.User, error) {
+	session, err := u.sessionUseCase.GetPending(challenge)
+	if err != nil {
+		return nil, nil, errs.New(errs.SameCode, "cannot get two-factor challenge", err)
+	}
+
+	user, err := u.userUserCase.GetById(session.UserId)
+	if err != nil {
+		return nil, nil, errs.New(errs.SameCode, "cannot get user of two-factor challenge", err)
+	} else if user == nil {
+		return nil, nil, errs.New(errs.ErrUserNotFound, "user of two-factor challenge not found")
+	}
+
+	return session, user, nil
+}
+
+// getOrCreateVerifiedUser creates the user on first sign in when allowed, and keeps the roles in sync with the directory groups
+func (u authUseCase) getOrCreateVerifiedUser(credential entity.VerifiedCredential) (*entity.User, error) {
+	roles := make([]string, 0, len(credential.Roles))
+	for _, role := range credential.Roles {
+		if (entity.User{Role: role}).IsRoles(entity.Roles) {
+			roles = append(roles, string(role))
+		}
+	}
+	role := entity.UserRole(strings.Join(roles, ","))
+
+	user, err := u.userUserCase.GetByEmail(credential.Email)
+	if err != nil {
+		return nil, errs.New(errs.SameCode, "cannot get user data to sign in", err)
+	}
+
+	if user == nil {
+		if !credential.CreateUser {
+			return nil, errs.New(errs.ErrUserNotFound, "password or email is incorrect")
+		}
+
+		if role == "" {
+			role = entity.UserRoleLecturer
+		}
+
+		err = u.userUserCase.Create(entity.CreateUserPayload{
+			Email:       credential.Email,
+			FirstNameEN: credential.FirstNameEN,
+			LastNameEN:  credential.LastNameEN,
+			Role:        role,
+		})
+		if err != nil {
+			return nil, errs.New(errs.SameCode, "cannot create user %s on first sign in", credential.Email, err)
+		}
+
+		user, err = u.userUserCase.GetByEmail(credential.Email)
+		if err != nil {
+			return nil, errs.New(errs.SameCode, "cannot get created user to sign in", err)
+		} else if user == nil {
+			return nil, errs.New(errs.ErrUserNotFound, "cannot find created user %s", credential.Email)
+		}
+
+		return user, nil
+	}
+
+	if role != "" && role != user.Role {
+		err = u.userUserCase.Update(user.Id, &entity.User{Role: role})
+		if err != nil {
+			return nil, errs.New(errs.SameCode, "cannot update roles of user %s", user.Id, err)
+		}
+		user.Role = role
+	}
+
+	return user, nil
+}
+
+func (u authUseCase) BeginSsoSignIn() (string, *fiber.Cookie, error) {
+	if u.ssoProvider == nil {
+		return "", nil, errs.New(errs.ErrSsoDisabled, "single sign-on is disabled")
+	}
+
+	// state, nonce and code verifier
+	values := make([]string, 3)
+	for i := range values {
+		value, err := sso.GenerateRandomString()
+		if err != nil {
+			return "", nil, errs.New(0, "cannot generate single sign-on state", err)
+		}
+		values[i] = value
+	}
+	state, nonce, codeVerifier := values[0], values[1], values[2]
+
+	authUrl, err := u.ssoProvider.AuthCodeUrl(state, nonce, sso.CodeChallenge(codeVerifier))
+	if err != nil {
+		return "", nil, errs.New(errs.ErrSsoProvider, "cannot build identity provider login address", err)
+	}
+
+	// the cookie is signed so the callback can trust the state it carries
+	cookie := &fiber.Cookie{
+		Name:     u.config.Session.CookieName + entity.SsoStateCookieSuffix,
+		SameSite: "Lax",
+		Path:     "/",
+		Value:    u.sessionUseCase.Sign(strings.Join(values, "~")),
+		HTTPOnly: true,
+		Secure:   false,
+		Expires:  time.Now().Add(ssoStateMaxAge),
+	}
+
+	return authUrl, cookie, nil
+}
+
+func (u authUseCase) SsoSignIn(payload entity.SsoCallbackPayload, stateCookie string, ipAddress string, userAgent string) (*entity.SignInResult, error) {
+	if u.ssoProvider == nil {
+		return nil, errs.New(errs.ErrSsoDisabled, "single sign-on is disabled")
+	} else if !strings.Contains(stateCookie, ".") {
+		return nil, errs.New(errs.ErrSsoSignIn, "single sign-on state is missing")
+	}
+
+	unsignedState, err := u.sessionUseCase.Unsign(stateCookie)
+	if err != nil {
+		return nil, errs.New(errs.ErrSsoSignIn, "invalid single sign-on state", err)
+	}
+
+	values := strings.Split(unsignedState, "~")
+	if len(values) != 3 || values[0] != payload.State {
+		return nil, errs.New(errs.ErrSsoSignIn, "single sign-on state mismatch")
+	}
+	nonce, codeVerifier := values[1], values[2]
+
+	identity, err := u.ssoProvider.Exchange(payload.Code, codeVerifier, nonce)
+	if err != nil {
+		return nil, errs.New(errs.ErrSsoSignIn, "cannot verify identity from identity provider", err)
+	}
+
+	user, err := u.userUserCase.GetByEmail(identity.Email)
+	if err != nil {
+		return nil, errs.New(errs.SameCode, "cannot get user data to sign in", err)
+	} else if user == nil {
+		return nil, errs.New(errs.ErrSsoSignIn, "no user account for email %s", identity.Email)
+	}
+
+	// the multi-factor policy of the identity provider is not known here, so roles requiring two-factor are still challenged
+	result, err := u.startSession(*user, ipAddress, userAgent)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
 }
 
 func (u authUseCase) SignOut(header string) (*fiber.Cookie, error) {
@@ -84,19 +397,26 @@ func (u authUseCase) ChangePassword(userId string, oldPassword string, newPasswo
 		return err
 	}
 
-	newHashedPassword, err := utils.HashPassword(newPassword)
+	err = u.passwordUseCase.Change(*user, newPassword)
 	if err != nil {
-		return err
+		return errs.New(errs.SameCode, "cannot change password of user id %s", userId, err)
 	}
 
-	u.userUserCase.Update(userId, &entity.User{
-		Password: newHashedPassword,
-	})
-
 	return nil
 }
 
-func (u authUseCase) ForgotPassword(email string) error {
+// ForgotPassword counts every request as a failure, so the throttle limits how often codes can be sent
+func (u authUseCase) ForgotPassword(email string, ipAddress string) error {
+	err := u.loginThrottleUseCase.Check(entity.ThrottleActionForgotPassword, email, ipAddress)
+	if err != nil {
+		return errs.New(errs.SameCode, "cannot request password reset", err)
+	}
+
+	err = u.loginThrottleUseCase.Fail(entity.ThrottleActionForgotPassword, email, ipAddress)
+	if err != nil {
+		return errs.New(errs.SameCode, "cannot count password reset request", err)
+	}
+
 	user, err := u.userUserCase.GetByEmail(email)
 	if err != nil {
 		return errs.New(errs.SameCode, "cannot get user data to sign in", err)
@@ -112,47 +432,67 @@ func (u authUseCase) ForgotPassword(email string) error {
 	return nil
 }
 
-func (u authUseCase) ResetPassword(email string, token string, newPassword string) error {
+func (u authUseCase) ResetPassword(email string, token string, newPassword string, ipAddress string) error {
+	err := u.loginThrottleUseCase.Check(entity.ThrottleActionResetPassword, email, ipAddress)
+	if err != nil {
+		return errs.New(errs.SameCode, "cannot reset password", err)
+	}
+
 	user, err := u.userUserCase.GetByEmail(email)
 	if err != nil {
 		return errs.New(errs.SameCode, "cannot get user id by token", err)
+	} else if user == nil {
+		return errs.New(errs.ErrResetTokenInvalid, "reset password token not found")
 	}
 
-	err = u.mailUseCase.ValidateResetPasswordToken(email, token)
+	// a password refused by the policy should not use up the token
+	err = u.passwordUseCase.Validate(newPassword, user)
 	if err != nil {
-		return errs.New(errs.SameCode, "cannot validate reset password token", err)
+		return errs.New(errs.SameCode, "cannot validate new password", err)
 	}
 
-	newHashedPassword, err := utils.HashPassword(newPassword)
+	// the token is used up before the password changes so a replayed request cannot pass
+	err = u.mailUseCase.ConsumeResetPasswordToken(email, token)
 	if err != nil {
-		return err
+		failErr := u.loginThrottleUseCase.Fail(entity.ThrottleActionResetPassword, email, ipAddress)
+		if failErr != nil {
+			return errs.New(errs.SameCode, "cannot count failed password reset", failErr)
+		}
+		return errs.New(errs.SameCode, "cannot validate reset password token", err)
 	}
 
-	err = u.userUserCase.Update(user.Id, &entity.User{
-		Password: newHashedPassword,
-	})
+	err = u.passwordUseCase.Change(*user, newPassword)
 	if err != nil {
 		return errs.New(errs.SameCode, "cannot update user password", err)
 	}
 
-	err = u.mailUseCase.DeleteToken(email)
+	// owning the mailbox is enough to lift a sign in lockout
+	err = u.loginThrottleUseCase.Unlock(email)
 	if err != nil {
-		return errs.New(errs.SameCode, "cannot delete token", err)
+		return errs.New(errs.SameCode, "cannot unlock user after password reset", err)
 	}
 
 	return nil
 }
 
-func (u authUseCase) GetSession(email string) (string, error) {
-	_, err := u.userUserCase.GetByEmail(email)
+func (u authUseCase) UnlockUser(userId string) error {
+	user, err := u.userUserCase.GetById(userId)
 	if err != nil {
-		return "", errs.New(errs.SameCode, "cannot get user data to sign in", err)
+		return errs.New(errs.SameCode, "cannot get user id %s to unlock", userId, err)
+	} else if user == nil {
+		return errs.New(errs.ErrUserNotFound, "user id %s not found to unlock", userId)
 	}
 
-	session, err := u.mailUseCase.GetToken(email)
+	err = u.loginThrottleUseCase.Unlock(user.Email)
 	if err != nil {
-		return "", errs.New(errs.SameCode, "cannot get session data", err)
+		return errs.New(errs.SameCode, "cannot unlock user id %s", userId, err)
 	}
 
-	return session, nil
+	return nil
+}
+
+// isCredentialRejected tells a wrong email or password apart from a verifier that failed to answer
+func isCredentialRejected(err error) bool {
+	var domainErr *errs.DomainError
+	return err == nil || (errors.As(err, &domainErr) && domainErr.Code == errs.ErrUserPassword)
 }
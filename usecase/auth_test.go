@@ -0,0 +1,636 @@
+package usecase
+
+import (
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/team-inu/inu-backyard/entity"
+	errs "github.com/team-inu/inu-backyard/entity/error"
+	"github.com/team-inu/inu-backyard/infrastructure/ldap"
+	"github.com/team-inu/inu-backyard/infrastructure/ldap/ldaptest"
+	"github.com/team-inu/inu-backyard/infrastructure/sso"
+	"github.com/team-inu/inu-backyard/infrastructure/sso/ssotest"
+	"github.com/team-inu/inu-backyard/internal/config"
+	"github.com/team-inu/inu-backyard/internal/utils"
+	"github.com/team-inu/inu-backyard/internal/utils/totp"
+)
+
+// stubs embed the interface so only the methods used by the sign in are implemented
+
+type stubSessionRepository struct {
+	entity.SessionRepository
+	sessions []entity.Session
+}
+
+func (r *stubSessionRepository) Create(session *entity.Session) error {
+	r.sessions = append(r.sessions, *session)
+	return nil
+}
+
+func (r *stubSessionRepository) DeleteDuplicates(userId string, ipAddress string, userAgent string) error {
+	return nil
+}
+
+func (r *stubSessionRepository) Get(id string) (*entity.Session, error) {
+	for _, session := range r.sessions {
+		if session.Id == id {
+			return &session, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *stubSessionRepository) Delete(id string) error {
+	for i := range r.sessions {
+		if r.sessions[i].Id == id {
+			r.sessions = append(r.sessions[:i], r.sessions[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func (r *stubSessionRepository) IncreaseTwoFactorAttempts(id string, maxAttempts int) (bool, error) {
+	for i := range r.sessions {
+		if r.sessions[i].Id == id && r.sessions[i].TwoFactorAttempts < maxAttempts {
+			r.sessions[i].TwoFactorAttempts++
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *stubSessionRepository) GetActiveByUserId(userId string) ([]entity.Session, error) {
+	sessions := []entity.Session{}
+	for _, session := range r.sessions {
+		if session.UserId == userId && !session.TwoFactorPending && session.ExpiredAt.After(time.Now()) {
+			sessions = append(sessions, session)
+		}
+	}
+	return sessions, nil
+}
+
+func (r *stubSessionRepository) UpdateExpiredAt(id string, expiredAt time.Time) error {
+	for i := range r.sessions {
+		if r.sessions[i].Id == id {
+			r.sessions[i].ExpiredAt = expiredAt
+		}
+	}
+	return nil
+}
+
+func (r *stubSessionRepository) DeleteByUserIdExcept(userId string, id string) error {
+	sessions := []entity.Session{}
+	for _, session := range r.sessions {
+		if session.UserId != userId || session.Id == id {
+			sessions = append(sessions, session)
+		}
+	}
+	r.sessions = sessions
+	return nil
+}
+
+func (r *stubSessionRepository) Activate(id string, newId string, expiredAt time.Time) (bool, error) {
+	for i := range r.sessions {
+		if r.sessions[i].Id == id && r.sessions[i].TwoFactorPending {
+			r.sessions[i].Id = newId
+			r.sessions[i].TwoFactorPending = false
+			r.sessions[i].ExpiredAt = expiredAt
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+type stubUserUseCase struct {
+	entity.UserUseCase
+	users []entity.User
+}
+
+func (u *stubUserUseCase) GetByEmail(email string) (*entity.User, error) {
+	for _, user := range u.users {
+		if user.Email == email {
+			return &user, nil
+		}
+	}
+	return nil, nil
+}
+
+func (u *stubUserUseCase) GetById(id string) (*entity.User, error) {
+	for _, user := range u.users {
+		if user.Id == id {
+			return &user, nil
+		}
+	}
+	return nil, nil
+}
+
+func (u *stubUserUseCase) Create(payload entity.CreateUserPayload) error {
+	u.users = append(u.users, entity.User{
+		Id:          "created-" + payload.Email,
+		Email:       payload.Email,
+		FirstNameEN: payload.FirstNameEN,
+		LastNameEN:  payload.LastNameEN,
+		Role:        payload.Role,
+	})
+	return nil
+}
+
+func (u *stubUserUseCase) Update(id string, user *entity.User) error {
+	for i := range u.users {
+		if u.users[i].Id == id && user.Role != "" {
+			u.users[i].Role = user.Role
+		}
+	}
+	return nil
+}
+
+// stubTwoFactorRepository keeps the totp columns on the users of stubUserUseCase
+type stubTwoFactorRepository struct {
+	entity.TwoFactorRepository
+	userUseCase   *stubUserUseCase
+	policies      []entity.TwoFactorPolicy
+	recoveryCodes []entity.RecoveryCode
+}
+
+func (r *stubTwoFactorRepository) UpdateTotp(userId string, secret string, enabled bool) error {
+	for i := range r.userUseCase.users {
+		if r.userUseCase.users[i].Id == userId {
+			r.userUseCase.users[i].TotpSecret = secret
+			r.userUseCase.users[i].TotpEnabled = enabled
+		}
+	}
+	return nil
+}
+
+func (r *stubTwoFactorRepository) UpdateTotpLastUsedStep(userId string, step int64) (bool, error) {
+	for i := range r.userUseCase.users {
+		if r.userUseCase.users[i].Id == userId && r.userUseCase.users[i].TotpLastUsedStep < step {
+			r.userUseCase.users[i].TotpLastUsedStep = step
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *stubTwoFactorRepository) ReplaceRecoveryCodes(userId string, codes []entity.RecoveryCode) error {
+	r.recoveryCodes = codes
+	return nil
+}
+
+func (r *stubTwoFactorRepository) UseRecoveryCode(userId string, codeHash string) (bool, error) {
+	for i := range r.recoveryCodes {
+		if r.recoveryCodes[i].UserId == userId && r.recoveryCodes[i].CodeHash == codeHash && r.recoveryCodes[i].UsedAt == nil {
+			usedAt := time.Now()
+			r.recoveryCodes[i].UsedAt = &usedAt
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *stubTwoFactorRepository) GetPolicies() ([]entity.TwoFactorPolicy, error) {
+	return r.policies, nil
+}
+
+// ssoCallback opens the provider login page and returns the callback payload it redirects with
+func ssoCallback(t *testing.T, authUrl string) entity.SsoCallbackPayload {
+	client := &http.Client{
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	resp, err := client.Get(authUrl)
+	if err != nil {
+		t.Fatalf("Failed to open login page: %v", err)
+	}
+	defer resp.Body.Close()
+
+	location, err := url.Parse(resp.Header.Get("Location"))
+	if err != nil {
+		t.Fatalf("Failed to parse callback address: %v", err)
+	}
+
+	return entity.SsoCallbackPayload{
+		Code:  location.Query().Get("code"),
+		State: location.Query().Get("state"),
+	}
+}
+
+func TestSsoSignIn(t *testing.T) {
+	idp, err := ssotest.NewIdentityProvider("inu-backyard", "secret")
+	if err != nil {
+		t.Fatalf("Failed to start identity provider: %v", err)
+	}
+	defer idp.Close()
+
+	authConfig := config.AuthConfig{
+		Session: config.SessionConfig{
+			MaxAge:     3600,
+			Secret:     "secret",
+			Prefix:     "$",
+			CookieName: "inu_backyard",
+		},
+		Oidc: config.OidcConfig{
+			Enabled:      true,
+			Issuer:       idp.Issuer(),
+			ClientId:     "inu-backyard",
+			ClientSecret: "secret",
+			RedirectUrl:  "http://localhost:3001/auth/sso/callback",
+		},
+	}
+
+	sessionRepository := &stubSessionRepository{}
+	sessionUseCase := NewSessionUseCase(sessionRepository, authConfig)
+	userUseCase := &stubUserUseCase{users: []entity.User{
+		{Id: "user-1", Email: "lecturer@example.com", Role: entity.UserRoleLecturer},
+		{Id: "head-1", Email: "head@example.com", Role: entity.UserRoleHeadOfCurriculum},
+	}}
+	twoFactorRepository := &stubTwoFactorRepository{
+		userUseCase: userUseCase,
+		policies:    []entity.TwoFactorPolicy{{Role: entity.UserRoleHeadOfCurriculum}},
+	}
+	twoFactorUseCase := NewTwoFactorUseCase(twoFactorRepository, userUseCase, authConfig.TwoFactor)
+	authUseCase := NewAuthUseCase(sessionUseCase, userUseCase, nil, sso.NewOidcProvider(authConfig.Oidc), authConfig, nil, twoFactorUseCase, NewLoginThrottleUseCase(&stubLoginThrottleRepository{}, userUseCase, nil, authConfig.Throttle), newTestPasswordUseCase(t, userUseCase, authConfig.Password))
+
+	t.Run("TestSignInExistingUser", func(t *testing.T) {
+		idp.Email = "lecturer@example.com"
+
+		authUrl, stateCookie, err := authUseCase.BeginSsoSignIn()
+		assert.Nil(t, err, "Expected no error while beginning sign in, got %v", err)
+		assert.Equal(t, "inu_backyard"+entity.SsoStateCookieSuffix, stateCookie.Name, "Expected state cookie name")
+
+		result, err := authUseCase.SsoSignIn(ssoCallback(t, authUrl), stateCookie.Value, "127.0.0.1", "test")
+		assert.Nil(t, err, "Expected no error while signing in, got %v", err)
+		assert.Equal(t, "inu_backyard", result.Cookie.Name, "Expected session cookie")
+		assert.Len(t, sessionRepository.sessions, 1, "Expected a session to be created")
+		assert.Equal(t, "user-1", sessionRepository.sessions[0].UserId, "Expected session to belong to the user")
+	})
+
+	t.Run("TestSignInTwoFactorRequired", func(t *testing.T) {
+		idp.Email = "head@example.com"
+
+		authUrl, stateCookie, _ := authUseCase.BeginSsoSignIn()
+		result, err := authUseCase.SsoSignIn(ssoCallback(t, authUrl), stateCookie.Value, "127.0.0.1", "test")
+		assert.Nil(t, err, "Expected no error while signing in, got %v", err)
+		assert.Nil(t, result.Cookie, "Expected no session cookie before the second factor")
+		assert.True(t, result.TwoFactorChallenge.EnrollmentRequired, "Expected a role requiring two-factor to be challenged")
+	})
+
+	t.Run("TestSignInUnknownUser", func(t *testing.T) {
+		idp.Email = "stranger@example.com"
+
+		authUrl, stateCookie, _ := authUseCase.BeginSsoSignIn()
+		result, err := authUseCase.SsoSignIn(ssoCallback(t, authUrl), stateCookie.Value, "127.0.0.1", "test")
+		assert.NotNil(t, err, "Expected error when no user has the email")
+		assert.Nil(t, result)
+	})
+
+	t.Run("TestSignInStateMismatch", func(t *testing.T) {
+		idp.Email = "lecturer@example.com"
+
+		authUrl, _, _ := authUseCase.BeginSsoSignIn()
+		_, otherStateCookie, _ := authUseCase.BeginSsoSignIn()
+
+		_, err := authUseCase.SsoSignIn(ssoCallback(t, authUrl), otherStateCookie.Value, "127.0.0.1", "test")
+		assert.NotNil(t, err, "Expected error when state does not match the cookie")
+	})
+
+	t.Run("TestSignInMissingState", func(t *testing.T) {
+		authUrl, _, _ := authUseCase.BeginSsoSignIn()
+
+		_, err := authUseCase.SsoSignIn(ssoCallback(t, authUrl), "", "127.0.0.1", "test")
+		assert.NotNil(t, err, "Expected error when state cookie is missing")
+	})
+
+	t.Run("TestSignInDisabled", func(t *testing.T) {
+		disabledAuthUseCase := NewAuthUseCase(sessionUseCase, userUseCase, nil, nil, authConfig, nil, twoFactorUseCase, NewLoginThrottleUseCase(&stubLoginThrottleRepository{}, userUseCase, nil, authConfig.Throttle), newTestPasswordUseCase(t, userUseCase, authConfig.Password))
+
+		_, _, err := disabledAuthUseCase.BeginSsoSignIn()
+		assert.NotNil(t, err, "Expected error when single sign-on is disabled")
+	})
+}
+
+func TestSignIn(t *testing.T) {
+	server, err := ldaptest.NewServer(
+		ldaptest.Entry{
+			Dn:       "cn=somchai,ou=people,dc=example,dc=com",
+			Password: "directory-secret",
+			Attributes: map[string][]string{
+				"mail":      {"somchai@example.com"},
+				"givenName": {"Somchai"},
+				"sn":        {"Jaidee"},
+				"memberOf":  {"cn=curriculum-heads,ou=groups,dc=example,dc=com"},
+			},
+		},
+		ldaptest.Entry{
+			Dn:       "cn=malee,ou=people,dc=example,dc=com",
+			Password: "directory-secret",
+			Attributes: map[string][]string{
+				"mail":     {"malee@example.com"},
+				"memberOf": {"cn=curriculum-heads,ou=groups,dc=example,dc=com"},
+			},
+		},
+	)
+	if err != nil {
+		t.Fatalf("Failed to start ldap server: %v", err)
+	}
+	defer server.Close()
+	server.RequireBind = false
+
+	caCertPath := filepath.Join(t.TempDir(), "ca.pem")
+	err = os.WriteFile(caCertPath, server.CaCertPem(), 0600)
+	if err != nil {
+		t.Fatalf("Failed to write ldap ca certificate: %v", err)
+	}
+
+	hashedPassword, _ := utils.HashPassword("local-secret")
+
+	authConfig := config.AuthConfig{
+		Session: config.SessionConfig{MaxAge: 3600, Secret: "secret", Prefix: "$", CookieName: "inu_backyard"},
+		Ldap: config.LdapConfig{
+			Enabled:            true,
+			Url:                server.Url(),
+			StartTls:           true,
+			CaCertPath:         caCertPath,
+			BaseDn:             "dc=example,dc=com",
+			UserFilter:         "(mail=%s)",
+			FirstNameAttribute: "givenName",
+			LastNameAttribute:  "sn",
+			GroupAttribute:     "memberOf",
+			GroupRoles: []config.LdapGroupRole{
+				{Group: "cn=curriculum-heads,ou=groups,dc=example,dc=com", Role: string(entity.UserRoleHeadOfCurriculum)},
+			},
+			CreateUsers: true,
+		},
+	}
+
+	sessionRepository := &stubSessionRepository{}
+	sessionUseCase := NewSessionUseCase(sessionRepository, authConfig)
+	userUseCase := &stubUserUseCase{users: []entity.User{
+		{Id: "local-1", Email: "local@example.com", Password: hashedPassword, Role: entity.UserRoleLecturer},
+		{Id: "malee-1", Email: "malee@example.com", Role: entity.UserRoleLecturer},
+	}}
+	ldapVerifier, err := ldap.NewVerifier(authConfig.Ldap)
+	if err != nil {
+		t.Fatalf("Failed to create ldap verifier: %v", err)
+	}
+	credentialVerifiers := []entity.CredentialVerifier{
+		ldapVerifier,
+		NewPasswordCredentialVerifier(userUseCase),
+	}
+	twoFactorUseCase := NewTwoFactorUseCase(&stubTwoFactorRepository{userUseCase: userUseCase}, userUseCase, authConfig.TwoFactor)
+	authUseCase := NewAuthUseCase(sessionUseCase, userUseCase, nil, nil, authConfig, credentialVerifiers, twoFactorUseCase, NewLoginThrottleUseCase(&stubLoginThrottleRepository{}, userUseCase, nil, authConfig.Throttle), newTestPasswordUseCase(t, userUseCase, authConfig.Password))
+
+	t.Run("TestSignInLocalPassword", func(t *testing.T) {
+		result, err := authUseCase.SignIn(entity.SignInPayload{Email: "local@example.com", Password: "local-secret"}, "127.0.0.1", "test")
+		assert.Nil(t, err, "Expected no error while signing in with local password, got %v", err)
+		assert.NotNil(t, result.Cookie, "Expected session cookie")
+	})
+
+	t.Run("TestSignInWrongPassword", func(t *testing.T) {
+		result, err := authUseCase.SignIn(entity.SignInPayload{Email: "local@example.com", Password: "wrong"}, "127.0.0.1", "test")
+		assert.NotNil(t, err, "Expected error with wrong password")
+		assert.Nil(t, result)
+	})
+
+	t.Run("TestSignInCreatesDirectoryUser", func(t *testing.T) {
+		result, err := authUseCase.SignIn(entity.SignInPayload{Email: "somchai@example.com", Password: "directory-secret"}, "127.0.0.1", "test")
+		assert.Nil(t, err, "Expected no error while signing in with directory password, got %v", err)
+		assert.NotNil(t, result.Cookie, "Expected session cookie")
+
+		user, _ := userUseCase.GetByEmail("somchai@example.com")
+		assert.NotNil(t, user, "Expected user to be created on first sign in")
+		assert.Equal(t, "Somchai", user.FirstNameEN, "Expected first name from directory")
+		assert.Equal(t, entity.UserRoleHeadOfCurriculum, user.Role, "Expected role mapped from group")
+	})
+
+	t.Run("TestSignInSyncsDirectoryRoles", func(t *testing.T) {
+		_, err := authUseCase.SignIn(entity.SignInPayload{Email: "malee@example.com", Password: "directory-secret"}, "127.0.0.1", "test")
+		assert.Nil(t, err, "Expected no error while signing in, got %v", err)
+
+		user, _ := userUseCase.GetByEmail("malee@example.com")
+		assert.Equal(t, entity.UserRoleHeadOfCurriculum, user.Role, "Expected role to follow directory group")
+	})
+
+	t.Run("TestSignInWithoutUserCreation", func(t *testing.T) {
+		noCreateConfig := authConfig.Ldap
+		noCreateConfig.CreateUsers = false
+		server.AddEntry(ldaptest.Entry{
+			Dn:         "cn=newcomer,ou=people,dc=example,dc=com",
+			Password:   "directory-secret",
+			Attributes: map[string][]string{"mail": {"newcomer@example.com"}},
+		})
+
+		noCreateVerifier, _ := ldap.NewVerifier(noCreateConfig)
+		noCreateAuthUseCase := NewAuthUseCase(sessionUseCase, userUseCase, nil, nil, authConfig, []entity.CredentialVerifier{noCreateVerifier}, twoFactorUseCase, NewLoginThrottleUseCase(&stubLoginThrottleRepository{}, userUseCase, nil, authConfig.Throttle), newTestPasswordUseCase(t, userUseCase, authConfig.Password))
+		_, err := noCreateAuthUseCase.SignIn(entity.SignInPayload{Email: "newcomer@example.com", Password: "directory-secret"}, "127.0.0.1", "test")
+		assert.NotNil(t, err, "Expected error when user creation is disabled")
+
+		user, _ := userUseCase.GetByEmail("newcomer@example.com")
+		assert.Nil(t, user, "Expected no user to be created")
+	})
+}
+
+func TestTwoFactorSignIn(t *testing.T) {
+	hashedPassword, _ := utils.HashPassword("local-secret")
+
+	authConfig := config.AuthConfig{
+		Session:   config.SessionConfig{MaxAge: 3600, Secret: "secret", Prefix: "$", CookieName: "inu_backyard"},
+		TwoFactor: config.TwoFactorConfig{Issuer: "inu-backyard"},
+		Throttle:  config.LoginThrottleConfig{MaxFailures: 20},
+	}
+
+	sessionRepository := &stubSessionRepository{}
+	sessionUseCase := NewSessionUseCase(sessionRepository, authConfig)
+	userUseCase := &stubUserUseCase{users: []entity.User{
+		{Id: "head-1", Email: "head@example.com", Password: hashedPassword, Role: entity.UserRoleHeadOfCurriculum},
+		{Id: "lecturer-1", Email: "lecturer@example.com", Password: hashedPassword, Role: entity.UserRoleLecturer},
+	}}
+	twoFactorRepository := &stubTwoFactorRepository{
+		userUseCase: userUseCase,
+		policies:    []entity.TwoFactorPolicy{{Role: entity.UserRoleHeadOfCurriculum}},
+	}
+	twoFactorUseCase := NewTwoFactorUseCase(twoFactorRepository, userUseCase, authConfig.TwoFactor)
+	authUseCase := NewAuthUseCase(sessionUseCase, userUseCase, nil, nil, authConfig, []entity.CredentialVerifier{NewPasswordCredentialVerifier(userUseCase)}, twoFactorUseCase, NewLoginThrottleUseCase(&stubLoginThrottleRepository{}, userUseCase, &stubMailUseCase{}, authConfig.Throttle), newTestPasswordUseCase(t, userUseCase, authConfig.Password))
+
+	signIn := func(email string) *entity.SignInResult {
+		result, err := authUseCase.SignIn(entity.SignInPayload{Email: email, Password: "local-secret"}, "127.0.0.1", "test")
+		if err != nil {
+			t.Fatalf("Failed to sign in: %v", err)
+		}
+		return result
+	}
+
+	var recoveryCodes []string
+
+	t.Run("TestSignInWithoutPolicy", func(t *testing.T) {
+		result := signIn("lecturer@example.com")
+		assert.NotNil(t, result.Cookie, "Expected session cookie without two-factor")
+		assert.Nil(t, result.TwoFactorChallenge)
+	})
+
+	t.Run("TestSignInRequiresEnrollment", func(t *testing.T) {
+		result := signIn("head@example.com")
+		assert.Nil(t, result.Cookie, "Expected no session cookie before two-factor")
+		assert.True(t, result.TwoFactorChallenge.EnrollmentRequired, "Expected enrollment to be required by policy")
+
+		_, err := sessionUseCase.Validate(result.TwoFactorChallenge.Challenge)
+		assert.NotNil(t, err, "Expected pending session not to be usable as a session")
+
+		enrollment, err := authUseCase.BeginChallengeEnrollment(result.TwoFactorChallenge.Challenge)
+		assert.Nil(t, err, "Expected no error while beginning enrollment, got %v", err)
+
+		code, _ := totp.GenerateCode(enrollment.Secret, totp.Step(time.Now()))
+		signInResult, err := authUseCase.VerifyTwoFactor(entity.VerifyTwoFactorPayload{Challenge: result.TwoFactorChallenge.Challenge, Code: code}, "127.0.0.1")
+		assert.Nil(t, err, "Expected no error while confirming enrollment, got %v", err)
+		assert.NotNil(t, signInResult.Cookie, "Expected session cookie after enrollment")
+		assert.Len(t, signInResult.RecoveryCodes, recoveryCodeCount, "Expected recovery codes after enrollment")
+
+		_, err = sessionUseCase.Validate(signInResult.Cookie.Value)
+		assert.Nil(t, err, "Expected activated session to be valid, got %v", err)
+
+		_, err = sessionUseCase.Validate(result.TwoFactorChallenge.Challenge)
+		assert.NotNil(t, err, "Expected the challenge not to be usable as the activated session")
+
+		recoveryCodes = signInResult.RecoveryCodes
+	})
+
+	t.Run("TestSignInReplayedCode", func(t *testing.T) {
+		user, _ := userUseCase.GetById("head-1")
+		code, _ := totp.GenerateCode(user.TotpSecret, user.TotpLastUsedStep)
+
+		result := signIn("head@example.com")
+		assert.False(t, result.TwoFactorChallenge.EnrollmentRequired, "Expected enrolled user to be challenged")
+
+		_, err := authUseCase.VerifyTwoFactor(entity.VerifyTwoFactorPayload{Challenge: result.TwoFactorChallenge.Challenge, Code: code}, "127.0.0.1")
+		assert.NotNil(t, err, "Expected used totp code to be refused")
+	})
+
+	t.Run("TestVerifyCodeUsedConcurrently", func(t *testing.T) {
+		user, _ := userUseCase.GetById("head-1")
+		code, _ := totp.GenerateCode(user.TotpSecret, user.TotpLastUsedStep)
+
+		// a request loading the user before another one saved the step
+		staleUser := *user
+		staleUser.TotpLastUsedStep = 0
+
+		err := twoFactorUseCase.Verify(staleUser, code)
+		assert.Equal(t, errs.ErrTwoFactorInvalidCode, errorCode(err), "Expected the code to be refused once its step is saved")
+	})
+
+	t.Run("TestSignInRecoveryCode", func(t *testing.T) {
+		result := signIn("head@example.com")
+
+		signInResult, err := authUseCase.VerifyTwoFactor(entity.VerifyTwoFactorPayload{Challenge: result.TwoFactorChallenge.Challenge, Code: strings.ToUpper(recoveryCodes[0])}, "127.0.0.1")
+		assert.Nil(t, err, "Expected no error with recovery code, got %v", err)
+		assert.NotNil(t, signInResult.Cookie, "Expected session cookie with recovery code")
+
+		result = signIn("head@example.com")
+		_, err = authUseCase.VerifyTwoFactor(entity.VerifyTwoFactorPayload{Challenge: result.TwoFactorChallenge.Challenge, Code: recoveryCodes[0]}, "127.0.0.1")
+		assert.NotNil(t, err, "Expected recovery code to be single use")
+	})
+
+	t.Run("TestSignInTooManyAttempts", func(t *testing.T) {
+		result := signIn("head@example.com")
+
+		for i := 0; i < maxTwoFactorAttempts; i++ {
+			_, err := authUseCase.VerifyTwoFactor(entity.VerifyTwoFactorPayload{Challenge: result.TwoFactorChallenge.Challenge, Code: "000000"}, "127.0.0.1")
+			assert.NotNil(t, err, "Expected wrong code to be refused")
+		}
+
+		user, _ := userUseCase.GetById("head-1")
+		code, _ := totp.GenerateCode(user.TotpSecret, totp.Step(time.Now())+1)
+		_, err := authUseCase.VerifyTwoFactor(entity.VerifyTwoFactorPayload{Challenge: result.TwoFactorChallenge.Challenge, Code: code}, "127.0.0.1")
+		assert.NotNil(t, err, "Expected challenge to be discarded after too many attempts")
+	})
+
+	t.Run("TestSignInTooManyConcurrentAttempts", func(t *testing.T) {
+		result := signIn("head@example.com")
+
+		// concurrent wrong codes all read the session before any of them is counted
+		session, err := sessionUseCase.GetPending(result.TwoFactorChallenge.Challenge)
+		assert.Nil(t, err)
+		for i := 0; i < maxTwoFactorAttempts; i++ {
+			err = sessionUseCase.FailPending(*session)
+			assert.Nil(t, err)
+		}
+
+		_, err = sessionUseCase.GetPending(result.TwoFactorChallenge.Challenge)
+		assert.NotNil(t, err, "Expected challenge to be discarded after too many attempts from a stale session")
+	})
+}
+
+func TestSignInLockout(t *testing.T) {
+	hashedPassword, _ := utils.HashPassword("local-secret")
+
+	authConfig := config.AuthConfig{
+		Session:  config.SessionConfig{MaxAge: 3600, Secret: "secret", Prefix: "$", CookieName: "inu_backyard"},
+		Throttle: config.LoginThrottleConfig{MaxFailures: 2},
+	}
+
+	sessionUseCase := NewSessionUseCase(&stubSessionRepository{}, authConfig)
+	userUseCase := &stubUserUseCase{users: []entity.User{{Id: "local-1", Email: "local@example.com", Password: hashedPassword}}}
+	twoFactorUseCase := NewTwoFactorUseCase(&stubTwoFactorRepository{userUseCase: userUseCase}, userUseCase, authConfig.TwoFactor)
+	loginThrottleUseCase := NewLoginThrottleUseCase(&stubLoginThrottleRepository{}, userUseCase, &stubMailUseCase{}, authConfig.Throttle)
+	authUseCase := NewAuthUseCase(sessionUseCase, userUseCase, nil, nil, authConfig, []entity.CredentialVerifier{NewPasswordCredentialVerifier(userUseCase)}, twoFactorUseCase, loginThrottleUseCase, newTestPasswordUseCase(t, userUseCase, authConfig.Password))
+
+	for i := 0; i < 2; i++ {
+		_, err := authUseCase.SignIn(entity.SignInPayload{Email: "local@example.com", Password: "wrong"}, "127.0.0.1", "test")
+		assert.Equal(t, errs.ErrUserPassword, errorCode(err), "Expected password error, got %v", err)
+	}
+
+	_, err := authUseCase.SignIn(entity.SignInPayload{Email: "local@example.com", Password: "local-secret"}, "127.0.0.1", "test")
+	assert.Equal(t, errs.ErrTooManyAttempts, errorCode(err), "Expected locked sign in even with the right password, got %v", err)
+
+	err = authUseCase.UnlockUser("local-1")
+	assert.Nil(t, err, "Expected no error while unlocking, got %v", err)
+
+	result, err := authUseCase.SignIn(entity.SignInPayload{Email: "local@example.com", Password: "local-secret"}, "127.0.0.1", "test")
+	assert.Nil(t, err, "Expected sign in after unlock, got %v", err)
+	assert.NotNil(t, result.Cookie, "Expected session cookie")
+}
+
+func TestTwoFactorSignInLockout(t *testing.T) {
+	hashedPassword, _ := utils.HashPassword("local-secret")
+
+	authConfig := config.AuthConfig{
+		Session:   config.SessionConfig{MaxAge: 3600, Secret: "secret", Prefix: "$", CookieName: "inu_backyard"},
+		TwoFactor: config.TwoFactorConfig{Issuer: "inu-backyard"},
+		Throttle:  config.LoginThrottleConfig{MaxFailures: 2},
+	}
+
+	sessionUseCase := NewSessionUseCase(&stubSessionRepository{}, authConfig)
+	userUseCase := &stubUserUseCase{users: []entity.User{{Id: "head-1", Email: "head@example.com", Password: hashedPassword, Role: entity.UserRoleHeadOfCurriculum}}}
+	twoFactorRepository := &stubTwoFactorRepository{
+		userUseCase: userUseCase,
+		policies:    []entity.TwoFactorPolicy{{Role: entity.UserRoleHeadOfCurriculum}},
+	}
+	twoFactorUseCase := NewTwoFactorUseCase(twoFactorRepository, userUseCase, authConfig.TwoFactor)
+	loginThrottleUseCase := NewLoginThrottleUseCase(&stubLoginThrottleRepository{}, userUseCase, &stubMailUseCase{}, authConfig.Throttle)
+	authUseCase := NewAuthUseCase(sessionUseCase, userUseCase, nil, nil, authConfig, []entity.CredentialVerifier{NewPasswordCredentialVerifier(userUseCase)}, twoFactorUseCase, loginThrottleUseCase, newTestPasswordUseCase(t, userUseCase, authConfig.Password))
+
+	var challenge string
+	for i := 0; i < 2; i++ {
+		// the right password must not reset the failed second factors
+		result, err := authUseCase.SignIn(entity.SignInPayload{Email: "head@example.com", Password: "local-secret"}, "127.0.0.1", "test")
+		assert.Nil(t, err, "Expected no error with the right password, got %v", err)
+		challenge = result.TwoFactorChallenge.Challenge
+
+		_, err = authUseCase.VerifyTwoFactor(entity.VerifyTwoFactorPayload{Challenge: challenge, Code: "000000"}, "127.0.0.1")
+		assert.NotNil(t, err, "Expected wrong code to be refused")
+	}
+
+	_, err := authUseCase.SignIn(entity.SignInPayload{Email: "head@example.com", Password: "local-secret"}, "127.0.0.1", "test")
+	assert.Equal(t, errs.ErrTooManyAttempts, errorCode(err), "Expected locked sign in after failed second factors, got %v", err)
+
+	_, err = authUseCase.VerifyTwoFactor(entity.VerifyTwoFactorPayload{Challenge: challenge, Code: "000000"}, "127.0.0.1")
+	assert.Equal(t, errs.ErrTooManyAttempts, errorCode(err), "Expected pending challenge to be locked too, got %v", err)
+}
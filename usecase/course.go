@@ -160,6 +160,7 @@ func (u courseUseCase) Create(user entity.User, payload entity.CreateCoursePaylo
 		AcademicYear:                 payload.AcademicYear,
 		GraduateYear:                 payload.GraduateYear,
 		ExpectedPassingCloPercentage: payload.ExpectedPassingCloPercentage,
+		AttainmentMethod:             payload.AttainmentMethod,
 		SemesterId:                   payload.SemesterId,
 		CriteriaGrade:                payload.CriteriaGrade,
 		PortfolioData:                emptyJson,
@@ -212,6 +213,7 @@ func (u courseUseCase) Update(user entity.User, id string, payload entity.Update
 		GraduateYear:                 payload.GraduateYear,
 		CriteriaGrade:                payload.CriteriaGrade,
 		ExpectedPassingCloPercentage: payload.ExpectedPassingCloPercentage,
+		AttainmentMethod:             payload.AttainmentMethod,
 	})
 	if err != nil {
 		return errs.New(errs.ErrUpdateCourse, "cannot update course by id %s", id, err)
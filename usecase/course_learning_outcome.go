@@ -232,7 +232,27 @@ func (u courseLearningOutcomeUseCase) Create(payload entity.CreateCourseLearning
 	return nil
 }
 
-func (u courseLearningOutcomeUseCase) CreateLinkProgramOutcome(id string, programOutcomeIds []string) error {
+// outcomeLinkWeights weighs the outcomes without a weight with entity.DefaultOutcomeLinkWeight, it also returns the weighted outcomes not being linked
+func outcomeLinkWeights(outcomeIds []string, weights map[string]float64) (map[string]float64, []string) {
+	linkWeights := make(map[string]float64, len(outcomeIds))
+	for _, outcomeId := range outcomeIds {
+		linkWeights[outcomeId] = entity.DefaultOutcomeLinkWeight
+		if weight, ok := weights[outcomeId]; ok {
+			linkWeights[outcomeId] = weight
+		}
+	}
+
+	unlinkedIds := []string{}
+	for outcomeId := range weights {
+		if _, ok := linkWeights[outcomeId]; !ok {
+			unlinkedIds = append(unlinkedIds, outcomeId)
+		}
+	}
+
+	return linkWeights, unlinkedIds
+}
+
+func (u courseLearningOutcomeUseCase) CreateLinkProgramOutcome(id string, programOutcomeIds []string, weights map[string]float64) error {
 	existCourseLearningOutcome, err := u.GetById(id)
 	if err != nil {
 		return errs.New(errs.SameCode, "cannot get courseLearningOutcome id %s to link programOutcome", id, err)
@@ -262,7 +282,12 @@ func (u courseLearningOutcomeUseCase) CreateLinkProgramOutcome(id string, progra
 		return errs.New(errs.ErrCreateEnrollment, "there are non exist po %v", slice.Subtraction(programOutcomeIds, useablePOIds))
 	}
 
-	err = u.courseLearningOutcomeRepo.CreateLinkProgramOutcome(id, programOutcomeIds)
+	linkWeights, unlinkedIds := outcomeLinkWeights(programOutcomeIds, weights)
+	if len(unlinkedIds) > 0 {
+		return errs.New(errs.ErrCreateCLO, "there are weights of unlinked po %v", unlinkedIds)
+	}
+
+	err = u.courseLearningOutcomeRepo.CreateLinkProgramOutcome(id, programOutcomeIds, linkWeights)
 	if err != nil {
 		return errs.New(errs.ErrCreateCLO, "cannot link CLO and program outcome", err)
 	}
@@ -270,7 +295,7 @@ func (u courseLearningOutcomeUseCase) CreateLinkProgramOutcome(id string, progra
 	return nil
 }
 
-func (u courseLearningOutcomeUseCase) CreateLinkSubProgramLearningOutcome(id string, subProgramLearningOutcomeIds []string) error {
+func (u courseLearningOutcomeUseCase) CreateLinkSubProgramLearningOutcome(id string, subProgramLearningOutcomeIds []string, weights map[string]float64) error {
 	existCourseLearningOutcome, err := u.GetById(id)
 	if err != nil {
 		return errs.New(errs.SameCode, "cannot get courseLearningOutcome id %s to link subPLO", id, err)
@@ -305,7 +330,12 @@ func (u courseLearningOutcomeUseCase) CreateLinkSubProgramLearningOutcome(id str
 		return errs.New(errs.ErrCreateEnrollment, "there are non exist sub plo %v", slice.Subtraction(subProgramLearningOutcomeIds, useablePLOIds))
 	}
 
-	err = u.courseLearningOutcomeRepo.CreateLinkSubProgramLearningOutcome(id, subProgramLearningOutcomeIds)
+	linkWeights, unlinkedIds := outcomeLinkWeights(subProgramLearningOutcomeIds, weights)
+	if len(unlinkedIds) > 0 {
+		return errs.New(errs.ErrCreateCLO, "there are weights of unlinked sub plo %v", unlinkedIds)
+	}
+
+	err = u.courseLearningOutcomeRepo.CreateLinkSubProgramLearningOutcome(id, subProgramLearningOutcomeIds, linkWeights)
 	if err != nil {
 		return errs.New(errs.ErrCreateCLO, "cannot link CLO and subPLO", err)
 	}
@@ -313,7 +343,7 @@ func (u courseLearningOutcomeUseCase) CreateLinkSubProgramLearningOutcome(id str
 	return nil
 }
 
-func (u courseLearningOutcomeUseCase) CreateLinkSubStudentOutcome(id string, subStudentOutcomeIds []string) error {
+func (u courseLearningOutcomeUseCase) CreateLinkSubStudentOutcome(id string, subStudentOutcomeIds []string, weights map[string]float64) error {
 	existCourseLearningOutcome, err := u.GetById(id)
 	if err != nil {
 		return errs.New(errs.SameCode, "cannot get courseLearningOutcome id %s to link subPLO", id, err)
@@ -345,7 +375,12 @@ func (u courseLearningOutcomeUseCase) CreateLinkSubStudentOutcome(id string, sub
 		return errs.New(errs.ErrCreateEnrollment, "there are non exist sub so %v", slice.Subtraction(subStudentOutcomeIds, useableSOIds))
 	}
 
-	err = u.courseLearningOutcomeRepo.CreateLinkSubStudentOutcome(id, subStudentOutcomeIds)
+	linkWeights, unlinkedIds := outcomeLinkWeights(subStudentOutcomeIds, weights)
+	if len(unlinkedIds) > 0 {
+		return errs.New(errs.ErrCreateCLO, "there are weights of unlinked sub so %v", unlinkedIds)
+	}
+
+	err = u.courseLearningOutcomeRepo.CreateLinkSubStudentOutcome(id, subStudentOutcomeIds, linkWeights)
 	if err != nil {
 		return errs.New(errs.SameCode, "cannot link CLO and sub student outcome", err)
 	}
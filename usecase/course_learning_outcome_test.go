@@ -0,0 +1,19 @@
+package usecase
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/team-inu/inu-backyard/entity"
+)
+
+func TestCourseLearningOutcome(t *testing.T) {
+	t.Run("TestOutcomeLinkWeights", func(t *testing.T) {
+		weights, unlinkedIds := outcomeLinkWeights([]string{"po-1", "po-2"}, map[string]float64{"po-2": 3})
+		assert.Equal(t, map[string]float64{"po-1": entity.DefaultOutcomeLinkWeight, "po-2": 3}, weights, "Expected outcomes without a weight to weigh the default")
+		assert.Empty(t, unlinkedIds)
+
+		_, unlinkedIds = outcomeLinkWeights([]string{"po-1"}, map[string]float64{"po-3": 2})
+		assert.Equal(t, []string{"po-3"}, unlinkedIds, "Expected a weight of an outcome not being linked to be reported")
+	})
+}
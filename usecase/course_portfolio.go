@@ -13,6 +13,7 @@ import (
 	errs "github.com/team-inu/inu-backyard/entity/error"
 	"github.com/team-inu/inu-backyard/utils"
 	"github.com/xuri/excelize/v2"
+	"go.uber.org/zap"
 )
 
 // TODO: refactor (real)
@@ -26,6 +27,10 @@ type coursePortfolioUseCase struct {
 	StudentUseCase               entity.StudentUseCase
 	CourseLearningOutcomeUseCase entity.CourseLearningOutcomeUseCase
 	CourseStreamUseCase          entity.CourseStreamsUseCase
+	ProgrammeUseCase             entity.ProgrammeUseCase
+	FeedbackUseCase              entity.FeedbackUseCase
+	NotificationUseCase          entity.NotificationUseCase
+	Logger                       *zap.Logger
 }
 
 func NewCoursePortfolioUseCase(
@@ -38,6 +43,10 @@ func NewCoursePortfolioUseCase(
 	studentUsecase entity.StudentUseCase,
 	courseLearningOutcomeUseCase entity.CourseLearningOutcomeUseCase,
 	courseStreamUseCase entity.CourseStreamsUseCase,
+	programmeUseCase entity.ProgrammeUseCase,
+	feedbackUseCase entity.FeedbackUseCase,
+	notificationUseCase entity.NotificationUseCase,
+	logger *zap.Logger,
 ) entity.CoursePortfolioUseCase {
 	return &coursePortfolioUseCase{
 		CoursePortfolioRepository:    coursePortfolioRepository,
@@ -49,6 +58,10 @@ func NewCoursePortfolioUseCase(
 		StudentUseCase:               studentUsecase,
 		CourseLearningOutcomeUseCase: courseLearningOutcomeUseCase,
 		CourseStreamUseCase:          courseStreamUseCase,
+		ProgrammeUseCase:             programmeUseCase,
+		FeedbackUseCase:              feedbackUseCase,
+		NotificationUseCase:          notificationUseCase,
+		Logger:                       logger,
 	}
 }
 
@@ -204,6 +217,11 @@ func (u coursePortfolioUseCase) Generate(courseId string) (*entity.CoursePortfol
 		return nil, errs.New(0, "cannot unmarshal data from db")
 	}
 
+	studentFeedback, err := u.FeedbackUseCase.GetSummaryByCourseId(courseId)
+	if err != nil {
+		return nil, errs.New(errs.SameCode, "cannot get student feedback of course id %s", courseId, err)
+	}
+
 	courseDevelopment := entity.CourseDevelopment{
 		// Plans:       portfolioData.Development.Plans,
 		// DoAndChecks: portfolioData.Development.DoAndChecks,
@@ -214,6 +232,7 @@ func (u coursePortfolioUseCase) Generate(courseId string) (*entity.CoursePortfol
 			// Other:              portfolioData.Development.SubjectComments.Other,
 		},
 		// OtherComment: portfolioData.Development.OtherComment,
+		StudentFeedback: studentFeedback,
 	}
 
 	courseSummary := entity.CourseSummary{
@@ -604,10 +623,16 @@ func (u coursePortfolioUseCase) GetStudentOutcomesStatusByCourseId(courseId stri
 		return nil, errs.New(errs.SameCode, "cannot evaluate passing po student by course id %s", courseId, err)
 	}
 
+	soRecords, err := u.CoursePortfolioRepository.EvaluatePassingSoStudents(courseId)
+	if err != nil {
+		return nil, errs.New(errs.SameCode, "cannot evaluate passing so student by course id %s", courseId, err)
+	}
+
 	cloRecords, err := u.CoursePortfolioRepository.EvaluatePassingCloStudents(courseId)
 
 	studentPloMap := make(map[string][]entity.PloData)
 	studentPoMap := make(map[string][]entity.PoData)
+	studentSoMap := make(map[string][]entity.SoData)
 	studentCloMap := make(map[string][]entity.CloData)
 
 	for _, record := range ploRecords {
@@ -629,6 +654,15 @@ func (u coursePortfolioUseCase) GetStudentOutcomesStatusByCourseId(courseId stri
 		})
 	}
 
+	for _, record := range soRecords {
+		studentSoMap[record.StudentId] = append(studentSoMap[record.StudentId], entity.SoData{
+			Id:              record.StudentOutcomeId,
+			Code:            record.Code,
+			DescriptionThai: record.DescriptionThai,
+			Pass:            record.Pass,
+		})
+	}
+
 	for _, record := range cloRecords {
 		studentCloMap[record.StudentId] = append(studentCloMap[record.StudentId], entity.CloData{
 			Pass:                    record.Pass,
@@ -653,6 +687,7 @@ func (u coursePortfolioUseCase) GetStudentOutcomesStatusByCourseId(courseId stri
 
 	for i := range students {
 		students[i].ProgramOutcomes = studentPoMap[students[i].StudentId]
+		students[i].StudentOutcomes = studentSoMap[students[i].StudentId]
 		students[i].CourseLearningOutcomes = studentCloMap[students[i].StudentId]
 	}
 
@@ -729,6 +764,142 @@ func (u coursePortfolioUseCase) GetAllProgramOutcomeCourses() ([]entity.PoCourse
 	return pos, nil
 }
 
+func (u coursePortfolioUseCase) GetAllStudentOutcomeCourses() ([]entity.SoCourses, error) {
+	records, err := u.CoursePortfolioRepository.EvaluateAllSoCourses()
+	if err != nil {
+		return nil, errs.New(errs.SameCode, "cannot evaluate so courses %s", err)
+	}
+
+	sosMap := make(map[string][]entity.CourseData)
+
+	for _, record := range records {
+		if record.CourseId == "" {
+			sosMap[record.StudentOutcomeId] = append(sosMap[record.StudentOutcomeId], entity.CourseData{})
+		} else {
+			sosMap[record.StudentOutcomeId] = append(sosMap[record.StudentOutcomeId], entity.CourseData{
+				Id:                record.CourseId,
+				Code:              record.Code,
+				Name:              record.Name,
+				PassingPercentage: record.PassingPercentage,
+				Year:              record.Year,
+				SemesterSequence:  record.SemesterSequence,
+			})
+		}
+	}
+
+	sos := make([]entity.SoCourses, 0)
+
+	for soId := range sosMap {
+		sos = append(sos, entity.SoCourses{
+			StudentOutcomeId: soId,
+			Courses:          sosMap[soId],
+		})
+	}
+
+	return sos, nil
+}
+
+func (u coursePortfolioUseCase) GetProgrammeStudentOutcomeAttainment(user entity.User, programmeId string, fromSerm, toSerm int) (*entity.ProgrammeSoAttainment, error) {
+	if !user.IsRoles([]entity.UserRole{entity.UserRoleABETManager, entity.UserRoleHeadOfCurriculum}) {
+		return nil, errs.New(errs.ErrAttainmentPermission, "no permission to get student outcome attainment")
+	}
+
+	programme, err := u.ProgrammeUseCase.GetById(programmeId)
+	if err != nil {
+		return nil, errs.New(errs.SameCode, "cannot get programme id %s while getting student outcome attainment", programmeId, err)
+	} else if programme == nil {
+		return nil, errs.New(errs.ErrProgrammeNotFound, "programme id %s not found while getting student outcome attainment", programmeId)
+	}
+
+	sos, err := u.ProgrammeUseCase.GetAllSO(programmeId)
+	if err != nil {
+		return nil, errs.New(errs.SameCode, "cannot get student outcomes of programme id %s", programmeId, err)
+	}
+
+	records, err := u.CoursePortfolioRepository.EvaluateSoCoursesByProgrammeId(programmeId)
+	if err != nil {
+		return nil, errs.New(errs.SameCode, "cannot evaluate so courses of programme id %s", programmeId, err)
+	}
+
+	coursesBySo := make(map[string][]entity.CourseData)
+	for _, record := range records {
+		if record.CourseId == "" || record.Year < fromSerm || record.Year > toSerm {
+			continue
+		}
+
+		coursesBySo[record.StudentOutcomeId] = append(coursesBySo[record.StudentOutcomeId], entity.CourseData{
+			Id:                record.CourseId,
+			Code:              record.Code,
+			Name:              record.Name,
+			PassingPercentage: record.PassingPercentage,
+			Year:              record.Year,
+			SemesterSequence:  record.SemesterSequence,
+		})
+	}
+
+	attainments := make([]entity.SoAttainment, 0, len(sos))
+	for _, so := range sos {
+		courses := coursesBySo[so.Id]
+		if courses == nil {
+			courses = []entity.CourseData{}
+		}
+
+		sort.Slice(courses, func(i, j int) bool {
+			if courses[i].Year != courses[j].Year {
+				return courses[i].Year < courses[j].Year
+			}
+			if courses[i].SemesterSequence != courses[j].SemesterSequence {
+				return courses[i].SemesterSequence < courses[j].SemesterSequence
+			}
+			return courses[i].Code < courses[j].Code
+		})
+
+		totalPercentage := 0.0
+		passingCourseCount := 0
+		for _, course := range courses {
+			totalPercentage += course.PassingPercentage
+			if course.PassingPercentage >= so.ExpectedCoursePassingPercentage {
+				passingCourseCount++
+			}
+		}
+
+		attainmentPercentage := 0.0
+		if len(courses) > 0 {
+			attainmentPercentage = totalPercentage / float64(len(courses))
+		}
+
+		attainments = append(attainments, entity.SoAttainment{
+			StudentOutcomeId:                so.Id,
+			Code:                            so.Code,
+			DescriptionThai:                 so.DescriptionThai,
+			DescriptionEng:                  so.DescriptionEng,
+			ExpectedCoursePassingPercentage: so.ExpectedCoursePassingPercentage,
+			AttainmentPercentage:            attainmentPercentage,
+			PassingCourseCount:              passingCourseCount,
+			IsAttained:                      len(courses) > 0 && attainmentPercentage >= so.ExpectedCoursePassingPercentage,
+			Courses:                         courses,
+		})
+	}
+
+	sort.Slice(attainments, func(i, j int) bool {
+		return attainments[i].Code < attainments[j].Code
+	})
+
+	peos, err := u.ProgrammeUseCase.GetAllPEOMapping(programmeId)
+	if err != nil {
+		return nil, errs.New(errs.SameCode, "cannot get peo mapping of programme id %s", programmeId, err)
+	}
+
+	return &entity.ProgrammeSoAttainment{
+		ProgrammeId:     programme.Id,
+		ProgrammeName:   programme.NameEN,
+		FromYear:        fromSerm,
+		ToYear:          toSerm,
+		StudentOutcomes: attainments,
+		PEOs:            peos,
+	}, nil
+}
+
 func (u coursePortfolioUseCase) UpdateCoursePortfolio(courseId string, implement entity.Implementation, educationOutcomes entity.EducationOutcome, continuous entity.ContinuousDevelopment) error {
 	portfolioData := &entity.PortfolioData{
 		Implementation:        implement,
@@ -741,11 +912,30 @@ func (u coursePortfolioUseCase) UpdateCoursePortfolio(courseId string, implement
 		return errs.New(errs.SameCode, "cannot marshal course summary %s", err)
 	}
 
+	course, err := u.CourseUseCase.GetById(courseId)
+	if err != nil {
+		return errs.New(errs.SameCode, "cannot get course id %s while updating course portfolio", courseId, err)
+	} else if course == nil {
+		return errs.New(errs.ErrCourseNotFound, "course id %s not found while updating course portfolio", courseId)
+	}
+
 	err = u.CoursePortfolioRepository.UpdateCoursePortfolio(courseId, JsonByte)
 	if err != nil {
 		return errs.New(errs.SameCode, "cannot update course portfolio %s", err)
 	}
 
+	err = u.NotificationUseCase.NotifyRole(entity.UserRoleHeadOfCurriculum, entity.NotificationEvent{
+		Type:        entity.NotificationTypePortfolioSubmitted,
+		Title:       fmt.Sprintf("Portfolio of %s %s submitted", course.Code, course.Name),
+		Message:     fmt.Sprintf("The course portfolio of %s %s (%s/%d) is completed and ready for review.", course.Code, course.Name, course.Semester.SemesterSequence, course.Semester.Year),
+		Link:        "/courses/" + courseId,
+		ReferenceId: courseId,
+	})
+	// the portfolio is saved, a failed notification must not fail it
+	if err != nil {
+		u.Logger.Error("Cannot notify portfolio submission", zap.String("courseId", courseId), zap.Error(err))
+	}
+
 	return nil
 }
 
@@ -767,11 +957,18 @@ func (u coursePortfolioUseCase) GetOutcomesByStudentId(studentId string) ([]enti
 		return nil, errs.New(errs.SameCode, "cannot evaluate student pos by student id %s", studentId, err)
 	}
 
+	soRecords, err := u.CoursePortfolioRepository.EvaluateStudentOutcomesByStudentId(studentId)
+	if err != nil {
+		return nil, errs.New(errs.SameCode, "cannot evaluate student sos by student id %s", studentId, err)
+	}
+
 	studentPloMap := make(map[string][]entity.StudentPloData)
 	studentPoMap := make(map[string][]entity.StudentPoData)
+	studentSoMap := make(map[string][]entity.StudentSoData)
 
 	PloCourseMap := make(map[string][]entity.StudentCourseData)
 	PoCourseMap := make(map[string][]entity.StudentCourseData)
+	SoCourseMap := make(map[string][]entity.StudentCourseData)
 
 	for _, record := range ploRecords {
 		studentPloData, ok := studentPloMap[record.StudentId]
@@ -892,6 +1089,63 @@ func (u coursePortfolioUseCase) GetOutcomesByStudentId(studentId string) ([]enti
 		}
 	}
 
+	for _, record := range soRecords {
+		studentData, found := studentSoMap[record.StudentId]
+		if !found {
+			studentSoMap[record.StudentId] = append(studentSoMap[record.StudentId], entity.StudentSoData{
+				StudentOutcomeId: record.StudentOutcomeId,
+				Code:             record.StudentOutcomeCode,
+				DescriptionThai:  record.DescriptionThai,
+			})
+		} else {
+			isExist := false
+			for i := range studentData {
+				if studentData[i].StudentOutcomeId == record.StudentOutcomeId {
+					isExist = true
+					break
+				}
+			}
+			if !isExist {
+				studentSoMap[record.StudentId] = append(studentSoMap[record.StudentId], entity.StudentSoData{
+					StudentOutcomeId: record.StudentOutcomeId,
+					Code:             record.StudentOutcomeCode,
+					DescriptionThai:  record.DescriptionThai,
+				})
+			}
+		}
+
+		soData, found := SoCourseMap[record.StudentOutcomeId]
+
+		if !found {
+			SoCourseMap[record.StudentOutcomeId] = append(SoCourseMap[record.StudentOutcomeId], entity.StudentCourseData{
+				Id:               record.CourseId,
+				Code:             record.CourseCode,
+				Name:             record.CourseName,
+				Pass:             record.Pass,
+				Year:             record.Year,
+				SemesterSequence: record.SemesterSequence,
+			})
+		} else {
+			isExist := false
+			for i := range soData {
+				if soData[i].Id == record.CourseId {
+					isExist = true
+					break
+				}
+			}
+			if !isExist {
+				SoCourseMap[record.StudentOutcomeId] = append(SoCourseMap[record.StudentOutcomeId], entity.StudentCourseData{
+					Id:               record.CourseId,
+					Code:             record.CourseCode,
+					Name:             record.CourseName,
+					Pass:             record.Pass,
+					Year:             record.Year,
+					SemesterSequence: record.SemesterSequence,
+				})
+			}
+		}
+	}
+
 	students := make([]entity.StudentOutcomes, 0)
 
 	for studentId := range studentPloMap {
@@ -902,17 +1156,22 @@ func (u coursePortfolioUseCase) GetOutcomesByStudentId(studentId string) ([]enti
 			studentPoMap[studentId][poIndex].Courses = PoCourseMap[studentPoMap[studentId][poIndex].ProgramOutcomeId]
 		}
 
+		for soIndex := range studentSoMap[studentId] {
+			studentSoMap[studentId][soIndex].Courses = SoCourseMap[studentSoMap[studentId][soIndex].StudentOutcomeId]
+		}
+
 		students = append(students, entity.StudentOutcomes{
 			StudentId:               studentId,
 			ProgramLearningOutcomes: studentPloMap[studentId],
 			ProgramOutcomes:         studentPoMap[studentId],
+			StudentOutcomes:         studentSoMap[studentId],
 		})
 	}
 
 	return students, nil
 }
 
-func (u coursePortfolioUseCase) GetCourseCloAssessment(programmeId string, fromSerm, toSerm int) (*entity.FileResponse, error) {
+func (u coursePortfolioUseCase) GetCourseCloAssessment(programmeId string, fromSerm, toSerm int, fileDir string) (*entity.FileResponse, error) {
 	rows, err := u.CoursePortfolioRepository.GetCourseCloAssessment(programmeId, fromSerm, toSerm)
 	if err != nil {
 		return nil, errs.New(errs.SameCode, "cannot get course clo assessment %s", err)
@@ -928,7 +1187,6 @@ func (u coursePortfolioUseCase) GetCourseCloAssessment(programmeId string, fromS
 
 	fmt.Println(string(jsonData))
 
-	fileDir := filepath.Join("output", "course_clo_assessment")
 	if err := os.MkdirAll(fileDir, os.ModePerm); err != nil {
 		return nil, errs.New(errs.SameCode, "cannot create directory %s", err)
 	}
@@ -1176,7 +1434,7 @@ func getSortedKeysBoolMap(m map[string]bool) []string {
 	return getSortedKeys(m)
 }
 
-func (u coursePortfolioUseCase) GetCourseLinkedOutcomes(programmeId string, fromSerm, toSerm int) (*entity.FileResponse, error) {
+func (u coursePortfolioUseCase) GetCourseLinkedOutcomes(programmeId string, fromSerm, toSerm int, fileDir string) (*entity.FileResponse, error) {
 	rows, err := u.CoursePortfolioRepository.GetCourseLinkedOutcomes(programmeId, fromSerm, toSerm)
 	if err != nil {
 		return nil, errs.New(errs.SameCode, "cannot get course linked outcomes %s", err)
@@ -1192,7 +1450,6 @@ func (u coursePortfolioUseCase) GetCourseLinkedOutcomes(programmeId string, from
 
 	// fmt.Println(string(jsonData))
 
-	fileDir := filepath.Join("output", "course_linked_outcomes")
 	if err := os.MkdirAll(fileDir, os.ModePerm); err != nil {
 		return nil, errs.New(errs.SameCode, "cannot create directory %s", err)
 	}
@@ -1571,7 +1828,7 @@ func WriteCourseLinkedOutcomes(outputs []CourseLinkedOutcome, filename string) e
 	return nil
 }
 
-func (u coursePortfolioUseCase) GetCourseOutcomesSuccessRate(programmeId string, fromSerm, toSerm int) (*entity.FileResponse, error) {
+func (u coursePortfolioUseCase) GetCourseOutcomesSuccessRate(programmeId string, fromSerm, toSerm int, fileDir string) (*entity.FileResponse, error) {
 	output, err := u.CoursePortfolioRepository.GetCourseOutcomesSuccessRate(
 		programmeId, fromSerm, toSerm,
 	)
@@ -1586,7 +1843,6 @@ func (u coursePortfolioUseCase) GetCourseOutcomesSuccessRate(programmeId string,
 
 	// fmt.Println(string(jsonData))
 
-	fileDir := filepath.Join("output", "course_outcomes_success_rate")
 	if err := os.MkdirAll(fileDir, os.ModePerm); err != nil {
 		return nil, errs.New(errs.SameCode, "cannot create directory %s", err)
 	}
@@ -1803,3 +2059,333 @@ func (u coursePortfolioUseCase) GetCourseOutcomes(courseId string) (*entity.Cour
 
 	return course, nil
 }
+
+type trendSemester struct {
+	Year             int
+	SemesterSequence string
+}
+
+func (s trendSemester) label() string {
+	return fmt.Sprintf("%s/%d", s.SemesterSequence, s.Year)
+}
+
+func sortTrendSemesters(semesters []trendSemester) {
+	sort.Slice(semesters, func(i, j int) bool {
+		if semesters[i].Year != semesters[j].Year {
+			return semesters[i].Year < semesters[j].Year
+		}
+		return semesters[i].SemesterSequence < semesters[j].SemesterSequence
+	})
+}
+
+func buildOutcomeTrend(trend entity.OutcomeTrend, samples map[trendSemester][]float64) entity.OutcomeTrend {
+	semesters := make([]trendSemester, 0, len(samples))
+	for semester := range samples {
+		semesters = append(semesters, semester)
+	}
+	sortTrendSemesters(semesters)
+
+	trend.Points = make([]entity.OutcomeTrendPoint, 0, len(semesters))
+
+	var previous *float64
+	for _, semester := range semesters {
+		percentages := samples[semester]
+
+		total := 0.0
+		for _, percentage := range percentages {
+			total += percentage
+		}
+		attainment := total / float64(len(percentages))
+
+		point := entity.OutcomeTrendPoint{
+			Year:                 semester.Year,
+			SemesterSequence:     semester.SemesterSequence,
+			Semester:             semester.label(),
+			CourseCount:          len(percentages),
+			AttainmentPercentage: attainment,
+			IsBelowExpected:      attainment < trend.ExpectedCoursePassingPercentage,
+		}
+
+		if previous != nil {
+			delta := attainment - *previous
+			point.Delta = &delta
+		}
+		previous = &attainment
+
+		trend.Points = append(trend.Points, point)
+	}
+
+	return trend
+}
+
+func (u coursePortfolioUseCase) GetOutcomeAttainmentTrend(programmeId string, fromSerm, toSerm int) (*entity.ProgrammeOutcomeTrend, error) {
+	programme, err := u.ProgrammeUseCase.GetById(programmeId)
+	if err != nil {
+		return nil, errs.New(errs.SameCode, "cannot get programme id %s while getting outcome trend", programmeId, err)
+	} else if programme == nil {
+		return nil, errs.New(errs.ErrProgrammeNotFound, "programme id %s not found while getting outcome trend", programmeId)
+	}
+
+	plos, err := u.ProgrammeUseCase.GetAllPLO(programmeId)
+	if err != nil {
+		return nil, errs.New(errs.SameCode, "cannot get plos of programme id %s", programmeId, err)
+	}
+
+	pos, err := u.ProgrammeUseCase.GetAllPO(programmeId)
+	if err != nil {
+		return nil, errs.New(errs.SameCode, "cannot get pos of programme id %s", programmeId, err)
+	}
+
+	sos, err := u.ProgrammeUseCase.GetAllSO(programmeId)
+	if err != nil {
+		return nil, errs.New(errs.SameCode, "cannot get sos of programme id %s", programmeId, err)
+	}
+
+	ploRecords, err := u.CoursePortfolioRepository.EvaluateAllPloCourses()
+	if err != nil {
+		return nil, errs.New(errs.SameCode, "cannot evaluate plo courses %s", err)
+	}
+
+	poRecords, err := u.CoursePortfolioRepository.EvaluateAllPoCourses()
+	if err != nil {
+		return nil, errs.New(errs.SameCode, "cannot evaluate po courses %s", err)
+	}
+
+	soRecords, err := u.CoursePortfolioRepository.EvaluateAllSoCourses()
+	if err != nil {
+		return nil, errs.New(errs.SameCode, "cannot evaluate so courses %s", err)
+	}
+
+	allSemesters := make(map[trendSemester]bool)
+	samples := make(map[string]map[trendSemester][]float64)
+
+	addSample := func(outcomeId string, courseId string, year int, semesterSequence string, percentage float64) {
+		if courseId == "" || year < fromSerm || year > toSerm {
+			return
+		}
+
+		semester := trendSemester{Year: year, SemesterSequence: semesterSequence}
+		if samples[outcomeId] == nil {
+			samples[outcomeId] = make(map[trendSemester][]float64)
+		}
+		samples[outcomeId][semester] = append(samples[outcomeId][semester], percentage)
+		allSemesters[semester] = true
+	}
+
+	for _, record := range ploRecords {
+		addSample(record.ProgramLearningOutcomeId, record.CourseId, record.Year, record.SemesterSequence, record.PassingPercentage)
+	}
+	for _, record := range poRecords {
+		addSample(record.ProgramOutcomeId, record.CourseId, record.Year, record.SemesterSequence, record.PassingPercentage)
+	}
+	for _, record := range soRecords {
+		addSample(record.StudentOutcomeId, record.CourseId, record.Year, record.SemesterSequence, record.PassingPercentage)
+	}
+
+	outcomes := make([]entity.OutcomeTrend, 0, len(plos)+len(pos)+len(sos))
+
+	for _, plo := range plos {
+		outcomes = append(outcomes, buildOutcomeTrend(entity.OutcomeTrend{
+			OutcomeType:                     entity.OutcomeTypePLO,
+			OutcomeId:                       plo.Id,
+			Code:                            plo.Code,
+			Description:                     plo.DescriptionThai,
+			ExpectedCoursePassingPercentage: plo.ExpectedCoursePassingPercentage,
+		}, samples[plo.Id]))
+	}
+
+	for _, po := range pos {
+		outcomes = append(outcomes, buildOutcomeTrend(entity.OutcomeTrend{
+			OutcomeType:                     entity.OutcomeTypePO,
+			OutcomeId:                       po.Id,
+			Code:                            po.Code,
+			Description:                     po.Description,
+			ExpectedCoursePassingPercentage: po.ExpectedCoursePassingPercentage,
+		}, samples[po.Id]))
+	}
+
+	for _, so := range sos {
+		outcomes = append(outcomes, buildOutcomeTrend(entity.OutcomeTrend{
+			OutcomeType:                     entity.OutcomeTypeSO,
+			OutcomeId:                       so.Id,
+			Code:                            so.Code,
+			Description:                     so.DescriptionThai,
+			ExpectedCoursePassingPercentage: so.ExpectedCoursePassingPercentage,
+		}, samples[so.Id]))
+	}
+
+	sort.SliceStable(outcomes, func(i, j int) bool {
+		if outcomes[i].OutcomeType != outcomes[j].OutcomeType {
+			return outcomes[i].OutcomeType < outcomes[j].OutcomeType
+		}
+		return outcomes[i].Code < outcomes[j].Code
+	})
+
+	// only semesters which have data for this programme's outcomes
+	semesters := make([]trendSemester, 0)
+	for semester := range allSemesters {
+		semesters = append(semesters, semester)
+	}
+	sortTrendSemesters(semesters)
+
+	semesterLabels := make([]string, 0, len(semesters))
+	for _, semester := range semesters {
+		semesterLabels = append(semesterLabels, semester.label())
+	}
+
+	return &entity.ProgrammeOutcomeTrend{
+		ProgrammeId:   programme.Id,
+		ProgrammeName: programme.NameEN,
+		FromYear:      fromSerm,
+		ToYear:        toSerm,
+		Semesters:     semesterLabels,
+		Outcomes:      outcomes,
+	}, nil
+}
+
+func (u coursePortfolioUseCase) GetOutcomeAttainmentTrendFile(programmeId string, fromSerm, toSerm int, fileDir string) (*entity.FileResponse, error) {
+	trend, err := u.GetOutcomeAttainmentTrend(programmeId, fromSerm, toSerm)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(fileDir, os.ModePerm); err != nil {
+		return nil, errs.New(errs.SameCode, "cannot create directory %s", err)
+	}
+	fileName := fmt.Sprintf("outcome_attainment_trend_%s.xlsx", time.Now().Format("20060102150405"))
+	filepath := filepath.Join(fileDir, fileName)
+
+	err = WriteOutcomeAttainmentTrend(*trend, filepath)
+	if err != nil {
+		return nil, errs.New(errs.SameCode, "cannot write to excel %s", err)
+	}
+
+	return &entity.FileResponse{
+		FileName: fileName,
+		FilePath: filepath,
+		FileType: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	}, nil
+}
+
+// WriteOutcomeAttainmentTrend writes one row per outcome and one column per semester,
+// so the attainment range can be charted directly
+func WriteOutcomeAttainmentTrend(trend entity.ProgrammeOutcomeTrend, filename string) error {
+	f := excelize.NewFile()
+	attainmentSheet := "Attainment"
+	deltaSheet := "Delta"
+	f.SetSheetName(f.GetSheetName(0), attainmentSheet)
+	if _, err := f.NewSheet(deltaSheet); err != nil {
+		return fmt.Errorf("failed to create sheet: %v", err)
+	}
+
+	headerStyle, err := f.NewStyle(&excelize.Style{
+		Alignment: &excelize.Alignment{
+			Horizontal: "center",
+			Vertical:   "center",
+		},
+		Font: &excelize.Font{
+			Bold: true,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create style: %v", err)
+	}
+
+	belowExpectedStyle, err := f.NewStyle(&excelize.Style{
+		Fill: excelize.Fill{
+			Type:    "pattern",
+			Pattern: 1,
+			Color:   []string{"#F8CBAD"},
+		},
+		NumFmt: 2,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create style: %v", err)
+	}
+
+	numberStyle, err := f.NewStyle(&excelize.Style{
+		NumFmt: 2,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create style: %v", err)
+	}
+
+	headers := []string{"Type", "Code", "Description", "Expected (%)"}
+	semesterColumn := make(map[string]int, len(trend.Semesters))
+	for i, semester := range trend.Semesters {
+		semesterColumn[semester] = len(headers) + i + 1
+	}
+
+	for _, sheet := range []string{attainmentSheet, deltaSheet} {
+		for i, h := range append(headers, trend.Semesters...) {
+			if err := f.SetCellValue(sheet, getCell(i+1, 1), h); err != nil {
+				return err
+			}
+		}
+		lastHeader := getCell(len(headers)+len(trend.Semesters), 1)
+		if err := f.SetCellStyle(sheet, "A1", lastHeader, headerStyle); err != nil {
+			return err
+		}
+	}
+
+	for i, outcome := range trend.Outcomes {
+		row := i + 2
+
+		for _, sheet := range []string{attainmentSheet, deltaSheet} {
+			values := []interface{}{string(outcome.OutcomeType), outcome.Code, outcome.Description, outcome.ExpectedCoursePassingPercentage}
+			for col, value := range values {
+				if err := f.SetCellValue(sheet, getCell(col+1, row), value); err != nil {
+					return err
+				}
+			}
+		}
+
+		for _, point := range outcome.Points {
+			col, ok := semesterColumn[point.Semester]
+			if !ok {
+				continue
+			}
+
+			cell := getCell(col, row)
+			if err := f.SetCellValue(attainmentSheet, cell, point.AttainmentPercentage); err != nil {
+				return err
+			}
+
+			style := numberStyle
+			if point.IsBelowExpected {
+				style = belowExpectedStyle
+			}
+			if err := f.SetCellStyle(attainmentSheet, cell, cell, style); err != nil {
+				return err
+			}
+
+			if point.Delta != nil {
+				if err := f.SetCellValue(deltaSheet, cell, *point.Delta); err != nil {
+					return err
+				}
+				if err := f.SetCellStyle(deltaSheet, cell, cell, numberStyle); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	for _, sheet := range []string{attainmentSheet, deltaSheet} {
+		if err := f.SetColWidth(sheet, "C", "C", 50); err != nil {
+			return err
+		}
+	}
+
+	// Save file
+	if err := f.SaveAs(filename); err != nil {
+		return err
+	}
+
+	// Cleanup old files
+	fileFolder := filepath.Dir(filename)
+	if err := utils.DeleteOldFiles(fileFolder, 1); err != nil {
+		return fmt.Errorf("cannot delete old files: %w", err)
+	}
+
+	return nil
+}
@@ -1,25 +1,34 @@
 package usecase
 
 import (
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/oklog/ulid/v2"
 	"github.com/team-inu/inu-backyard/entity"
 	errs "github.com/team-inu/inu-backyard/entity/error"
+	"go.uber.org/zap"
 )
 
 type courseStreamUseCase struct {
 	courseStreamRepository entity.CourseStreamRepository
 	courseUseCase          entity.CourseUseCase
+	notificationUseCase    entity.NotificationUseCase
+	logger                 *zap.Logger
 }
 
 func NewCourseStreamUseCase(
 	courseStreamRepository entity.CourseStreamRepository,
 	courseUseCase entity.CourseUseCase,
+	notificationUseCase entity.NotificationUseCase,
+	logger *zap.Logger,
 ) entity.CourseStreamsUseCase {
 	return &courseStreamUseCase{
 		courseStreamRepository: courseStreamRepository,
 		courseUseCase:          courseUseCase,
+		notificationUseCase:    notificationUseCase,
+		logger:                 logger,
 	}
 }
 
@@ -53,6 +62,18 @@ func (u *courseStreamUseCase) Create(payload entity.CreateCourseStreamPayload) e
 		return errs.New(errs.ErrCreateCourseStream, "cannot create stream course", err)
 	}
 
+	err = u.notificationUseCase.Notify(courseLecturerIds(targetCourse, payload.SenderId), entity.NotificationEvent{
+		Type:        entity.NotificationTypeCourseStream,
+		Title:       fmt.Sprintf("New %s comment from %s %s", strings.ToLower(string(payload.StreamType)), fromCourse.Code, fromCourse.Name),
+		Message:     payload.Comment,
+		Link:        "/courses/" + targetCourse.Id,
+		ReferenceId: targetCourse.Id,
+	})
+	// the stream course is saved, a failed notification must not fail it
+	if err != nil {
+		u.logger.Error("Cannot notify lecturers about stream course", zap.String("courseId", targetCourse.Id), zap.Error(err))
+	}
+
 	return nil
 }
 
@@ -0,0 +1,32 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/team-inu/inu-backyard/entity"
+	"go.uber.org/zap"
+)
+
+type stubCourseStreamRepository struct {
+	entity.CourseStreamRepository
+	courseStreams []entity.CourseStream
+}
+
+func (r *stubCourseStreamRepository) Create(courseStream *entity.CourseStream) error {
+	r.courseStreams = append(r.courseStreams, *courseStream)
+	return nil
+}
+
+func TestCourseStream(t *testing.T) {
+	t.Run("TestCreateNotificationFailure", func(t *testing.T) {
+		courseStreamRepository := &stubCourseStreamRepository{}
+		notificationUseCase := &stubNotificationUseCase{err: errors.New("connection refused")}
+		courseStreamUseCase := NewCourseStreamUseCase(courseStreamRepository, &stubCourseUseCase{}, notificationUseCase, zap.NewNop())
+
+		err := courseStreamUseCase.Create(entity.CreateCourseStreamPayload{FromCourseId: "from", TargetCourseId: "target", StreamType: entity.UpCourseStreamType, Comment: "Please cover recursion", SenderId: "sender"})
+		assert.Nil(t, err, "Expected the saved stream course not to fail on a notification error, got %v", err)
+		assert.Len(t, courseStreamRepository.courseStreams, 1)
+	})
+}
@@ -0,0 +1,36 @@
+package usecase
+
+import (
+	"github.com/team-inu/inu-backyard/entity"
+	errs "github.com/team-inu/inu-backyard/entity/error"
+	"github.com/team-inu/inu-backyard/internal/utils"
+)
+
+// passwordCredentialVerifier checks the bcrypt password stored in the user table
+type passwordCredentialVerifier struct {
+	userUseCase entity.UserUseCase
+}
+
+func NewPasswordCredentialVerifier(userUseCase entity.UserUseCase) entity.CredentialVerifier {
+	return &passwordCredentialVerifier{
+		userUseCase: userUseCase,
+	}
+}
+
+func (v passwordCredentialVerifier) Verify(email string, password string) (*entity.VerifiedCredential, error) {
+	user, err := v.userUseCase.GetByEmail(email)
+	if err != nil {
+		return nil, errs.New(errs.SameCode, "cannot get user data to verify password", err)
+	} else if user == nil {
+		return nil, nil
+	}
+
+	err = utils.CheckPassword(user.Password, password)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.VerifiedCredential{
+		Email: user.Email,
+	}, nil
+}
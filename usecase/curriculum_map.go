@@ -0,0 +1,476 @@
+package usecase
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/team-inu/inu-backyard/entity"
+	errs "github.com/team-inu/inu-backyard/entity/error"
+	"github.com/team-inu/inu-backyard/utils"
+	"github.com/xuri/excelize/v2"
+)
+
+type curriculumMapUseCase struct {
+	curriculumMapRepository entity.CurriculumMapRepository
+	programmeUseCase        entity.ProgrammeUseCase
+}
+
+func NewCurriculumMapUseCase(
+	curriculumMapRepository entity.CurriculumMapRepository,
+	programmeUseCase entity.ProgrammeUseCase,
+) entity.CurriculumMapUseCase {
+	return &curriculumMapUseCase{
+		curriculumMapRepository: curriculumMapRepository,
+		programmeUseCase:        programmeUseCase,
+	}
+}
+
+type curriculumCell struct {
+	outcomeType entity.OutcomeType
+	outcomeId   string
+}
+
+type curriculumCourse struct {
+	code string
+	name string
+	clos map[string]bool
+	// outcome -> linked clo ids
+	links map[curriculumCell]map[string]bool
+}
+
+// deriveCurriculumLevel maps the share of a course's CLOs linked to an outcome to a level,
+// a third or less is introduced, up to two thirds is reinforced and above that is mastered
+func deriveCurriculumLevel(linkedCloCount int, cloCount int) entity.CurriculumLevel {
+	if linkedCloCount == 0 || cloCount == 0 {
+		return entity.CurriculumLevelNone
+	}
+
+	ratio := float64(linkedCloCount) / float64(cloCount)
+	switch {
+	case ratio > 2.0/3.0:
+		return entity.CurriculumLevelMastered
+	case ratio > 1.0/3.0:
+		return entity.CurriculumLevelReinforced
+	default:
+		return entity.CurriculumLevelIntroduced
+	}
+}
+
+func (u curriculumMapUseCase) getOutcomes(programmeId string) ([]entity.CurriculumMapOutcome, error) {
+	plos, err := u.programmeUseCase.GetAllPLO(programmeId)
+	if err != nil {
+		return nil, errs.New(errs.SameCode, "cannot get plos of programme id %s", programmeId, err)
+	}
+
+	sos, err := u.programmeUseCase.GetAllSO(programmeId)
+	if err != nil {
+		return nil, errs.New(errs.SameCode, "cannot get sos of programme id %s", programmeId, err)
+	}
+
+	sort.Slice(plos, func(i, j int) bool { return plos[i].Code < plos[j].Code })
+	sort.Slice(sos, func(i, j int) bool { return sos[i].Code < sos[j].Code })
+
+	outcomes := make([]entity.CurriculumMapOutcome, 0)
+	for _, plo := range plos {
+		outcomes = append(outcomes, entity.CurriculumMapOutcome{
+			OutcomeType: entity.OutcomeTypePLO,
+			OutcomeId:   plo.Id,
+			Code:        plo.Code,
+			Description: plo.DescriptionThai,
+		})
+
+		splos := plo.SubProgramLearningOutcomes
+		sort.Slice(splos, func(i, j int) bool { return splos[i].Code < splos[j].Code })
+		for _, splo := range splos {
+			outcomes = append(outcomes, entity.CurriculumMapOutcome{
+				OutcomeType: entity.OutcomeTypeSPLO,
+				OutcomeId:   splo.Id,
+				Code:        splo.Code,
+				Description: splo.DescriptionThai,
+				ParentId:    plo.Id,
+			})
+		}
+	}
+
+	for _, so := range sos {
+		outcomes = append(outcomes, entity.CurriculumMapOutcome{
+			OutcomeType: entity.OutcomeTypeSO,
+			OutcomeId:   so.Id,
+			Code:        so.Code,
+			Description: so.DescriptionThai,
+		})
+	}
+
+	return outcomes, nil
+}
+
+func (u curriculumMapUseCase) getCourses(programmeId string) ([]*curriculumCourse, error) {
+	links, err := u.curriculumMapRepository.GetCourseOutcomeLinks(programmeId)
+	if err != nil {
+		return nil, errs.New(errs.ErrQueryCurriculumMap, "cannot get course outcome links of programme id %s", programmeId, err)
+	}
+
+	courses := make([]*curriculumCourse, 0)
+	courseByCode := make(map[string]*curriculumCourse)
+
+	addLink := func(course *curriculumCourse, outcomeType entity.OutcomeType, outcomeId string, cloId string) {
+		if outcomeId == "" {
+			return
+		}
+
+		cell := curriculumCell{outcomeType: outcomeType, outcomeId: outcomeId}
+		if course.links[cell] == nil {
+			course.links[cell] = make(map[string]bool)
+		}
+		course.links[cell][cloId] = true
+	}
+
+	for _, link := range links {
+		course, ok := courseByCode[link.CourseCode]
+		if !ok {
+			course = &curriculumCourse{
+				code:  link.CourseCode,
+				name:  link.CourseName,
+				clos:  make(map[string]bool),
+				links: make(map[curriculumCell]map[string]bool),
+			}
+			courseByCode[link.CourseCode] = course
+			courses = append(courses, course)
+		}
+
+		if link.CloId == "" {
+			continue
+		}
+
+		course.clos[link.CloId] = true
+		addLink(course, entity.OutcomeTypePLO, link.PloId, link.CloId)
+		addLink(course, entity.OutcomeTypeSPLO, link.SploId, link.CloId)
+		addLink(course, entity.OutcomeTypeSO, link.SoId, link.CloId)
+	}
+
+	return courses, nil
+}
+
+func (u curriculumMapUseCase) Get(programmeId string) (*entity.CurriculumMap, error) {
+	programme, err := u.programmeUseCase.GetById(programmeId)
+	if err != nil {
+		return nil, errs.New(errs.SameCode, "cannot get programme id %s while getting curriculum map", programmeId, err)
+	} else if programme == nil {
+		return nil, errs.New(errs.ErrProgrammeNotFound, "programme id %s not found while getting curriculum map", programmeId)
+	}
+
+	outcomes, err := u.getOutcomes(programmeId)
+	if err != nil {
+		return nil, err
+	}
+
+	courses, err := u.getCourses(programmeId)
+	if err != nil {
+		return nil, err
+	}
+
+	levels, err := u.curriculumMapRepository.GetLevels(programmeId)
+	if err != nil {
+		return nil, errs.New(errs.ErrQueryCurriculumMap, "cannot get curriculum map levels of programme id %s", programmeId, err)
+	}
+
+	overrides := make(map[string]map[curriculumCell]entity.CurriculumMapLevel)
+	for _, level := range levels {
+		if overrides[level.CourseCode] == nil {
+			overrides[level.CourseCode] = make(map[curriculumCell]entity.CurriculumMapLevel)
+		}
+		overrides[level.CourseCode][curriculumCell{outcomeType: level.OutcomeType, outcomeId: level.OutcomeId}] = level
+	}
+
+	highestLevels := make(map[curriculumCell]entity.CurriculumLevel)
+
+	mapCourses := make([]entity.CurriculumMapCourse, 0, len(courses))
+	for _, course := range courses {
+		cells := make([]entity.CurriculumMapCell, 0, len(outcomes))
+
+		for _, outcome := range outcomes {
+			key := curriculumCell{outcomeType: outcome.OutcomeType, outcomeId: outcome.OutcomeId}
+			linkedCloCount := len(course.links[key])
+
+			cell := entity.CurriculumMapCell{
+				OutcomeType:    outcome.OutcomeType,
+				OutcomeId:      outcome.OutcomeId,
+				Code:           outcome.Code,
+				Level:          deriveCurriculumLevel(linkedCloCount, len(course.clos)),
+				LinkedCloCount: linkedCloCount,
+			}
+
+			if override, ok := overrides[course.code][key]; ok {
+				cell.Level = override.Level
+				cell.OverrideId = override.Id
+			}
+			cell.Label = cell.Level.Label()
+
+			if cell.Level > highestLevels[key] {
+				highestLevels[key] = cell.Level
+			}
+
+			cells = append(cells, cell)
+		}
+
+		mapCourses = append(mapCourses, entity.CurriculumMapCourse{
+			CourseCode: course.code,
+			CourseName: course.name,
+			CloCount:   len(course.clos),
+			Cells:      cells,
+		})
+	}
+
+	gaps := make([]entity.CurriculumMapGap, 0)
+	for _, outcome := range outcomes {
+		highestLevel := highestLevels[curriculumCell{outcomeType: outcome.OutcomeType, outcomeId: outcome.OutcomeId}]
+
+		var reason entity.CurriculumGapReason
+		if highestLevel == entity.CurriculumLevelNone {
+			reason = entity.CurriculumGapNoCourse
+		} else if highestLevel < entity.CurriculumLevelMastered {
+			reason = entity.CurriculumGapNeverMastered
+		} else {
+			continue
+		}
+
+		gaps = append(gaps, entity.CurriculumMapGap{
+			OutcomeType:  outcome.OutcomeType,
+			OutcomeId:    outcome.OutcomeId,
+			Code:         outcome.Code,
+			Reason:       reason,
+			HighestLevel: highestLevel,
+		})
+	}
+
+	return &entity.CurriculumMap{
+		ProgrammeId:   programme.Id,
+		ProgrammeName: programme.NameEN,
+		Outcomes:      outcomes,
+		Courses:       mapCourses,
+		Gaps:          gaps,
+	}, nil
+}
+
+func (u curriculumMapUseCase) SetLevel(programmeId string, payload entity.SetCurriculumMapLevelPayload) error {
+	programme, err := u.programmeUseCase.GetById(programmeId)
+	if err != nil {
+		return errs.New(errs.SameCode, "cannot get programme id %s while setting curriculum map level", programmeId, err)
+	} else if programme == nil {
+		return errs.New(errs.ErrProgrammeNotFound, "programme id %s not found while setting curriculum map level", programmeId)
+	}
+
+	outcomes, err := u.getOutcomes(programmeId)
+	if err != nil {
+		return err
+	}
+
+	isOutcomeExist := false
+	for _, outcome := range outcomes {
+		if outcome.OutcomeType == payload.OutcomeType && outcome.OutcomeId == payload.OutcomeId {
+			isOutcomeExist = true
+			break
+		}
+	}
+	if !isOutcomeExist {
+		return errs.New(errs.ErrCreateCurriculumMapLevel, "%s id %s is not in programme id %s", payload.OutcomeType, payload.OutcomeId, programmeId)
+	}
+
+	courses, err := u.getCourses(programmeId)
+	if err != nil {
+		return err
+	}
+
+	isCourseExist := false
+	for _, course := range courses {
+		if course.code == payload.CourseCode {
+			isCourseExist = true
+			break
+		}
+	}
+	if !isCourseExist {
+		return errs.New(errs.ErrCreateCurriculumMapLevel, "course code %s is not in programme id %s", payload.CourseCode, programmeId)
+	}
+
+	existLevel, err := u.curriculumMapRepository.GetLevel(programmeId, payload.CourseCode, payload.OutcomeType, payload.OutcomeId)
+	if err != nil {
+		return errs.New(errs.ErrQueryCurriculumMap, "cannot get curriculum map level", err)
+	}
+
+	if existLevel != nil {
+		err = u.curriculumMapRepository.UpdateLevel(existLevel.Id, &entity.CurriculumMapLevel{Level: payload.Level})
+		if err != nil {
+			return errs.New(errs.ErrUpdateCurriculumMapLevel, "cannot update curriculum map level id %s", existLevel.Id, err)
+		}
+
+		return nil
+	}
+
+	err = u.curriculumMapRepository.CreateLevel(&entity.CurriculumMapLevel{
+		Id:          ulid.Make().String(),
+		ProgrammeId: programmeId,
+		CourseCode:  payload.CourseCode,
+		OutcomeType: payload.OutcomeType,
+		OutcomeId:   payload.OutcomeId,
+		Level:       payload.Level,
+	})
+	if err != nil {
+		return errs.New(errs.ErrCreateCurriculumMapLevel, "cannot create curriculum map level", err)
+	}
+
+	return nil
+}
+
+func (u curriculumMapUseCase) DeleteLevel(programmeId string, id string) error {
+	level, err := u.curriculumMapRepository.GetLevelById(id)
+	if err != nil {
+		return errs.New(errs.ErrQueryCurriculumMap, "cannot get curriculum map level id %s to delete", id, err)
+	} else if level == nil || level.ProgrammeId != programmeId {
+		return errs.New(errs.ErrCurriculumMapLevelNotFound, "curriculum map level id %s not found in programme id %s", id, programmeId)
+	}
+
+	err = u.curriculumMapRepository.DeleteLevel(id)
+	if err != nil {
+		return errs.New(errs.ErrDeleteCurriculumMapLevel, "cannot delete curriculum map level id %s", id, err)
+	}
+
+	return nil
+}
+
+func (u curriculumMapUseCase) GetFile(programmeId string, fileDir string) (*entity.FileResponse, error) {
+	curriculumMap, err := u.Get(programmeId)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(fileDir, os.ModePerm); err != nil {
+		return nil, errs.New(errs.SameCode, "cannot create directory %s", err)
+	}
+	fileName := fmt.Sprintf("curriculum_map_%s.xlsx", time.Now().Format("20060102150405"))
+	filepath := filepath.Join(fileDir, fileName)
+
+	err = WriteCurriculumMap(*curriculumMap, filepath)
+	if err != nil {
+		return nil, errs.New(errs.SameCode, "cannot write to excel %s", err)
+	}
+
+	return &entity.FileResponse{
+		FileName: fileName,
+		FilePath: filepath,
+		FileType: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	}, nil
+}
+
+func WriteCurriculumMap(curriculumMap entity.CurriculumMap, filename string) error {
+	f := excelize.NewFile()
+	mapSheet := "Curriculum Map"
+	gapSheet := "Gaps"
+	f.SetSheetName(f.GetSheetName(0), mapSheet)
+	if _, err := f.NewSheet(gapSheet); err != nil {
+		return fmt.Errorf("failed to create sheet: %v", err)
+	}
+
+	headerStyle, err := f.NewStyle(&excelize.Style{
+		Alignment: &excelize.Alignment{
+			Horizontal: "center",
+			Vertical:   "center",
+		},
+		Font: &excelize.Font{
+			Bold: true,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create style: %v", err)
+	}
+
+	centerStyle, err := f.NewStyle(&excelize.Style{
+		Alignment: &excelize.Alignment{
+			Horizontal: "center",
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create style: %v", err)
+	}
+
+	// Outcome type on the first row, outcome code on the second
+	headers := []string{"Course Code", "Course Name"}
+	for i, h := range headers {
+		if err := f.SetCellValue(mapSheet, getCell(i+1, 1), h); err != nil {
+			return err
+		}
+		if err := f.MergeCell(mapSheet, getCell(i+1, 1), getCell(i+1, 2)); err != nil {
+			return err
+		}
+	}
+	for i, outcome := range curriculumMap.Outcomes {
+		col := len(headers) + i + 1
+		if err := f.SetCellValue(mapSheet, getCell(col, 1), string(outcome.OutcomeType)); err != nil {
+			return err
+		}
+		if err := f.SetCellValue(mapSheet, getCell(col, 2), outcome.Code); err != nil {
+			return err
+		}
+	}
+	lastCol := len(headers) + len(curriculumMap.Outcomes)
+	if err := f.SetCellStyle(mapSheet, "A1", getCell(lastCol, 2), headerStyle); err != nil {
+		return err
+	}
+
+	for i, course := range curriculumMap.Courses {
+		row := i + 3
+		if err := f.SetCellValue(mapSheet, getCell(1, row), course.CourseCode); err != nil {
+			return err
+		}
+		if err := f.SetCellValue(mapSheet, getCell(2, row), course.CourseName); err != nil {
+			return err
+		}
+		for j, cell := range course.Cells {
+			if err := f.SetCellValue(mapSheet, getCell(len(headers)+j+1, row), cell.Label); err != nil {
+				return err
+			}
+		}
+	}
+	if len(curriculumMap.Courses) > 0 && lastCol > len(headers) {
+		if err := f.SetCellStyle(mapSheet, getCell(len(headers)+1, 3), getCell(lastCol, len(curriculumMap.Courses)+2), centerStyle); err != nil {
+			return err
+		}
+	}
+	if err := f.SetColWidth(mapSheet, "B", "B", 40); err != nil {
+		return err
+	}
+
+	gapHeaders := []string{"Type", "Code", "Reason", "Highest Level"}
+	for i, h := range gapHeaders {
+		if err := f.SetCellValue(gapSheet, getCell(i+1, 1), h); err != nil {
+			return err
+		}
+	}
+	if err := f.SetCellStyle(gapSheet, "A1", getCell(len(gapHeaders), 1), headerStyle); err != nil {
+		return err
+	}
+	for i, gap := range curriculumMap.Gaps {
+		values := []interface{}{string(gap.OutcomeType), gap.Code, string(gap.Reason), gap.HighestLevel.Label()}
+		for col, value := range values {
+			if err := f.SetCellValue(gapSheet, getCell(col+1, i+2), value); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Save file
+	if err := f.SaveAs(filename); err != nil {
+		return err
+	}
+
+	// Cleanup old files
+	fileFolder := filepath.Dir(filename)
+	if err := utils.DeleteOldFiles(fileFolder, 1); err != nil {
+		return fmt.Errorf("cannot delete old files: %w", err)
+	}
+
+	return nil
+}
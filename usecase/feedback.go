@@ -0,0 +1,326 @@
+package usecase
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/team-inu/inu-backyard/entity"
+	errs "github.com/team-inu/inu-backyard/entity/error"
+	"github.com/team-inu/inu-backyard/internal/config"
+)
+
+// time a student has to follow the feedback link sent by email
+const feedbackTokenMaxAge = 7 * 24 * time.Hour
+
+type feedbackUseCase struct {
+	feedbackRepo         entity.FeedbackRepository
+	courseUseCase        entity.CourseUseCase
+	studentUseCase       entity.StudentUseCase
+	enrollmentUseCase    entity.EnrollmentUseCase
+	mailUseCase          entity.MailUseCase
+	loginThrottleUseCase entity.LoginThrottleUseCase
+	clientConfig         config.ClientConfig
+}
+
+func NewFeedbackUseCase(
+	feedbackRepo entity.FeedbackRepository,
+	courseUseCase entity.CourseUseCase,
+	studentUseCase entity.StudentUseCase,
+	enrollmentUseCase entity.EnrollmentUseCase,
+	mailUseCase entity.MailUseCase,
+	loginThrottleUseCase entity.LoginThrottleUseCase,
+	clientConfig config.ClientConfig,
+) entity.FeedbackUseCase {
+	return &feedbackUseCase{
+		feedbackRepo:         feedbackRepo,
+		courseUseCase:        courseUseCase,
+		studentUseCase:       studentUseCase,
+		enrollmentUseCase:    enrollmentUseCase,
+		mailUseCase:          mailUseCase,
+		loginThrottleUseCase: loginThrottleUseCase,
+		clientConfig:         clientConfig,
+	}
+}
+
+// RequestLink counts every request as a failure, so the throttle limits how often links can be sent
+func (u feedbackUseCase) RequestLink(payload entity.RequestFeedbackLinkPayload, ipAddress string) error {
+	err := u.loginThrottleUseCase.Check(entity.ThrottleActionCourseFeedback, payload.Email, ipAddress)
+	if err != nil {
+		return errs.New(errs.SameCode, "cannot request feedback link", err)
+	}
+
+	err = u.loginThrottleUseCase.Fail(entity.ThrottleActionCourseFeedback, payload.Email, ipAddress)
+	if err != nil {
+		return errs.New(errs.SameCode, "cannot count feedback link request", err)
+	}
+
+	course, err := u.courseUseCase.GetById(payload.CourseId)
+	if err != nil {
+		return errs.New(errs.SameCode, "cannot get course id %s to request feedback link", payload.CourseId, err)
+	} else if course == nil {
+		return errs.New(errs.ErrCourseNotFound, "course id %s not found while requesting feedback link", payload.CourseId)
+	}
+
+	student, err := u.getEnrolledStudent(payload.CourseId, payload.StudentId)
+	if err != nil {
+		return err
+	} else if !strings.EqualFold(student.Email, payload.Email) {
+		return errs.New(errs.ErrFeedbackNotEnrolled, "student id %s is not enrolled in course id %s", payload.StudentId, payload.CourseId)
+	}
+
+	err = u.checkNoFeedback(payload.CourseId, payload.StudentId)
+	if err != nil {
+		return err
+	}
+
+	token, err := generateSurveyToken()
+	if err != nil {
+		return errs.New(errs.ErrCreateFeedback, "cannot generate feedback token", err)
+	}
+
+	feedbackToken := &entity.FeedbackToken{
+		Id:        ulid.Make().String(),
+		CourseId:  payload.CourseId,
+		StudentId: payload.StudentId,
+		TokenHash: hashSurveyToken(token),
+		ExpiresAt: time.Now().Add(feedbackTokenMaxAge),
+	}
+
+	err = u.feedbackRepo.CreateToken(feedbackToken)
+	if err != nil {
+		return errs.New(errs.ErrCreateFeedback, "cannot create feedback token of student id %s", payload.StudentId, err)
+	}
+
+	// the link goes to the email on record, so only the student can follow it
+	link := fmt.Sprintf("%s/feedbacks/respond?token=%s", strings.TrimRight(u.clientConfig.BaseUrl, "/"), url.QueryEscape(token))
+	err = u.mailUseCase.SendFeedbackLinkEmail(student.Email, course.Name, link, feedbackToken.ExpiresAt)
+	if err != nil {
+		return errs.New(errs.SameCode, "cannot send feedback link to student id %s", payload.StudentId, err)
+	}
+
+	return nil
+}
+
+func (u feedbackUseCase) Create(payload entity.CreateFeedbackPayload, ipAddress string) error {
+	err := u.loginThrottleUseCase.Check(entity.ThrottleActionCourseFeedback, "", ipAddress)
+	if err != nil {
+		return errs.New(errs.SameCode, "cannot create feedback", err)
+	}
+
+	token, err := u.getOpenToken(payload.Token)
+	if err != nil {
+		if errs.HasCode(err, errs.ErrFeedbackTokenNotFound) {
+			failErr := u.loginThrottleUseCase.Fail(entity.ThrottleActionCourseFeedback, "", ipAddress)
+			if failErr != nil {
+				return errs.New(errs.SameCode, "cannot count failed feedback", failErr)
+			}
+		}
+		return err
+	}
+
+	// the student may have withdrawn or given feedback through another link since the link was sent
+	_, err = u.getEnrolledStudent(token.CourseId, token.StudentId)
+	if err != nil {
+		return err
+	}
+
+	err = u.checkNoFeedback(token.CourseId, token.StudentId)
+	if err != nil {
+		return err
+	}
+
+	feedback := &entity.Feedback{
+		Id:        ulid.Make().String(),
+		CourseId:  token.CourseId,
+		StudentId: token.StudentId,
+		Comments:  payload.Comments,
+		Rating:    payload.Rating,
+		Date:      time.Now(),
+	}
+
+	accepted, err := u.feedbackRepo.CreateWithToken(token.Id, feedback)
+	if err != nil {
+		return errs.New(errs.ErrCreateFeedback, "cannot create feedback", err)
+	} else if !accepted {
+		return errs.New(errs.ErrFeedbackTokenUsed, "feedback link has already been used")
+	}
+
+	return nil
+}
+
+func (u feedbackUseCase) getOpenToken(token string) (*entity.FeedbackToken, error) {
+	feedbackToken, err := u.feedbackRepo.GetTokenByHash(hashSurveyToken(token))
+	if err != nil {
+		return nil, errs.New(errs.ErrQueryFeedback, "cannot get feedback token", err)
+	} else if feedbackToken == nil {
+		return nil, errs.New(errs.ErrFeedbackTokenNotFound, "feedback link not found")
+	}
+
+	if feedbackToken.UsedAt != nil {
+		return nil, errs.New(errs.ErrFeedbackTokenUsed, "feedback link has already been used")
+	}
+
+	if time.Now().After(feedbackToken.ExpiresAt) {
+		return nil, errs.New(errs.ErrFeedbackTokenExpired, "feedback link has expired")
+	}
+
+	return feedbackToken, nil
+}
+
+func (u feedbackUseCase) getEnrolledStudent(courseId string, studentId string) (*entity.Student, error) {
+	student, err := u.studentUseCase.GetById(studentId)
+	if err != nil {
+		return nil, errs.New(errs.SameCode, "cannot get student id %s to give feedback", studentId, err)
+	} else if student == nil {
+		return nil, errs.New(errs.ErrFeedbackNotEnrolled, "student id %s is not enrolled in course id %s", studentId, courseId)
+	}
+
+	enrollStatus := entity.EnrollmentStatusEnroll
+	joinedStudentIds, err := u.enrollmentUseCase.FilterJoinedStudent([]string{studentId}, courseId, &enrollStatus)
+	if err != nil {
+		return nil, errs.New(errs.SameCode, "cannot check enrollment of student id %s", studentId, err)
+	} else if len(joinedStudentIds) == 0 {
+		return nil, errs.New(errs.ErrFeedbackNotEnrolled, "student id %s is not enrolled in course id %s", studentId, courseId)
+	}
+
+	return student, nil
+}
+
+func (u feedbackUseCase) checkNoFeedback(courseId string, studentId string) error {
+	existFeedback, err := u.feedbackRepo.GetByCourseIdAndStudentId(courseId, studentId)
+	if err != nil {
+		return errs.New(errs.ErrQueryFeedback, "cannot get feedback of student id %s", studentId, err)
+	} else if existFeedback != nil {
+		return errs.New(errs.ErrDupFeedback, "student id %s has already given feedback to course id %s", studentId, courseId)
+	}
+
+	return nil
+}
+
+func (u feedbackUseCase) GetCourseSummary(user entity.User, courseId string) (*entity.CourseFeedbackSummary, error) {
+	course, err := u.courseUseCase.GetById(courseId)
+	if err != nil {
+		return nil, errs.New(errs.SameCode, "cannot get course id %s to get feedback", courseId, err)
+	} else if course == nil {
+		return nil, errs.New(errs.ErrCourseNotFound, "course id %s not found while getting feedback", courseId)
+	}
+
+	isLecturer := false
+	for _, lecturer := range course.Lecturers {
+		if lecturer != nil && lecturer.Id == user.Id {
+			isLecturer = true
+			break
+		}
+	}
+
+	if !isLecturer && !user.IsRoles([]entity.UserRole{
+		entity.UserRoleHeadOfCurriculum,
+		entity.UserRoleModerator,
+		entity.UserRoleTABEEManager,
+		entity.UserRoleAUNQAManager,
+		entity.UserRoleABETManager,
+	}) {
+		return nil, errs.New(errs.ErrFeedbackPermission, "no permission to get feedback of course id %s", courseId)
+	}
+
+	return u.summarizeCourse(*course)
+}
+
+func (u feedbackUseCase) GetSummaryByCourseId(courseId string) (*entity.CourseFeedbackSummary, error) {
+	course, err := u.courseUseCase.GetById(courseId)
+	if err != nil {
+		return nil, errs.New(errs.SameCode, "cannot get course id %s to get feedback", courseId, err)
+	} else if course == nil {
+		return nil, errs.New(errs.ErrCourseNotFound, "course id %s not found while getting feedback", courseId)
+	}
+
+	return u.summarizeCourse(*course)
+}
+
+func (u feedbackUseCase) summarizeCourse(course entity.Course) (*entity.CourseFeedbackSummary, error) {
+	feedbacks, err := u.feedbackRepo.GetByCourseId(course.Id)
+	if err != nil {
+		return nil, errs.New(errs.ErrQueryFeedback, "cannot get feedbacks of course id %s", course.Id, err)
+	}
+
+	summary := summarizeFeedbacks(course, feedbacks)
+
+	return &summary, nil
+}
+
+func (u feedbackUseCase) GetLecturerSummaries(user entity.User, semesterId string) ([]entity.CourseFeedbackSummary, error) {
+	feedbacks, err := u.feedbackRepo.GetByLecturerId(user.Id, semesterId)
+	if err != nil {
+		return nil, errs.New(errs.ErrQueryFeedback, "cannot get feedbacks of lecturer id %s", user.Id, err)
+	}
+
+	courseById := make(map[string]entity.Course)
+	feedbacksByCourseId := make(map[string][]entity.Feedback)
+	for _, feedback := range feedbacks {
+		courseById[feedback.CourseId] = feedback.Course
+		feedbacksByCourseId[feedback.CourseId] = append(feedbacksByCourseId[feedback.CourseId], feedback)
+	}
+
+	summaries := make([]entity.CourseFeedbackSummary, 0, len(courseById))
+	for courseId, course := range courseById {
+		summaries = append(summaries, summarizeFeedbacks(course, feedbacksByCourseId[courseId]))
+	}
+
+	sort.Slice(summaries, func(i, j int) bool {
+		if summaries[i].Year != summaries[j].Year {
+			return summaries[i].Year > summaries[j].Year
+		}
+		if summaries[i].SemesterSequence != summaries[j].SemesterSequence {
+			return summaries[i].SemesterSequence > summaries[j].SemesterSequence
+		}
+		return summaries[i].CourseCode < summaries[j].CourseCode
+	})
+
+	return summaries, nil
+}
+
+// summarizeFeedbacks drops who gave each feedback and sorts the comments so their order does not follow submission time
+func summarizeFeedbacks(course entity.Course, feedbacks []entity.Feedback) entity.CourseFeedbackSummary {
+	summary := entity.CourseFeedbackSummary{
+		CourseId:         course.Id,
+		CourseCode:       course.Code,
+		CourseName:       course.Name,
+		SemesterId:       course.SemesterId,
+		Year:             course.Semester.Year,
+		SemesterSequence: course.Semester.SemesterSequence,
+		ResponseCount:    len(feedbacks),
+		Comments:         []string{},
+	}
+
+	countByRating := make(map[int]int)
+	totalRating := 0
+	for _, feedback := range feedbacks {
+		countByRating[feedback.Rating]++
+		totalRating += feedback.Rating
+
+		comment := strings.TrimSpace(feedback.Comments)
+		if comment != "" {
+			summary.Comments = append(summary.Comments, comment)
+		}
+	}
+
+	if len(feedbacks) > 0 {
+		summary.AverageRating = float64(totalRating) / float64(len(feedbacks))
+	}
+
+	summary.RatingHistogram = make([]entity.RatingCount, 0, 5)
+	for rating := 1; rating <= 5; rating++ {
+		summary.RatingHistogram = append(summary.RatingHistogram, entity.RatingCount{
+			Rating: rating,
+			Count:  countByRating[rating],
+		})
+	}
+
+	sort.Strings(summary.Comments)
+
+	return summary
+}
@@ -0,0 +1,134 @@
+package usecase
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/team-inu/inu-backyard/entity"
+	errs "github.com/team-inu/inu-backyard/entity/error"
+	"github.com/team-inu/inu-backyard/internal/config"
+)
+
+type stubFeedbackRepository struct {
+	entity.FeedbackRepository
+	tokens    []entity.FeedbackToken
+	feedbacks []entity.Feedback
+}
+
+func (r *stubFeedbackRepository) GetByCourseIdAndStudentId(courseId string, studentId string) (*entity.Feedback, error) {
+	for _, feedback := range r.feedbacks {
+		if feedback.CourseId == courseId && feedback.StudentId == studentId {
+			return &feedback, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *stubFeedbackRepository) CreateToken(token *entity.FeedbackToken) error {
+	r.tokens = append(r.tokens, *token)
+	return nil
+}
+
+func (r *stubFeedbackRepository) GetTokenByHash(tokenHash string) (*entity.FeedbackToken, error) {
+	for _, token := range r.tokens {
+		if token.TokenHash == tokenHash {
+			return &token, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *stubFeedbackRepository) CreateWithToken(tokenId string, feedback *entity.Feedback) (bool, error) {
+	for i := range r.tokens {
+		if r.tokens[i].Id == tokenId && r.tokens[i].UsedAt == nil {
+			now := time.Now()
+			r.tokens[i].UsedAt = &now
+			r.feedbacks = append(r.feedbacks, *feedback)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+type stubStudentUseCase struct {
+	entity.StudentUseCase
+}
+
+func (u *stubStudentUseCase) GetById(id string) (*entity.Student, error) {
+	return &entity.Student{Id: id, Email: id + "@student.example.com"}, nil
+}
+
+func (u *stubMailUseCase) SendFeedbackLinkEmail(to string, courseName string, link string, expiresAt time.Time) error {
+	u.feedbackLinks = append(u.feedbackLinks, link)
+	return nil
+}
+
+func TestFeedback(t *testing.T) {
+	newFeedbackUseCase := func() (entity.FeedbackUseCase, *stubFeedbackRepository, *stubMailUseCase) {
+		feedbackRepository := &stubFeedbackRepository{}
+		mailUseCase := &stubMailUseCase{}
+		loginThrottleUseCase := NewLoginThrottleUseCase(&stubLoginThrottleRepository{}, nil, mailUseCase, config.LoginThrottleConfig{MaxFailures: 2, IpMaxFailures: 2})
+		feedbackUseCase := NewFeedbackUseCase(feedbackRepository, &stubCourseUseCase{}, &stubStudentUseCase{}, &stubEnrollmentUseCase{}, mailUseCase, loginThrottleUseCase, config.ClientConfig{BaseUrl: "http://localhost:3000"})
+
+		return feedbackUseCase, feedbackRepository, mailUseCase
+	}
+
+	linkToken := func(t *testing.T, link string) string {
+		parsed, err := url.Parse(link)
+		assert.Nil(t, err)
+		return parsed.Query().Get("token")
+	}
+
+	t.Run("TestCreateWithLink", func(t *testing.T) {
+		feedbackUseCase, feedbackRepository, mailUseCase := newFeedbackUseCase()
+
+		err := feedbackUseCase.RequestLink(entity.RequestFeedbackLinkPayload{CourseId: "course", StudentId: "student", Email: "STUDENT@student.example.com"}, "127.0.0.1")
+		assert.Nil(t, err, "Expected no error while requesting link, got %v", err)
+		assert.Len(t, mailUseCase.feedbackLinks, 1, "Expected the link to be mailed")
+
+		token := linkToken(t, mailUseCase.feedbackLinks[0])
+		err = feedbackUseCase.Create(entity.CreateFeedbackPayload{Token: token, Rating: 4}, "127.0.0.1")
+		assert.Nil(t, err, "Expected no error while creating feedback, got %v", err)
+		assert.Len(t, feedbackRepository.feedbacks, 1)
+		assert.Equal(t, "student", feedbackRepository.feedbacks[0].StudentId, "Expected the feedback to belong to the student of the link")
+
+		err = feedbackUseCase.Create(entity.CreateFeedbackPayload{Token: token, Rating: 1}, "127.0.0.1")
+		assert.Equal(t, errs.ErrFeedbackTokenUsed, errorCode(err), "Expected the link to be single use")
+	})
+
+	t.Run("TestRequestLinkEmailMismatch", func(t *testing.T) {
+		feedbackUseCase, _, mailUseCase := newFeedbackUseCase()
+
+		err := feedbackUseCase.RequestLink(entity.RequestFeedbackLinkPayload{CourseId: "course", StudentId: "student", Email: "other@example.com"}, "127.0.0.1")
+		assert.Equal(t, errs.ErrFeedbackNotEnrolled, errorCode(err), "Expected an email of another student to be refused")
+		assert.Empty(t, mailUseCase.feedbackLinks)
+	})
+
+	t.Run("TestRequestLinkThrottled", func(t *testing.T) {
+		feedbackUseCase, _, mailUseCase := newFeedbackUseCase()
+
+		payload := entity.RequestFeedbackLinkPayload{CourseId: "course", StudentId: "student", Email: "student@student.example.com"}
+		for i := 0; i < 2; i++ {
+			err := feedbackUseCase.RequestLink(payload, "127.0.0.1")
+			assert.Nil(t, err)
+		}
+
+		err := feedbackUseCase.RequestLink(payload, "127.0.0.1")
+		assert.Equal(t, errs.ErrTooManyAttempts, errorCode(err), "Expected link requests to be throttled, got %v", err)
+		assert.Len(t, mailUseCase.feedbackLinks, 2)
+	})
+
+	t.Run("TestCreateUnknownTokenThrottled", func(t *testing.T) {
+		feedbackUseCase, _, _ := newFeedbackUseCase()
+
+		for i := 0; i < 2; i++ {
+			err := feedbackUseCase.Create(entity.CreateFeedbackPayload{Token: "guess", Rating: 5}, "127.0.0.1")
+			assert.Equal(t, errs.ErrFeedbackTokenNotFound, errorCode(err))
+		}
+
+		err := feedbackUseCase.Create(entity.CreateFeedbackPayload{Token: "guess", Rating: 5}, "127.0.0.1")
+		assert.Equal(t, errs.ErrTooManyAttempts, errorCode(err), "Expected guessed links to be throttled by ip address, got %v", err)
+	})
+}
@@ -0,0 +1,150 @@
+package usecase
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/team-inu/inu-backyard/entity"
+	errs "github.com/team-inu/inu-backyard/entity/error"
+	"github.com/team-inu/inu-backyard/internal/config"
+)
+
+type fileUseCase struct {
+	fileRepo  entity.FileRepository
+	fileStore entity.FileStore
+	config    config.StorageConfig
+}
+
+// zero values in config fall back to a 20 MiB file size limit
+func NewFileUseCase(
+	fileRepo entity.FileRepository,
+	fileStore entity.FileStore,
+	config config.StorageConfig,
+) entity.FileUseCase {
+	if config.MaxFileSize <= 0 {
+		config.MaxFileSize = 20 << 20
+	}
+
+	return &fileUseCase{
+		fileRepo:  fileRepo,
+		fileStore: fileStore,
+		config:    config,
+	}
+}
+
+// Save reads the whole content first, the size and checksum are needed before it is stored
+func (u fileUseCase) Save(prefix string, name string, contentType string, content io.Reader) (*entity.StoredFile, error) {
+	name = path.Base(strings.ReplaceAll(name, "\\", "/"))
+	if prefix == "" || name == "" || name == "." || name == "/" {
+		return nil, errs.New(errs.ErrInvalidFile, "file name %s is not valid", name)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(content, u.config.MaxFileSize+1))
+	if err != nil {
+		return nil, errs.New(errs.ErrCreateFile, "cannot read file %s", name, err)
+	} else if int64(len(data)) > u.config.MaxFileSize {
+		return nil, errs.New(errs.ErrFileTooLarge, "file %s is larger than %d bytes", name, u.config.MaxFileSize)
+	}
+
+	if contentType == "" {
+		contentType = http.DetectContentType(data)
+	}
+
+	checksum := sha256.Sum256(data)
+	id := ulid.Make().String()
+	file := &entity.StoredFile{
+		Id: id,
+		// the name stays in the metadata, keys only hold safe characters
+		Key:         prefix + "/" + id + strings.ToLower(path.Ext(name)),
+		Name:        name,
+		ContentType: contentType,
+		Size:        int64(len(data)),
+		Checksum:    hex.EncodeToString(checksum[:]),
+		CreatedAt:   time.Now(),
+	}
+
+	err = u.fileStore.Put(file.Key, file.ContentType, bytes.NewReader(data), file.Size)
+	if err != nil {
+		return nil, errs.New(errs.ErrCreateFile, "cannot store file %s", name, err)
+	}
+
+	err = u.fileRepo.Create(file)
+	if err != nil {
+		u.fileStore.Delete(file.Key)
+		return nil, errs.New(errs.ErrCreateFile, "cannot save metadata of file %s", name, err)
+	}
+
+	return file, nil
+}
+
+func (u fileUseCase) SaveLocal(prefix string, file entity.FileResponse) (*entity.StoredFile, error) {
+	content, err := os.Open(file.FilePath)
+	if err != nil {
+		return nil, errs.New(errs.ErrCreateFile, "cannot open file %s", file.FilePath, err)
+	}
+	defer content.Close()
+
+	return u.Save(prefix, file.FileName, file.FileType, content)
+}
+
+func (u fileUseCase) GetById(id string) (*entity.StoredFile, error) {
+	file, err := u.fileRepo.GetById(id)
+	if err != nil {
+		return nil, errs.New(errs.ErrQueryFile, "cannot get file id %s", id, err)
+	} else if file == nil {
+		return nil, errs.New(errs.ErrFileNotFound, "file id %s not found", id)
+	}
+
+	return file, nil
+}
+
+func (u fileUseCase) Open(id string) (*entity.StoredFile, io.ReadCloser, error) {
+	file, err := u.GetById(id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	content, err := u.fileStore.Open(file.Key)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil, errs.New(errs.ErrFileNotFound, "content of file id %s not found", id)
+	} else if err != nil {
+		return nil, nil, errs.New(errs.ErrQueryFile, "cannot open file id %s", id, err)
+	}
+
+	return file, content, nil
+}
+
+// Delete removes the contents before the metadata so a failure leaves nothing unreachable in the store
+func (u fileUseCase) Delete(ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	files, err := u.fileRepo.GetByIds(ids)
+	if err != nil {
+		return errs.New(errs.ErrQueryFile, "cannot get files to delete", err)
+	}
+
+	for _, file := range files {
+		err = u.fileStore.Delete(file.Key)
+		if err != nil {
+			return errs.New(errs.ErrDeleteFile, "cannot delete content of file id %s", file.Id, err)
+		}
+	}
+
+	err = u.fileRepo.Delete(ids)
+	if err != nil {
+		return errs.New(errs.ErrDeleteFile, "cannot delete files", err)
+	}
+
+	return nil
+}
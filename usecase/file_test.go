@@ -0,0 +1,108 @@
+package usecase
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/team-inu/inu-backyard/entity"
+	errs "github.com/team-inu/inu-backyard/entity/error"
+	"github.com/team-inu/inu-backyard/infrastructure/storage"
+	"github.com/team-inu/inu-backyard/internal/config"
+)
+
+type stubFileRepository struct {
+	entity.FileRepository
+	files map[string]entity.StoredFile
+}
+
+func (r *stubFileRepository) Create(file *entity.StoredFile) error {
+	if r.files == nil {
+		r.files = map[string]entity.StoredFile{}
+	}
+	r.files[file.Id] = *file
+	return nil
+}
+
+func (r *stubFileRepository) GetById(id string) (*entity.StoredFile, error) {
+	file, ok := r.files[id]
+	if !ok {
+		return nil, nil
+	}
+	return &file, nil
+}
+
+func (r *stubFileRepository) GetByIds(ids []string) ([]entity.StoredFile, error) {
+	files := []entity.StoredFile{}
+	for _, id := range ids {
+		if file, ok := r.files[id]; ok {
+			files = append(files, file)
+		}
+	}
+	return files, nil
+}
+
+func (r *stubFileRepository) Delete(ids []string) error {
+	for _, id := range ids {
+		delete(r.files, id)
+	}
+	return nil
+}
+
+func TestFile(t *testing.T) {
+	fileStore := storage.NewMemoryStore()
+	fileRepository := &stubFileRepository{}
+	fileUseCase := NewFileUseCase(fileRepository, fileStore, config.StorageConfig{MaxFileSize: 16})
+
+	t.Run("TestSave", func(t *testing.T) {
+		file, err := fileUseCase.Save("uploads", `C:\Users\somchai\รายงาน.XLSX`, "", strings.NewReader("hello"))
+		assert.Nil(t, err, "Expected no error while saving, got %v", err)
+		assert.Equal(t, "รายงาน.XLSX", file.Name, "Expected directories to be dropped from the name")
+		assert.Equal(t, "uploads/"+file.Id+".xlsx", file.Key, "Expected key without the original name")
+		assert.Equal(t, int64(5), file.Size)
+		assert.Equal(t, "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824", file.Checksum)
+		assert.Equal(t, "text/plain; charset=utf-8", file.ContentType, "Expected content type to be detected")
+
+		opened, content, err := fileUseCase.Open(file.Id)
+		assert.Nil(t, err, "Expected no error while opening, got %v", err)
+		data, _ := io.ReadAll(content)
+		content.Close()
+		assert.Equal(t, "hello", string(data))
+		assert.Equal(t, file.Checksum, opened.Checksum)
+	})
+
+	t.Run("TestSave_TooLarge", func(t *testing.T) {
+		_, err := fileUseCase.Save("uploads", "large.xlsx", "", strings.NewReader(strings.Repeat("a", 17)))
+		assert.Equal(t, errs.ErrFileTooLarge, errorCode(err), "Expected file over the limit to be refused, got %v", err)
+
+		_, err = fileUseCase.Save("uploads", "limit.xlsx", "", strings.NewReader(strings.Repeat("a", 16)))
+		assert.Nil(t, err, "Expected file at the limit to be saved, got %v", err)
+	})
+
+	t.Run("TestSave_StoreFailure", func(t *testing.T) {
+		failingStore := storage.NewMemoryStore()
+		failingStore.Err = errors.New("bucket is gone")
+		failingRepository := &stubFileRepository{}
+
+		_, err := NewFileUseCase(failingRepository, failingStore, config.StorageConfig{}).Save("uploads", "report.xlsx", "", strings.NewReader("hello"))
+		assert.Equal(t, errs.ErrCreateFile, errorCode(err), "Expected store error, got %v", err)
+		assert.Empty(t, failingRepository.files, "Expected no metadata without content")
+	})
+
+	t.Run("TestDelete", func(t *testing.T) {
+		ids := []string{}
+		for id := range fileRepository.files {
+			ids = append(ids, id)
+		}
+
+		err := fileUseCase.Delete(ids)
+		assert.Nil(t, err, "Expected no error while deleting, got %v", err)
+		assert.Empty(t, fileStore.Keys(), "Expected contents to be deleted")
+		assert.Empty(t, fileRepository.files, "Expected metadata to be deleted")
+
+		_, _, err = fileUseCase.Open(ids[0])
+		assert.Equal(t, errs.ErrFileNotFound, errorCode(err), "Expected deleted file to be not found, got %v", err)
+	})
+}
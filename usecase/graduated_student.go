@@ -0,0 +1,282 @@
+package usecase
+
+import (
+	"sort"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/team-inu/inu-backyard/entity"
+	errs "github.com/team-inu/inu-backyard/entity/error"
+	slice "github.com/team-inu/inu-backyard/internal/utils/slice"
+)
+
+type graduatedStudentUseCase struct {
+	graduatedStudentRepo entity.GraduatedStudentRepository
+	studentUseCase       entity.StudentUseCase
+	programmeUseCase     entity.ProgrammeUseCase
+}
+
+func NewGraduatedStudentUseCase(graduatedStudentRepo entity.GraduatedStudentRepository, studentUseCase entity.StudentUseCase, programmeUseCase entity.ProgrammeUseCase) entity.GraduatedStudentUseCase {
+	return &graduatedStudentUseCase{
+		graduatedStudentRepo: graduatedStudentRepo,
+		studentUseCase:       studentUseCase,
+		programmeUseCase:     programmeUseCase,
+	}
+}
+
+func (u graduatedStudentUseCase) GetAll() ([]entity.GraduatedStudent, error) {
+	graduatedStudents, err := u.graduatedStudentRepo.GetAll()
+	if err != nil {
+		return nil, errs.New(errs.ErrQueryGraduatedStudent, "cannot get all graduated students", err)
+	}
+
+	return graduatedStudents, nil
+}
+
+func (u graduatedStudentUseCase) GetByParams(programmeId string, year string) ([]entity.GraduatedStudent, error) {
+	graduatedStudents, err := u.graduatedStudentRepo.GetByParams(programmeId, year)
+	if err != nil {
+		return nil, errs.New(errs.ErrQueryGraduatedStudent, "cannot get graduated students by programme id %s and year %s", programmeId, year, err)
+	}
+
+	return graduatedStudents, nil
+}
+
+func (u graduatedStudentUseCase) GetById(id string) (*entity.GraduatedStudent, error) {
+	graduatedStudent, err := u.graduatedStudentRepo.GetById(id)
+	if err != nil {
+		return nil, errs.New(errs.ErrQueryGraduatedStudent, "cannot get graduated student by id %s", id, err)
+	}
+
+	return graduatedStudent, nil
+}
+
+func (u graduatedStudentUseCase) Create(payload entity.CreateGraduatedStudentPayload) (*entity.GraduatedStudent, error) {
+	student, err := u.studentUseCase.GetById(payload.StudentId)
+	if err != nil {
+		return nil, errs.New(errs.SameCode, "cannot get student id %s to graduate", payload.StudentId, err)
+	} else if student == nil {
+		return nil, errs.New(errs.ErrStudentNotFound, "student id %s not found while graduating", payload.StudentId)
+	}
+
+	existedIds, err := u.graduatedStudentRepo.FilterExisted([]string{payload.StudentId})
+	if err != nil {
+		return nil, errs.New(errs.ErrQueryGraduatedStudent, "cannot check graduation of student id %s", payload.StudentId, err)
+	} else if len(existedIds) > 0 {
+		return nil, errs.New(errs.ErrDupGraduatedStudent, "student id %s has already graduated", payload.StudentId)
+	}
+
+	graduatedStudent := &entity.GraduatedStudent{
+		Id:                 ulid.Make().String(),
+		StudentId:          payload.StudentId,
+		Year:               payload.Year,
+		Workplace:          payload.Workplace,
+		Remarks:            payload.Remarks,
+		EmploymentStatus:   payload.EmploymentStatus,
+		MonthsToEmployment: payload.MonthsToEmployment,
+		SalaryBand:         payload.SalaryBand,
+	}
+
+	err = u.graduatedStudentRepo.Create(graduatedStudent)
+	if err != nil {
+		return nil, errs.New(errs.ErrCreateGraduatedStudent, "cannot create graduated student", err)
+	}
+
+	return graduatedStudent, nil
+}
+
+func (u graduatedStudentUseCase) GraduateCohort(payload entity.GraduateCohortPayload) (*entity.GraduateCohortResult, error) {
+	programme, err := u.programmeUseCase.GetById(payload.ProgrammeId)
+	if err != nil {
+		return nil, errs.New(errs.SameCode, "cannot get programme id %s to graduate cohort", payload.ProgrammeId, err)
+	} else if programme == nil {
+		return nil, errs.New(errs.ErrProgrammeNotFound, "programme id %s not found while graduating cohort", payload.ProgrammeId)
+	}
+
+	// a negative limit disables paging
+	cohort, err := u.studentUseCase.GetByParams("", &entity.Student{ProgrammeId: payload.ProgrammeId, Year: payload.StudentYear}, -1, 0)
+	if err != nil {
+		return nil, errs.New(errs.SameCode, "cannot get students of cohort %s", payload.StudentYear, err)
+	}
+
+	cohortIds := []string{}
+	if cohort != nil {
+		for _, student := range cohort.Students {
+			cohortIds = append(cohortIds, student.Id)
+		}
+	}
+
+	studentIds := cohortIds
+	if len(payload.StudentIds) > 0 {
+		nonCohortIds := slice.Subtraction(payload.StudentIds, cohortIds)
+		if len(nonCohortIds) > 0 {
+			return nil, errs.New(errs.ErrStudentNotFound, "students not found in cohort %s: %v", payload.StudentYear, nonCohortIds)
+		}
+
+		studentIds = payload.StudentIds
+	}
+
+	result := &entity.GraduateCohortResult{
+		GraduatedStudentIds: []string{},
+		SkippedStudentIds:   []string{},
+	}
+	if len(studentIds) == 0 {
+		return result, nil
+	}
+
+	existedIds, err := u.graduatedStudentRepo.FilterExisted(studentIds)
+	if err != nil {
+		return nil, errs.New(errs.ErrQueryGraduatedStudent, "cannot check graduation of cohort %s", payload.StudentYear, err)
+	}
+
+	newIds := slice.Subtraction(studentIds, existedIds)
+	result.SkippedStudentIds = slice.Intersection(studentIds, existedIds)
+	if len(newIds) == 0 {
+		return result, nil
+	}
+
+	graduatedStudents := make([]entity.GraduatedStudent, 0, len(newIds))
+	for _, studentId := range newIds {
+		graduatedStudents = append(graduatedStudents, entity.GraduatedStudent{
+			Id:        ulid.Make().String(),
+			StudentId: studentId,
+			Year:      payload.GraduationYear,
+		})
+	}
+
+	err = u.graduatedStudentRepo.CreateMany(graduatedStudents)
+	if err != nil {
+		return nil, errs.New(errs.ErrCreateGraduatedStudent, "cannot graduate cohort %s", payload.StudentYear, err)
+	}
+
+	result.GraduatedStudentIds = newIds
+
+	return result, nil
+}
+
+func (u graduatedStudentUseCase) Update(id string, payload entity.UpdateGraduatedStudentPayload) error {
+	existGraduatedStudent, err := u.GetById(id)
+	if err != nil {
+		return errs.New(errs.SameCode, "cannot get graduated student id %s to update", id, err)
+	} else if existGraduatedStudent == nil {
+		return errs.New(errs.ErrGraduatedStudentNotFound, "cannot get graduated student id %s to update", id)
+	}
+
+	err = u.graduatedStudentRepo.Update(id, &entity.GraduatedStudent{
+		Year:               payload.Year,
+		Workplace:          payload.Workplace,
+		Remarks:            payload.Remarks,
+		EmploymentStatus:   payload.EmploymentStatus,
+		MonthsToEmployment: payload.MonthsToEmployment,
+		SalaryBand:         payload.SalaryBand,
+	})
+	if err != nil {
+		return errs.New(errs.ErrUpdateGraduatedStudent, "cannot update graduated student by id %s", id, err)
+	}
+
+	return nil
+}
+
+func (u graduatedStudentUseCase) Delete(id string) error {
+	existGraduatedStudent, err := u.GetById(id)
+	if err != nil {
+		return errs.New(errs.SameCode, "cannot get graduated student id %s to delete", id, err)
+	} else if existGraduatedStudent == nil {
+		return errs.New(errs.ErrGraduatedStudentNotFound, "cannot get graduated student id %s to delete", id)
+	}
+
+	err = u.graduatedStudentRepo.Delete(id)
+	if err != nil {
+		return errs.New(errs.ErrDeleteGraduatedStudent, "cannot delete graduated student by id %s", id, err)
+	}
+
+	return nil
+}
+
+func (u graduatedStudentUseCase) GetAlumniSummary(programmeId string) (*entity.AlumniSummary, error) {
+	programme, err := u.programmeUseCase.GetById(programmeId)
+	if err != nil {
+		return nil, errs.New(errs.SameCode, "cannot get programme id %s to get alumni summary", programmeId, err)
+	} else if programme == nil {
+		return nil, errs.New(errs.ErrProgrammeNotFound, "programme id %s not found while getting alumni summary", programmeId)
+	}
+
+	graduatedStudents, err := u.GetByParams(programmeId, "")
+	if err != nil {
+		return nil, errs.New(errs.SameCode, "cannot get graduated students of programme id %s", programmeId, err)
+	}
+
+	graduatedStudentsByYear := make(map[string][]entity.GraduatedStudent)
+	for _, graduatedStudent := range graduatedStudents {
+		graduatedStudentsByYear[graduatedStudent.Year] = append(graduatedStudentsByYear[graduatedStudent.Year], graduatedStudent)
+	}
+
+	years := make([]string, 0, len(graduatedStudentsByYear))
+	for year := range graduatedStudentsByYear {
+		years = append(years, year)
+	}
+	sort.Strings(years)
+
+	yearSummaries := make([]entity.AlumniYearSummary, 0, len(years))
+	for _, year := range years {
+		yearSummaries = append(yearSummaries, entity.AlumniYearSummary{
+			Year:          year,
+			AlumniOutcome: summarizeAlumniOutcome(graduatedStudentsByYear[year]),
+		})
+	}
+
+	return &entity.AlumniSummary{
+		ProgrammeId:   programme.Id,
+		ProgrammeName: programme.NameEN,
+		AlumniOutcome: summarizeAlumniOutcome(graduatedStudents),
+		Years:         yearSummaries,
+	}, nil
+}
+
+// summarizeAlumniOutcome computes rates over graduates who reported an employment status
+func summarizeAlumniOutcome(graduatedStudents []entity.GraduatedStudent) entity.AlumniOutcome {
+	outcome := entity.AlumniOutcome{
+		TotalGraduates: len(graduatedStudents),
+	}
+
+	salaryBandCount := make(map[entity.SalaryBand]int)
+	totalMonths := 0
+	monthsCount := 0
+	for _, graduatedStudent := range graduatedStudents {
+		switch graduatedStudent.EmploymentStatus {
+		case entity.EmploymentStatusEmployed:
+			outcome.Employed++
+			if graduatedStudent.MonthsToEmployment != nil {
+				totalMonths += *graduatedStudent.MonthsToEmployment
+				monthsCount++
+			}
+			if graduatedStudent.SalaryBand != "" {
+				salaryBandCount[graduatedStudent.SalaryBand]++
+			}
+		case entity.EmploymentStatusFurtherStudy:
+			outcome.FurtherStudy++
+		case entity.EmploymentStatusSeeking:
+			outcome.Seeking++
+		}
+	}
+
+	outcome.Reported = outcome.Employed + outcome.FurtherStudy + outcome.Seeking
+	if outcome.Reported > 0 {
+		outcome.EmploymentRate = float64(outcome.Employed) / float64(outcome.Reported) * 100
+		outcome.FurtherStudyRate = float64(outcome.FurtherStudy) / float64(outcome.Reported) * 100
+	}
+
+	if monthsCount > 0 {
+		averageMonths := float64(totalMonths) / float64(monthsCount)
+		outcome.AverageMonthsToEmployment = &averageMonths
+	}
+
+	outcome.SalaryBands = make([]entity.SalaryBandCount, 0, len(entity.SalaryBands))
+	for _, band := range entity.SalaryBands {
+		outcome.SalaryBands = append(outcome.SalaryBands, entity.SalaryBandCount{
+			SalaryBand: band,
+			Count:      salaryBandCount[band],
+		})
+	}
+
+	return outcome
+}
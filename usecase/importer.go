@@ -7,6 +7,7 @@ import (
 	"github.com/team-inu/inu-backyard/entity"
 	errs "github.com/team-inu/inu-backyard/entity/error"
 	"github.com/team-inu/inu-backyard/repository"
+	"go.uber.org/zap"
 )
 
 type ImporterUseCase struct {
@@ -18,6 +19,9 @@ type ImporterUseCase struct {
 	programLearningOutcomeUseCase entity.ProgramLearningOutcomeUseCase
 	courseLearningOutcomeUseCase  entity.CourseLearningOutcomeUseCase
 	userUseCase                   entity.UserUseCase
+	notificationUseCase           entity.NotificationUseCase
+	milestoneUseCase              entity.MilestoneUseCase
+	logger                        *zap.Logger
 }
 
 func NewImporterUseCase(
@@ -29,6 +33,9 @@ func NewImporterUseCase(
 	programLearningOutcomeUseCase entity.ProgramLearningOutcomeUseCase,
 	courseLearningOutcomeUseCase entity.CourseLearningOutcomeUseCase,
 	userUseCase entity.UserUseCase,
+	notificationUseCase entity.NotificationUseCase,
+	milestoneUseCase entity.MilestoneUseCase,
+	logger *zap.Logger,
 ) ImporterUseCase {
 	return ImporterUseCase{
 		importerRepository:            importerRepository,
@@ -39,6 +46,9 @@ func NewImporterUseCase(
 		programLearningOutcomeUseCase: programLearningOutcomeUseCase,
 		courseLearningOutcomeUseCase:  courseLearningOutcomeUseCase,
 		userUseCase:                   userUseCase,
+		notificationUseCase:           notificationUseCase,
+		milestoneUseCase:              milestoneUseCase,
+		logger:                        logger,
 	}
 }
 
@@ -107,6 +117,15 @@ func (u ImporterUseCase) UpdateOrCreate(
 		}
 	}
 
+	if !user.IsRoles([]entity.UserRole{entity.UserRoleHeadOfCurriculum}) {
+		isLocked, err := u.milestoneUseCase.IsScoreEntryLocked(course.SemesterId)
+		if err != nil {
+			return errs.New(errs.SameCode, "cannot check score entry lock while import course", err)
+		} else if isLocked {
+			return errs.New(errs.ErrScoreEntryLocked, "score entry of semester id %s is closed", course.SemesterId)
+		}
+	}
+
 	// prepare old data to delete
 	oldAssignments, err := u.assignmentUseCase.GetByCourseId(courseId)
 	if err != nil {
@@ -279,8 +298,70 @@ func (u ImporterUseCase) UpdateOrCreate(
 
 		isDelete,
 	)
+	if err != nil {
+		return err
+	}
+
+	u.notifyImported(course, user, studentIds, assignmentGroups)
+
+	return nil
+}
+
+// notifyImported only logs failures since the import is already saved
+func (u ImporterUseCase) notifyImported(course *entity.Course, user *entity.User, studentIds []string, assignmentGroups []ImportAssignmentGroup) {
+	err := u.notificationUseCase.Notify(courseLecturerIds(course, user.Id), entity.NotificationEvent{
+		Type:        entity.NotificationTypeImportCompleted,
+		Title:       fmt.Sprintf("%s %s was imported", course.Code, course.Name),
+		Message:     fmt.Sprintf("%s %s imported %d students and %d assignment groups.", user.FirstNameEN, user.LastNameEN, len(studentIds), len(assignmentGroups)),
+		Link:        "/courses/" + course.Id,
+		ReferenceId: course.Id,
+	})
+	if err != nil {
+		u.logger.Error("Cannot notify import", zap.String("courseId", course.Id), zap.Error(err))
+	}
+
+	students, assignments := countMissingScores(studentIds, assignmentGroups)
+	if students == 0 {
+		return
+	}
+
+	err = u.notificationUseCase.Notify(courseLecturerIds(course, ""), entity.NotificationEvent{
+		Type:        entity.NotificationTypeMissingScores,
+		Title:       fmt.Sprintf("Missing scores in %s %s", course.Code, course.Name),
+		Message:     fmt.Sprintf("%d enrolled students are missing scores in %d assignments.", students, assignments),
+		Link:        "/courses/" + course.Id,
+		ReferenceId: course.Id,
+	})
+	if err != nil {
+		u.logger.Error("Cannot notify missing scores", zap.String("courseId", course.Id), zap.Error(err))
+	}
+}
+
+// countMissingScores returns how many students lack a score and in how many assignments
+func countMissingScores(studentIds []string, assignmentGroups []ImportAssignmentGroup) (int, int) {
+	missingStudents := map[string]bool{}
+	missingAssignments := 0
+	for _, assignmentGroup := range assignmentGroups {
+		for _, assignment := range assignmentGroup.Assignments {
+			scored := make(map[string]bool, len(assignment.Scores))
+			for _, score := range assignment.Scores {
+				scored[score.StudentId] = true
+			}
+
+			isMissing := false
+			for _, studentId := range studentIds {
+				if !scored[studentId] {
+					missingStudents[studentId] = true
+					isMissing = true
+				}
+			}
+			if isMissing {
+				missingAssignments++
+			}
+		}
+	}
 
-	return err
+	return len(missingStudents), missingAssignments
 }
 
 func (u ImporterUseCase) Delete() {
@@ -0,0 +1,207 @@
+package usecase
+
+import (
+	"strings"
+	"time"
+
+	"github.com/team-inu/inu-backyard/entity"
+	errs "github.com/team-inu/inu-backyard/entity/error"
+	"github.com/team-inu/inu-backyard/internal/config"
+)
+
+var throttleActions = []entity.ThrottleAction{
+	entity.ThrottleActionSignIn,
+	entity.ThrottleActionForgotPassword,
+	entity.ThrottleActionResetPassword,
+}
+
+type loginThrottleUseCase struct {
+	loginThrottleRepo entity.LoginThrottleRepository
+	userUseCase       entity.UserUseCase
+	mailUseCase       entity.MailUseCase
+	config            config.LoginThrottleConfig
+}
+
+// zero values in config fall back to 5 failures per email, 20 per ip address, a 15 minute window and 1 minute to 1 day lockouts
+func NewLoginThrottleUseCase(
+	loginThrottleRepo entity.LoginThrottleRepository,
+	userUseCase entity.UserUseCase,
+	mailUseCase entity.MailUseCase,
+	config config.LoginThrottleConfig,
+) entity.LoginThrottleUseCase {
+	if config.MaxFailures <= 0 {
+		config.MaxFailures = 5
+	}
+	if config.IpMaxFailures <= 0 {
+		config.IpMaxFailures = 20
+	}
+	if config.Window <= 0 {
+		config.Window = 900
+	}
+	if config.BaseLockout <= 0 {
+		config.BaseLockout = 60
+	}
+	if config.MaxLockout <= 0 {
+		config.MaxLockout = 86400
+	}
+
+	return &loginThrottleUseCase{
+		loginThrottleRepo: loginThrottleRepo,
+		userUseCase:       userUseCase,
+		mailUseCase:       mailUseCase,
+		config:            config,
+	}
+}
+
+func (u loginThrottleUseCase) Check(action entity.ThrottleAction, email string, ipAddress string) error {
+	keys := []string{}
+	if email != "" {
+		keys = append(keys, emailThrottleKey(action, email))
+	}
+	if ipAddress != "" {
+		keys = append(keys, ipThrottleKey(action, ipAddress))
+	}
+
+	throttles, err := u.loginThrottleRepo.GetByKeys(keys)
+	if err != nil {
+		return errs.New(errs.ErrUpdateThrottle, "cannot get login throttles", err)
+	}
+
+	now := time.Now()
+	for _, throttle := range throttles {
+		if throttle.LockedUntil != nil && now.Before(*throttle.LockedUntil) {
+			return errs.New(errs.ErrTooManyAttempts, "too many attempts, try again after %s", throttle.LockedUntil.Format(time.RFC3339))
+		}
+	}
+
+	return nil
+}
+
+func (u loginThrottleUseCase) Fail(action entity.ThrottleAction, email string, ipAddress string) error {
+	if email != "" {
+		lockedUntil, err := u.fail(emailThrottleKey(action, email), u.config.MaxFailures)
+		if err != nil {
+			return err
+		}
+
+		if lockedUntil != nil && action == entity.ThrottleActionSignIn {
+			err = u.notifyLocked(email, *lockedUntil)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if ipAddress == "" {
+		return nil
+	}
+
+	_, err := u.fail(ipThrottleKey(action, ipAddress), u.config.IpMaxFailures)
+	return err
+}
+
+func (u loginThrottleUseCase) Succeed(action entity.ThrottleAction, email string) error {
+	err := u.loginThrottleRepo.DeleteByKeys([]string{emailThrottleKey(action, email)})
+	if err != nil {
+		return errs.New(errs.ErrUpdateThrottle, "cannot reset login throttle of %s", email, err)
+	}
+
+	return nil
+}
+
+func (u loginThrottleUseCase) Unlock(email string) error {
+	keys := make([]string, 0, len(throttleActions))
+	for _, action := range throttleActions {
+		keys = append(keys, emailThrottleKey(action, email))
+	}
+
+	err := u.loginThrottleRepo.DeleteByKeys(keys)
+	if err != nil {
+		return errs.New(errs.ErrUpdateThrottle, "cannot unlock %s", email, err)
+	}
+
+	return nil
+}
+
+// DeleteStale keeps throttles recent enough to still count toward the backoff
+func (u loginThrottleUseCase) DeleteStale() (int64, error) {
+	deleted, err := u.loginThrottleRepo.DeleteStale(time.Now().Add(-time.Duration(u.config.MaxLockout) * time.Second))
+	if err != nil {
+		return 0, errs.New(errs.ErrUpdateThrottle, "cannot delete stale login throttles", err)
+	}
+
+	return deleted, nil
+}
+
+// fail counts a failure of the key and returns the lockout end when the failure locks it
+func (u loginThrottleUseCase) fail(key string, maxFailures int) (*time.Time, error) {
+	var lockedUntil *time.Time
+
+	_, err := u.loginThrottleRepo.Update(key, func(throttle *entity.LoginThrottle) {
+		now := time.Now()
+		if now.Sub(throttle.LastFailedAt) > time.Duration(u.config.Window)*time.Second {
+			throttle.Failures = 0
+		}
+		if now.Sub(throttle.LastFailedAt) > time.Duration(u.config.MaxLockout)*time.Second {
+			throttle.LockCount = 0
+		}
+
+		throttle.Failures++
+		throttle.LastFailedAt = now
+
+		if throttle.Failures < maxFailures {
+			return
+		}
+
+		until := now.Add(u.lockoutDuration(throttle.LockCount))
+		throttle.LockedUntil = &until
+		throttle.LockCount++
+		throttle.Failures = 0
+		lockedUntil = &until
+	})
+	if err != nil {
+		return nil, errs.New(errs.ErrUpdateThrottle, "cannot count failed attempt", err)
+	}
+
+	return lockedUntil, nil
+}
+
+// lockoutDuration doubles the base lockout for each previous lockout
+func (u loginThrottleUseCase) lockoutDuration(lockCount int) time.Duration {
+	duration := time.Duration(u.config.BaseLockout) * time.Second
+	maxDuration := time.Duration(u.config.MaxLockout) * time.Second
+
+	for i := 0; i < lockCount && duration < maxDuration; i++ {
+		duration *= 2
+	}
+
+	if duration > maxDuration {
+		return maxDuration
+	}
+	return duration
+}
+
+// notifyLocked only mails existing users, so guessed emails cannot be used to send mail
+func (u loginThrottleUseCase) notifyLocked(email string, lockedUntil time.Time) error {
+	user, err := u.userUseCase.GetByEmail(email)
+	if err != nil {
+		return errs.New(errs.SameCode, "cannot get user to notify lockout", err)
+	} else if user == nil {
+		return nil
+	}
+
+	err = u.mailUseCase.SendAccountLockedEmail(user.Email, lockedUntil)
+	if err != nil {
+		return errs.New(errs.SameCode, "cannot send lockout email", err)
+	}
+
+	return nil
+}
+
+func emailThrottleKey(action entity.ThrottleAction, email string) string {
+	return string(action) + ":email:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+func ipThrottleKey(action entity.ThrottleAction, ipAddress string) string {
+	return string(action) + ":ip:" + ipAddress
+}
@@ -0,0 +1,123 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/team-inu/inu-backyard/entity"
+	errs "github.com/team-inu/inu-backyard/entity/error"
+	"github.com/team-inu/inu-backyard/internal/config"
+)
+
+type stubLoginThrottleRepository struct {
+	entity.LoginThrottleRepository
+	throttles map[string]*entity.LoginThrottle
+}
+
+func (r *stubLoginThrottleRepository) GetByKeys(keys []string) ([]entity.LoginThrottle, error) {
+	throttles := []entity.LoginThrottle{}
+	for _, key := range keys {
+		if throttle, ok := r.throttles[key]; ok {
+			throttles = append(throttles, *throttle)
+		}
+	}
+	return throttles, nil
+}
+
+func (r *stubLoginThrottleRepository) Update(key string, update func(throttle *entity.LoginThrottle)) (*entity.LoginThrottle, error) {
+	if r.throttles == nil {
+		r.throttles = map[string]*entity.LoginThrottle{}
+	}
+	if _, ok := r.throttles[key]; !ok {
+		r.throttles[key] = &entity.LoginThrottle{Key: key, LastFailedAt: time.Now()}
+	}
+
+	update(r.throttles[key])
+	return r.throttles[key], nil
+}
+
+func (r *stubLoginThrottleRepository) DeleteByKeys(keys []string) error {
+	for _, key := range keys {
+		delete(r.throttles, key)
+	}
+	return nil
+}
+
+type stubMailUseCase struct {
+	entity.MailUseCase
+	lockedEmails  []string
+	feedbackLinks []string
+}
+
+func (u *stubMailUseCase) SendAccountLockedEmail(to string, lockedUntil time.Time) error {
+	u.lockedEmails = append(u.lockedEmails, to)
+	return nil
+}
+
+func TestLoginThrottle(t *testing.T) {
+	loginThrottleRepository := &stubLoginThrottleRepository{}
+	userUseCase := &stubUserUseCase{users: []entity.User{{Id: "user-1", Email: "lecturer@example.com"}}}
+	mailUseCase := &stubMailUseCase{}
+	loginThrottleUseCase := NewLoginThrottleUseCase(loginThrottleRepository, userUseCase, mailUseCase, config.LoginThrottleConfig{
+		MaxFailures:   3,
+		IpMaxFailures: 5,
+		BaseLockout:   60,
+		MaxLockout:    200,
+	})
+
+	failUntilLocked := func(email string, ipAddress string) {
+		for i := 0; i < 3; i++ {
+			err := loginThrottleUseCase.Fail(entity.ThrottleActionSignIn, email, ipAddress)
+			assert.Nil(t, err, "Expected no error while counting failure, got %v", err)
+		}
+	}
+
+	t.Run("TestLockAfterFailures", func(t *testing.T) {
+		err := loginThrottleUseCase.Check(entity.ThrottleActionSignIn, "lecturer@example.com", "10.0.0.1")
+		assert.Nil(t, err, "Expected no lock before failures, got %v", err)
+
+		failUntilLocked("lecturer@example.com", "10.0.0.1")
+
+		err = loginThrottleUseCase.Check(entity.ThrottleActionSignIn, "Lecturer@example.com", "10.0.0.2")
+		assert.Equal(t, errs.ErrTooManyAttempts, errorCode(err), "Expected email to be locked from any address, got %v", err)
+		assert.Equal(t, []string{"lecturer@example.com"}, mailUseCase.lockedEmails, "Expected lockout email to the user")
+
+		err = loginThrottleUseCase.Check(entity.ThrottleActionResetPassword, "lecturer@example.com", "10.0.0.1")
+		assert.Nil(t, err, "Expected other actions not to be locked, got %v", err)
+	})
+
+	t.Run("TestExponentialBackoff", func(t *testing.T) {
+		key := emailThrottleKey(entity.ThrottleActionSignIn, "lecturer@example.com")
+		expected := []time.Duration{2 * time.Minute, 200 * time.Second}
+
+		for _, duration := range expected {
+			failUntilLocked("lecturer@example.com", "10.0.0.1")
+
+			lockedFor := time.Until(*loginThrottleRepository.throttles[key].LockedUntil)
+			assert.InDelta(t, duration.Seconds(), lockedFor.Seconds(), 2, "Expected lockout to double up to the max")
+		}
+	})
+
+	t.Run("TestNoEmailForUnknownUser", func(t *testing.T) {
+		failUntilLocked("stranger@example.com", "")
+		assert.Len(t, mailUseCase.lockedEmails, 3, "Expected no lockout email for an unknown email")
+	})
+
+	t.Run("TestIpLock", func(t *testing.T) {
+		for i := 0; i < 5; i++ {
+			loginThrottleUseCase.Fail(entity.ThrottleActionSignIn, "guess"+string(rune('a'+i))+"@example.com", "10.0.0.9")
+		}
+
+		err := loginThrottleUseCase.Check(entity.ThrottleActionSignIn, "another@example.com", "10.0.0.9")
+		assert.Equal(t, errs.ErrTooManyAttempts, errorCode(err), "Expected address to be locked across emails, got %v", err)
+	})
+
+	t.Run("TestUnlock", func(t *testing.T) {
+		err := loginThrottleUseCase.Unlock("lecturer@example.com")
+		assert.Nil(t, err, "Expected no error while unlocking, got %v", err)
+
+		err = loginThrottleUseCase.Check(entity.ThrottleActionSignIn, "lecturer@example.com", "10.0.0.2")
+		assert.Nil(t, err, "Expected email to be unlocked, got %v", err)
+	})
+}
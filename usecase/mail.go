@@ -1,54 +1,394 @@
 package usecase
 
 import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/oklog/ulid/v2"
 	"github.com/team-inu/inu-backyard/entity"
 	errs "github.com/team-inu/inu-backyard/entity/error"
+	"github.com/team-inu/inu-backyard/internal/config"
 	"github.com/team-inu/inu-backyard/internal/utils"
+)
 
-	"github.com/team-inu/inu-backyard/infrastructure/mail"
+const (
+	resetTokenMaxAge = 15 * time.Minute
+	// codes tried before the token is locked and a new one must be requested
+	maxResetTokenAttempts = 5
+	// tokens an email can request within resetTokenRequestWindow
+	maxResetTokenRequests   = 3
+	resetTokenRequestWindow = time.Hour
+	// a claimed mail is picked up again after the lease when the worker stopped while sending it
+	outboxLease = 10 * time.Minute
 )
 
 type MailUseCase struct {
 	MailRepository entity.MailRepository
+	Mailer         entity.Mailer
+	Renderer       entity.MailRenderer
+	Config         config.MailConfig
+	ClientConfig   config.ClientConfig
+	// encrypts the queued bodies, they may hold codes and links
+	BodyCipher cipher.AEAD
 }
 
+// zero values in config fall back to Thai templates, 5 attempts, a 30 second backoff, batches of 20 and 30 days retention,
+// the encryption key is required unless the driver is file or memory
 func NewMailUseCase(
 	mailRepository entity.MailRepository,
-) entity.MailUseCase {
+	mailer entity.Mailer,
+	renderer entity.MailRenderer,
+	config config.MailConfig,
+	clientConfig config.ClientConfig,
+) (entity.MailUseCase, error) {
+	if config.Language != "en" {
+		config.Language = "th"
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = 5
+	}
+	if config.RetryBackoff <= 0 {
+		config.RetryBackoff = 30
+	}
+	if config.BatchSize <= 0 {
+		config.BatchSize = 20
+	}
+	if config.Retention <= 0 {
+		config.Retention = 30
+	}
+
+	// mails queued with a random key cannot be read after a restart or by another replica,
+	// so only the development drivers may go without a key
+	encryptionKey := make([]byte, sha256.Size)
+	if config.EncryptionKey != "" {
+		hash := sha256.Sum256([]byte(config.EncryptionKey))
+		encryptionKey = hash[:]
+	} else if config.Driver == "file" || config.Driver == "memory" {
+		if _, err := rand.Read(encryptionKey); err != nil {
+			return nil, fmt.Errorf("cannot generate mail encryption key: %w", err)
+		}
+	} else {
+		return nil, fmt.Errorf("mail encryption key is required unless the mail driver is file or memory")
+	}
+
+	block, err := aes.NewCipher(encryptionKey)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create mail cipher: %w", err)
+	}
+
+	bodyCipher, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create mail cipher: %w", err)
+	}
+
 	return &MailUseCase{
 		MailRepository: mailRepository,
-	}
+		Mailer:         mailer,
+		Renderer:       renderer,
+		Config:         config,
+		ClientConfig:   clientConfig,
+		BodyCipher:     bodyCipher,
+	}, nil
 }
 
 func (u MailUseCase) SendForgotPasswordEmail(to string) error {
-	otp := utils.GenerateRandomInt(6)
-	err := u.MailRepository.SaveToken(to, otp)
+	otp, err := u.createResetPasswordToken(to)
+	if err != nil {
+		return err
+	}
+
+	return u.enqueue(to, entity.MailTemplateForgotPassword, map[string]interface{}{
+		"Code":     otp,
+		"ExpireIn": int(resetTokenMaxAge.Minutes()),
+		"ResetUrl": strings.TrimRight(u.ClientConfig.BaseUrl, "/") + "/reset-password",
+	})
+}
+
+func (u MailUseCase) createResetPasswordToken(email string) (string, error) {
+	createdAt := time.Now()
+
+	requested, err := u.MailRepository.CountTokensSince(email, createdAt.Add(-resetTokenRequestWindow))
+	if err != nil {
+		return "", errs.New(errs.ErrQueryResetToken, "cannot count reset password tokens of %s", email, err)
+	} else if requested >= maxResetTokenRequests {
+		return "", errs.New(errs.ErrResetTokenRequestLimit, "too many reset password requests for %s", email)
+	}
+
+	otp, err := utils.GenerateRandomInt(6)
+	if err != nil {
+		return "", errs.New(errs.ErrCreateResetToken, "cannot generate reset password token", err)
+	}
+
+	tokenHash, err := utils.HashPassword(otp)
 	if err != nil {
-		return errs.New(errs.SameCode, "cannot save token")
+		return "", errs.New(errs.ErrCreateResetToken, "cannot hash reset password token", err)
 	}
 
-	go mail.SentForgotPasswordMail(to, otp)
+	err = u.MailRepository.CreateToken(&entity.PasswordResetToken{
+		Id:        ulid.Make().String(),
+		Email:     email,
+		TokenHash: tokenHash,
+		ExpiredAt: createdAt.Add(resetTokenMaxAge),
+		CreatedAt: createdAt,
+	})
+	if err != nil {
+		return "", errs.New(errs.ErrCreateResetToken, "cannot save reset password token of %s", email, err)
+	}
+
+	return otp, nil
+}
+
+func (u MailUseCase) ConsumeResetPasswordToken(email string, token string) error {
+	resetToken, err := u.MailRepository.GetToken(email)
+	if err != nil {
+		return errs.New(errs.ErrQueryResetToken, "cannot get reset password token of %s", email, err)
+	} else if resetToken == nil {
+		return errs.New(errs.ErrResetTokenInvalid, "reset password token not found")
+	}
+
+	if !time.Now().Before(resetToken.ExpiredAt) {
+		return errs.New(errs.ErrResetTokenInvalid, "reset password token expired")
+	}
+
+	// the attempt is counted in the database before the comparison so concurrent guesses cannot all pass the cap
+	isCounted, err := u.MailRepository.IncreaseAttempts(resetToken.Id, maxResetTokenAttempts)
+	if err != nil {
+		return errs.New(errs.ErrUpdateResetToken, "cannot count reset password token attempt", err)
+	} else if !isCounted {
+		return errs.New(errs.ErrResetTokenLocked, "reset password token is locked after too many attempts")
+	}
+
+	err = utils.CheckPassword(resetToken.TokenHash, token)
+	if err != nil {
+		return errs.New(errs.ErrResetTokenInvalid, "reset password token not match")
+	}
+
+	isUsed, err := u.MailRepository.UseToken(resetToken.Id)
+	if err != nil {
+		return errs.New(errs.ErrUpdateResetToken, "cannot use reset password token", err)
+	} else if !isUsed {
+		return errs.New(errs.ErrResetTokenInvalid, "reset password token is already used")
+	}
 
 	return nil
 }
 
-func (u MailUseCase) ValidateResetPasswordToken(email, token string) error {
-	userToken, err := u.MailRepository.GetToken(email)
+// DeleteExpiredTokens keeps tokens still counted by the request limit
+func (u MailUseCase) DeleteExpiredTokens() (int64, error) {
+	deleted, err := u.MailRepository.DeleteExpiredTokens(time.Now().Add(-resetTokenRequestWindow))
 	if err != nil {
-		return errs.New(errs.SameCode, "token not found")
+		return 0, errs.New(errs.ErrUpdateResetToken, "cannot delete expired reset password tokens", err)
 	}
 
-	if userToken != token {
-		return errs.New(errs.SameCode, "token not match")
+	return deleted, nil
+}
+
+func (u MailUseCase) SendAccountLockedEmail(to string, lockedUntil time.Time) error {
+	return u.enqueue(to, entity.MailTemplateAccountLocked, map[string]interface{}{
+		"LockedUntil": lockedUntil,
+	})
+}
+
+func (u MailUseCase) SendSurveyInvitationEmail(to string, name string, surveyTitle string, link string, expiresAt time.Time) error {
+	return u.enqueue(to, entity.MailTemplateSurveyInvitation, map[string]interface{}{
+		"Name":        name,
+		"SurveyTitle": surveyTitle,
+		"Link":        link,
+		"ExpiresAt":   expiresAt,
+	})
+}
+
+func (u MailUseCase) SendFeedbackLinkEmail(to string, courseName string, link string, expiresAt time.Time) error {
+	return u.enqueue(to, entity.MailTemplateFeedbackLink, map[string]interface{}{
+		"CourseName": courseName,
+		"Link":       link,
+		"ExpiresAt":  expiresAt,
+	})
+}
+
+func (u MailUseCase) SendNotificationEmail(to string, title string, message string, link string) error {
+	return u.enqueue(to, entity.MailTemplateNotification, map[string]interface{}{
+		"Title":   title,
+		"Message": message,
+		"Link":    strings.TrimRight(u.ClientConfig.BaseUrl, "/") + link,
+	})
+}
+
+// enqueue renders the mail now so the worker only has to send it
+func (u MailUseCase) enqueue(to string, template entity.MailTemplate, data interface{}) error {
+	subject, html, err := u.Renderer.Render(template, u.Config.Language, data)
+	if err != nil {
+		return errs.New(errs.ErrMailTemplate, "cannot render %s mail", template, err)
+	}
+
+	body, err := u.encryptBody(html)
+	if err != nil {
+		return errs.New(errs.ErrCreateMail, "cannot encrypt %s mail", template, err)
+	}
+
+	createdAt := time.Now()
+	err = u.MailRepository.CreateOutbox(&entity.MailOutbox{
+		Id:            ulid.Make().String(),
+		Recipient:     to,
+		Template:      template,
+		Language:      u.Config.Language,
+		Subject:       subject,
+		Body:          body,
+		Status:        entity.MailStatusPending,
+		NextAttemptAt: createdAt,
+		CreatedAt:     createdAt,
+	})
+	if err != nil {
+		return errs.New(errs.ErrCreateMail, "cannot queue %s mail to %s", template, to, err)
 	}
 
 	return nil
 }
 
-func (u MailUseCase) GetToken(email string) (string, error) {
-	return u.MailRepository.GetToken(email)
+func (u MailUseCase) ProcessOutbox() (int, error) {
+	mails, err := u.MailRepository.ClaimOutbox(time.Now(), outboxLease, u.Config.BatchSize)
+	if err != nil {
+		return 0, errs.New(errs.ErrQueryMail, "cannot claim mails to send", err)
+	}
+
+	sent := 0
+	for i := range mails {
+		mail := &mails[i]
+		mail.Attempts++
+
+		html, sendErr := u.decryptBody(mail.Body)
+		if sendErr == nil {
+			sendErr = u.Mailer.Send(entity.MailMessage{
+				To:      mail.Recipient,
+				Subject: mail.Subject,
+				Html:    html,
+			})
+		}
+
+		now := time.Now()
+		if sendErr == nil {
+			mail.Status = entity.MailStatusSent
+			mail.SentAt = &now
+			mail.Body = ""
+			mail.LastError = ""
+			sent++
+		} else {
+			mail.LastError = sendErr.Error()
+			if mail.Attempts >= u.Config.MaxAttempts {
+				mail.Status = entity.MailStatusFailed
+			} else {
+				mail.NextAttemptAt = now.Add(u.retryBackoff(mail.Attempts))
+			}
+		}
+
+		err = u.MailRepository.UpdateOutbox(mail)
+		if err != nil {
+			return sent, errs.New(errs.ErrUpdateMail, "cannot update status of mail id %s", mail.Id, err)
+		}
+	}
+
+	return sent, nil
+}
+
+func (u MailUseCase) encryptBody(html string) (string, error) {
+	nonce := make([]byte, u.BodyCipher.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(u.BodyCipher.Seal(nonce, nonce, []byte(html), nil)), nil
+}
+
+// decryptBody fails for mails queued under another key, they are retried until marked failed
+func (u MailUseCase) decryptBody(body string) (string, error) {
+	nonceSize := u.BodyCipher.NonceSize()
+	sealed, err := base64.StdEncoding.DecodeString(body)
+	if err != nil {
+		return "", fmt.Errorf("cannot decode mail body: %w", err)
+	} else if len(sealed) < nonceSize {
+		return "", fmt.Errorf("mail body is too short")
+	}
+
+	html, err := u.BodyCipher.Open(nil, sealed[:nonceSize], sealed[nonceSize:], nil)
+	if err != nil {
+		return "", fmt.Errorf("cannot decrypt mail body: %w", err)
+	}
+
+	return string(html), nil
+}
+
+// retryBackoff doubles the configured backoff after each failed attempt
+func (u MailUseCase) retryBackoff(attempts int) time.Duration {
+	shift := attempts - 1
+	if shift > 16 {
+		shift = 16
+	}
+	return time.Duration(u.Config.RetryBackoff) * time.Second << shift
 }
 
-func (u MailUseCase) DeleteToken(email string) error {
-	return u.MailRepository.DeleteToken(email)
+func (u MailUseCase) GetOutbox(status string, pageIndex string, pageSize string) (*entity.Pagination, error) {
+	mailStatus := entity.MailStatus(strings.ToUpper(status))
+	switch mailStatus {
+	case "", entity.MailStatusPending, entity.MailStatusSent, entity.MailStatusFailed:
+	default:
+		return nil, errs.New(errs.ErrInvalidMailStatus, "mail status %s is not valid", status)
+	}
+
+	offset, limit, err := utils.ValidatePagination(pageIndex, pageSize)
+	if err != nil {
+		return nil, errs.New(errs.ErrQueryMail, "cannot get mails", err)
+	}
+
+	mails, err := u.MailRepository.GetOutbox(mailStatus, offset, limit)
+	if err != nil {
+		return nil, errs.New(errs.ErrQueryMail, "cannot get mails", err)
+	}
+
+	return mails, nil
+}
+
+func (u MailUseCase) GetOutboxById(id string) (*entity.MailOutbox, error) {
+	mail, err := u.MailRepository.GetOutboxById(id)
+	if err != nil {
+		return nil, errs.New(errs.ErrQueryMail, "cannot get mail by id %s", id, err)
+	} else if mail == nil {
+		return nil, errs.New(errs.ErrMailNotFound, "mail id %s not found", id)
+	}
+
+	return mail, nil
+}
+
+func (u MailUseCase) RetryOutbox(id string) error {
+	mail, err := u.GetOutboxById(id)
+	if err != nil {
+		return err
+	} else if mail.Status != entity.MailStatusFailed {
+		return errs.New(errs.ErrMailNotRetryable, "only failed mails can be retried, mail id %s is %s", id, mail.Status)
+	}
+
+	mail.Status = entity.MailStatusPending
+	mail.Attempts = 0
+	mail.NextAttemptAt = time.Now()
+
+	err = u.MailRepository.UpdateOutbox(mail)
+	if err != nil {
+		return errs.New(errs.ErrUpdateMail, "cannot queue mail id %s again", id, err)
+	}
+
+	return nil
+}
+
+func (u MailUseCase) DeleteOldOutbox() (int64, error) {
+	deleted, err := u.MailRepository.DeleteOutboxBefore(time.Now().AddDate(0, 0, -u.Config.Retention))
+	if err != nil {
+		return 0, errs.New(errs.ErrUpdateMail, "cannot delete old mails", err)
+	}
+
+	return deleted, nil
 }
@@ -0,0 +1,274 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/team-inu/inu-backyard/entity"
+	errs "github.com/team-inu/inu-backyard/entity/error"
+	"github.com/team-inu/inu-backyard/infrastructure/mail"
+	"github.com/team-inu/inu-backyard/internal/config"
+)
+
+type stubMailRepository struct {
+	entity.MailRepository
+	tokens []entity.PasswordResetToken
+	outbox []entity.MailOutbox
+}
+
+func (r *stubMailRepository) CreateToken(token *entity.PasswordResetToken) error {
+	for i := range r.tokens {
+		if r.tokens[i].Email == token.Email && r.tokens[i].UsedAt == nil {
+			r.tokens[i].UsedAt = &token.CreatedAt
+		}
+	}
+	r.tokens = append(r.tokens, *token)
+	return nil
+}
+
+func (r *stubMailRepository) GetToken(email string) (*entity.PasswordResetToken, error) {
+	for i := len(r.tokens) - 1; i >= 0; i-- {
+		if r.tokens[i].Email == email && r.tokens[i].UsedAt == nil {
+			token := r.tokens[i]
+			return &token, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *stubMailRepository) CountTokensSince(email string, since time.Time) (int64, error) {
+	var count int64
+	for _, token := range r.tokens {
+		if token.Email == email && !token.CreatedAt.Before(since) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *stubMailRepository) IncreaseAttempts(id string, maxAttempts int) (bool, error) {
+	for i := range r.tokens {
+		if r.tokens[i].Id == id && r.tokens[i].Attempts < maxAttempts {
+			r.tokens[i].Attempts++
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *stubMailRepository) UseToken(id string) (bool, error) {
+	for i := range r.tokens {
+		if r.tokens[i].Id == id && r.tokens[i].UsedAt == nil {
+			usedAt := time.Now()
+			r.tokens[i].UsedAt = &usedAt
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *stubMailRepository) CreateOutbox(mail *entity.MailOutbox) error {
+	r.outbox = append(r.outbox, *mail)
+	return nil
+}
+
+func (r *stubMailRepository) ClaimOutbox(now time.Time, lease time.Duration, limit int) ([]entity.MailOutbox, error) {
+	mails := []entity.MailOutbox{}
+	for i := range r.outbox {
+		if len(mails) < limit && r.outbox[i].Status == entity.MailStatusPending && !r.outbox[i].NextAttemptAt.After(now) {
+			mails = append(mails, r.outbox[i])
+			r.outbox[i].NextAttemptAt = now.Add(lease)
+		}
+	}
+	return mails, nil
+}
+
+func (r *stubMailRepository) UpdateOutbox(mail *entity.MailOutbox) error {
+	for i := range r.outbox {
+		if r.outbox[i].Id == mail.Id {
+			r.outbox[i] = *mail
+		}
+	}
+	return nil
+}
+
+func (r *stubMailRepository) GetOutboxById(id string) (*entity.MailOutbox, error) {
+	for _, mail := range r.outbox {
+		if mail.Id == id {
+			return &mail, nil
+		}
+	}
+	return nil, nil
+}
+
+func errorCode(err error) int {
+	if domainErr, ok := err.(*errs.DomainError); ok {
+		return domainErr.Code
+	}
+	return 0
+}
+
+func TestResetPasswordToken(t *testing.T) {
+	mailRepository := &stubMailRepository{}
+	mailUseCase := MailUseCase{MailRepository: mailRepository}
+
+	t.Run("TestCreateStoresHash", func(t *testing.T) {
+		otp, err := mailUseCase.createResetPasswordToken("lecturer@example.com")
+		assert.Nil(t, err, "Expected no error while creating token, got %v", err)
+		assert.Len(t, otp, 6, "Expected a 6 digit code")
+		assert.NotContains(t, mailRepository.tokens[0].TokenHash, otp, "Expected code not to be stored in plain text")
+	})
+
+	t.Run("TestConsumeOnce", func(t *testing.T) {
+		otp, _ := mailUseCase.createResetPasswordToken("head@example.com")
+
+		err := mailUseCase.ConsumeResetPasswordToken("head@example.com", otp)
+		assert.Nil(t, err, "Expected no error while consuming token, got %v", err)
+
+		err = mailUseCase.ConsumeResetPasswordToken("head@example.com", otp)
+		assert.Equal(t, errs.ErrResetTokenInvalid, errorCode(err), "Expected used token to be refused, got %v", err)
+	})
+
+	t.Run("TestNewTokenReplacesOld", func(t *testing.T) {
+		oldOtp, _ := mailUseCase.createResetPasswordToken("staff@example.com")
+		newOtp, _ := mailUseCase.createResetPasswordToken("staff@example.com")
+		if oldOtp == newOtp {
+			t.Skip("Codes collided")
+		}
+
+		err := mailUseCase.ConsumeResetPasswordToken("staff@example.com", oldOtp)
+		assert.NotNil(t, err, "Expected previous token to stop working")
+	})
+
+	t.Run("TestLockAfterAttempts", func(t *testing.T) {
+		otp, _ := mailUseCase.createResetPasswordToken("student@example.com")
+
+		for i := 0; i < maxResetTokenAttempts; i++ {
+			err := mailUseCase.ConsumeResetPasswordToken("student@example.com", "wrong")
+			assert.Equal(t, errs.ErrResetTokenInvalid, errorCode(err), "Expected wrong code to be refused, got %v", err)
+		}
+
+		err := mailUseCase.ConsumeResetPasswordToken("student@example.com", otp)
+		assert.Equal(t, errs.ErrResetTokenLocked, errorCode(err), "Expected token to be locked, got %v", err)
+	})
+
+	t.Run("TestExpired", func(t *testing.T) {
+		otp, _ := mailUseCase.createResetPasswordToken("dean@example.com")
+		mailRepository.tokens[len(mailRepository.tokens)-1].ExpiredAt = time.Now().Add(-time.Second)
+
+		err := mailUseCase.ConsumeResetPasswordToken("dean@example.com", otp)
+		assert.Equal(t, errs.ErrResetTokenInvalid, errorCode(err), "Expected expired token to be refused, got %v", err)
+	})
+
+	t.Run("TestRequestLimit", func(t *testing.T) {
+		for i := 0; i < maxResetTokenRequests; i++ {
+			_, err := mailUseCase.createResetPasswordToken("busy@example.com")
+			assert.Nil(t, err, "Expected no error within the request limit, got %v", err)
+		}
+
+		_, err := mailUseCase.createResetPasswordToken("busy@example.com")
+		assert.Equal(t, errs.ErrResetTokenRequestLimit, errorCode(err), "Expected request limit, got %v", err)
+	})
+}
+
+func TestMailOutbox(t *testing.T) {
+	renderer, err := mail.NewRenderer()
+	if err != nil {
+		t.Fatalf("Failed to parse mail templates: %v", err)
+	}
+
+	mailRepository := &stubMailRepository{}
+	mailer := mail.NewMemoryMailer()
+	mailUseCase, err := NewMailUseCase(mailRepository, mailer, renderer, config.MailConfig{Language: "en", MaxAttempts: 2, RetryBackoff: 60, EncryptionKey: "secret"}, config.ClientConfig{BaseUrl: "http://localhost:3000/"})
+	if err != nil {
+		t.Fatalf("Failed to create mail use case: %v", err)
+	}
+
+	t.Run("TestQueueAndSend", func(t *testing.T) {
+		err := mailUseCase.SendForgotPasswordEmail("lecturer@example.com")
+		assert.Nil(t, err, "Expected no error while queueing mail, got %v", err)
+		assert.Empty(t, mailer.Messages(), "Expected mail to wait in the outbox")
+		assert.NotContains(t, mailRepository.outbox[0].Body, "reset-password", "Expected queued body to be encrypted")
+
+		sent, err := mailUseCase.ProcessOutbox()
+		assert.Nil(t, err, "Expected no error while processing outbox, got %v", err)
+		assert.Equal(t, 1, sent, "Expected queued mail to be sent")
+
+		messages := mailer.Messages()
+		assert.Equal(t, "Reset Your Password", messages[0].Subject)
+		assert.Contains(t, messages[0].Html, "http://localhost:3000/reset-password", "Expected reset link from client base url")
+
+		assert.Equal(t, entity.MailStatusSent, mailRepository.outbox[0].Status, "Expected mail to be marked sent")
+		assert.Empty(t, mailRepository.outbox[0].Body, "Expected body with the code to be cleared after sending")
+	})
+
+	t.Run("TestRetryWithBackoff", func(t *testing.T) {
+		mailer.Err = errors.New("connection refused")
+		defer func() { mailer.Err = nil }()
+
+		err := mailUseCase.SendAccountLockedEmail("head@example.com", time.Now().Add(time.Hour))
+		assert.Nil(t, err, "Expected no error while queueing mail, got %v", err)
+
+		sent, err := mailUseCase.ProcessOutbox()
+		assert.Nil(t, err, "Expected send failure to be recorded, got %v", err)
+		assert.Equal(t, 0, sent)
+
+		queued := mailRepository.outbox[len(mailRepository.outbox)-1]
+		assert.Equal(t, entity.MailStatusPending, queued.Status, "Expected mail to be retried")
+		assert.Equal(t, "connection refused", queued.LastError)
+		assert.WithinDuration(t, time.Now().Add(time.Minute), queued.NextAttemptAt, 5*time.Second, "Expected retry after the backoff")
+
+		sent, _ = mailUseCase.ProcessOutbox()
+		assert.Equal(t, 0, sent, "Expected mail not to be sent before the backoff")
+
+		mailRepository.outbox[len(mailRepository.outbox)-1].NextAttemptAt = time.Now()
+		mailUseCase.ProcessOutbox()
+
+		failed := mailRepository.outbox[len(mailRepository.outbox)-1]
+		assert.Equal(t, entity.MailStatusFailed, failed.Status, "Expected mail to fail after max attempts")
+		assert.NotEmpty(t, failed.Body, "Expected encrypted body to be kept for a retry")
+
+		mailer.Err = nil
+		err = mailUseCase.RetryOutbox(failed.Id)
+		assert.Nil(t, err, "Expected no error while retrying failed mail, got %v", err)
+
+		sent, _ = mailUseCase.ProcessOutbox()
+		assert.Equal(t, 1, sent, "Expected retried mail to be sent")
+		assert.Empty(t, mailRepository.outbox[len(mailRepository.outbox)-1].Body, "Expected body to be cleared after sending")
+	})
+
+	t.Run("TestBodyOfAnotherKey", func(t *testing.T) {
+		otherUseCase, err := NewMailUseCase(mailRepository, mailer, renderer, config.MailConfig{Language: "en", MaxAttempts: 1, EncryptionKey: "other"}, config.ClientConfig{})
+		assert.Nil(t, err)
+
+		err = otherUseCase.SendAccountLockedEmail("head@example.com", time.Now().Add(time.Hour))
+		assert.Nil(t, err)
+
+		sent, err := mailUseCase.ProcessOutbox()
+		assert.Nil(t, err, "Expected undecryptable body to be recorded as a failure, got %v", err)
+		assert.Equal(t, 0, sent)
+
+		failed := mailRepository.outbox[len(mailRepository.outbox)-1]
+		assert.Contains(t, failed.LastError, "cannot decrypt mail body")
+	})
+
+	t.Run("TestEncryptionKeyRequired", func(t *testing.T) {
+		_, err := NewMailUseCase(mailRepository, mailer, renderer, config.MailConfig{Driver: "smtp"}, config.ClientConfig{})
+		assert.NotNil(t, err, "Expected the smtp driver to require an encryption key")
+
+		_, err = NewMailUseCase(mailRepository, mailer, renderer, config.MailConfig{Driver: "memory"}, config.ClientConfig{})
+		assert.Nil(t, err, "Expected the memory driver to use a random key, got %v", err)
+	})
+
+	t.Run("TestRetrySentMail", func(t *testing.T) {
+		err := mailUseCase.RetryOutbox(mailRepository.outbox[0].Id)
+		assert.Equal(t, errs.ErrMailNotRetryable, errorCode(err), "Expected sent mail not to be retried, got %v", err)
+	})
+
+	t.Run("TestInvalidStatus", func(t *testing.T) {
+		_, err := mailUseCase.GetOutbox("unknown", "", "")
+		assert.Equal(t, errs.ErrInvalidMailStatus, errorCode(err), "Expected invalid status error, got %v", err)
+	})
+}
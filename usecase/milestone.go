@@ -0,0 +1,263 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/team-inu/inu-backyard/entity"
+	errs "github.com/team-inu/inu-backyard/entity/error"
+)
+
+const defaultRemindDaysBefore = 3
+
+// deadlines in reminders are shown in Thailand time
+var deadlineZone = time.FixedZone("ICT", 7*60*60)
+
+var milestoneReminderTitles = map[entity.MilestoneType]string{
+	entity.MilestoneTypeScoreEntryClose: "Score entry closes on %s",
+	entity.MilestoneTypePortfolioDue:    "Course portfolios are due on %s",
+	entity.MilestoneTypeSurveyClose:     "Surveys close on %s",
+}
+
+var milestoneReminderMessages = map[entity.MilestoneType]string{
+	entity.MilestoneTypeScoreEntryClose: "Some enrolled students have no score yet in %s.",
+	entity.MilestoneTypePortfolioDue:    "The course portfolio is not completed yet in %s.",
+	entity.MilestoneTypeSurveyClose:     "Some surveys are not completed yet in %s.",
+}
+
+type milestoneUseCase struct {
+	milestoneRepo       entity.MilestoneRepository
+	semesterUseCase     entity.SemesterUseCase
+	notificationUseCase entity.NotificationUseCase
+}
+
+func NewMilestoneUseCase(
+	milestoneRepo entity.MilestoneRepository,
+	semesterUseCase entity.SemesterUseCase,
+	notificationUseCase entity.NotificationUseCase,
+) entity.MilestoneUseCase {
+	return &milestoneUseCase{
+		milestoneRepo:       milestoneRepo,
+		semesterUseCase:     semesterUseCase,
+		notificationUseCase: notificationUseCase,
+	}
+}
+
+func (u milestoneUseCase) GetById(id string) (*entity.SemesterMilestone, error) {
+	milestone, err := u.milestoneRepo.GetById(id)
+	if err != nil {
+		return nil, errs.New(errs.ErrQueryMilestone, "cannot get milestone by id %s", id, err)
+	} else if milestone == nil {
+		return nil, errs.New(errs.ErrMilestoneNotFound, "milestone id %s not found", id)
+	}
+
+	return milestone, nil
+}
+
+func (u milestoneUseCase) GetBySemesterId(semesterId string) ([]entity.SemesterMilestone, error) {
+	milestones, err := u.milestoneRepo.GetBySemesterId(semesterId)
+	if err != nil {
+		return nil, errs.New(errs.ErrQueryMilestone, "cannot get milestones of semester id %s", semesterId, err)
+	}
+
+	return milestones, nil
+}
+
+func (u milestoneUseCase) Create(semesterId string, payload entity.CreateMilestonePayload) error {
+	semester, err := u.semesterUseCase.GetById(semesterId)
+	if err != nil {
+		return errs.New(errs.SameCode, "cannot get semester id %s to create milestone", semesterId, err)
+	} else if semester == nil {
+		return errs.New(errs.ErrSemesterNotFound, "semester id %s not found to create milestone", semesterId)
+	}
+
+	if !isMilestoneType(payload.Type) {
+		return errs.New(errs.ErrInvalidMilestone, "milestone type %s is not valid", payload.Type)
+	} else if payload.LockScoreEntry && payload.Type != entity.MilestoneTypeScoreEntryClose {
+		return errs.New(errs.ErrInvalidMilestone, "only %s milestones can lock score entry", entity.MilestoneTypeScoreEntryClose)
+	}
+
+	for _, milestone := range semester.Milestones {
+		if milestone.Type == payload.Type {
+			return errs.New(errs.ErrDupMilestone, "semester id %s already has a %s milestone", semesterId, payload.Type)
+		}
+	}
+
+	createdAt := time.Now()
+	err = u.milestoneRepo.Create(&entity.SemesterMilestone{
+		Id:               ulid.Make().String(),
+		SemesterId:       semesterId,
+		Type:             payload.Type,
+		DueAt:            payload.DueAt,
+		RemindDaysBefore: remindDaysBefore(payload.RemindDaysBefore),
+		LockScoreEntry:   payload.LockScoreEntry,
+		CreatedAt:        createdAt,
+		UpdatedAt:        createdAt,
+	})
+	if err != nil {
+		return errs.New(errs.ErrCreateMilestone, "cannot create milestone of semester id %s", semesterId, err)
+	}
+
+	return nil
+}
+
+func (u milestoneUseCase) Update(id string, payload entity.UpdateMilestonePayload) error {
+	milestone, err := u.GetById(id)
+	if err != nil {
+		return err
+	}
+
+	if payload.LockScoreEntry && milestone.Type != entity.MilestoneTypeScoreEntryClose {
+		return errs.New(errs.ErrInvalidMilestone, "only %s milestones can lock score entry", entity.MilestoneTypeScoreEntryClose)
+	}
+
+	// a moved deadline is reminded again
+	if !milestone.DueAt.Equal(payload.DueAt) || milestone.RemindDaysBefore != remindDaysBefore(payload.RemindDaysBefore) {
+		milestone.RemindedAt = nil
+	}
+
+	milestone.DueAt = payload.DueAt
+	milestone.RemindDaysBefore = remindDaysBefore(payload.RemindDaysBefore)
+	milestone.LockScoreEntry = payload.LockScoreEntry
+	milestone.UpdatedAt = time.Now()
+
+	err = u.milestoneRepo.Update(milestone)
+	if err != nil {
+		return errs.New(errs.ErrUpdateMilestone, "cannot update milestone id %s", id, err)
+	}
+
+	return nil
+}
+
+func (u milestoneUseCase) Delete(id string) error {
+	_, err := u.GetById(id)
+	if err != nil {
+		return err
+	}
+
+	err = u.milestoneRepo.Delete(id)
+	if err != nil {
+		return errs.New(errs.ErrDeleteMilestone, "cannot delete milestone id %s", id, err)
+	}
+
+	return nil
+}
+
+func (u milestoneUseCase) IsScoreEntryLocked(semesterId string) (bool, error) {
+	milestones, err := u.GetBySemesterId(semesterId)
+	if err != nil {
+		return false, err
+	}
+
+	now := time.Now()
+	for _, milestone := range milestones {
+		if milestone.Type == entity.MilestoneTypeScoreEntryClose && milestone.LockScoreEntry && !now.Before(milestone.DueAt) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (u milestoneUseCase) SendReminders() (int, error) {
+	now := time.Now()
+	milestones, err := u.milestoneRepo.GetUnreminded(now)
+	if err != nil {
+		return 0, errs.New(errs.ErrQueryMilestone, "cannot get milestones to remind", err)
+	}
+
+	reminded := 0
+	failures := []error{}
+	for _, milestone := range milestones {
+		if !milestone.IsReminderDue(now) {
+			continue
+		}
+
+		// a failed milestone does not hold back the others, it is tried again on the next run only when no lecturer was reminded
+		// so reminded lecturers are not reminded twice, the scheduler lease keeps replicas from overlapping
+		isSent, err := u.remind(milestone)
+		if err != nil {
+			failures = append(failures, err)
+		}
+		if !isSent {
+			continue
+		}
+
+		isMarked, err := u.milestoneRepo.MarkReminded(milestone.Id, now)
+		if err != nil {
+			failures = append(failures, errs.New(errs.ErrUpdateMilestone, "cannot mark milestone id %s reminded", milestone.Id, err))
+		} else if isMarked {
+			reminded++
+		}
+	}
+
+	if len(failures) > 0 {
+		return reminded, errs.New(errs.SameCode, "cannot remind %d milestones", len(failures), errors.Join(failures...))
+	}
+
+	return reminded, nil
+}
+
+// remind sends one notification per lecturer listing their incomplete courses, a failed lecturer does not hold back the others,
+// it returns false when no lecturer could be reminded
+func (u milestoneUseCase) remind(milestone entity.SemesterMilestone) (bool, error) {
+	courses, err := u.milestoneRepo.GetIncompleteCourses(milestone.SemesterId, milestone.Type)
+	if err != nil {
+		return false, errs.New(errs.ErrQueryMilestone, "cannot get incomplete courses of milestone id %s", milestone.Id, err)
+	}
+
+	courseNamesByLecturer := map[string][]string{}
+	for _, course := range courses {
+		for _, lecturerId := range courseLecturerIds(&course, "") {
+			courseNamesByLecturer[lecturerId] = append(courseNamesByLecturer[lecturerId], course.Code+" "+course.Name)
+		}
+	}
+
+	lecturerIds := make([]string, 0, len(courseNamesByLecturer))
+	for lecturerId := range courseNamesByLecturer {
+		lecturerIds = append(lecturerIds, lecturerId)
+	}
+	sort.Strings(lecturerIds)
+
+	dueAt := milestone.DueAt.In(deadlineZone).Format("2 January 2006 15:04")
+	failures := []error{}
+	for _, lecturerId := range lecturerIds {
+		err = u.notificationUseCase.Notify([]string{lecturerId}, entity.NotificationEvent{
+			Type:        entity.NotificationTypeDeadlineReminder,
+			Title:       fmt.Sprintf(milestoneReminderTitles[milestone.Type], dueAt),
+			Message:     fmt.Sprintf(milestoneReminderMessages[milestone.Type], strings.Join(courseNamesByLecturer[lecturerId], ", ")),
+			Link:        "/courses",
+			ReferenceId: milestone.Id,
+		})
+		if err != nil {
+			failures = append(failures, errs.New(errs.SameCode, "cannot remind lecturer id %s of milestone id %s", lecturerId, milestone.Id, err))
+		}
+	}
+
+	isSent := len(lecturerIds) == 0 || len(failures) < len(lecturerIds)
+	if len(failures) > 0 {
+		return isSent, errors.Join(failures...)
+	}
+
+	return isSent, nil
+}
+
+func remindDaysBefore(days *int) int {
+	if days == nil {
+		return defaultRemindDaysBefore
+	}
+	return *days
+}
+
+func isMilestoneType(milestoneType entity.MilestoneType) bool {
+	for _, t := range entity.MilestoneTypes {
+		if t == milestoneType {
+			return true
+		}
+	}
+	return false
+}
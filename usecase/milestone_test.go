@@ -0,0 +1,238 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/team-inu/inu-backyard/entity"
+	errs "github.com/team-inu/inu-backyard/entity/error"
+	"github.com/team-inu/inu-backyard/internal/config"
+)
+
+type stubMilestoneRepository struct {
+	entity.MilestoneRepository
+	milestones        []entity.SemesterMilestone
+	incompleteCourses []entity.Course
+}
+
+func (r *stubMilestoneRepository) GetBySemesterId(semesterId string) ([]entity.SemesterMilestone, error) {
+	milestones := []entity.SemesterMilestone{}
+	for _, milestone := range r.milestones {
+		if milestone.SemesterId == semesterId {
+			milestones = append(milestones, milestone)
+		}
+	}
+	return milestones, nil
+}
+
+func (r *stubMilestoneRepository) GetUnreminded(now time.Time) ([]entity.SemesterMilestone, error) {
+	milestones := []entity.SemesterMilestone{}
+	for _, milestone := range r.milestones {
+		if milestone.RemindedAt == nil && milestone.DueAt.After(now) {
+			milestones = append(milestones, milestone)
+		}
+	}
+	return milestones, nil
+}
+
+func (r *stubMilestoneRepository) MarkReminded(id string, remindedAt time.Time) (bool, error) {
+	for i, milestone := range r.milestones {
+		if milestone.Id == id && milestone.RemindedAt == nil {
+			r.milestones[i].RemindedAt = &remindedAt
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *stubMilestoneRepository) GetIncompleteCourses(semesterId string, milestoneType entity.MilestoneType) ([]entity.Course, error) {
+	return r.incompleteCourses, nil
+}
+
+type stubNotificationUseCase struct {
+	entity.NotificationUseCase
+	events map[string][]entity.NotificationEvent
+	err    error
+	// notifications of this user fail with err
+	failingUserId string
+}
+
+func (u *stubNotificationUseCase) Notify(userIds []string, event entity.NotificationEvent) error {
+	if u.err != nil && (u.failingUserId == "" || userIds[0] == u.failingUserId) {
+		return u.err
+	}
+	if u.events == nil {
+		u.events = map[string][]entity.NotificationEvent{}
+	}
+	for _, userId := range userIds {
+		u.events[userId] = append(u.events[userId], event)
+	}
+	return nil
+}
+
+type stubSchedulerLeaseRepository struct {
+	holder    string
+	expiresAt time.Time
+}
+
+func (r *stubSchedulerLeaseRepository) Acquire(name string, holder string, now time.Time, expiresAt time.Time) (bool, error) {
+	if r.holder != "" && r.holder != holder && r.expiresAt.After(now) {
+		return false, nil
+	}
+	r.holder = holder
+	r.expiresAt = expiresAt
+	return true, nil
+}
+
+func TestMilestone(t *testing.T) {
+	now := time.Now()
+
+	t.Run("TestIsReminderDue", func(t *testing.T) {
+		milestone := entity.SemesterMilestone{DueAt: now.Add(48 * time.Hour), RemindDaysBefore: 3}
+		assert.True(t, milestone.IsReminderDue(now), "Expected reminder within the reminder days")
+
+		milestone.RemindDaysBefore = 1
+		assert.False(t, milestone.IsReminderDue(now), "Expected no reminder before the reminder days")
+
+		milestone = entity.SemesterMilestone{DueAt: now.Add(-time.Hour), RemindDaysBefore: 3}
+		assert.False(t, milestone.IsReminderDue(now), "Expected no reminder after the deadline")
+	})
+
+	t.Run("TestIsScoreEntryLocked", func(t *testing.T) {
+		milestoneUseCase := NewMilestoneUseCase(&stubMilestoneRepository{milestones: []entity.SemesterMilestone{
+			{Id: "passed", SemesterId: "locked", Type: entity.MilestoneTypeScoreEntryClose, DueAt: now.Add(-time.Hour), LockScoreEntry: true},
+			{Id: "upcoming", SemesterId: "open", Type: entity.MilestoneTypeScoreEntryClose, DueAt: now.Add(time.Hour), LockScoreEntry: true},
+			{Id: "no-lock", SemesterId: "unlocked", Type: entity.MilestoneTypeScoreEntryClose, DueAt: now.Add(-time.Hour)},
+		}}, nil, nil)
+
+		isLocked, err := milestoneUseCase.IsScoreEntryLocked("locked")
+		assert.Nil(t, err)
+		assert.True(t, isLocked, "Expected score entry to be locked after the deadline")
+
+		isLocked, _ = milestoneUseCase.IsScoreEntryLocked("open")
+		assert.False(t, isLocked, "Expected score entry to be open before the deadline")
+
+		isLocked, _ = milestoneUseCase.IsScoreEntryLocked("unlocked")
+		assert.False(t, isLocked, "Expected score entry to stay open without auto-lock")
+	})
+
+	t.Run("TestSendReminders", func(t *testing.T) {
+		milestoneRepository := &stubMilestoneRepository{
+			milestones: []entity.SemesterMilestone{
+				{Id: "portfolio", SemesterId: "semester", Type: entity.MilestoneTypePortfolioDue, DueAt: now.Add(24 * time.Hour), RemindDaysBefore: 3},
+				{Id: "later", SemesterId: "semester", Type: entity.MilestoneTypeSurveyClose, DueAt: now.Add(30 * 24 * time.Hour), RemindDaysBefore: 3},
+			},
+			incompleteCourses: []entity.Course{
+				{Id: "c1", Code: "CPE100", Name: "Programming", Lecturers: []*entity.User{{Id: "somchai"}, {Id: "malee"}}},
+				{Id: "c2", Code: "CPE200", Name: "Algorithms", Lecturers: []*entity.User{{Id: "somchai"}}},
+			},
+		}
+		notificationUseCase := &stubNotificationUseCase{}
+		milestoneUseCase := NewMilestoneUseCase(milestoneRepository, nil, notificationUseCase)
+
+		reminded, err := milestoneUseCase.SendReminders()
+		assert.Nil(t, err, "Expected no error while sending reminders, got %v", err)
+		assert.Equal(t, 1, reminded, "Expected only the upcoming milestone to be reminded")
+
+		assert.Len(t, notificationUseCase.events["somchai"], 1, "Expected one reminder per lecturer")
+		assert.Contains(t, notificationUseCase.events["somchai"][0].Message, "CPE100 Programming, CPE200 Algorithms")
+		assert.Equal(t, entity.NotificationTypeDeadlineReminder, notificationUseCase.events["malee"][0].Type)
+		assert.Equal(t, "portfolio", notificationUseCase.events["malee"][0].ReferenceId)
+
+		reminded, err = milestoneUseCase.SendReminders()
+		assert.Nil(t, err)
+		assert.Equal(t, 0, reminded, "Expected a milestone to be reminded once")
+		assert.Len(t, notificationUseCase.events["somchai"], 1, "Expected no second reminder")
+	})
+
+	t.Run("TestSendReminders_NotificationFailure", func(t *testing.T) {
+		milestoneRepository := &stubMilestoneRepository{
+			milestones: []entity.SemesterMilestone{
+				{Id: "portfolio", SemesterId: "semester", Type: entity.MilestoneTypePortfolioDue, DueAt: now.Add(24 * time.Hour), RemindDaysBefore: 3},
+			},
+			incompleteCourses: []entity.Course{
+				{Id: "c1", Code: "CPE100", Name: "Programming", Lecturers: []*entity.User{{Id: "somchai"}}},
+			},
+		}
+		notificationUseCase := &stubNotificationUseCase{err: errors.New("connection refused")}
+		milestoneUseCase := NewMilestoneUseCase(milestoneRepository, nil, notificationUseCase)
+
+		_, err := milestoneUseCase.SendReminders()
+		assert.NotNil(t, err, "Expected the failed reminder to be reported")
+		assert.Nil(t, milestoneRepository.milestones[0].RemindedAt, "Expected a failed reminder not to be marked")
+
+		notificationUseCase.err = nil
+		reminded, err := milestoneUseCase.SendReminders()
+		assert.Nil(t, err)
+		assert.Equal(t, 1, reminded, "Expected the reminder to be sent on the next run")
+		assert.NotNil(t, milestoneRepository.milestones[0].RemindedAt)
+	})
+
+	t.Run("TestSendReminders_PartialFailure", func(t *testing.T) {
+		milestoneRepository := &stubMilestoneRepository{
+			milestones: []entity.SemesterMilestone{
+				{Id: "portfolio", SemesterId: "semester", Type: entity.MilestoneTypePortfolioDue, DueAt: now.Add(24 * time.Hour), RemindDaysBefore: 3},
+				{Id: "survey", SemesterId: "semester", Type: entity.MilestoneTypeSurveyClose, DueAt: now.Add(48 * time.Hour), RemindDaysBefore: 3},
+			},
+			incompleteCourses: []entity.Course{
+				{Id: "c1", Code: "CPE100", Name: "Programming", Lecturers: []*entity.User{{Id: "somchai"}, {Id: "malee"}}},
+			},
+		}
+		notificationUseCase := &stubNotificationUseCase{err: errors.New("connection refused"), failingUserId: "malee"}
+		milestoneUseCase := NewMilestoneUseCase(milestoneRepository, nil, notificationUseCase)
+
+		reminded, err := milestoneUseCase.SendReminders()
+		assert.NotNil(t, err, "Expected the failed lecturers to be reported")
+		assert.Equal(t, 2, reminded, "Expected a failed lecturer not to hold back the later milestones")
+		assert.Len(t, notificationUseCase.events["somchai"], 2)
+
+		reminded, err = milestoneUseCase.SendReminders()
+		assert.Nil(t, err)
+		assert.Equal(t, 0, reminded)
+		assert.Len(t, notificationUseCase.events["somchai"], 2, "Expected reminded lecturers not to be reminded again")
+	})
+
+	t.Run("TestCreate_LockOnlyScoreEntry", func(t *testing.T) {
+		milestoneUseCase := NewMilestoneUseCase(&stubMilestoneRepository{}, &stubSemesterUseCase{}, nil)
+
+		err := milestoneUseCase.Create("semester", entity.CreateMilestonePayload{Type: entity.MilestoneTypePortfolioDue, DueAt: now, LockScoreEntry: true})
+		assert.Equal(t, errs.ErrInvalidMilestone, errorCode(err), "Expected lock on portfolio milestone to be refused, got %v", err)
+	})
+}
+
+type stubSemesterUseCase struct {
+	entity.SemesterUseCase
+}
+
+func (u *stubSemesterUseCase) GetById(id string) (*entity.Semester, error) {
+	return &entity.Semester{Id: id}, nil
+}
+
+func TestScheduler(t *testing.T) {
+	leaseRepository := &stubSchedulerLeaseRepository{}
+	milestoneRepository := &stubMilestoneRepository{milestones: []entity.SemesterMilestone{
+		{Id: "scores", SemesterId: "semester", Type: entity.MilestoneTypeScoreEntryClose, DueAt: time.Now().Add(time.Hour), RemindDaysBefore: 1},
+	}}
+	milestoneUseCase := NewMilestoneUseCase(milestoneRepository, nil, &stubNotificationUseCase{})
+
+	firstReplica := NewSchedulerUseCase(leaseRepository, milestoneUseCase, config.SchedulerConfig{})
+	secondReplica := NewSchedulerUseCase(leaseRepository, milestoneUseCase, config.SchedulerConfig{})
+
+	reminded, err := firstReplica.Run()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, reminded, "Expected the first replica to take the lease and run")
+
+	milestoneRepository.milestones[0].RemindedAt = nil
+
+	reminded, err = secondReplica.Run()
+	assert.Nil(t, err)
+	assert.Equal(t, 0, reminded, "Expected another replica to wait while the lease is held")
+
+	leaseRepository.expiresAt = time.Now().Add(-time.Second)
+
+	reminded, err = secondReplica.Run()
+	assert.Nil(t, err)
+	assert.Equal(t, 1, reminded, "Expected another replica to take over an expired lease")
+}
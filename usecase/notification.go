@@ -0,0 +1,248 @@
+package usecase
+
+import (
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/team-inu/inu-backyard/entity"
+	errs "github.com/team-inu/inu-backyard/entity/error"
+	"github.com/team-inu/inu-backyard/internal/utils"
+)
+
+// read notifications are kept for 90 days, unread ones until they are read
+const notificationRetention = 90 * 24 * time.Hour
+
+type notificationUseCase struct {
+	notificationRepo entity.NotificationRepository
+	userUseCase      entity.UserUseCase
+	mailUseCase      entity.MailUseCase
+}
+
+func NewNotificationUseCase(
+	notificationRepo entity.NotificationRepository,
+	userUseCase entity.UserUseCase,
+	mailUseCase entity.MailUseCase,
+) entity.NotificationUseCase {
+	return &notificationUseCase{
+		notificationRepo: notificationRepo,
+		userUseCase:      userUseCase,
+		mailUseCase:      mailUseCase,
+	}
+}
+
+func (u notificationUseCase) Notify(userIds []string, event entity.NotificationEvent) error {
+	notified := map[string]bool{}
+	for _, userId := range userIds {
+		if notified[userId] {
+			continue
+		}
+		notified[userId] = true
+
+		err := u.notify(userId, event)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (u notificationUseCase) notify(userId string, event entity.NotificationEvent) error {
+	preference, err := u.getPreference(userId, event.Type)
+	if err != nil {
+		return err
+	}
+
+	// a notification grouped into an unread one was already emailed
+	isNew := true
+	if preference.InApp {
+		createdAt := time.Now()
+		isNew, err = u.notificationRepo.Create(&entity.Notification{
+			Id:          ulid.Make().String(),
+			UserId:      userId,
+			Type:        event.Type,
+			Title:       event.Title,
+			Message:     event.Message,
+			Link:        event.Link,
+			ReferenceId: event.ReferenceId,
+			Count:       1,
+			CreatedAt:   createdAt,
+			UpdatedAt:   createdAt,
+		})
+		if err != nil {
+			return errs.New(errs.ErrCreateNotification, "cannot create %s notification for user id %s", event.Type, userId, err)
+		}
+	}
+
+	if !preference.Email || !isNew {
+		return nil
+	}
+
+	user, err := u.userUseCase.GetById(userId)
+	if err != nil {
+		return errs.New(errs.SameCode, "cannot get user id %s to email notification", userId, err)
+	} else if user == nil {
+		return nil
+	}
+
+	err = u.mailUseCase.SendNotificationEmail(user.Email, event.Title, event.Message, event.Link)
+	if err != nil {
+		return errs.New(errs.SameCode, "cannot email %s notification to user id %s", event.Type, userId, err)
+	}
+
+	return nil
+}
+
+func (u notificationUseCase) NotifyRole(role entity.UserRole, event entity.NotificationEvent) error {
+	userIds, err := u.notificationRepo.GetUserIdsByRole(role)
+	if err != nil {
+		return errs.New(errs.ErrQueryNotification, "cannot get users with role %s to notify", role, err)
+	}
+
+	return u.Notify(userIds, event)
+}
+
+func (u notificationUseCase) GetByUserId(userId string, isUnreadOnly bool, pageIndex string, pageSize string) (*entity.Pagination, error) {
+	offset, limit, err := utils.ValidatePagination(pageIndex, pageSize)
+	if err != nil {
+		return nil, errs.New(errs.ErrQueryNotification, "cannot get notifications", err)
+	}
+
+	notifications, err := u.notificationRepo.GetByUserId(userId, isUnreadOnly, offset, limit)
+	if err != nil {
+		return nil, errs.New(errs.ErrQueryNotification, "cannot get notifications of user id %s", userId, err)
+	}
+
+	return notifications, nil
+}
+
+func (u notificationUseCase) CountUnread(userId string) (int64, error) {
+	count, err := u.notificationRepo.CountUnread(userId)
+	if err != nil {
+		return 0, errs.New(errs.ErrQueryNotification, "cannot count unread notifications of user id %s", userId, err)
+	}
+
+	return count, nil
+}
+
+func (u notificationUseCase) MarkRead(userId string, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	err := u.notificationRepo.MarkRead(userId, ids, time.Now())
+	if err != nil {
+		return errs.New(errs.ErrUpdateNotification, "cannot mark notifications of user id %s read", userId, err)
+	}
+
+	return nil
+}
+
+func (u notificationUseCase) MarkAllRead(userId string) error {
+	err := u.notificationRepo.MarkRead(userId, nil, time.Now())
+	if err != nil {
+		return errs.New(errs.ErrUpdateNotification, "cannot mark all notifications of user id %s read", userId, err)
+	}
+
+	return nil
+}
+
+func (u notificationUseCase) GetPreferences(userId string) ([]entity.NotificationPreference, error) {
+	saved, err := u.notificationRepo.GetPreferences(userId)
+	if err != nil {
+		return nil, errs.New(errs.ErrQueryNotification, "cannot get notification preferences of user id %s", userId, err)
+	}
+
+	savedByType := map[entity.NotificationType]entity.NotificationPreference{}
+	for _, preference := range saved {
+		savedByType[preference.Type] = preference
+	}
+
+	preferences := make([]entity.NotificationPreference, 0, len(entity.NotificationTypes))
+	for _, notificationType := range entity.NotificationTypes {
+		preference, ok := savedByType[notificationType]
+		if !ok {
+			preference = defaultNotificationPreference(userId, notificationType)
+		}
+		preferences = append(preferences, preference)
+	}
+
+	return preferences, nil
+}
+
+func (u notificationUseCase) getPreference(userId string, notificationType entity.NotificationType) (*entity.NotificationPreference, error) {
+	preferences, err := u.GetPreferences(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, preference := range preferences {
+		if preference.Type == notificationType {
+			return &preference, nil
+		}
+	}
+
+	preference := defaultNotificationPreference(userId, notificationType)
+	return &preference, nil
+}
+
+func (u notificationUseCase) UpdatePreferences(userId string, payload entity.UpdateNotificationPreferencesPayload) error {
+	preferences := make([]entity.NotificationPreference, 0, len(payload.Preferences))
+	for _, preference := range payload.Preferences {
+		if !isNotificationType(preference.Type) {
+			return errs.New(errs.ErrInvalidNotificationType, "notification type %s is not valid", preference.Type)
+		}
+
+		preferences = append(preferences, entity.NotificationPreference{
+			UserId: userId,
+			Type:   preference.Type,
+			InApp:  preference.InApp,
+			Email:  preference.Email,
+		})
+	}
+
+	err := u.notificationRepo.UpdatePreferences(preferences)
+	if err != nil {
+		return errs.New(errs.ErrUpdateNotification, "cannot update notification preferences of user id %s", userId, err)
+	}
+
+	return nil
+}
+
+func (u notificationUseCase) DeleteOld() (int64, error) {
+	deleted, err := u.notificationRepo.DeleteReadBefore(time.Now().Add(-notificationRetention))
+	if err != nil {
+		return 0, errs.New(errs.ErrUpdateNotification, "cannot delete old notifications", err)
+	}
+
+	return deleted, nil
+}
+
+func defaultNotificationPreference(userId string, notificationType entity.NotificationType) entity.NotificationPreference {
+	return entity.NotificationPreference{
+		UserId: userId,
+		Type:   notificationType,
+		InApp:  true,
+		Email:  false,
+	}
+}
+
+func isNotificationType(notificationType entity.NotificationType) bool {
+	for _, t := range entity.NotificationTypes {
+		if t == notificationType {
+			return true
+		}
+	}
+	return false
+}
+
+// courseLecturerIds returns the lecturers of a course loaded with its lecturers, the user who caused the event is left out
+func courseLecturerIds(course *entity.Course, excludedUserId string) []string {
+	userIds := make([]string, 0, len(course.Lecturers))
+	for _, lecturer := range course.Lecturers {
+		if lecturer.Id != excludedUserId {
+			userIds = append(userIds, lecturer.Id)
+		}
+	}
+	return userIds
+}
@@ -0,0 +1,241 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/team-inu/inu-backyard/entity"
+	errs "github.com/team-inu/inu-backyard/entity/error"
+	"github.com/team-inu/inu-backyard/infrastructure/mail"
+	"github.com/team-inu/inu-backyard/internal/config"
+)
+
+type stubNotificationRepository struct {
+	entity.NotificationRepository
+	notifications []entity.Notification
+	preferences   []entity.NotificationPreference
+	userUseCase   *stubUserUseCase
+}
+
+func (r *stubNotificationRepository) Create(notification *entity.Notification) (bool, error) {
+	for i, existing := range r.notifications {
+		if existing.UserId == notification.UserId && existing.Type == notification.Type && existing.ReferenceId == notification.ReferenceId && existing.ReadAt == nil {
+			r.notifications[i].Message = notification.Message
+			r.notifications[i].Count++
+			return false, nil
+		}
+	}
+	r.notifications = append(r.notifications, *notification)
+	return true, nil
+}
+
+func (r *stubNotificationRepository) CountUnread(userId string) (int64, error) {
+	var count int64
+	for _, notification := range r.notifications {
+		if notification.UserId == userId && notification.ReadAt == nil {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *stubNotificationRepository) MarkRead(userId string, ids []string, readAt time.Time) error {
+	for i, notification := range r.notifications {
+		if notification.UserId != userId || notification.ReadAt != nil {
+			continue
+		}
+		for _, id := range ids {
+			if id == notification.Id {
+				r.notifications[i].ReadAt = &readAt
+			}
+		}
+		if len(ids) == 0 {
+			r.notifications[i].ReadAt = &readAt
+		}
+	}
+	return nil
+}
+
+func (r *stubNotificationRepository) GetPreferences(userId string) ([]entity.NotificationPreference, error) {
+	preferences := []entity.NotificationPreference{}
+	for _, preference := range r.preferences {
+		if preference.UserId == userId {
+			preferences = append(preferences, preference)
+		}
+	}
+	return preferences, nil
+}
+
+func (r *stubNotificationRepository) UpdatePreferences(preferences []entity.NotificationPreference) error {
+	for _, preference := range preferences {
+		isUpdated := false
+		for i, existing := range r.preferences {
+			if existing.UserId == preference.UserId && existing.Type == preference.Type {
+				r.preferences[i] = preference
+				isUpdated = true
+			}
+		}
+		if !isUpdated {
+			r.preferences = append(r.preferences, preference)
+		}
+	}
+	return nil
+}
+
+func (r *stubNotificationRepository) GetUserIdsByRole(role entity.UserRole) ([]string, error) {
+	userIds := []string{}
+	for _, user := range r.userUseCase.users {
+		if user.IsRoles([]entity.UserRole{role}) {
+			userIds = append(userIds, user.Id)
+		}
+	}
+	return userIds, nil
+}
+
+func (r *stubNotificationRepository) countFor(userId string) int {
+	count := 0
+	for _, notification := range r.notifications {
+		if notification.UserId == userId {
+			count++
+		}
+	}
+	return count
+}
+
+func TestNotification(t *testing.T) {
+	renderer, err := mail.NewRenderer()
+	if err != nil {
+		t.Fatalf("Failed to parse mail templates: %v", err)
+	}
+
+	userUseCase := &stubUserUseCase{users: []entity.User{
+		{Id: "lecturer", Email: "lecturer@example.com", Role: entity.UserRoleLecturer},
+		{Id: "head", Email: "head@example.com", Role: entity.UserRoleLecturer + "," + entity.UserRoleHeadOfCurriculum},
+	}}
+	notificationRepository := &stubNotificationRepository{userUseCase: userUseCase}
+	mailer := mail.NewMemoryMailer()
+	mailUseCase, err := NewMailUseCase(&stubMailRepository{}, mailer, renderer, config.MailConfig{Driver: "memory", Language: "en"}, config.ClientConfig{BaseUrl: "http://localhost:3000"})
+	if err != nil {
+		t.Fatalf("Failed to create mail use case: %v", err)
+	}
+	notificationUseCase := NewNotificationUseCase(notificationRepository, userUseCase, mailUseCase)
+
+	streamEvent := entity.NotificationEvent{
+		Type:        entity.NotificationTypeCourseStream,
+		Title:       "New upstream comment from CPE100",
+		Message:     "Please cover recursion",
+		Link:        "/courses/course-1",
+		ReferenceId: "course-1",
+	}
+
+	t.Run("TestNotify_GroupsUnread", func(t *testing.T) {
+		err := notificationUseCase.Notify([]string{"lecturer", "lecturer"}, streamEvent)
+		assert.Nil(t, err, "Expected no error while notifying, got %v", err)
+
+		err = notificationUseCase.Notify([]string{"lecturer"}, streamEvent)
+		assert.Nil(t, err, "Expected no error while notifying, got %v", err)
+
+		assert.Equal(t, 1, notificationRepository.countFor("lecturer"), "Expected events of the same reference to be grouped")
+		assert.Equal(t, 2, notificationRepository.notifications[0].Count, "Expected grouped notification to count both events")
+
+		count, err := notificationUseCase.CountUnread("lecturer")
+		assert.Nil(t, err)
+		assert.Equal(t, int64(1), count, "Expected one unread notification")
+	})
+
+	t.Run("TestMarkAllRead", func(t *testing.T) {
+		err := notificationUseCase.MarkAllRead("lecturer")
+		assert.Nil(t, err, "Expected no error while marking read, got %v", err)
+
+		count, _ := notificationUseCase.CountUnread("lecturer")
+		assert.Equal(t, int64(0), count, "Expected no unread notification")
+
+		err = notificationUseCase.Notify([]string{"lecturer"}, streamEvent)
+		assert.Nil(t, err)
+		assert.Equal(t, 2, notificationRepository.countFor("lecturer"), "Expected a new notification once the previous one is read")
+	})
+
+	t.Run("TestNotifyRole", func(t *testing.T) {
+		err := notificationUseCase.NotifyRole(entity.UserRoleHeadOfCurriculum, entity.NotificationEvent{
+			Type:        entity.NotificationTypePortfolioSubmitted,
+			Title:       "Portfolio of CPE100 submitted",
+			ReferenceId: "course-1",
+		})
+		assert.Nil(t, err, "Expected no error while notifying role, got %v", err)
+		assert.Equal(t, 1, notificationRepository.countFor("head"), "Expected curriculum head to be notified")
+		assert.Equal(t, 2, notificationRepository.countFor("lecturer"), "Expected lecturer without the role to be left out")
+	})
+
+	t.Run("TestPreferences", func(t *testing.T) {
+		preferences, err := notificationUseCase.GetPreferences("head")
+		assert.Nil(t, err)
+		assert.Len(t, preferences, len(entity.NotificationTypes), "Expected a preference for every type")
+		assert.True(t, preferences[0].InApp, "Expected in-app to be on by default")
+		assert.False(t, preferences[0].Email, "Expected email to be off by default")
+
+		err = notificationUseCase.UpdatePreferences("head", entity.UpdateNotificationPreferencesPayload{
+			Preferences: []entity.NotificationPreferencePayload{{Type: "UNKNOWN"}},
+		})
+		assert.Equal(t, errs.ErrInvalidNotificationType, errorCode(err), "Expected unknown type to be refused, got %v", err)
+	})
+
+	t.Run("TestNotify_EmailOnly", func(t *testing.T) {
+		err := notificationUseCase.UpdatePreferences("head", entity.UpdateNotificationPreferencesPayload{
+			Preferences: []entity.NotificationPreferencePayload{{Type: entity.NotificationTypeSurveyResponse, InApp: false, Email: true}},
+		})
+		assert.Nil(t, err, "Expected no error while updating preferences, got %v", err)
+
+		surveyEvent := entity.NotificationEvent{
+			Type:        entity.NotificationTypeSurveyResponse,
+			Title:       "New response to Alumni Survey",
+			Message:     "A respondent answered the survey Alumni Survey.",
+			Link:        "/surveys/survey-1",
+			ReferenceId: "survey-1",
+		}
+		err = notificationUseCase.NotifyRole(entity.UserRoleHeadOfCurriculum, surveyEvent)
+		assert.Nil(t, err, "Expected no error while notifying, got %v", err)
+		assert.Equal(t, 1, notificationRepository.countFor("head"), "Expected no in-app notification when turned off")
+
+		_, err = mailUseCase.ProcessOutbox()
+		assert.Nil(t, err)
+
+		messages := mailer.Messages()
+		assert.Len(t, messages, 1, "Expected notification to be emailed")
+		assert.Equal(t, "head@example.com", messages[0].To)
+		assert.Equal(t, "New response to Alumni Survey", messages[0].Subject)
+		assert.Contains(t, messages[0].Html, "http://localhost:3000/surveys/survey-1", "Expected link from client base url")
+	})
+
+	t.Run("TestNotify_EmailOncePerGroup", func(t *testing.T) {
+		err := notificationUseCase.UpdatePreferences("lecturer", entity.UpdateNotificationPreferencesPayload{
+			Preferences: []entity.NotificationPreferencePayload{{Type: entity.NotificationTypeMissingScores, InApp: true, Email: true}},
+		})
+		assert.Nil(t, err)
+
+		event := entity.NotificationEvent{Type: entity.NotificationTypeMissingScores, Title: "Missing scores in CPE100", ReferenceId: "course-1"}
+		for i := 0; i < 3; i++ {
+			err = notificationUseCase.Notify([]string{"lecturer"}, event)
+			assert.Nil(t, err)
+		}
+
+		_, err = mailUseCase.ProcessOutbox()
+		assert.Nil(t, err)
+		assert.Len(t, mailer.Messages(), 2, "Expected grouped events to be emailed once")
+	})
+}
+
+func TestCountMissingScores(t *testing.T) {
+	five := 5.0
+	groups := []ImportAssignmentGroup{{
+		Name: "Quiz",
+		Assignments: []assignment{
+			{Name: "Quiz 1", Scores: []score{{Score: &five, StudentId: "s1"}, {Score: &five, StudentId: "s2"}}},
+			{Name: "Quiz 2", Scores: []score{{Score: &five, StudentId: "s1"}}},
+		},
+	}}
+
+	students, assignments := countMissingScores([]string{"s1", "s2", "s3"}, groups)
+	assert.Equal(t, 2, students, "Expected s2 and s3 to miss scores")
+	assert.Equal(t, 2, assignments, "Expected both quizzes to miss scores")
+}
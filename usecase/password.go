@@ -0,0 +1,120 @@
+package usecase
+
+import (
+	"time"
+
+	"github.com/team-inu/inu-backyard/entity"
+	errs "github.com/team-inu/inu-backyard/entity/error"
+	"github.com/team-inu/inu-backyard/internal/config"
+	"github.com/team-inu/inu-backyard/internal/utils"
+	"github.com/team-inu/inu-backyard/internal/utils/password"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const passwordViolationReused = "reused"
+
+type passwordUseCase struct {
+	passwordRepo entity.PasswordRepository
+	policy       *password.Policy
+	config       config.PasswordConfig
+}
+
+func NewPasswordUseCase(
+	passwordRepo entity.PasswordRepository,
+	policy *password.Policy,
+	config config.PasswordConfig,
+) entity.PasswordUseCase {
+	return &passwordUseCase{
+		passwordRepo: passwordRepo,
+		policy:       policy,
+		config:       config,
+	}
+}
+
+func (u passwordUseCase) Validate(newPassword string, user *entity.User) error {
+	violations := u.policy.Validate(newPassword)
+
+	if len(violations) == 0 && user != nil {
+		isReused, err := u.isReused(newPassword, *user)
+		if err != nil {
+			return err
+		} else if isReused {
+			violations = append(violations, passwordViolationReused)
+		}
+	}
+
+	if len(violations) == 0 {
+		return nil
+	}
+
+	details := make([]errs.ValidationErrorDetail, 0, len(violations))
+	for _, violation := range violations {
+		details = append(details, errs.ValidationErrorDetail{Field: "password", Tag: violation})
+	}
+
+	return errs.NewValidationErr(errs.ErrPasswordPolicy, "password does not meet the password policy", details)
+}
+
+func (u passwordUseCase) Change(user entity.User, newPassword string) error {
+	err := u.Validate(newPassword, &user)
+	if err != nil {
+		return err
+	}
+
+	hashPassword, err := utils.HashPassword(newPassword)
+	if err != nil {
+		return errs.New(errs.ErrUpdatePassword, "cannot hash password of user id %s", user.Id, err)
+	}
+
+	err = u.passwordRepo.UpdatePassword(user.Id, hashPassword, time.Now(), u.config.HistorySize)
+	if err != nil {
+		return errs.New(errs.ErrUpdatePassword, "cannot update password of user id %s", user.Id, err)
+	}
+
+	return nil
+}
+
+func (u passwordUseCase) IsChangeRequired(user entity.User) bool {
+	if user.MustChangePassword {
+		return true
+	} else if user.PasswordChangedAt == nil {
+		return false
+	}
+
+	days := 0
+	for _, expiry := range u.config.Expiry {
+		if expiry.Days <= 0 || !user.IsRoles([]entity.UserRole{entity.UserRole(expiry.Role)}) {
+			continue
+		}
+
+		if days == 0 || expiry.Days < days {
+			days = expiry.Days
+		}
+	}
+
+	return days > 0 && time.Since(*user.PasswordChangedAt) > time.Duration(days)*24*time.Hour
+}
+
+// isReused compares with the current password and the history, the history may not contain the current password of users created before it existed
+func (u passwordUseCase) isReused(newPassword string, user entity.User) (bool, error) {
+	hashes := []string{user.Password}
+
+	if u.config.HistorySize > 0 {
+		histories, err := u.passwordRepo.GetHistory(user.Id, u.config.HistorySize)
+		if err != nil {
+			return false, errs.New(errs.ErrQueryPassword, "cannot get password history of user id %s", user.Id, err)
+		}
+
+		for _, history := range histories {
+			hashes = append(hashes, history.PasswordHash)
+		}
+	}
+
+	for _, hash := range hashes {
+		if hash != "" && bcrypt.CompareHashAndPassword([]byte(hash), []byte(newPassword)) == nil {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
@@ -0,0 +1,153 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/team-inu/inu-backyard/entity"
+	errs "github.com/team-inu/inu-backyard/entity/error"
+	"github.com/team-inu/inu-backyard/internal/config"
+	"github.com/team-inu/inu-backyard/internal/utils"
+	"github.com/team-inu/inu-backyard/internal/utils/password"
+)
+
+// stubPasswordRepository keeps the password columns on the users of stubUserUseCase
+type stubPasswordRepository struct {
+	entity.PasswordRepository
+	userUseCase *stubUserUseCase
+	histories   []entity.PasswordHistory
+}
+
+func (r *stubPasswordRepository) GetHistory(userId string, limit int) ([]entity.PasswordHistory, error) {
+	histories := []entity.PasswordHistory{}
+	for i := len(r.histories) - 1; i >= 0 && len(histories) < limit; i-- {
+		if r.histories[i].UserId == userId {
+			histories = append(histories, r.histories[i])
+		}
+	}
+	return histories, nil
+}
+
+func (r *stubPasswordRepository) UpdatePassword(userId string, passwordHash string, changedAt time.Time, historySize int) error {
+	for i := range r.userUseCase.users {
+		if r.userUseCase.users[i].Id == userId {
+			r.userUseCase.users[i].Password = passwordHash
+			r.userUseCase.users[i].PasswordChangedAt = &changedAt
+			r.userUseCase.users[i].MustChangePassword = false
+		}
+	}
+	r.histories = append(r.histories, entity.PasswordHistory{UserId: userId, PasswordHash: passwordHash, CreatedAt: changedAt})
+	return nil
+}
+
+func newTestPasswordUseCase(t *testing.T, userUseCase *stubUserUseCase, passwordConfig config.PasswordConfig) entity.PasswordUseCase {
+	policy, err := password.NewPolicy(passwordConfig.MinLength, passwordConfig.RequireUpper, passwordConfig.RequireLower, passwordConfig.RequireDigit, passwordConfig.RequireSymbol, "")
+	if err != nil {
+		t.Fatalf("Failed to create password policy: %v", err)
+	}
+	return NewPasswordUseCase(&stubPasswordRepository{userUseCase: userUseCase}, policy, passwordConfig)
+}
+
+func violationTags(err error) []string {
+	domainErr, ok := err.(*errs.DomainError)
+	if !ok {
+		return nil
+	}
+
+	tags := []string{}
+	for _, detail := range domainErr.Details.([]errs.ValidationErrorDetail) {
+		tags = append(tags, detail.Tag)
+	}
+	return tags
+}
+
+func TestPassword(t *testing.T) {
+	hashPassword, err := utils.HashPassword("Initial-pass1")
+	if err != nil {
+		t.Fatalf("Failed to hash password: %v", err)
+	}
+
+	passwordConfig := config.PasswordConfig{
+		MinLength:    10,
+		RequireUpper: true,
+		RequireDigit: true,
+		HistorySize:  2,
+		Expiry: []config.PasswordExpiryConfig{
+			{Role: string(entity.UserRoleHeadOfCurriculum), Days: 90},
+			{Role: string(entity.UserRoleLecturer), Days: 30},
+		},
+	}
+	userUseCase := &stubUserUseCase{users: []entity.User{{Id: "user-1", Email: "lecturer@example.com", Password: hashPassword}}}
+	passwordUseCase := newTestPasswordUseCase(t, userUseCase, passwordConfig)
+
+	t.Run("TestValidate_Policy", func(t *testing.T) {
+		err := passwordUseCase.Validate("short", nil)
+		assert.Equal(t, errs.ErrPasswordPolicy, errorCode(err), "Expected policy error, got %v", err)
+		assert.ElementsMatch(t, []string{password.ViolationMinLength, password.ViolationUpper, password.ViolationDigit}, violationTags(err), "Expected every broken rule")
+	})
+
+	t.Run("TestValidate_Breached", func(t *testing.T) {
+		err := passwordUseCase.Validate("Password123", nil)
+		assert.Equal(t, []string{password.ViolationBreached}, violationTags(err), "Expected breached password to be refused, got %v", err)
+	})
+
+	t.Run("TestChange_History", func(t *testing.T) {
+		user, _ := userUseCase.GetById("user-1")
+		err := passwordUseCase.Change(*user, "Initial-pass1")
+		assert.Equal(t, []string{passwordViolationReused}, violationTags(err), "Expected current password to be refused, got %v", err)
+
+		for _, newPassword := range []string{"Second-pass2", "Third-pass33"} {
+			user, _ = userUseCase.GetById("user-1")
+			err = passwordUseCase.Change(*user, newPassword)
+			assert.Nil(t, err, "Expected no error while changing to %s, got %v", newPassword, err)
+		}
+
+		user, _ = userUseCase.GetById("user-1")
+		err = passwordUseCase.Change(*user, "Second-pass2")
+		assert.Equal(t, []string{passwordViolationReused}, violationTags(err), "Expected password in history to be refused, got %v", err)
+
+		err = passwordUseCase.Change(*user, "Initial-pass1")
+		assert.Nil(t, err, "Expected password out of history to be accepted, got %v", err)
+	})
+
+	t.Run("TestIsChangeRequired", func(t *testing.T) {
+		longAgo := time.Now().Add(-60 * 24 * time.Hour)
+
+		assert.True(t, passwordUseCase.IsChangeRequired(entity.User{MustChangePassword: true}), "Expected forced change")
+		assert.False(t, passwordUseCase.IsChangeRequired(entity.User{Role: entity.UserRoleLecturer}), "Expected no expiry without change date")
+		assert.True(t, passwordUseCase.IsChangeRequired(entity.User{Role: entity.UserRoleLecturer, PasswordChangedAt: &longAgo}), "Expected lecturer password to expire")
+		assert.False(t, passwordUseCase.IsChangeRequired(entity.User{Role: entity.UserRoleHeadOfCurriculum, PasswordChangedAt: &longAgo}), "Expected longer expiry for head of curriculum")
+		assert.True(t, passwordUseCase.IsChangeRequired(entity.User{Role: entity.UserRoleHeadOfCurriculum + "," + entity.UserRoleLecturer, PasswordChangedAt: &longAgo}), "Expected shortest expiry of all roles")
+	})
+}
+
+func TestForcedPasswordChange(t *testing.T) {
+	hashedPassword, _ := utils.HashPassword("Bulk-created1")
+
+	authConfig := config.AuthConfig{
+		Session:  config.SessionConfig{MaxAge: 3600, Secret: "secret", Prefix: "$", CookieName: "inu_backyard"},
+		Password: config.PasswordConfig{MinLength: 10, HistorySize: 5},
+	}
+
+	sessionUseCase := NewSessionUseCase(&stubSessionRepository{}, authConfig)
+	userUseCase := &stubUserUseCase{users: []entity.User{
+		{Id: "user-1", Email: "lecturer@example.com", Password: hashedPassword, MustChangePassword: true},
+	}}
+	twoFactorUseCase := NewTwoFactorUseCase(&stubTwoFactorRepository{userUseCase: userUseCase}, userUseCase, authConfig.TwoFactor)
+	authUseCase := NewAuthUseCase(sessionUseCase, userUseCase, nil, nil, authConfig, []entity.CredentialVerifier{NewPasswordCredentialVerifier(userUseCase)}, twoFactorUseCase, NewLoginThrottleUseCase(&stubLoginThrottleRepository{}, userUseCase, nil, authConfig.Throttle), newTestPasswordUseCase(t, userUseCase, authConfig.Password))
+
+	result, err := authUseCase.SignIn(entity.SignInPayload{Email: "lecturer@example.com", Password: "Bulk-created1"}, "127.0.0.1", "test")
+	assert.Nil(t, err, "Expected no error while signing in, got %v", err)
+	assert.True(t, result.PasswordChangeRequired, "Expected bulk created user to change password")
+
+	err = authUseCase.ChangePassword("user-1", "Bulk-created1", "Bulk-created1")
+	assert.Equal(t, errs.ErrPasswordPolicy, errorCode(err), "Expected same password to be refused, got %v", err)
+
+	err = authUseCase.ChangePassword("user-1", "Bulk-created1", "my own choice")
+	assert.Nil(t, err, "Expected no error while changing password, got %v", err)
+
+	result, err = authUseCase.SignIn(entity.SignInPayload{Email: "lecturer@example.com", Password: "my own choice"}, "127.0.0.1", "test")
+	assert.Nil(t, err, "Expected no error while signing in with new password, got %v", err)
+	assert.False(t, result.PasswordChangeRequired, "Expected no change required after changing password")
+}
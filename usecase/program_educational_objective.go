@@ -0,0 +1,220 @@
+package usecase
+
+import (
+	"github.com/oklog/ulid/v2"
+	"github.com/team-inu/inu-backyard/entity"
+	errs "github.com/team-inu/inu-backyard/entity/error"
+	slice "github.com/team-inu/inu-backyard/internal/utils/slice"
+)
+
+type programEducationalObjectiveUseCase struct {
+	programEducationalObjectiveRepo entity.ProgramEducationalObjectiveRepository
+	programmeUseCase                entity.ProgrammeUseCase
+}
+
+func NewProgramEducationalObjectiveUseCase(programEducationalObjectiveRepo entity.ProgramEducationalObjectiveRepository, programmeUseCase entity.ProgrammeUseCase) entity.ProgramEducationalObjectiveUseCase {
+	return &programEducationalObjectiveUseCase{programEducationalObjectiveRepo: programEducationalObjectiveRepo, programmeUseCase: programmeUseCase}
+}
+
+func (u programEducationalObjectiveUseCase) GetByProgrammeId(programmeId string) ([]entity.ProgramEducationalObjective, error) {
+	programme, err := u.programmeUseCase.GetById(programmeId)
+	if err != nil {
+		return nil, errs.New(errs.SameCode, "cannot get programme id %s to get peos", programmeId, err)
+	} else if programme == nil {
+		return nil, errs.New(errs.ErrProgrammeNotFound, "programme id %s not found while getting peos", programmeId)
+	}
+
+	peos, err := u.programEducationalObjectiveRepo.GetByProgrammeId(programmeId)
+	if err != nil {
+		return nil, errs.New(errs.ErrQueryPEO, "cannot get peos by programme id %s", programmeId, err)
+	}
+
+	return peos, nil
+}
+
+func (u programEducationalObjectiveUseCase) GetById(programmeId string, id string) (*entity.ProgramEducationalObjective, error) {
+	peo, err := u.programEducationalObjectiveRepo.GetById(id)
+	if err != nil {
+		return nil, errs.New(errs.ErrQueryPEO, "cannot get peo by id %s", id, err)
+	}
+
+	if peo != nil && peo.ProgrammeId != programmeId {
+		return nil, nil
+	}
+
+	return peo, nil
+}
+
+func (u programEducationalObjectiveUseCase) Create(programmeId string, payload entity.CreateProgramEducationalObjectivePayload) error {
+	programme, err := u.programmeUseCase.GetById(programmeId)
+	if err != nil {
+		return errs.New(errs.SameCode, "cannot get programme id %s to create peo", programmeId, err)
+	} else if programme == nil {
+		return errs.New(errs.ErrProgrammeNotFound, "programme id %s not found while creating peo", programmeId)
+	}
+
+	peo := &entity.ProgramEducationalObjective{
+		Id:          ulid.Make().String(),
+		Code:        payload.Code,
+		Description: payload.Description,
+		ProgrammeId: programmeId,
+	}
+
+	err = u.programEducationalObjectiveRepo.Create(peo)
+	if err != nil {
+		return errs.New(errs.ErrCreatePEO, "cannot create peo", err)
+	}
+
+	return nil
+}
+
+func (u programEducationalObjectiveUseCase) Update(programmeId string, id string, payload entity.UpdateProgramEducationalObjectivePayload) error {
+	existPEO, err := u.GetById(programmeId, id)
+	if err != nil {
+		return errs.New(errs.SameCode, "cannot get peo id %s to update", id, err)
+	} else if existPEO == nil {
+		return errs.New(errs.ErrPEONotFound, "cannot get peo id %s to update", id)
+	}
+
+	err = u.programEducationalObjectiveRepo.Update(id, &entity.ProgramEducationalObjective{
+		Code:        payload.Code,
+		Description: payload.Description,
+	})
+	if err != nil {
+		return errs.New(errs.ErrUpdatePEO, "cannot update peo by id %s", id, err)
+	}
+
+	return nil
+}
+
+func (u programEducationalObjectiveUseCase) Delete(programmeId string, id string) error {
+	existPEO, err := u.GetById(programmeId, id)
+	if err != nil {
+		return errs.New(errs.SameCode, "cannot get peo id %s to delete", id, err)
+	} else if existPEO == nil {
+		return errs.New(errs.ErrPEONotFound, "cannot get peo id %s to delete", id)
+	}
+
+	err = u.programEducationalObjectiveRepo.Delete(id)
+	if err != nil {
+		return errs.New(errs.ErrDeletePEO, "cannot delete peo by id %s", id, err)
+	}
+
+	return nil
+}
+
+func (u programEducationalObjectiveUseCase) CreateLinkProgramLearningOutcome(programmeId string, id string, ploIds []string) error {
+	peo, err := u.GetById(programmeId, id)
+	if err != nil {
+		return errs.New(errs.SameCode, "cannot get peo id %s to create link", id, err)
+	} else if peo == nil {
+		return errs.New(errs.ErrPEONotFound, "peo id %s not found while creating link", id)
+	}
+
+	plos, err := u.programmeUseCase.GetAllPLO(programmeId)
+	if err != nil {
+		return errs.New(errs.SameCode, "cannot get plos of programme id %s while creating link", programmeId, err)
+	}
+
+	programmePLOIds := make([]string, 0, len(plos))
+	for _, plo := range plos {
+		programmePLOIds = append(programmePLOIds, plo.Id)
+	}
+
+	existedPLOIds := slice.Intersection(programmePLOIds, ploIds)
+	if len(existedPLOIds) != len(ploIds) {
+		nonExistedPLOIds := slice.Subtraction(ploIds, existedPLOIds)
+		return errs.New(errs.ErrPLONotFound, "plo ids not found in programme while creating link: %v", nonExistedPLOIds)
+	}
+
+	linkedPLOIds := make([]string, 0, len(peo.ProgramLearningOutcomes))
+	for _, plo := range peo.ProgramLearningOutcomes {
+		linkedPLOIds = append(linkedPLOIds, plo.Id)
+	}
+
+	newPLOIds := slice.Subtraction(ploIds, linkedPLOIds)
+	if len(newPLOIds) == 0 {
+		return nil
+	}
+
+	err = u.programEducationalObjectiveRepo.CreateLinkProgramLearningOutcome(id, newPLOIds)
+	if err != nil {
+		return errs.New(errs.ErrCreatePEO, "cannot create link between peo and plo", err)
+	}
+
+	return nil
+}
+
+func (u programEducationalObjectiveUseCase) CreateLinkStudentOutcome(programmeId string, id string, soIds []string) error {
+	peo, err := u.GetById(programmeId, id)
+	if err != nil {
+		return errs.New(errs.SameCode, "cannot get peo id %s to create link", id, err)
+	} else if peo == nil {
+		return errs.New(errs.ErrPEONotFound, "peo id %s not found while creating link", id)
+	}
+
+	sos, err := u.programmeUseCase.GetAllSO(programmeId)
+	if err != nil {
+		return errs.New(errs.SameCode, "cannot get sos of programme id %s while creating link", programmeId, err)
+	}
+
+	programmeSOIds := make([]string, 0, len(sos))
+	for _, so := range sos {
+		programmeSOIds = append(programmeSOIds, so.Id)
+	}
+
+	existedSOIds := slice.Intersection(programmeSOIds, soIds)
+	if len(existedSOIds) != len(soIds) {
+		nonExistedSOIds := slice.Subtraction(soIds, existedSOIds)
+		return errs.New(errs.ErrSONotFound, "so ids not found in programme while creating link: %v", nonExistedSOIds)
+	}
+
+	linkedSOIds := make([]string, 0, len(peo.StudentOutcomes))
+	for _, so := range peo.StudentOutcomes {
+		linkedSOIds = append(linkedSOIds, so.Id)
+	}
+
+	newSOIds := slice.Subtraction(soIds, linkedSOIds)
+	if len(newSOIds) == 0 {
+		return nil
+	}
+
+	err = u.programEducationalObjectiveRepo.CreateLinkStudentOutcome(id, newSOIds)
+	if err != nil {
+		return errs.New(errs.ErrCreatePEO, "cannot create link between peo and so", err)
+	}
+
+	return nil
+}
+
+func (u programEducationalObjectiveUseCase) DeleteLinkProgramLearningOutcome(programmeId string, id string, ploId string) error {
+	peo, err := u.GetById(programmeId, id)
+	if err != nil {
+		return errs.New(errs.SameCode, "cannot get peo id %s to delete link", id, err)
+	} else if peo == nil {
+		return errs.New(errs.ErrPEONotFound, "peo id %s not found while deleting link", id)
+	}
+
+	err = u.programEducationalObjectiveRepo.DeleteLinkProgramLearningOutcome(id, ploId)
+	if err != nil {
+		return errs.New(errs.ErrDeletePEO, "cannot delete link between peo and plo", err)
+	}
+
+	return nil
+}
+
+func (u programEducationalObjectiveUseCase) DeleteLinkStudentOutcome(programmeId string, id string, soId string) error {
+	peo, err := u.GetById(programmeId, id)
+	if err != nil {
+		return errs.New(errs.SameCode, "cannot get peo id %s to delete link", id, err)
+	} else if peo == nil {
+		return errs.New(errs.ErrPEONotFound, "peo id %s not found while deleting link", id)
+	}
+
+	err = u.programEducationalObjectiveRepo.DeleteLinkStudentOutcome(id, soId)
+	if err != nil {
+		return errs.New(errs.ErrDeletePEO, "cannot delete link between peo and so", err)
+	}
+
+	return nil
+}
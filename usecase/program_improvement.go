@@ -0,0 +1,394 @@
+package usecase
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/team-inu/inu-backyard/entity"
+	errs "github.com/team-inu/inu-backyard/entity/error"
+	slice "github.com/team-inu/inu-backyard/internal/utils/slice"
+	"github.com/team-inu/inu-backyard/utils"
+	"github.com/xuri/excelize/v2"
+)
+
+type programImprovementUseCase struct {
+	programImprovementRepo entity.ProgramImprovementRepository
+	programmeUseCase       entity.ProgrammeUseCase
+	courseUseCase          entity.CourseUseCase
+	surveyUseCase          entity.SurveyUseCase
+	userUseCase            entity.UserUseCase
+}
+
+func NewProgramImprovementUseCase(
+	programImprovementRepo entity.ProgramImprovementRepository,
+	programmeUseCase entity.ProgrammeUseCase,
+	courseUseCase entity.CourseUseCase,
+	surveyUseCase entity.SurveyUseCase,
+	userUseCase entity.UserUseCase,
+) entity.ProgramImprovementUseCase {
+	return &programImprovementUseCase{
+		programImprovementRepo: programImprovementRepo,
+		programmeUseCase:       programmeUseCase,
+		courseUseCase:          courseUseCase,
+		surveyUseCase:          surveyUseCase,
+		userUseCase:            userUseCase,
+	}
+}
+
+func (u programImprovementUseCase) GetByProgrammeId(programmeId string, status entity.ProgramImprovementStatus) ([]entity.ProgramImprovement, error) {
+	programme, err := u.programmeUseCase.GetById(programmeId)
+	if err != nil {
+		return nil, errs.New(errs.SameCode, "cannot get programme id %s to get program improvements", programmeId, err)
+	} else if programme == nil {
+		return nil, errs.New(errs.ErrProgrammeNotFound, "programme id %s not found while getting program improvements", programmeId)
+	}
+
+	improvements, err := u.programImprovementRepo.GetByProgrammeId(programmeId, status)
+	if err != nil {
+		return nil, errs.New(errs.ErrQueryProgramImprovement, "cannot get program improvements by programme id %s", programmeId, err)
+	}
+
+	return improvements, nil
+}
+
+func (u programImprovementUseCase) GetById(programmeId string, id string) (*entity.ProgramImprovement, error) {
+	improvement, err := u.programImprovementRepo.GetById(id)
+	if err != nil {
+		return nil, errs.New(errs.ErrQueryProgramImprovement, "cannot get program improvement by id %s", id, err)
+	}
+
+	if improvement != nil && improvement.ProgrammeId != programmeId {
+		return nil, nil
+	}
+
+	return improvement, nil
+}
+
+func (u programImprovementUseCase) Create(user entity.User, programmeId string, payload entity.CreateProgramImprovementRequestPayload) error {
+	programme, err := u.programmeUseCase.GetById(programmeId)
+	if err != nil {
+		return errs.New(errs.SameCode, "cannot get programme id %s to create program improvement", programmeId, err)
+	} else if programme == nil {
+		return errs.New(errs.ErrProgrammeNotFound, "programme id %s not found while creating program improvement", programmeId)
+	}
+
+	ownerId := payload.OwnerId
+	if ownerId == "" {
+		ownerId = user.Id
+	} else if err := u.validateOwner(ownerId); err != nil {
+		return err
+	}
+
+	status := payload.Status
+	if status == "" {
+		status = entity.ProgramImprovementStatusOpen
+	} else if status == entity.ProgramImprovementStatusVerified {
+		return errs.New(errs.ErrInvalidProgramImprovementStatus, "cannot create program improvement as verified before it is closed")
+	}
+
+	links := entity.ProgramImprovementLinks{
+		CourseIds:                 payload.CourseIds,
+		ProgramLearningOutcomeIds: payload.ProgramLearningOutcomeIds,
+		ProgramOutcomeIds:         payload.ProgramOutcomeIds,
+		SurveyIds:                 payload.SurveyIds,
+	}
+
+	err = u.validateLinks(programmeId, links)
+	if err != nil {
+		return err
+	}
+
+	improvement := &entity.ProgramImprovement{
+		Id:              ulid.Make().String(),
+		ProgrammeId:     programmeId,
+		IssueIdentified: payload.IssueIdentified,
+		ActionTaken:     payload.ActionTaken,
+		Result:          payload.Result,
+		Date:            payload.Date,
+		Status:          status,
+		OwnerId:         ownerId,
+		DueDate:         payload.DueDate,
+	}
+
+	err = u.programImprovementRepo.Create(improvement)
+	if err != nil {
+		return errs.New(errs.ErrCreateProgramImprovement, "cannot create program improvement", err)
+	}
+
+	err = u.programImprovementRepo.ReplaceLinks(improvement.Id, links)
+	if err != nil {
+		return errs.New(errs.ErrCreateProgramImprovement, "cannot link program improvement id %s", improvement.Id, err)
+	}
+
+	return nil
+}
+
+func (u programImprovementUseCase) Update(programmeId string, id string, payload entity.UpdateProgramImprovementRequestPayload) error {
+	existImprovement, err := u.GetById(programmeId, id)
+	if err != nil {
+		return errs.New(errs.SameCode, "cannot get program improvement id %s to update", id, err)
+	} else if existImprovement == nil {
+		return errs.New(errs.ErrProgramImprovementNotFound, "cannot get program improvement id %s to update", id)
+	}
+
+	// an item can only be verified once its action has been closed
+	if payload.Status == entity.ProgramImprovementStatusVerified &&
+		existImprovement.Status != entity.ProgramImprovementStatusClosed &&
+		existImprovement.Status != entity.ProgramImprovementStatusVerified {
+		return errs.New(errs.ErrInvalidProgramImprovementStatus, "cannot verify program improvement id %s with status %s", id, existImprovement.Status)
+	}
+
+	if payload.OwnerId != "" {
+		if err := u.validateOwner(payload.OwnerId); err != nil {
+			return err
+		}
+	}
+
+	links := entity.ProgramImprovementLinks{
+		CourseIds:                 payload.CourseIds,
+		ProgramLearningOutcomeIds: payload.ProgramLearningOutcomeIds,
+		ProgramOutcomeIds:         payload.ProgramOutcomeIds,
+		SurveyIds:                 payload.SurveyIds,
+	}
+
+	err = u.validateLinks(programmeId, links)
+	if err != nil {
+		return err
+	}
+
+	improvement := &entity.ProgramImprovement{
+		IssueIdentified: payload.IssueIdentified,
+		ActionTaken:     payload.ActionTaken,
+		Result:          payload.Result,
+		Status:          payload.Status,
+		OwnerId:         payload.OwnerId,
+		DueDate:         payload.DueDate,
+	}
+	if payload.Date != nil {
+		improvement.Date = *payload.Date
+	}
+
+	err = u.programImprovementRepo.Update(id, improvement)
+	if err != nil {
+		return errs.New(errs.ErrUpdateProgramImprovement, "cannot update program improvement by id %s", id, err)
+	}
+
+	err = u.programImprovementRepo.ReplaceLinks(id, links)
+	if err != nil {
+		return errs.New(errs.ErrUpdateProgramImprovement, "cannot update links of program improvement id %s", id, err)
+	}
+
+	return nil
+}
+
+func (u programImprovementUseCase) Delete(programmeId string, id string) error {
+	existImprovement, err := u.GetById(programmeId, id)
+	if err != nil {
+		return errs.New(errs.SameCode, "cannot get program improvement id %s to delete", id, err)
+	} else if existImprovement == nil {
+		return errs.New(errs.ErrProgramImprovementNotFound, "cannot get program improvement id %s to delete", id)
+	}
+
+	err = u.programImprovementRepo.Delete(id)
+	if err != nil {
+		return errs.New(errs.ErrDeleteProgramImprovement, "cannot delete program improvement by id %s", id, err)
+	}
+
+	return nil
+}
+
+func (u programImprovementUseCase) validateOwner(ownerId string) error {
+	owner, err := u.userUseCase.GetById(ownerId)
+	if err != nil {
+		return errs.New(errs.SameCode, "cannot get owner id %s of program improvement", ownerId, err)
+	} else if owner == nil {
+		return errs.New(errs.ErrUserNotFound, "owner id %s of program improvement not found", ownerId)
+	}
+
+	return nil
+}
+
+func (u programImprovementUseCase) validateLinks(programmeId string, links entity.ProgramImprovementLinks) error {
+	for _, courseId := range links.CourseIds {
+		course, err := u.courseUseCase.GetById(courseId)
+		if err != nil {
+			return errs.New(errs.SameCode, "cannot get course id %s to link program improvement", courseId, err)
+		} else if course == nil || course.ProgrammeId != programmeId {
+			return errs.New(errs.ErrCourseNotFound, "course id %s not found in programme id %s", courseId, programmeId)
+		}
+	}
+
+	if len(links.ProgramLearningOutcomeIds) > 0 {
+		plos, err := u.programmeUseCase.GetAllPLO(programmeId)
+		if err != nil {
+			return errs.New(errs.SameCode, "cannot get plos of programme id %s to link program improvement", programmeId, err)
+		}
+
+		ploIds := make([]string, 0, len(plos))
+		for _, plo := range plos {
+			ploIds = append(ploIds, plo.Id)
+		}
+
+		nonExistedPLOIds := slice.Subtraction(links.ProgramLearningOutcomeIds, ploIds)
+		if len(nonExistedPLOIds) > 0 {
+			return errs.New(errs.ErrPLONotFound, "plo ids not found in programme while linking program improvement: %v", nonExistedPLOIds)
+		}
+	}
+
+	if len(links.ProgramOutcomeIds) > 0 {
+		pos, err := u.programmeUseCase.GetAllPO(programmeId)
+		if err != nil {
+			return errs.New(errs.SameCode, "cannot get pos of programme id %s to link program improvement", programmeId, err)
+		}
+
+		poIds := make([]string, 0, len(pos))
+		for _, po := range pos {
+			poIds = append(poIds, po.Id)
+		}
+
+		nonExistedPOIds := slice.Subtraction(links.ProgramOutcomeIds, poIds)
+		if len(nonExistedPOIds) > 0 {
+			return errs.New(errs.ErrPONotFound, "po ids not found in programme while linking program improvement: %v", nonExistedPOIds)
+		}
+	}
+
+	for _, surveyId := range links.SurveyIds {
+		survey, err := u.surveyUseCase.GetById(surveyId)
+		if err != nil {
+			return errs.New(errs.SameCode, "cannot get survey id %s to link program improvement", surveyId, err)
+		} else if survey == nil {
+			return errs.New(errs.ErrSurveyNotFound, "survey id %s not found while linking program improvement", surveyId)
+		}
+	}
+
+	return nil
+}
+
+func (u programImprovementUseCase) GetLogFile(programmeId string, fileDir string) (*entity.FileResponse, error) {
+	improvements, err := u.GetByProgrammeId(programmeId, "")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(fileDir, os.ModePerm); err != nil {
+		return nil, errs.New(errs.SameCode, "cannot create directory %s", err)
+	}
+	fileName := fmt.Sprintf("cqi_log_%s.xlsx", time.Now().Format("20060102150405"))
+	filepath := filepath.Join(fileDir, fileName)
+
+	err = WriteProgramImprovementLog(improvements, filepath)
+	if err != nil {
+		return nil, errs.New(errs.SameCode, "cannot write to excel %s", err)
+	}
+
+	return &entity.FileResponse{
+		FileName: fileName,
+		FilePath: filepath,
+		FileType: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	}, nil
+}
+
+func WriteProgramImprovementLog(improvements []entity.ProgramImprovement, filename string) error {
+	f := excelize.NewFile()
+	sheet := "CQI Log"
+	f.SetSheetName(f.GetSheetName(0), sheet)
+
+	headerStyle, err := f.NewStyle(&excelize.Style{
+		Alignment: &excelize.Alignment{
+			Horizontal: "center",
+			Vertical:   "center",
+		},
+		Font: &excelize.Font{
+			Bold: true,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create style: %v", err)
+	}
+
+	headers := []string{"No.", "Date", "Issue Identified", "Courses", "PLOs", "POs", "Surveys", "Action Taken", "Owner", "Due Date", "Status", "Result"}
+	for i, h := range headers {
+		if err := f.SetCellValue(sheet, getCell(i+1, 1), h); err != nil {
+			return err
+		}
+	}
+	if err := f.SetCellStyle(sheet, "A1", getCell(len(headers), 1), headerStyle); err != nil {
+		return err
+	}
+
+	for i, improvement := range improvements {
+		courses := make([]string, 0, len(improvement.Courses))
+		for _, course := range improvement.Courses {
+			courses = append(courses, course.Code)
+		}
+		plos := make([]string, 0, len(improvement.ProgramLearningOutcomes))
+		for _, plo := range improvement.ProgramLearningOutcomes {
+			plos = append(plos, plo.Code)
+		}
+		pos := make([]string, 0, len(improvement.ProgramOutcomes))
+		for _, po := range improvement.ProgramOutcomes {
+			pos = append(pos, po.Code)
+		}
+		surveys := make([]string, 0, len(improvement.Surveys))
+		for _, survey := range improvement.Surveys {
+			surveys = append(surveys, survey.Title)
+		}
+
+		owner := improvement.OwnerId
+		if improvement.Owner != nil {
+			owner = strings.TrimSpace(improvement.Owner.FirstNameEN + " " + improvement.Owner.LastNameEN)
+		}
+
+		dueDate := ""
+		if improvement.DueDate != nil {
+			dueDate = improvement.DueDate.Format("2006-01-02")
+		}
+
+		values := []interface{}{
+			i + 1,
+			improvement.Date.Format("2006-01-02"),
+			improvement.IssueIdentified,
+			strings.Join(courses, ", "),
+			strings.Join(plos, ", "),
+			strings.Join(pos, ", "),
+			strings.Join(surveys, ", "),
+			improvement.ActionTaken,
+			owner,
+			dueDate,
+			improvement.Status.Label(),
+			improvement.Result,
+		}
+		for col, value := range values {
+			if err := f.SetCellValue(sheet, getCell(col+1, i+2), value); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := f.SetColWidth(sheet, "C", "C", 40); err != nil {
+		return err
+	}
+	if err := f.SetColWidth(sheet, "H", "H", 40); err != nil {
+		return err
+	}
+	if err := f.SetColWidth(sheet, "L", "L", 40); err != nil {
+		return err
+	}
+
+	// Save file
+	if err := f.SaveAs(filename); err != nil {
+		return err
+	}
+
+	// Cleanup old files
+	fileFolder := filepath.Dir(filename)
+	if err := utils.DeleteOldFiles(fileFolder, 1); err != nil {
+		return fmt.Errorf("cannot delete old files: %w", err)
+	}
+
+	return nil
+}
@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"encoding/json"
+	"sort"
 
 	"github.com/oklog/ulid/v2"
 	"github.com/team-inu/inu-backyard/entity"
@@ -88,7 +89,8 @@ func (u programmeUseCase) Create(payload entity.CreateProgrammePayload) error {
 		Year:          payload.Year,
 		DepartmentId:  payload.DepartmentId,
 
-		Structure: json,
+		AttainmentMethod: payload.AttainmentMethod,
+		Structure:        json,
 	}
 
 	err = u.programmeRepo.Create(programme)
@@ -119,7 +121,8 @@ func (u programmeUseCase) Update(id string, programme *entity.UpdateProgrammePay
 		Year:          programme.Year,
 		DepartmentId:  programme.DepartmentId,
 
-		Structure: existProgramme.Structure,
+		AttainmentMethod: programme.AttainmentMethod,
+		Structure:        existProgramme.Structure,
 	})
 	if err != nil {
 		return errs.New(errs.ErrUpdateProgramme, "cannot update programme by id %s", id, err)
@@ -210,8 +213,20 @@ func (u programmeUseCase) GetAllCourseLinkedPLO(programmeIds []string) ([]entity
 			return nil, errs.New(errs.ErrQueryProgramme, "cannot get course outcome by programme id %s", id, err)
 		}
 
+		peos, err := u.GetAllPEOMapping(id)
+		if err != nil {
+			return nil, errs.New(errs.SameCode, "cannot get peo mapping by programme id %s", id, err)
+		}
+
+		feedback, err := u.GetStakeholderFeedback(id)
+		if err != nil {
+			return nil, errs.New(errs.SameCode, "cannot get stakeholder feedback by programme id %s", id, err)
+		}
+
 		resp.ProgrammeName = programme.NameTH + ", " + programme.NameEN
 		resp.ProgrammeYear = programme.Year
+		resp.PEOs = peos
+		resp.StakeholderFeedback = feedback
 
 		programmeLinkedPLOs = append(programmeLinkedPLOs, *resp)
 	}
@@ -233,9 +248,15 @@ func (u programmeUseCase) GetAllCourseLinkedSO(programmeId []string) ([]entity.P
 		if err != nil {
 			return nil, errs.New(errs.ErrQueryProgramme, "cannot get course outcome by programme id %s", programmeId, err)
 		}
+		peos, err := u.GetAllPEOMapping(id)
+		if err != nil {
+			return nil, errs.New(errs.SameCode, "cannot get peo mapping by programme id %s", id, err)
+		}
+
 		//TODO:
 		resp.ProgrammeName = programme.NameTH + ", " + programme.NameEN
 		resp.ProgrammeYear = programme.Year
+		resp.PEOs = peos
 
 		programmeLinkedSOs = append(programmeLinkedSOs, *resp)
 	}
@@ -371,3 +392,102 @@ func (u programmeUseCase) GetAllSO(programmeId string) ([]entity.StudentOutcome,
 
 	return sos, nil
 }
+
+func (u programmeUseCase) GetAllPEO(programmeId string) ([]entity.ProgramEducationalObjective, error) {
+	peos, err := u.programmeRepo.GetAllPEO(programmeId)
+	if err != nil {
+		return nil, errs.New(errs.ErrQueryPEO, "cannot get peo by programme id %s", programmeId, err)
+	}
+
+	return peos, nil
+}
+
+func (u programmeUseCase) GetAllPEOMapping(programmeId string) ([]entity.ProgramEducationalObjectiveMapping, error) {
+	peos, err := u.GetAllPEO(programmeId)
+	if err != nil {
+		return nil, err
+	}
+
+	mappings := make([]entity.ProgramEducationalObjectiveMapping, 0, len(peos))
+	for _, peo := range peos {
+		plos := make([]string, 0, len(peo.ProgramLearningOutcomes))
+		for _, plo := range peo.ProgramLearningOutcomes {
+			plos = append(plos, plo.Code)
+		}
+
+		sos := make([]string, 0, len(peo.StudentOutcomes))
+		for _, so := range peo.StudentOutcomes {
+			sos = append(sos, so.Code)
+		}
+
+		sort.Strings(plos)
+		sort.Strings(sos)
+
+		mappings = append(mappings, entity.ProgramEducationalObjectiveMapping{
+			Id:          peo.Id,
+			Code:        peo.Code,
+			Description: peo.Description,
+			PLOs:        plos,
+			SOs:         sos,
+		})
+	}
+
+	return mappings, nil
+}
+
+func (u programmeUseCase) GetStakeholderFeedback(programmeId string) ([]entity.StakeholderOutcomeFeedback, error) {
+	records, err := u.programmeRepo.GetStakeholderFeedback(programmeId)
+	if err != nil {
+		return nil, errs.New(errs.ErrQuerySurvey, "cannot get stakeholder feedback by programme id %s", programmeId, err)
+	}
+
+	plos, err := u.GetAllPLO(programmeId)
+	if err != nil {
+		return nil, err
+	}
+
+	peos, err := u.GetAllPEO(programmeId)
+	if err != nil {
+		return nil, err
+	}
+
+	codeByOutcome := make(map[entity.OutcomeType]map[string]string)
+	codeByOutcome[entity.OutcomeTypePLO] = make(map[string]string, len(plos))
+	for _, plo := range plos {
+		codeByOutcome[entity.OutcomeTypePLO][plo.Id] = plo.Code
+	}
+	codeByOutcome[entity.OutcomeTypePEO] = make(map[string]string, len(peos))
+	for _, peo := range peos {
+		codeByOutcome[entity.OutcomeTypePEO][peo.Id] = peo.Code
+	}
+
+	feedback := make([]entity.StakeholderOutcomeFeedback, 0, len(records))
+	for _, record := range records {
+		code, ok := codeByOutcome[record.OutcomeType][record.OutcomeId]
+		if !ok {
+			// question mapped to an outcome of another programme
+			continue
+		}
+
+		feedback = append(feedback, entity.StakeholderOutcomeFeedback{
+			OutcomeType:   record.OutcomeType,
+			OutcomeId:     record.OutcomeId,
+			Code:          code,
+			Audience:      record.Audience,
+			ResponseCount: record.ResponseCount,
+			AverageScore:  record.AverageScore,
+		})
+	}
+
+	sort.Slice(feedback, func(i, j int) bool {
+		if feedback[i].OutcomeType != feedback[j].OutcomeType {
+			return feedback[i].OutcomeType < feedback[j].OutcomeType
+		}
+		if feedback[i].Code != feedback[j].Code {
+			return feedback[i].Code < feedback[j].Code
+		}
+		return feedback[i].Audience < feedback[j].Audience
+	})
+
+	return feedback, nil
+}
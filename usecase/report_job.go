@@ -0,0 +1,257 @@
+package usecase
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/team-inu/inu-backyard/entity"
+	errs "github.com/team-inu/inu-backyard/entity/error"
+	"github.com/team-inu/inu-backyard/internal/config"
+)
+
+const (
+	// a running job is claimed again after the lease when its worker stopped
+	reportJobLease = 30 * time.Minute
+	// claims before a job whose worker keeps stopping is marked failed
+	maxReportJobAttempts = 3
+)
+
+type reportJobUseCase struct {
+	reportJobRepo             entity.ReportJobRepository
+	coursePortfolioUseCase    entity.CoursePortfolioUseCase
+	curriculumMapUseCase      entity.CurriculumMapUseCase
+	programImprovementUseCase entity.ProgramImprovementUseCase
+	fileUseCase               entity.FileUseCase
+	config                    config.ReportConfig
+	signingKey                []byte
+}
+
+// zero values in config fall back to 15 minute links, 24 hours retention and a random signing key
+func NewReportJobUseCase(
+	reportJobRepo entity.ReportJobRepository,
+	coursePortfolioUseCase entity.CoursePortfolioUseCase,
+	curriculumMapUseCase entity.CurriculumMapUseCase,
+	programImprovementUseCase entity.ProgramImprovementUseCase,
+	fileUseCase entity.FileUseCase,
+	config config.ReportConfig,
+) (entity.ReportJobUseCase, error) {
+	if config.LinkExpiry <= 0 {
+		config.LinkExpiry = 15
+	}
+	if config.Retention <= 0 {
+		config.Retention = 24
+	}
+
+	// a link signed with a random key would fail on other replicas and after a restart
+	if config.SigningKey == "" {
+		return nil, fmt.Errorf("report signing key is required")
+	}
+
+	return &reportJobUseCase{
+		reportJobRepo:             reportJobRepo,
+		coursePortfolioUseCase:    coursePortfolioUseCase,
+		curriculumMapUseCase:      curriculumMapUseCase,
+		programImprovementUseCase: programImprovementUseCase,
+		fileUseCase:               fileUseCase,
+		config:                    config,
+		signingKey:                []byte(config.SigningKey),
+	}, nil
+}
+
+func (u reportJobUseCase) Enqueue(user entity.User, reportType entity.ReportType, programmeId string, fromSemester int, toSemester int) (*entity.ReportJob, error) {
+	switch reportType {
+	case entity.ReportTypeCourseCloAssessment, entity.ReportTypeCourseLinkedOutcomes, entity.ReportTypeCourseOutcomesSuccessRate, entity.ReportTypeOutcomeAttainmentTrend:
+		if fromSemester <= 0 || toSemester < fromSemester {
+			return nil, errs.New(errs.ErrInvalidReportJob, "semester range %d-%d of report is not valid", fromSemester, toSemester)
+		}
+	case entity.ReportTypeCurriculumMap, entity.ReportTypeProgramImprovementLog:
+		fromSemester, toSemester = 0, 0
+	default:
+		return nil, errs.New(errs.ErrInvalidReportJob, "report type %s is not valid", reportType)
+	}
+
+	if programmeId == "" {
+		return nil, errs.New(errs.ErrInvalidReportJob, "programme of report is not set")
+	}
+
+	createdAt := time.Now()
+	job := &entity.ReportJob{
+		Id:           ulid.Make().String(),
+		UserId:       user.Id,
+		Type:         reportType,
+		ProgrammeId:  programmeId,
+		FromSemester: fromSemester,
+		ToSemester:   toSemester,
+		Status:       entity.ReportJobStatusPending,
+		CreatedAt:    createdAt,
+		UpdatedAt:    createdAt,
+	}
+
+	err := u.reportJobRepo.Create(job)
+	if err != nil {
+		return nil, errs.New(errs.ErrCreateReportJob, "cannot queue %s report", reportType, err)
+	}
+
+	return job, nil
+}
+
+func (u reportJobUseCase) GetById(user entity.User, id string) (*entity.ReportJob, error) {
+	job, err := u.reportJobRepo.GetById(id)
+	if err != nil {
+		return nil, errs.New(errs.ErrQueryReportJob, "cannot get report job id %s", id, err)
+	} else if job == nil {
+		return nil, errs.New(errs.ErrReportJobNotFound, "report job id %s not found", id)
+	}
+
+	if job.UserId != user.Id && !user.IsRoles([]entity.UserRole{entity.UserRoleHeadOfCurriculum}) {
+		return nil, errs.New(errs.ErrReportJobPermission, "no permission to view report job id %s", id)
+	}
+
+	if job.Status == entity.ReportJobStatusCompleted {
+		expiresAt := time.Now().Add(time.Duration(u.config.LinkExpiry) * time.Minute).Truncate(time.Second)
+		job.DownloadUrl = fmt.Sprintf("/jobs/%s/download?expires=%d&signature=%s", job.Id, expiresAt.Unix(), u.sign(job.Id, expiresAt.Unix()))
+		job.DownloadExpiresAt = &expiresAt
+	}
+
+	return job, nil
+}
+
+func (u reportJobUseCase) ProcessNext() (bool, error) {
+	job, err := u.reportJobRepo.ClaimNext(time.Now(), reportJobLease)
+	if err != nil {
+		return false, errs.New(errs.ErrQueryReportJob, "cannot claim report job", err)
+	} else if job == nil {
+		return false, nil
+	}
+
+	var file *entity.StoredFile
+	if job.Attempts > maxReportJobAttempts {
+		err = fmt.Errorf("report worker stopped %d times while generating", maxReportJobAttempts)
+	} else {
+		file, err = u.generate(*job)
+	}
+
+	finishedAt := time.Now()
+	job.FinishedAt = &finishedAt
+	if err != nil {
+		job.Status = entity.ReportJobStatusFailed
+		job.Error = err.Error()
+	} else {
+		job.Status = entity.ReportJobStatusCompleted
+		job.FileName = file.Name
+		job.FileId = file.Id
+	}
+
+	err = u.reportJobRepo.Update(job)
+	if err != nil {
+		return true, errs.New(errs.ErrUpdateReportJob, "cannot update status of report job id %s", job.Id, err)
+	}
+
+	return true, nil
+}
+
+// generate writes the report into a temporary directory and moves it into the file store
+func (u reportJobUseCase) generate(job entity.ReportJob) (file *entity.StoredFile, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("report generation panicked: %v", r)
+		}
+	}()
+
+	fileDir, err := os.MkdirTemp("", "report-"+job.Id+"-")
+	if err != nil {
+		return nil, fmt.Errorf("cannot create directory for report: %w", err)
+	}
+	defer os.RemoveAll(fileDir)
+
+	var generated *entity.FileResponse
+	switch job.Type {
+	case entity.ReportTypeCourseCloAssessment:
+		generated, err = u.coursePortfolioUseCase.GetCourseCloAssessment(job.ProgrammeId, job.FromSemester, job.ToSemester, fileDir)
+	case entity.ReportTypeCourseLinkedOutcomes:
+		generated, err = u.coursePortfolioUseCase.GetCourseLinkedOutcomes(job.ProgrammeId, job.FromSemester, job.ToSemester, fileDir)
+	case entity.ReportTypeCourseOutcomesSuccessRate:
+		generated, err = u.coursePortfolioUseCase.GetCourseOutcomesSuccessRate(job.ProgrammeId, job.FromSemester, job.ToSemester, fileDir)
+	case entity.ReportTypeOutcomeAttainmentTrend:
+		generated, err = u.coursePortfolioUseCase.GetOutcomeAttainmentTrendFile(job.ProgrammeId, job.FromSemester, job.ToSemester, fileDir)
+	case entity.ReportTypeCurriculumMap:
+		generated, err = u.curriculumMapUseCase.GetFile(job.ProgrammeId, fileDir)
+	case entity.ReportTypeProgramImprovementLog:
+		generated, err = u.programImprovementUseCase.GetLogFile(job.ProgrammeId, fileDir)
+	default:
+		err = fmt.Errorf("report type %s is not supported", job.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return u.fileUseCase.SaveLocal("reports", *generated)
+}
+
+func (u reportJobUseCase) OpenDownload(id string, expires string, signature string) (*entity.StoredFile, io.ReadCloser, error) {
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() > expiresAt {
+		return nil, nil, errs.New(errs.ErrReportLinkInvalid, "download link of report job id %s is expired", id)
+	}
+
+	decoded, err := hex.DecodeString(signature)
+	if err != nil || !hmac.Equal(decoded, u.signature(id, expiresAt)) {
+		return nil, nil, errs.New(errs.ErrReportLinkInvalid, "download link of report job id %s is not valid", id)
+	}
+
+	job, err := u.reportJobRepo.GetById(id)
+	if err != nil {
+		return nil, nil, errs.New(errs.ErrQueryReportJob, "cannot get report job id %s", id, err)
+	} else if job == nil {
+		return nil, nil, errs.New(errs.ErrReportJobNotFound, "report job id %s not found", id)
+	} else if job.Status != entity.ReportJobStatusCompleted {
+		return nil, nil, errs.New(errs.ErrReportJobNotReady, "report job id %s is %s", id, job.Status)
+	}
+
+	return u.fileUseCase.Open(job.FileId)
+}
+
+func (u reportJobUseCase) DeleteExpired() (int64, error) {
+	jobs, err := u.reportJobRepo.GetFinishedBefore(time.Now().Add(-time.Duration(u.config.Retention) * time.Hour))
+	if err != nil {
+		return 0, errs.New(errs.ErrQueryReportJob, "cannot get expired report jobs", err)
+	}
+
+	ids := make([]string, 0, len(jobs))
+	fileIds := []string{}
+	for _, job := range jobs {
+		ids = append(ids, job.Id)
+		if job.FileId != "" {
+			fileIds = append(fileIds, job.FileId)
+		}
+	}
+
+	err = u.fileUseCase.Delete(fileIds)
+	if err != nil {
+		return 0, errs.New(errs.SameCode, "cannot delete files of expired report jobs", err)
+	}
+
+	err = u.reportJobRepo.Delete(ids)
+	if err != nil {
+		return 0, errs.New(errs.ErrUpdateReportJob, "cannot delete expired report jobs", err)
+	}
+
+	return int64(len(ids)), nil
+}
+
+func (u reportJobUseCase) signature(id string, expiresAt int64) []byte {
+	mac := hmac.New(sha256.New, u.signingKey)
+	fmt.Fprintf(mac, "%s:%d", id, expiresAt)
+	return mac.Sum(nil)
+}
+
+func (u reportJobUseCase) sign(id string, expiresAt int64) string {
+	return hex.EncodeToString(u.signature(id, expiresAt))
+}
@@ -0,0 +1,243 @@
+package usecase
+
+import (
+	"errors"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/team-inu/inu-backyard/entity"
+	errs "github.com/team-inu/inu-backyard/entity/error"
+	"github.com/team-inu/inu-backyard/infrastructure/storage"
+	"github.com/team-inu/inu-backyard/internal/config"
+)
+
+type stubReportJobRepository struct {
+	entity.ReportJobRepository
+	jobs []entity.ReportJob
+}
+
+func (r *stubReportJobRepository) Create(job *entity.ReportJob) error {
+	r.jobs = append(r.jobs, *job)
+	return nil
+}
+
+func (r *stubReportJobRepository) GetById(id string) (*entity.ReportJob, error) {
+	for _, job := range r.jobs {
+		if job.Id == id {
+			return &job, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *stubReportJobRepository) ClaimNext(now time.Time, lease time.Duration) (*entity.ReportJob, error) {
+	for i, job := range r.jobs {
+		if job.Status == entity.ReportJobStatusPending {
+			r.jobs[i].Status = entity.ReportJobStatusRunning
+			r.jobs[i].StartedAt = &now
+			r.jobs[i].Attempts++
+			claimed := r.jobs[i]
+			return &claimed, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *stubReportJobRepository) Update(job *entity.ReportJob) error {
+	for i := range r.jobs {
+		if r.jobs[i].Id == job.Id {
+			r.jobs[i] = *job
+		}
+	}
+	return nil
+}
+
+func (r *stubReportJobRepository) GetFinishedBefore(before time.Time) ([]entity.ReportJob, error) {
+	jobs := []entity.ReportJob{}
+	for _, job := range r.jobs {
+		if job.FinishedAt != nil && job.FinishedAt.Before(before) {
+			jobs = append(jobs, job)
+		}
+	}
+	return jobs, nil
+}
+
+func (r *stubReportJobRepository) Delete(ids []string) error {
+	kept := []entity.ReportJob{}
+	for _, job := range r.jobs {
+		isDeleted := false
+		for _, id := range ids {
+			isDeleted = isDeleted || job.Id == id
+		}
+		if !isDeleted {
+			kept = append(kept, job)
+		}
+	}
+	r.jobs = kept
+	return nil
+}
+
+type stubReportPortfolioUseCase struct {
+	entity.CoursePortfolioUseCase
+}
+
+func (u *stubReportPortfolioUseCase) GetCourseCloAssessment(programmeId string, fromSerm, toSerm int, fileDir string) (*entity.FileResponse, error) {
+	if programmeId == "broken" {
+		return nil, errors.New("cannot query clo assessment")
+	}
+
+	err := os.MkdirAll(fileDir, os.ModePerm)
+	if err != nil {
+		return nil, err
+	}
+
+	filePath := filepath.Join(fileDir, "course_clo_assessment.xlsx")
+	err = os.WriteFile(filePath, []byte("xlsx"), 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.FileResponse{FileName: "course_clo_assessment.xlsx", FilePath: filePath, FileType: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"}, nil
+}
+
+func (u *stubReportPortfolioUseCase) GetCourseLinkedOutcomes(programmeId string, fromSerm, toSerm int, fileDir string) (*entity.FileResponse, error) {
+	panic("unexpected nil row")
+}
+
+type stubReportCurriculumMapUseCase struct {
+	entity.CurriculumMapUseCase
+}
+
+func (u *stubReportCurriculumMapUseCase) GetFile(programmeId string, fileDir string) (*entity.FileResponse, error) {
+	filePath := filepath.Join(fileDir, "curriculum_map.xlsx")
+	err := os.WriteFile(filePath, []byte("map"), 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.FileResponse{FileName: "curriculum_map.xlsx", FilePath: filePath, FileType: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"}, nil
+}
+
+func TestReportJob(t *testing.T) {
+	fileStore := storage.NewMemoryStore()
+	fileUseCase := NewFileUseCase(&stubFileRepository{}, fileStore, config.StorageConfig{})
+	reportJobRepository := &stubReportJobRepository{}
+	reportJobUseCase, err := NewReportJobUseCase(reportJobRepository, &stubReportPortfolioUseCase{}, &stubReportCurriculumMapUseCase{}, nil, fileUseCase, config.ReportConfig{Retention: 1, SigningKey: "secret"})
+	if err != nil {
+		t.Fatalf("Failed to create report job use case: %v", err)
+	}
+
+	t.Run("TestSigningKeyRequired", func(t *testing.T) {
+		_, err := NewReportJobUseCase(reportJobRepository, &stubReportPortfolioUseCase{}, &stubReportCurriculumMapUseCase{}, nil, fileUseCase, config.ReportConfig{})
+		assert.NotNil(t, err, "Expected a missing signing key to be refused")
+	})
+
+	owner := entity.User{Id: "owner", Role: entity.UserRoleLecturer}
+	stranger := entity.User{Id: "stranger", Role: entity.UserRoleLecturer}
+
+	t.Run("TestEnqueue_InvalidRange", func(t *testing.T) {
+		_, err := reportJobUseCase.Enqueue(owner, entity.ReportTypeCourseCloAssessment, "programme", 2567, 2566)
+		assert.Equal(t, errs.ErrInvalidReportJob, errorCode(err), "Expected reversed range to be refused, got %v", err)
+
+		_, err = reportJobUseCase.Enqueue(owner, "UNKNOWN", "programme", 2566, 2567)
+		assert.Equal(t, errs.ErrInvalidReportJob, errorCode(err), "Expected unknown type to be refused, got %v", err)
+	})
+
+	job, err := reportJobUseCase.Enqueue(owner, entity.ReportTypeCourseCloAssessment, "programme", 2566, 2567)
+	assert.Nil(t, err, "Expected no error while queueing report, got %v", err)
+	assert.Equal(t, entity.ReportJobStatusPending, job.Status)
+
+	t.Run("TestProcessAndDownload", func(t *testing.T) {
+		isProcessed, err := reportJobUseCase.ProcessNext()
+		assert.Nil(t, err, "Expected no error while processing, got %v", err)
+		assert.True(t, isProcessed, "Expected pending job to be processed")
+
+		completed, err := reportJobUseCase.GetById(owner, job.Id)
+		assert.Nil(t, err)
+		assert.Equal(t, entity.ReportJobStatusCompleted, completed.Status)
+		assert.True(t, strings.HasPrefix(completed.DownloadUrl, "/jobs/"+job.Id+"/download?"), "Expected signed download link, got %s", completed.DownloadUrl)
+
+		link, _ := url.Parse(completed.DownloadUrl)
+		query := link.Query()
+
+		file, content, err := reportJobUseCase.OpenDownload(job.Id, query.Get("expires"), query.Get("signature"))
+		assert.Nil(t, err, "Expected signed link to open, got %v", err)
+		if err == nil {
+			data, _ := io.ReadAll(content)
+			content.Close()
+			assert.Equal(t, "xlsx", string(data), "Expected generated report from the file store")
+			assert.Equal(t, "course_clo_assessment.xlsx", file.Name)
+		}
+
+		_, _, err = reportJobUseCase.OpenDownload(job.Id, query.Get("expires")+"0", query.Get("signature"))
+		assert.Equal(t, errs.ErrReportLinkInvalid, errorCode(err), "Expected extended expiry to break the signature, got %v", err)
+
+		_, _, err = reportJobUseCase.OpenDownload(job.Id, "1", query.Get("signature"))
+		assert.Equal(t, errs.ErrReportLinkInvalid, errorCode(err), "Expected expired link to be refused, got %v", err)
+
+		isProcessed, err = reportJobUseCase.ProcessNext()
+		assert.Nil(t, err)
+		assert.False(t, isProcessed, "Expected no job left")
+	})
+
+	t.Run("TestGetById_Permission", func(t *testing.T) {
+		_, err := reportJobUseCase.GetById(stranger, job.Id)
+		assert.Equal(t, errs.ErrReportJobPermission, errorCode(err), "Expected other users to be refused, got %v", err)
+
+		_, err = reportJobUseCase.GetById(entity.User{Id: "head", Role: entity.UserRoleHeadOfCurriculum}, job.Id)
+		assert.Nil(t, err, "Expected curriculum head to view any job, got %v", err)
+	})
+
+	t.Run("TestProcess_Failure", func(t *testing.T) {
+		broken, _ := reportJobUseCase.Enqueue(owner, entity.ReportTypeCourseCloAssessment, "broken", 2566, 2567)
+		panicking, _ := reportJobUseCase.Enqueue(owner, entity.ReportTypeCourseLinkedOutcomes, "programme", 2566, 2567)
+
+		for i := 0; i < 2; i++ {
+			_, err := reportJobUseCase.ProcessNext()
+			assert.Nil(t, err)
+		}
+
+		failed, _ := reportJobUseCase.GetById(owner, broken.Id)
+		assert.Equal(t, entity.ReportJobStatusFailed, failed.Status)
+		assert.Contains(t, failed.Error, "cannot query clo assessment")
+		assert.Empty(t, failed.DownloadUrl, "Expected no download link for failed job")
+
+		failed, _ = reportJobUseCase.GetById(owner, panicking.Id)
+		assert.Equal(t, entity.ReportJobStatusFailed, failed.Status, "Expected a panic to fail the job instead of the worker")
+
+		_, _, err = reportJobUseCase.OpenDownload(broken.Id, "9999999999", "00")
+		assert.Equal(t, errs.ErrReportLinkInvalid, errorCode(err))
+	})
+
+	t.Run("TestDeleteExpired", func(t *testing.T) {
+		finishedAt := time.Now().Add(-2 * time.Hour)
+		for i := range reportJobRepository.jobs {
+			reportJobRepository.jobs[i].FinishedAt = &finishedAt
+		}
+
+		deleted, err := reportJobUseCase.DeleteExpired()
+		assert.Nil(t, err, "Expected no error while deleting, got %v", err)
+		assert.Equal(t, int64(3), deleted)
+		assert.Empty(t, reportJobRepository.jobs)
+		assert.Empty(t, fileStore.Keys(), "Expected report file to be deleted")
+	})
+
+	t.Run("TestProcessProgrammeReport", func(t *testing.T) {
+		job, err := reportJobUseCase.Enqueue(owner, entity.ReportTypeCurriculumMap, "programme", 0, 0)
+		assert.Nil(t, err, "Expected a programme report to need no semester range, got %v", err)
+
+		_, err = reportJobUseCase.ProcessNext()
+		assert.Nil(t, err)
+
+		completed, _ := reportJobUseCase.GetById(owner, job.Id)
+		assert.Equal(t, entity.ReportJobStatusCompleted, completed.Status)
+		assert.Equal(t, "curriculum_map.xlsx", completed.FileName)
+		assert.Len(t, fileStore.Keys(), 1, "Expected the export in the file store")
+	})
+}
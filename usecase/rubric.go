@@ -0,0 +1,332 @@
+package usecase
+
+import (
+	"github.com/oklog/ulid/v2"
+	"github.com/team-inu/inu-backyard/entity"
+	errs "github.com/team-inu/inu-backyard/entity/error"
+)
+
+type rubricUseCase struct {
+	rubricRepo                   entity.RubricRepository
+	programmeUseCase             entity.ProgrammeUseCase
+	assignmentUseCase            entity.AssignmentUseCase
+	courseUseCase                entity.CourseUseCase
+	courseLearningOutcomeUseCase entity.CourseLearningOutcomeUseCase
+	assessmentItemUseCase        entity.AssessmentItemUseCase
+}
+
+func NewRubricUseCase(
+	rubricRepo entity.RubricRepository,
+	programmeUseCase entity.ProgrammeUseCase,
+	assignmentUseCase entity.AssignmentUseCase,
+	courseUseCase entity.CourseUseCase,
+	courseLearningOutcomeUseCase entity.CourseLearningOutcomeUseCase,
+	assessmentItemUseCase entity.AssessmentItemUseCase,
+) entity.RubricUseCase {
+	return &rubricUseCase{
+		rubricRepo:                   rubricRepo,
+		programmeUseCase:             programmeUseCase,
+		assignmentUseCase:            assignmentUseCase,
+		courseUseCase:                courseUseCase,
+		courseLearningOutcomeUseCase: courseLearningOutcomeUseCase,
+		assessmentItemUseCase:        assessmentItemUseCase,
+	}
+}
+
+func (u rubricUseCase) GetById(programmeId string, id string) (*entity.Rubric, error) {
+	rubric, err := u.rubricRepo.GetById(id)
+	if err != nil {
+		return nil, errs.New(errs.ErrQueryRubric, "cannot get rubric by id %s", id, err)
+	}
+
+	if rubric != nil && rubric.ProgrammeId != programmeId {
+		return nil, nil
+	}
+
+	return rubric, nil
+}
+
+func (u rubricUseCase) GetByProgrammeId(programmeId string) ([]entity.Rubric, error) {
+	programme, err := u.programmeUseCase.GetById(programmeId)
+	if err != nil {
+		return nil, errs.New(errs.SameCode, "cannot get programme id %s to get rubrics", programmeId, err)
+	} else if programme == nil {
+		return nil, errs.New(errs.ErrProgrammeNotFound, "programme id %s not found while getting rubrics", programmeId)
+	}
+
+	rubrics, err := u.rubricRepo.GetByProgrammeId(programmeId)
+	if err != nil {
+		return nil, errs.New(errs.ErrQueryRubric, "cannot get rubrics by programme id %s", programmeId, err)
+	}
+
+	return rubrics, nil
+}
+
+func (u rubricUseCase) Create(programmeId string, payload entity.CreateRubricPayload) error {
+	programme, err := u.programmeUseCase.GetById(programmeId)
+	if err != nil {
+		return errs.New(errs.SameCode, "cannot get programme id %s to create rubric", programmeId, err)
+	} else if programme == nil {
+		return errs.New(errs.ErrProgrammeNotFound, "programme id %s not found while creating rubric", programmeId)
+	}
+
+	criteria, err := newRubricCriteria(payload.Criteria)
+	if err != nil {
+		return err
+	}
+
+	rubric := &entity.Rubric{
+		Id:          ulid.Make().String(),
+		ProgrammeId: programmeId,
+		Name:        payload.Name,
+		Description: payload.Description,
+		Criteria:    criteria,
+	}
+
+	err = u.rubricRepo.Create(rubric)
+	if err != nil {
+		return errs.New(errs.ErrCreateRubric, "cannot create rubric", err)
+	}
+
+	return nil
+}
+
+func (u rubricUseCase) Update(programmeId string, id string, payload entity.UpdateRubricPayload) error {
+	existRubric, err := u.GetById(programmeId, id)
+	if err != nil {
+		return errs.New(errs.SameCode, "cannot get rubric id %s to update", id, err)
+	} else if existRubric == nil {
+		return errs.New(errs.ErrRubricNotFound, "cannot get rubric id %s to update", id)
+	}
+
+	rubric := &entity.Rubric{
+		Name:        payload.Name,
+		Description: payload.Description,
+	}
+
+	if len(payload.Criteria) > 0 {
+		err = u.checkNotAttached(id)
+		if err != nil {
+			return err
+		}
+
+		rubric.Criteria, err = newRubricCriteria(payload.Criteria)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = u.rubricRepo.Update(id, rubric)
+	if err != nil {
+		return errs.New(errs.ErrUpdateRubric, "cannot update rubric by id %s", id, err)
+	}
+
+	return nil
+}
+
+func (u rubricUseCase) Delete(programmeId string, id string) error {
+	rubric, err := u.GetById(programmeId, id)
+	if err != nil {
+		return errs.New(errs.SameCode, "cannot get rubric id %s to delete", id, err)
+	} else if rubric == nil {
+		return errs.New(errs.ErrRubricNotFound, "cannot get rubric id %s to delete", id)
+	}
+
+	err = u.checkNotAttached(id)
+	if err != nil {
+		return err
+	}
+
+	err = u.rubricRepo.Delete(id)
+	if err != nil {
+		return errs.New(errs.ErrDeleteRubric, "cannot delete rubric by id %s", id, err)
+	}
+
+	return nil
+}
+
+func (u rubricUseCase) Attach(assignmentId string, payload entity.AttachRubricPayload) error {
+	assignment, err := u.assignmentUseCase.GetById(assignmentId)
+	if err != nil {
+		return errs.New(errs.SameCode, "cannot get assignment id %s to attach rubric", assignmentId, err)
+	} else if assignment == nil {
+		return errs.New(errs.ErrAssignmentNotFound, "assignment id %s not found while attaching rubric", assignmentId)
+	} else if assignment.RubricId != nil {
+		return errs.New(errs.ErrInvalidRubric, "assignment id %s is already graded by rubric id %s", assignmentId, *assignment.RubricId)
+	}
+
+	assignmentGroup, err := u.assignmentUseCase.GetGroupByGroupId(assignment.AssignmentGroupId)
+	if err != nil {
+		return errs.New(errs.SameCode, "cannot get assignment group id %s to attach rubric", assignment.AssignmentGroupId, err)
+	} else if assignmentGroup == nil {
+		return errs.New(errs.ErrAssignmentNotFound, "assignment group id %s not found while attaching rubric", assignment.AssignmentGroupId)
+	}
+
+	course, err := u.courseUseCase.GetById(assignmentGroup.CourseId)
+	if err != nil {
+		return errs.New(errs.SameCode, "cannot get course id %s to attach rubric", assignmentGroup.CourseId, err)
+	} else if course == nil {
+		return errs.New(errs.ErrCourseNotFound, "course id %s not found while attaching rubric", assignmentGroup.CourseId)
+	}
+
+	rubric, err := u.GetById(course.ProgrammeId, payload.RubricId)
+	if err != nil {
+		return errs.New(errs.SameCode, "cannot get rubric id %s to attach", payload.RubricId, err)
+	} else if rubric == nil {
+		return errs.New(errs.ErrRubricNotFound, "rubric id %s not found in programme id %s of the assignment", payload.RubricId, course.ProgrammeId)
+	}
+
+	items, err := u.assessmentItemUseCase.GetByAssignmentId(assignmentId)
+	if err != nil {
+		return errs.New(errs.SameCode, "cannot get assessment items of assignment id %s to attach rubric", assignmentId, err)
+	}
+
+	totalMaxScore := rubric.MaxScore()
+	for _, item := range items {
+		totalMaxScore += item.MaxScore
+	}
+	if totalMaxScore > float64(assignment.MaxScore) {
+		return errs.New(errs.ErrInvalidRubric, "max scores of assessment items with rubric id %s add up to %.2f over max score %d of assignment id %s", rubric.Id, totalMaxScore, assignment.MaxScore, assignmentId)
+	}
+
+	isCriterion := map[string]bool{}
+	for _, criterion := range rubric.Criteria {
+		isCriterion[criterion.Id] = true
+	}
+	for criterionId := range payload.CourseLearningOutcomeIds {
+		if !isCriterion[criterionId] {
+			return errs.New(errs.ErrInvalidRubric, "criterion id %s is not a criterion of rubric id %s", criterionId, rubric.Id)
+		}
+	}
+
+	criterionItems := []entity.AssessmentItem{}
+	for _, criterion := range rubric.Criteria {
+		courseLearningOutcomes := []*entity.CourseLearningOutcome{}
+		for _, cloId := range payload.CourseLearningOutcomeIds[criterion.Id] {
+			clo, err := u.courseLearningOutcomeUseCase.GetById(cloId)
+			if err != nil {
+				return errs.New(errs.SameCode, "cannot get clo id %s of rubric criterion", cloId, err)
+			} else if clo == nil || clo.CourseId != course.Id {
+				return errs.New(errs.ErrInvalidRubric, "clo id %s is not a clo of course id %s", cloId, course.Id)
+			}
+
+			courseLearningOutcomes = append(courseLearningOutcomes, &entity.CourseLearningOutcome{Id: cloId})
+		}
+		criterionId := criterion.Id
+		criterionItems = append(criterionItems, entity.AssessmentItem{
+			Id:                      ulid.Make().String(),
+			AssignmentId:            assignmentId,
+			Name:                    criterion.Name,
+			Description:             criterion.Description,
+			MaxScore:                criterion.MaxPoints(),
+			ExpectedScorePercentage: assignment.ExpectedScorePercentage,
+			Sequence:                criterion.Sequence,
+			CourseLearningOutcomes:  courseLearningOutcomes,
+			RubricCriterionId:       &criterionId,
+		})
+	}
+	err = u.rubricRepo.Attach(assignmentId, rubric.Id, criterionItems)
+	if err != nil {
+		return errs.New(errs.ErrAttachRubric, "cannot attach rubric id %s to assignment id %s", rubric.Id, assignmentId, err)
+	}
+
+	return nil
+}
+
+func (u rubricUseCase) Detach(assignmentId string) error {
+	assignment, err := u.assignmentUseCase.GetById(assignmentId)
+	if err != nil {
+		return errs.New(errs.SameCode, "cannot get assignment id %s to detach rubric", assignmentId, err)
+	} else if assignment == nil {
+		return errs.New(errs.ErrAssignmentNotFound, "assignment id %s not found while detaching rubric", assignmentId)
+	} else if assignment.RubricId == nil {
+		return errs.New(errs.ErrInvalidRubric, "assignment id %s is not graded by a rubric", assignmentId)
+	}
+
+	err = u.rubricRepo.Detach(assignmentId)
+	if err != nil {
+		return errs.New(errs.ErrAttachRubric, "cannot detach rubric from assignment id %s", assignmentId, err)
+	}
+
+	return nil
+}
+
+// SaveGrades scores the assessment items of the rubric criteria with the points of the graded levels
+func (u rubricUseCase) SaveGrades(user entity.User, assignmentId string, studentGrades []entity.StudentRubricGrade) error {
+	items, err := u.assessmentItemUseCase.GetByAssignmentId(assignmentId)
+	if err != nil {
+		return errs.New(errs.SameCode, "cannot get assessment items of assignment id %s to save rubric grades", assignmentId, err)
+	}
+
+	itemIdByCriterion := map[string]string{}
+	for _, item := range items {
+		if item.RubricCriterionId != nil {
+			itemIdByCriterion[*item.RubricCriterionId] = item.Id
+		}
+	}
+	if len(itemIdByCriterion) == 0 {
+		return errs.New(errs.ErrInvalidRubric, "assignment id %s is not graded by a rubric", assignmentId)
+	}
+
+	studentScores := make([]entity.StudentItemScores, 0, len(studentGrades))
+	for _, studentGrade := range studentGrades {
+		levels := make(map[string]string, len(studentGrade.Levels))
+		for criterionId, levelId := range studentGrade.Levels {
+			itemId, ok := itemIdByCriterion[criterionId]
+			if !ok {
+				return errs.New(errs.ErrInvalidRubric, "criterion id %s is not a criterion of the rubric of assignment id %s", criterionId, assignmentId)
+			}
+
+			levels[itemId] = levelId
+		}
+
+		studentScores = append(studentScores, entity.StudentItemScores{
+			StudentId: studentGrade.StudentId,
+			Levels:    levels,
+		})
+	}
+
+	return u.assessmentItemUseCase.SaveScores(user, assignmentId, studentScores)
+}
+
+func (u rubricUseCase) checkNotAttached(id string) error {
+	isAttached, err := u.rubricRepo.IsAttached(id)
+	if err != nil {
+		return errs.New(errs.ErrQueryRubric, "cannot check assignments of rubric id %s", id, err)
+	} else if isAttached {
+		return errs.New(errs.ErrRubricInUse, "rubric id %s grades assignments", id)
+	}
+
+	return nil
+}
+
+// newRubricCriteria builds the criteria of a rubric, every criterion needs a level with points
+func newRubricCriteria(payloads []entity.RubricCriterionPayload) ([]entity.RubricCriterion, error) {
+	criteria := make([]entity.RubricCriterion, 0, len(payloads))
+	for _, criterionPayload := range payloads {
+		criterion := entity.RubricCriterion{
+			Id:          ulid.Make().String(),
+			Name:        criterionPayload.Name,
+			Description: criterionPayload.Description,
+			Sequence:    criterionPayload.Sequence,
+		}
+
+		for _, levelPayload := range criterionPayload.Levels {
+			criterion.Levels = append(criterion.Levels, entity.RubricLevel{
+				Id:                ulid.Make().String(),
+				RubricCriterionId: criterion.Id,
+				Name:              levelPayload.Name,
+				Descriptor:        levelPayload.Descriptor,
+				Points:            *levelPayload.Points,
+			})
+		}
+
+		if criterion.MaxPoints() == 0 {
+			return nil, errs.New(errs.ErrInvalidRubric, "criterion %s has no level with points", criterion.Name)
+		}
+
+		criteria = append(criteria, criterion)
+	}
+
+	return criteria, nil
+}
@@ -0,0 +1,134 @@
+package usecase
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/team-inu/inu-backyard/entity"
+	errs "github.com/team-inu/inu-backyard/entity/error"
+)
+
+type stubRubricRepository struct {
+	entity.RubricRepository
+	rubric        entity.Rubric
+	attachedItems []entity.AssessmentItem
+}
+
+func (r *stubRubricRepository) GetById(id string) (*entity.Rubric, error) {
+	if id != r.rubric.Id {
+		return nil, nil
+	}
+
+	return &r.rubric, nil
+}
+
+func (r *stubRubricRepository) Attach(assignmentId string, rubricId string, items []entity.AssessmentItem) error {
+	r.attachedItems = items
+	return nil
+}
+
+type stubCourseLearningOutcomeUseCase struct {
+	entity.CourseLearningOutcomeUseCase
+}
+
+func (u *stubCourseLearningOutcomeUseCase) GetById(id string) (*entity.CourseLearningOutcome, error) {
+	if id == "clo-other-course" {
+		return &entity.CourseLearningOutcome{Id: id, CourseId: "other"}, nil
+	}
+
+	return &entity.CourseLearningOutcome{Id: id, CourseId: "course"}, nil
+}
+
+// a rubric of 5 + 4 points for an assignment of 20 already having an item of 10
+func newRubricUseCase() (entity.RubricUseCase, *stubRubricRepository) {
+	repository := &stubRubricRepository{
+		rubric: entity.Rubric{
+			Id:          "presentation",
+			ProgrammeId: "programme",
+			Criteria: []entity.RubricCriterion{
+				{Id: "delivery", Levels: []entity.RubricLevel{{Id: "delivery-poor", Points: 1}, {Id: "delivery-good", Points: 5}}},
+				{Id: "content", Levels: []entity.RubricLevel{{Id: "content-poor", Points: 0}, {Id: "content-good", Points: 4}}},
+			},
+		},
+	}
+	assessmentItemUseCase, _ := newAssessmentItemUseCase()
+	assignmentUseCase := &stubAssignmentUseCase{
+		assignment: entity.Assignment{Id: "exam", MaxScore: 20, ExpectedScorePercentage: 60, AssignmentGroupId: "midterm"},
+	}
+
+	return NewRubricUseCase(repository, nil, assignmentUseCase, &stubCourseUseCase{}, &stubCourseLearningOutcomeUseCase{}, assessmentItemUseCase), repository
+}
+
+func TestRubric(t *testing.T) {
+	t.Run("TestNewRubricCriteria", func(t *testing.T) {
+		points := 3.0
+		criteria, err := newRubricCriteria([]entity.RubricCriterionPayload{{Name: "delivery", Levels: []entity.RubricLevelPayload{{Name: "good", Points: &points}}}})
+		assert.Nil(t, err)
+		assert.Equal(t, criteria[0].Id, criteria[0].Levels[0].RubricCriterionId)
+
+		noPoints := 0.0
+		_, err = newRubricCriteria([]entity.RubricCriterionPayload{{Name: "delivery", Levels: []entity.RubricLevelPayload{{Name: "poor", Points: &noPoints}}}})
+		assert.Equal(t, errs.ErrInvalidRubric, errorCode(err), "Expected a criterion without points to be refused")
+	})
+
+	t.Run("TestAttach", func(t *testing.T) {
+		rubricUseCase, repository := newRubricUseCase()
+
+		err := rubricUseCase.Attach("exam", entity.AttachRubricPayload{RubricId: "presentation", CourseLearningOutcomeIds: map[string][]string{"content": {"clo-1"}}})
+		assert.Nil(t, err)
+		assert.Len(t, repository.attachedItems, 2, "Expected an assessment item per criterion")
+		assert.Equal(t, 5.0, repository.attachedItems[0].MaxScore, "Expected the max score of an item to be the top level points")
+		assert.Equal(t, "delivery", *repository.attachedItems[0].RubricCriterionId)
+		assert.Empty(t, repository.attachedItems[0].CourseLearningOutcomes)
+		assert.Equal(t, "clo-1", repository.attachedItems[1].CourseLearningOutcomes[0].Id, "Expected the CLOs of the criterion on its item")
+	})
+
+	t.Run("TestAttach_Invalid", func(t *testing.T) {
+		rubricUseCase, repository := newRubricUseCase()
+
+		err := rubricUseCase.Attach("exam", entity.AttachRubricPayload{RubricId: "presentation", CourseLearningOutcomeIds: map[string][]string{"content": {"clo-other-course"}}})
+		assert.Equal(t, errs.ErrInvalidRubric, errorCode(err), "Expected a CLO of another course to be refused")
+
+		err = rubricUseCase.Attach("exam", entity.AttachRubricPayload{RubricId: "presentation", CourseLearningOutcomeIds: map[string][]string{"style": {"clo-1"}}})
+		assert.Equal(t, errs.ErrInvalidRubric, errorCode(err), "Expected a criterion of another rubric to be refused")
+
+		repository.rubric.ProgrammeId = "other"
+		err = rubricUseCase.Attach("exam", entity.AttachRubricPayload{RubricId: "presentation"})
+		assert.Equal(t, errs.ErrRubricNotFound, errorCode(err), "Expected a rubric of another programme to be refused")
+
+		repository.rubric.ProgrammeId = "programme"
+		repository.rubric.Criteria[0].Levels[1].Points = 7
+		err = rubricUseCase.Attach("exam", entity.AttachRubricPayload{RubricId: "presentation"})
+		assert.Equal(t, errs.ErrInvalidRubric, errorCode(err), "Expected the items adding up over the max score of the assignment to be refused")
+		assert.Nil(t, repository.attachedItems)
+	})
+
+	t.Run("TestSaveGrades", func(t *testing.T) {
+		criterionId := "delivery"
+		assessmentItemRepository := &stubAssessmentItemRepository{
+			items: []entity.AssessmentItem{{
+				Id:                "question-delivery",
+				AssignmentId:      "exam",
+				MaxScore:          5,
+				RubricCriterionId: &criterionId,
+				RubricCriterion:   &entity.RubricCriterion{Id: criterionId, Levels: []entity.RubricLevel{{Id: "delivery-poor", Points: 1}, {Id: "delivery-good", Points: 5}}},
+			}},
+		}
+		assignmentUseCase := &stubAssignmentUseCase{assignment: entity.Assignment{Id: "exam", MaxScore: 20, AssignmentGroupId: "midterm"}}
+		assessmentItemUseCase := NewAssessmentItemUseCase(assessmentItemRepository, assignmentUseCase, &stubCourseUseCase{}, nil, &stubEnrollmentUseCase{}, nil)
+		rubricUseCase := NewRubricUseCase(&stubRubricRepository{}, nil, assignmentUseCase, &stubCourseUseCase{}, nil, assessmentItemUseCase)
+		user := entity.User{Id: "head", Role: entity.UserRoleHeadOfCurriculum}
+
+		err := rubricUseCase.SaveGrades(user, "exam", []entity.StudentRubricGrade{{StudentId: "student", Levels: map[string]string{"delivery": "delivery-good"}}})
+		assert.Nil(t, err)
+		assert.Len(t, assessmentItemRepository.scores, 1)
+		assert.Equal(t, 5.0, assessmentItemRepository.scores[0].Score, "Expected the score to be the points of the level")
+		assert.Equal(t, "delivery-good", *assessmentItemRepository.scores[0].RubricLevelId)
+
+		err = rubricUseCase.SaveGrades(user, "exam", []entity.StudentRubricGrade{{StudentId: "student", Levels: map[string]string{"delivery": "content-good"}}})
+		assert.Equal(t, errs.ErrInvalidAssessmentItemScore, errorCode(err), "Expected a level of another criterion to be refused")
+
+		err = assessmentItemUseCase.SaveScores(user, "exam", []entity.StudentItemScores{{StudentId: "student", Scores: map[string]float64{"question-delivery": 3}}})
+		assert.Equal(t, errs.ErrInvalidAssessmentItemScore, errorCode(err), "Expected a rubric item to be scored only by a level")
+	})
+}
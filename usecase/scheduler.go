@@ -0,0 +1,55 @@
+package usecase
+
+import (
+	"os"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/team-inu/inu-backyard/entity"
+	errs "github.com/team-inu/inu-backyard/entity/error"
+	"github.com/team-inu/inu-backyard/internal/config"
+)
+
+const schedulerLeaseName = "scheduler"
+
+type schedulerUseCase struct {
+	leaseRepo        entity.SchedulerLeaseRepository
+	milestoneUseCase entity.MilestoneUseCase
+	config           config.SchedulerConfig
+	// identifies this replica while it holds the lease
+	holder string
+}
+
+// a zero interval falls back to 5 minutes, the lease outlives three intervals so a slow run keeps it
+func NewSchedulerUseCase(
+	leaseRepo entity.SchedulerLeaseRepository,
+	milestoneUseCase entity.MilestoneUseCase,
+	config config.SchedulerConfig,
+) entity.SchedulerUseCase {
+	if config.Interval <= 0 {
+		config.Interval = 300
+	}
+
+	hostname, _ := os.Hostname()
+
+	return &schedulerUseCase{
+		leaseRepo:        leaseRepo,
+		milestoneUseCase: milestoneUseCase,
+		config:           config,
+		holder:           hostname + "-" + ulid.Make().String(),
+	}
+}
+
+func (u schedulerUseCase) Run() (int, error) {
+	now := time.Now()
+	leaseDuration := 3 * time.Duration(u.config.Interval) * time.Second
+
+	isLeader, err := u.leaseRepo.Acquire(schedulerLeaseName, u.holder, now, now.Add(leaseDuration))
+	if err != nil {
+		return 0, errs.New(errs.ErrSchedulerLease, "cannot acquire scheduler lease", err)
+	} else if !isLeader {
+		return 0, nil
+	}
+
+	return u.milestoneUseCase.SendReminders()
+}
@@ -14,6 +14,9 @@ type scoreUseCase struct {
 	courseUseCase     entity.CourseUseCase
 	userUseCase       entity.UserUseCase
 	studentUseCase    entity.StudentUseCase
+	milestoneUseCase  entity.MilestoneUseCase
+
+	assessmentItemUseCase entity.AssessmentItemUseCase
 }
 
 func NewScoreUseCase(
@@ -23,6 +26,8 @@ func NewScoreUseCase(
 	courseUseCase entity.CourseUseCase,
 	userUseCase entity.UserUseCase,
 	studentUsecase entity.StudentUseCase,
+	milestoneUseCase entity.MilestoneUseCase,
+	assessmentItemUseCase entity.AssessmentItemUseCase,
 ) entity.ScoreUseCase {
 	return &scoreUseCase{
 		scoreRepo:         scoreRepo,
@@ -31,6 +36,9 @@ func NewScoreUseCase(
 		courseUseCase:     courseUseCase,
 		userUseCase:       userUseCase,
 		studentUseCase:    studentUsecase,
+		milestoneUseCase:  milestoneUseCase,
+
+		assessmentItemUseCase: assessmentItemUseCase,
 	}
 }
 
@@ -173,6 +181,16 @@ func (u scoreUseCase) CreateMany(userId string, assignmentId string, studentScor
 		}
 	}
 
+	err = u.checkScoreEntryOpen(*user, course.SemesterId)
+	if err != nil {
+		return err
+	}
+
+	err = u.checkNotScoredByItems(assignmentId)
+	if err != nil {
+		return err
+	}
+
 	for _, studentScore := range studentScores {
 		if *studentScore.Score > float64(assignment.MaxScore) {
 			return errs.New(errs.ErrCreateScore, "score %f of student id %s is more than max score of assignment (score: %d)", studentScore.Score, studentScore.StudentId, assignment.MaxScore)
@@ -233,6 +251,16 @@ func (u scoreUseCase) Update(user entity.User, scoreId string, score float64) er
 		return errs.New(errs.ErrUpdateScore, "no permission to update score")
 	}
 
+	err = u.checkScoreEntryOpenByAssignment(user, existScore.AssignmentId)
+	if err != nil {
+		return err
+	}
+
+	err = u.checkNotScoredByItems(existScore.AssignmentId)
+	if err != nil {
+		return err
+	}
+
 	err = u.scoreRepo.Update(scoreId, &entity.Score{
 		Score:        score,
 		StudentId:    existScore.StudentId,
@@ -258,6 +286,16 @@ func (u scoreUseCase) Delete(user entity.User, id string) error {
 		return errs.New(errs.ErrDeleteScore, "no permission to delete score")
 	}
 
+	err = u.checkScoreEntryOpenByAssignment(user, existScore.AssignmentId)
+	if err != nil {
+		return err
+	}
+
+	err = u.checkNotScoredByItems(existScore.AssignmentId)
+	if err != nil {
+		return err
+	}
+
 	err = u.scoreRepo.Delete(id)
 	if err != nil {
 		return errs.New(errs.ErrDeleteScore, "cannot delete score by id %s", id, err)
@@ -265,6 +303,59 @@ func (u scoreUseCase) Delete(user entity.User, id string) error {
 	return nil
 }
 
+// checkScoreEntryOpen refuses changes once score entry of the semester is locked, curriculum heads can still correct scores
+func (u scoreUseCase) checkScoreEntryOpen(user entity.User, semesterId string) error {
+	if user.IsRoles([]entity.UserRole{entity.UserRoleHeadOfCurriculum}) {
+		return nil
+	}
+
+	isLocked, err := u.milestoneUseCase.IsScoreEntryLocked(semesterId)
+	if err != nil {
+		return errs.New(errs.SameCode, "cannot check score entry lock of semester id %s", semesterId, err)
+	} else if isLocked {
+		return errs.New(errs.ErrScoreEntryLocked, "score entry of semester id %s is closed", semesterId)
+	}
+
+	return nil
+}
+
+func (u scoreUseCase) checkScoreEntryOpenByAssignment(user entity.User, assignmentId string) error {
+	assignment, err := u.assignmentUseCase.GetById(assignmentId)
+	if err != nil {
+		return errs.New(errs.SameCode, "cannot get assignment id %s to check score entry lock", assignmentId, err)
+	} else if assignment == nil {
+		return errs.New(errs.ErrAssignmentNotFound, "assignment id %s not found to check score entry lock", assignmentId)
+	}
+
+	assignmentGroup, err := u.assignmentUseCase.GetGroupByGroupId(assignment.AssignmentGroupId)
+	if err != nil {
+		return errs.New(errs.SameCode, "cannot get assignment group id %s to check score entry lock", assignment.AssignmentGroupId, err)
+	} else if assignmentGroup == nil {
+		return errs.New(errs.ErrAssignmentNotFound, "assignment group id %s not found to check score entry lock", assignment.AssignmentGroupId)
+	}
+
+	course, err := u.courseUseCase.GetById(assignmentGroup.CourseId)
+	if err != nil {
+		return errs.New(errs.SameCode, "cannot get course id %s to check score entry lock", assignmentGroup.CourseId, err)
+	} else if course == nil {
+		return errs.New(errs.ErrCourseNotFound, "course id %s not found to check score entry lock", assignmentGroup.CourseId)
+	}
+
+	return u.checkScoreEntryOpen(user, course.SemesterId)
+}
+
+// checkNotScoredByItems refuses direct changes to the scores of an assignment with assessment items, they are the totals of the item scores
+func (u scoreUseCase) checkNotScoredByItems(assignmentId string) error {
+	items, err := u.assessmentItemUseCase.GetByAssignmentId(assignmentId)
+	if err != nil {
+		return errs.New(errs.SameCode, "cannot get assessment items of assignment id %s", assignmentId, err)
+	} else if len(items) > 0 {
+		return errs.New(errs.ErrScoreByAssessmentItems, "scores of assignment id %s are saved by its assessment items", assignmentId)
+	}
+
+	return nil
+}
+
 func (u scoreUseCase) FilterSubmittedScoreStudents(assignmentId string, studentIds []string) ([]string, error) {
 	submittedScoreStudentIds, err := u.scoreRepo.FilterSubmittedScoreStudents(assignmentId, studentIds)
 	if err != nil {
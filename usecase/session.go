@@ -16,6 +16,13 @@ import (
 	"github.com/team-inu/inu-backyard/internal/config"
 )
 
+const (
+	// time given to enter the second factor after the password
+	pendingSessionMaxAge = 5 * time.Minute
+	// wrong second factors before the pending session is destroyed
+	maxTwoFactorAttempts = 5
+)
+
 type sessionUseCase struct {
 	sessionRepository entity.SessionRepository
 	config            config.AuthConfig
@@ -84,16 +91,7 @@ func (u sessionUseCase) Create(
 		return nil, errs.New(errs.ErrCreateSession, "cannot create session for user id %d", userId, err)
 	}
 
-	cookie := &fiber.Cookie{
-		Name:     u.config.Session.CookieName,
-		SameSite: "Strict",
-		Path:     "/",
-		Value:    signedId,
-		HTTPOnly: true,
-		Secure:   false,
-		Expires:  expiredAt,
-	}
-	return cookie, nil
+	return u.newCookie(signedId, expiredAt), nil
 }
 
 func (u sessionUseCase) Get(header string) (*entity.Session, error) {
@@ -137,5 +135,207 @@ func (u sessionUseCase) Validate(header string) (*entity.Session, error) {
 		return nil, errs.New(errs.ErrSessionExpired, "session expired")
 	}
 
+	if session.TwoFactorPending {
+		return nil, errs.New(errs.ErrInvalidSession, "session is waiting for two-factor verification")
+	}
+
+	return session, nil
+}
+
+func (u sessionUseCase) CreatePending(userId string, ipAddress string, userAgent string) (*entity.Session, string, error) {
+	if err := u.sessionRepository.DeleteDuplicates(userId, ipAddress, userAgent); err != nil {
+		return nil, "", errs.New(errs.ErrDupSession, "cannot delete previous session to create a pending session for user id %s", userId, err)
+	}
+
+	createdAt := time.Now()
+	session := &entity.Session{
+		Id:               uuid.NewString(),
+		UserId:           userId,
+		IpAddress:        ipAddress,
+		UserAgent:        userAgent,
+		ExpiredAt:        createdAt.Add(pendingSessionMaxAge),
+		CreatedAt:        createdAt,
+		TwoFactorPending: true,
+	}
+
+	err := u.sessionRepository.Create(session)
+	if err != nil {
+		return nil, "", errs.New(errs.ErrCreateSession, "cannot create pending session for user id %s", userId, err)
+	}
+
+	return session, u.Sign(session.Id), nil
+}
+
+func (u sessionUseCase) GetPending(challenge string) (*entity.Session, error) {
+	if !strings.Contains(challenge, ".") {
+		return nil, errs.New(errs.ErrInvalidSession, "invalid two-factor challenge")
+	}
+
+	session, err := u.Get(challenge)
+	if err != nil {
+		return nil, errs.New(errs.SameCode, "cannot get pending session", err)
+	} else if session == nil || !session.TwoFactorPending {
+		return nil, errs.New(errs.ErrInvalidSession, "two-factor challenge is invalid")
+	}
+
+	if !time.Now().Before(session.ExpiredAt) {
+		return nil, errs.New(errs.ErrSessionExpired, "two-factor challenge expired")
+	}
+
 	return session, nil
 }
+
+func (u sessionUseCase) FailPending(session entity.Session) error {
+	// the attempt is counted in the database so concurrent wrong codes cannot all pass the cap
+	counted, err := u.sessionRepository.IncreaseTwoFactorAttempts(session.Id, maxTwoFactorAttempts-1)
+	if err != nil {
+		return errs.New(errs.ErrUpdateSession, "cannot count two-factor attempt of session id %s", session.Id, err)
+	} else if counted {
+		return nil
+	}
+
+	err = u.sessionRepository.Delete(session.Id)
+	if err != nil {
+		return errs.New(errs.ErrDeleteSession, "cannot delete pending session id %s", session.Id, err)
+	}
+
+	return nil
+}
+
+// ActivatePending gives the session a new id as the challenge was sent outside of the cookie
+func (u sessionUseCase) ActivatePending(id string) (*fiber.Cookie, error) {
+	newId := uuid.NewString()
+	expiredAt := time.Now().Add(time.Duration(u.config.Session.MaxAge) * time.Second)
+
+	activated, err := u.sessionRepository.Activate(id, newId, expiredAt)
+	if err != nil {
+		return nil, errs.New(errs.ErrUpdateSession, "cannot activate session id %s", id, err)
+	} else if !activated {
+		return nil, errs.New(errs.ErrInvalidSession, "session id %s is not waiting for two-factor verification", id)
+	}
+
+	return u.newCookie(u.Sign(newId), expiredAt), nil
+}
+
+func (u sessionUseCase) Renew(session entity.Session) (*fiber.Cookie, error) {
+	if !u.config.Session.Sliding {
+		return nil, nil
+	}
+
+	maxAge := time.Duration(u.config.Session.MaxAge) * time.Second
+	if time.Until(session.ExpiredAt) > maxAge/2 {
+		return nil, nil
+	}
+
+	expiredAt := time.Now().Add(maxAge)
+
+	err := u.sessionRepository.UpdateExpiredAt(session.Id, expiredAt)
+	if err != nil {
+		return nil, errs.New(errs.ErrUpdateSession, "cannot renew session id %s", session.Id, err)
+	}
+
+	return u.newCookie(u.Sign(session.Id), expiredAt), nil
+}
+
+func (u sessionUseCase) GetActiveByUserId(userId string, currentId string) ([]entity.ActiveSession, error) {
+	sessions, err := u.sessionRepository.GetActiveByUserId(userId)
+	if err != nil {
+		return nil, errs.New(errs.ErrQuerySession, "cannot get sessions of user id %s", userId, err)
+	}
+
+	activeSessions := make([]entity.ActiveSession, 0, len(sessions))
+	for _, session := range sessions {
+		activeSessions = append(activeSessions, entity.ActiveSession{
+			Id:        session.Id,
+			IpAddress: session.IpAddress,
+			UserAgent: session.UserAgent,
+			Device:    describeDevice(session.UserAgent),
+			CreatedAt: session.CreatedAt,
+			ExpiredAt: session.ExpiredAt,
+			Current:   session.Id == currentId,
+		})
+	}
+
+	return activeSessions, nil
+}
+
+func (u sessionUseCase) Revoke(userId string, id string) (*fiber.Cookie, error) {
+	session, err := u.sessionRepository.Get(id)
+	if err != nil {
+		return nil, errs.New(errs.ErrGetSession, "cannot get session id %s to revoke", id, err)
+	} else if session == nil || session.UserId != userId {
+		return nil, errs.New(errs.ErrSessionNotFound, "session id %s not found", id)
+	}
+
+	cookie, err := u.Destroy(id)
+	if err != nil {
+		return nil, errs.New(errs.ErrDeleteSession, "cannot revoke session id %s", id, err)
+	}
+
+	return cookie, nil
+}
+
+func (u sessionUseCase) DestroyOthers(userId string, currentId string) error {
+	err := u.sessionRepository.DeleteByUserIdExcept(userId, currentId)
+	if err != nil {
+		return errs.New(errs.ErrDeleteSession, "cannot delete other sessions of user id %s", userId, err)
+	}
+
+	return nil
+}
+
+func (u sessionUseCase) DeleteExpired() (int64, error) {
+	deleted, err := u.sessionRepository.DeleteExpired(time.Now())
+	if err != nil {
+		return 0, errs.New(errs.ErrDeleteSession, "cannot delete expired sessions", err)
+	}
+
+	return deleted, nil
+}
+
+func (u sessionUseCase) newCookie(signedId string, expiredAt time.Time) *fiber.Cookie {
+	return &fiber.Cookie{
+		Name:     u.config.Session.CookieName,
+		SameSite: "Strict",
+		Path:     "/",
+		Value:    signedId,
+		HTTPOnly: true,
+		Secure:   false,
+		Expires:  expiredAt,
+	}
+}
+
+// describeDevice turns a user agent into a short label like "Chrome on Windows"
+func describeDevice(userAgent string) string {
+	browser := "Unknown browser"
+	for _, candidate := range []struct{ token, name string }{
+		// order matters, Edge and Opera user agents also contain Chrome and Safari
+		{"Edg/", "Edge"},
+		{"OPR/", "Opera"},
+		{"Firefox/", "Firefox"},
+		{"Chrome/", "Chrome"},
+		{"Safari/", "Safari"},
+	} {
+		if strings.Contains(userAgent, candidate.token) {
+			browser = candidate.name
+			break
+		}
+	}
+
+	system := "unknown system"
+	for _, candidate := range []struct{ token, name string }{
+		{"Android", "Android"},
+		{"iPhone", "iOS"},
+		{"iPad", "iPadOS"},
+		{"Windows", "Windows"},
+		{"Mac OS X", "macOS"},
+		{"Linux", "Linux"},
+	} {
+		if strings.Contains(userAgent, candidate.token) {
+			system = candidate.name
+			break
+		}
+	}
+
+	return browser + " on " + system
+}
@@ -0,0 +1,79 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/team-inu/inu-backyard/entity"
+	"github.com/team-inu/inu-backyard/internal/config"
+)
+
+func TestSession(t *testing.T) {
+	authConfig := config.AuthConfig{
+		Session: config.SessionConfig{MaxAge: 3600, Secret: "secret", Prefix: "$", CookieName: "inu_backyard", Sliding: true},
+	}
+
+	now := time.Now()
+	sessionRepository := &stubSessionRepository{sessions: []entity.Session{
+		{Id: "fresh", UserId: "user-1", UserAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 Chrome/124.0 Safari/537.36", ExpiredAt: now.Add(50 * time.Minute)},
+		{Id: "stale", UserId: "user-1", UserAgent: "Mozilla/5.0 (iPhone; CPU iPhone OS 17_4 like Mac OS X) AppleWebKit/605.1.15 Version/17.4 Mobile Safari/604.1", ExpiredAt: now.Add(10 * time.Minute)},
+		{Id: "pending", UserId: "user-1", ExpiredAt: now.Add(5 * time.Minute), TwoFactorPending: true},
+		{Id: "expired", UserId: "user-1", ExpiredAt: now.Add(-time.Minute)},
+		{Id: "other", UserId: "user-2", ExpiredAt: now.Add(50 * time.Minute)},
+	}}
+	sessionUseCase := NewSessionUseCase(sessionRepository, authConfig)
+
+	t.Run("TestRenew", func(t *testing.T) {
+		cookie, err := sessionUseCase.Renew(sessionRepository.sessions[0])
+		assert.Nil(t, err, "Expected no error while renewing, got %v", err)
+		assert.Nil(t, cookie, "Expected no renewal before half of max age")
+
+		cookie, err = sessionUseCase.Renew(sessionRepository.sessions[1])
+		assert.Nil(t, err, "Expected no error while renewing, got %v", err)
+		assert.NotNil(t, cookie, "Expected renewed cookie after half of max age")
+		assert.True(t, sessionRepository.sessions[1].ExpiredAt.After(now.Add(55*time.Minute)), "Expected expiry to be extended")
+	})
+
+	t.Run("TestRenewNotSliding", func(t *testing.T) {
+		notSlidingConfig := authConfig
+		notSlidingConfig.Session.Sliding = false
+
+		cookie, err := NewSessionUseCase(sessionRepository, notSlidingConfig).Renew(entity.Session{Id: "stale", ExpiredAt: now.Add(time.Minute)})
+		assert.Nil(t, err, "Expected no error while renewing, got %v", err)
+		assert.Nil(t, cookie, "Expected no renewal when sliding expiration is off")
+	})
+
+	t.Run("TestGetActiveByUserId", func(t *testing.T) {
+		sessions, err := sessionUseCase.GetActiveByUserId("user-1", "fresh")
+		assert.Nil(t, err, "Expected no error while getting sessions, got %v", err)
+		assert.Len(t, sessions, 2, "Expected pending and expired sessions to be hidden")
+		assert.True(t, sessions[0].Current, "Expected the request session to be marked")
+		assert.Equal(t, "Chrome on Windows", sessions[0].Device)
+		assert.Equal(t, "Safari on iOS", sessions[1].Device)
+	})
+
+	t.Run("TestRevokeOtherUserSession", func(t *testing.T) {
+		_, err := sessionUseCase.Revoke("user-1", "other")
+		assert.NotNil(t, err, "Expected session of another user not to be found")
+		assert.Len(t, sessionRepository.sessions, 5, "Expected no session to be deleted")
+	})
+
+	t.Run("TestDestroyOthers", func(t *testing.T) {
+		err := sessionUseCase.DestroyOthers("user-1", "fresh")
+		assert.Nil(t, err, "Expected no error while destroying other sessions, got %v", err)
+
+		ids := []string{}
+		for _, session := range sessionRepository.sessions {
+			ids = append(ids, session.Id)
+		}
+		assert.Equal(t, []string{"fresh", "other"}, ids, "Expected only the current session and other users to remain")
+	})
+
+	t.Run("TestRevoke", func(t *testing.T) {
+		cookie, err := sessionUseCase.Revoke("user-1", "fresh")
+		assert.Nil(t, err, "Expected no error while revoking, got %v", err)
+		assert.True(t, cookie.Expires.Before(now), "Expected an expired cookie")
+		assert.Len(t, sessionRepository.sessions, 1, "Expected the session to be deleted")
+	})
+}
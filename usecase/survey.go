@@ -1,20 +1,53 @@
 package usecase
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/oklog/ulid/v2"
 	"github.com/team-inu/inu-backyard/entity"
 	errs "github.com/team-inu/inu-backyard/entity/error"
+	"github.com/team-inu/inu-backyard/internal/config"
+	"go.uber.org/zap"
 )
 
+const defaultSurveyInvitationExpireDays = 30
+
 type surveyUseCase struct {
-	surveyRepo entity.SurveyRepository
+	surveyRepo              entity.SurveyRepository
+	programmeUseCase        entity.ProgrammeUseCase
+	graduatedStudentUseCase entity.GraduatedStudentUseCase
+	mailUseCase             entity.MailUseCase
+	courseUseCase           entity.CourseUseCase
+	notificationUseCase     entity.NotificationUseCase
+	clientConfig            config.ClientConfig
+	logger                  *zap.Logger
 }
 
-func NewSurveyUseCase(surveyRepo entity.SurveyRepository) entity.SurveyUseCase {
+func NewSurveyUseCase(
+	surveyRepo entity.SurveyRepository,
+	programmeUseCase entity.ProgrammeUseCase,
+	graduatedStudentUseCase entity.GraduatedStudentUseCase,
+	mailUseCase entity.MailUseCase,
+	courseUseCase entity.CourseUseCase,
+	notificationUseCase entity.NotificationUseCase,
+	clientConfig config.ClientConfig,
+	logger *zap.Logger,
+) entity.SurveyUseCase {
 	return &surveyUseCase{
-		surveyRepo: surveyRepo,
+		surveyRepo:              surveyRepo,
+		programmeUseCase:        programmeUseCase,
+		graduatedStudentUseCase: graduatedStudentUseCase,
+		mailUseCase:             mailUseCase,
+		courseUseCase:           courseUseCase,
+		notificationUseCase:     notificationUseCase,
+		clientConfig:            clientConfig,
+		logger:                  logger,
 	}
 }
 
@@ -35,6 +68,24 @@ func (u surveyUseCase) GetAll() ([]entity.Survey, error) {
 }
 
 func (u surveyUseCase) Create(request *entity.CreateSurveyRequest) error {
+	audience := request.Audience
+	if audience == "" {
+		audience = entity.SurveyAudienceCourse
+	}
+
+	if audience != entity.SurveyAudienceCourse {
+		if request.ProgrammeId == "" {
+			return errs.New(errs.ErrCreateSurvey, "%s survey requires a programme", audience)
+		}
+
+		programme, err := u.programmeUseCase.GetById(request.ProgrammeId)
+		if err != nil {
+			return errs.New(errs.SameCode, "cannot get programme id %s to create survey", request.ProgrammeId, err)
+		} else if programme == nil {
+			return errs.New(errs.ErrProgrammeNotFound, "programme id %s not found while creating survey", request.ProgrammeId)
+		}
+	}
+
 	for idx := range request.Questions {
 		request.Questions[idx].Id = ulid.Make().String()
 	}
@@ -44,6 +95,8 @@ func (u surveyUseCase) Create(request *entity.CreateSurveyRequest) error {
 		Title:       request.Title,
 		Description: request.Description,
 		CourseId:    request.CourseId,
+		ProgrammeId: request.ProgrammeId,
+		Audience:    audience,
 		IsComplete:  request.IsComplete,
 		Questions:   request.Questions,
 		CreateAt:    time.Now(),
@@ -137,6 +190,7 @@ func (u surveyUseCase) CreateQuestion(surveyId string, question *entity.CreateQu
 		POId:     question.POId,
 		PLOId:    question.PLOId,
 		SOId:     question.SOId,
+		PEOId:    question.PEOId,
 		SurveyId: surveyId,
 	}
 
@@ -162,6 +216,7 @@ func (u surveyUseCase) UpdateQuestion(questionId string, question *entity.Update
 		POId:     question.POId,
 		PLOId:    question.PLOId,
 		SOId:     question.SOId,
+		PEOId:    question.PEOId,
 	}
 
 	err = u.surveyRepo.UpdateQuestion(questionToUpdate)
@@ -196,3 +251,290 @@ func (u surveyUseCase) GetSurveysWithCourseAndOutcomes() ([]entity.SurveyWithCou
 	}
 	return surveys, nil
 }
+
+func (u surveyUseCase) GetByProgrammeId(programmeId string) ([]entity.Survey, error) {
+	surveys, err := u.surveyRepo.GetByProgrammeId(programmeId)
+	if err != nil {
+		return nil, errs.New(errs.ErrQuerySurvey, "cannot get surveys by programme id %s", programmeId, err)
+	}
+	return surveys, nil
+}
+
+func hashSurveyToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
+
+func generateSurveyToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func (u surveyUseCase) CreateInvitations(surveyId string, payload entity.CreateSurveyInvitationPayload) (*entity.CreateSurveyInvitationResult, error) {
+	survey, err := u.GetById(surveyId)
+	if err != nil {
+		return nil, errs.New(errs.SameCode, "cannot get survey id %s to invite", surveyId, err)
+	} else if survey == nil {
+		return nil, errs.New(errs.ErrSurveyNotFound, "survey id %s not found to invite", surveyId)
+	}
+
+	if survey.ProgrammeId == "" || survey.Audience == entity.SurveyAudienceCourse {
+		return nil, errs.New(errs.ErrCreateSurveyInvitation, "survey id %s is not a programme survey", surveyId)
+	}
+
+	recipients := payload.Recipients
+	graduatedStudentIdByEmail := make(map[string]string)
+	if payload.GraduationYear != "" {
+		if survey.Audience != entity.SurveyAudienceAlumni {
+			return nil, errs.New(errs.ErrCreateSurveyInvitation, "only alumni surveys can invite graduates")
+		}
+
+		graduatedStudents, err := u.graduatedStudentUseCase.GetByParams(survey.ProgrammeId, payload.GraduationYear)
+		if err != nil {
+			return nil, errs.New(errs.SameCode, "cannot get graduates of year %s to invite", payload.GraduationYear, err)
+		}
+
+		for _, graduatedStudent := range graduatedStudents {
+			if graduatedStudent.Student.Email == "" {
+				continue
+			}
+
+			graduatedStudentIdByEmail[graduatedStudent.Student.Email] = graduatedStudent.Id
+			recipients = append(recipients, entity.SurveyRecipientPayload{
+				Email: graduatedStudent.Student.Email,
+				Name:  strings.TrimSpace(graduatedStudent.Student.FirstNameEN + " " + graduatedStudent.Student.LastNameEN),
+			})
+		}
+	}
+
+	if len(recipients) == 0 {
+		return nil, errs.New(errs.ErrCreateSurveyInvitation, "no recipients to invite")
+	}
+
+	// an email that already holds an open invitation is not invited again
+	existInvitations, err := u.surveyRepo.GetInvitationsBySurveyId(surveyId)
+	if err != nil {
+		return nil, errs.New(errs.ErrQuerySurveyInvitation, "cannot get invitations of survey id %s", surveyId, err)
+	}
+
+	now := time.Now()
+	invitedEmails := make(map[string]bool)
+	for _, invitation := range existInvitations {
+		if invitation.RespondedAt == nil && invitation.ExpiresAt.After(now) {
+			invitedEmails[strings.ToLower(invitation.Email)] = true
+		}
+	}
+
+	expireDays := payload.ExpiresInDays
+	if expireDays == 0 {
+		expireDays = defaultSurveyInvitationExpireDays
+	}
+	expiresAt := now.AddDate(0, 0, expireDays)
+
+	type invitationMail struct {
+		to    string
+		name  string
+		token string
+	}
+
+	invitations := []entity.SurveyInvitation{}
+	mails := []invitationMail{}
+	for _, recipient := range recipients {
+		email := strings.ToLower(strings.TrimSpace(recipient.Email))
+		if invitedEmails[email] {
+			continue
+		}
+		invitedEmails[email] = true
+
+		token, err := generateSurveyToken()
+		if err != nil {
+			return nil, errs.New(errs.ErrCreateSurveyInvitation, "cannot generate survey token", err)
+		}
+
+		invitations = append(invitations, entity.SurveyInvitation{
+			Id:                 ulid.Make().String(),
+			SurveyId:           surveyId,
+			Email:              email,
+			Name:               recipient.Name,
+			GraduatedStudentId: graduatedStudentIdByEmail[recipient.Email],
+			TokenHash:          hashSurveyToken(token),
+			ExpiresAt:          expiresAt,
+		})
+		mails = append(mails, invitationMail{to: email, name: recipient.Name, token: token})
+	}
+
+	result := &entity.CreateSurveyInvitationResult{InvitedEmails: []string{}}
+	if len(invitations) == 0 {
+		return result, nil
+	}
+
+	err = u.surveyRepo.CreateInvitations(invitations)
+	if err != nil {
+		return nil, errs.New(errs.ErrCreateSurveyInvitation, "cannot create invitations of survey id %s", surveyId, err)
+	}
+
+	// the invitations are saved, an email that cannot be queued is left out of the invited emails
+	baseUrl := strings.TrimRight(u.clientConfig.BaseUrl, "/")
+	for _, m := range mails {
+		link := fmt.Sprintf("%s/surveys/respond?token=%s", baseUrl, url.QueryEscape(m.token))
+		err = u.mailUseCase.SendSurveyInvitationEmail(m.to, m.name, survey.Title, link, expiresAt)
+		if err != nil {
+			u.logger.Error("Cannot send survey invitation", zap.String("surveyId", surveyId), zap.String("email", m.to), zap.Error(err))
+			continue
+		}
+
+		result.InvitedEmails = append(result.InvitedEmails, m.to)
+	}
+
+	return result, nil
+}
+
+func (u surveyUseCase) GetInvitationsBySurveyId(surveyId string) ([]entity.SurveyInvitation, error) {
+	invitations, err := u.surveyRepo.GetInvitationsBySurveyId(surveyId)
+	if err != nil {
+		return nil, errs.New(errs.ErrQuerySurveyInvitation, "cannot get invitations of survey id %s", surveyId, err)
+	}
+	return invitations, nil
+}
+
+func (u surveyUseCase) getOpenInvitation(token string) (*entity.SurveyInvitation, error) {
+	invitation, err := u.surveyRepo.GetInvitationByTokenHash(hashSurveyToken(token))
+	if err != nil {
+		return nil, errs.New(errs.ErrQuerySurveyInvitation, "cannot get survey invitation", err)
+	} else if invitation == nil {
+		return nil, errs.New(errs.ErrSurveyInvitationNotFound, "survey invitation not found")
+	}
+
+	if invitation.RespondedAt != nil {
+		return nil, errs.New(errs.ErrSurveyInvitationUsed, "survey invitation has already been used")
+	}
+
+	if time.Now().After(invitation.ExpiresAt) {
+		return nil, errs.New(errs.ErrSurveyInvitationExpired, "survey invitation has expired")
+	}
+
+	return invitation, nil
+}
+
+func (u surveyUseCase) GetFormByToken(token string) (*entity.SurveyForm, error) {
+	invitation, err := u.getOpenInvitation(token)
+	if err != nil {
+		return nil, err
+	}
+
+	survey, err := u.GetById(invitation.SurveyId)
+	if err != nil {
+		return nil, errs.New(errs.SameCode, "cannot get survey of invitation", err)
+	} else if survey == nil {
+		return nil, errs.New(errs.ErrSurveyNotFound, "survey of invitation not found")
+	}
+
+	questions := make([]entity.SurveyFormQuestion, 0, len(survey.Questions))
+	for _, question := range survey.Questions {
+		questions = append(questions, entity.SurveyFormQuestion{
+			Id:       question.Id,
+			Question: question.Question,
+		})
+	}
+
+	return &entity.SurveyForm{
+		SurveyId:    survey.Id,
+		Title:       survey.Title,
+		Description: survey.Description,
+		Audience:    survey.Audience,
+		ExpiresAt:   invitation.ExpiresAt,
+		Questions:   questions,
+	}, nil
+}
+
+func (u surveyUseCase) SubmitResponse(token string, payload entity.SubmitSurveyResponsePayload) error {
+	invitation, err := u.getOpenInvitation(token)
+	if err != nil {
+		return err
+	}
+
+	questions, err := u.GetQuestionsBySurveyId(invitation.SurveyId)
+	if err != nil {
+		return errs.New(errs.SameCode, "cannot get questions of survey id %s", invitation.SurveyId, err)
+	}
+
+	isSurveyQuestion := make(map[string]bool, len(questions))
+	for _, question := range questions {
+		isSurveyQuestion[question.Id] = true
+	}
+
+	answered := make(map[string]bool, len(payload.Answers))
+	scores := make([]entity.QScore, 0, len(payload.Answers))
+	for _, answer := range payload.Answers {
+		if !isSurveyQuestion[answer.QuestionId] {
+			return errs.New(errs.ErrSubmitSurveyResponse, "question id %s is not in this survey", answer.QuestionId)
+		}
+		if answered[answer.QuestionId] {
+			return errs.New(errs.ErrSubmitSurveyResponse, "question id %s is answered more than once", answer.QuestionId)
+		}
+		answered[answer.QuestionId] = true
+
+		scores = append(scores, entity.QScore{
+			Id:         ulid.Make().String(),
+			Score:      answer.Score,
+			QuestionId: answer.QuestionId,
+		})
+	}
+
+	accepted, err := u.surveyRepo.SubmitResponse(invitation.Id, scores)
+	if err != nil {
+		return errs.New(errs.ErrSubmitSurveyResponse, "cannot submit survey response", err)
+	} else if !accepted {
+		return errs.New(errs.ErrSurveyInvitationUsed, "survey invitation has already been used")
+	}
+
+	err = u.notifyResponse(invitation.SurveyId)
+	if err != nil {
+		u.logger.Error("Cannot notify survey response", zap.String("surveyId", invitation.SurveyId), zap.Error(err))
+	}
+
+	return nil
+}
+
+// notifyResponse tells the lecturers of a course survey, or the curriculum heads of a programme survey
+func (u surveyUseCase) notifyResponse(surveyId string) error {
+	survey, err := u.GetById(surveyId)
+	if err != nil {
+		return err
+	} else if survey == nil {
+		return nil
+	}
+
+	event := entity.NotificationEvent{
+		Type:        entity.NotificationTypeSurveyResponse,
+		Title:       fmt.Sprintf("New response to %s", survey.Title),
+		Message:     fmt.Sprintf("A respondent answered the survey %s.", survey.Title),
+		Link:        "/surveys/" + survey.Id,
+		ReferenceId: survey.Id,
+	}
+
+	if survey.CourseId == "" {
+		err = u.notificationUseCase.NotifyRole(entity.UserRoleHeadOfCurriculum, event)
+		if err != nil {
+			return errs.New(errs.SameCode, "cannot notify response of survey id %s", survey.Id, err)
+		}
+		return nil
+	}
+
+	course, err := u.courseUseCase.GetById(survey.CourseId)
+	if err != nil {
+		return errs.New(errs.SameCode, "cannot get course id %s to notify survey response", survey.CourseId, err)
+	} else if course == nil {
+		return nil
+	}
+
+	err = u.notificationUseCase.Notify(courseLecturerIds(course, ""), event)
+	if err != nil {
+		return errs.New(errs.SameCode, "cannot notify response of survey id %s", survey.Id, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,276 @@
+package usecase
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"github.com/team-inu/inu-backyard/entity"
+	errs "github.com/team-inu/inu-backyard/entity/error"
+	"github.com/team-inu/inu-backyard/internal/config"
+	"github.com/team-inu/inu-backyard/internal/utils/totp"
+)
+
+const recoveryCodeCount = 10
+
+type twoFactorUseCase struct {
+	twoFactorRepo entity.TwoFactorRepository
+	userUseCase   entity.UserUseCase
+	config        config.TwoFactorConfig
+}
+
+func NewTwoFactorUseCase(
+	twoFactorRepo entity.TwoFactorRepository,
+	userUseCase entity.UserUseCase,
+	config config.TwoFactorConfig,
+) entity.TwoFactorUseCase {
+	return &twoFactorUseCase{
+		twoFactorRepo: twoFactorRepo,
+		userUseCase:   userUseCase,
+		config:        config,
+	}
+}
+
+func (u twoFactorUseCase) BeginEnrollment(user entity.User) (*entity.TotpEnrollment, error) {
+	if user.TotpEnabled {
+		return nil, errs.New(errs.ErrTwoFactorAlreadyEnabled, "two-factor of user id %s is already enabled", user.Id)
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, errs.New(errs.ErrUpdateTwoFactor, "cannot generate totp secret", err)
+	}
+
+	err = u.twoFactorRepo.UpdateTotp(user.Id, secret, false)
+	if err != nil {
+		return nil, errs.New(errs.ErrUpdateTwoFactor, "cannot save totp secret of user id %s", user.Id, err)
+	}
+
+	issuer := u.config.Issuer
+	if issuer == "" {
+		issuer = "inu-backyard"
+	}
+
+	return &entity.TotpEnrollment{
+		Secret:          secret,
+		ProvisioningUri: totp.ProvisioningUri(issuer, user.Email, secret),
+	}, nil
+}
+
+func (u twoFactorUseCase) ConfirmEnrollment(user entity.User, code string) ([]string, error) {
+	if user.TotpEnabled {
+		return nil, errs.New(errs.ErrTwoFactorAlreadyEnabled, "two-factor of user id %s is already enabled", user.Id)
+	} else if user.TotpSecret == "" {
+		return nil, errs.New(errs.ErrTwoFactorNotEnrolled, "user id %s has not begun two-factor enrollment", user.Id)
+	}
+
+	step, err := totp.Validate(user.TotpSecret, code, time.Now())
+	if err != nil {
+		return nil, errs.New(errs.ErrTwoFactorInvalidCode, "cannot validate totp code", err)
+	} else if step < 0 {
+		return nil, errs.New(errs.ErrTwoFactorInvalidCode, "totp code is incorrect")
+	}
+
+	err = u.twoFactorRepo.UpdateTotp(user.Id, user.TotpSecret, true)
+	if err != nil {
+		return nil, errs.New(errs.ErrUpdateTwoFactor, "cannot enable two-factor of user id %s", user.Id, err)
+	}
+
+	isSaved, err := u.twoFactorRepo.UpdateTotpLastUsedStep(user.Id, step)
+	if err != nil {
+		return nil, errs.New(errs.ErrUpdateTwoFactor, "cannot save totp step of user id %s", user.Id, err)
+	} else if !isSaved {
+		return nil, errs.New(errs.ErrTwoFactorInvalidCode, "totp code is already used")
+	}
+
+	return u.replaceRecoveryCodes(user.Id)
+}
+
+func (u twoFactorUseCase) Disable(user entity.User, code string) error {
+	isRequired, err := u.IsRequired(user)
+	if err != nil {
+		return errs.New(errs.SameCode, "cannot check two-factor policy of user id %s", user.Id, err)
+	} else if isRequired {
+		return errs.New(errs.ErrTwoFactorRequired, "two-factor is required for the role of user id %s", user.Id)
+	}
+
+	err = u.Verify(user, code)
+	if err != nil {
+		return errs.New(errs.SameCode, "cannot verify code to disable two-factor", err)
+	}
+
+	return u.Reset(user.Id)
+}
+
+func (u twoFactorUseCase) Reset(userId string) error {
+	user, err := u.userUseCase.GetById(userId)
+	if err != nil {
+		return errs.New(errs.SameCode, "cannot get user id %s to reset two-factor", userId, err)
+	} else if user == nil {
+		return errs.New(errs.ErrUserNotFound, "user id %s not found to reset two-factor", userId)
+	}
+
+	err = u.twoFactorRepo.UpdateTotp(userId, "", false)
+	if err != nil {
+		return errs.New(errs.ErrUpdateTwoFactor, "cannot disable two-factor of user id %s", userId, err)
+	}
+
+	err = u.twoFactorRepo.ReplaceRecoveryCodes(userId, nil)
+	if err != nil {
+		return errs.New(errs.ErrUpdateTwoFactor, "cannot delete recovery codes of user id %s", userId, err)
+	}
+
+	return nil
+}
+
+func (u twoFactorUseCase) RegenerateRecoveryCodes(user entity.User, code string) ([]string, error) {
+	err := u.Verify(user, code)
+	if err != nil {
+		return nil, errs.New(errs.SameCode, "cannot verify code to regenerate recovery codes", err)
+	}
+
+	return u.replaceRecoveryCodes(user.Id)
+}
+
+func (u twoFactorUseCase) GetStatus(user entity.User) (*entity.TwoFactorStatus, error) {
+	isRequired, err := u.IsRequired(user)
+	if err != nil {
+		return nil, errs.New(errs.SameCode, "cannot check two-factor policy of user id %s", user.Id, err)
+	}
+
+	remaining, err := u.twoFactorRepo.CountUnusedRecoveryCodes(user.Id)
+	if err != nil {
+		return nil, errs.New(errs.ErrQueryTwoFactor, "cannot count recovery codes of user id %s", user.Id, err)
+	}
+
+	return &entity.TwoFactorStatus{
+		Enabled:                user.TotpEnabled,
+		Required:               isRequired,
+		RemainingRecoveryCodes: remaining,
+	}, nil
+}
+
+func (u twoFactorUseCase) Verify(user entity.User, code string) error {
+	if !user.TotpEnabled {
+		return errs.New(errs.ErrTwoFactorNotEnrolled, "two-factor of user id %s is not enabled", user.Id)
+	}
+
+	code = strings.TrimSpace(code)
+
+	if len(code) == totp.Digits {
+		step, err := totp.Validate(user.TotpSecret, code, time.Now())
+		if err != nil {
+			return errs.New(errs.ErrTwoFactorInvalidCode, "cannot validate totp code", err)
+		} else if step < 0 || step <= user.TotpLastUsedStep {
+			return errs.New(errs.ErrTwoFactorInvalidCode, "totp code is incorrect")
+		}
+
+		// the step is saved only when still unused so concurrent requests cannot both use the code
+		isSaved, err := u.twoFactorRepo.UpdateTotpLastUsedStep(user.Id, step)
+		if err != nil {
+			return errs.New(errs.ErrUpdateTwoFactor, "cannot save totp step of user id %s", user.Id, err)
+		} else if !isSaved {
+			return errs.New(errs.ErrTwoFactorInvalidCode, "totp code is already used")
+		}
+
+		return nil
+	}
+
+	isUsed, err := u.twoFactorRepo.UseRecoveryCode(user.Id, hashRecoveryCode(code))
+	if err != nil {
+		return errs.New(errs.ErrUpdateTwoFactor, "cannot use recovery code of user id %s", user.Id, err)
+	} else if !isUsed {
+		return errs.New(errs.ErrTwoFactorInvalidCode, "recovery code is incorrect or already used")
+	}
+
+	return nil
+}
+
+func (u twoFactorUseCase) IsRequired(user entity.User) (bool, error) {
+	roles, err := u.GetPolicy()
+	if err != nil {
+		return false, err
+	}
+
+	return user.IsRoles(roles), nil
+}
+
+func (u twoFactorUseCase) GetPolicy() ([]entity.UserRole, error) {
+	policies, err := u.twoFactorRepo.GetPolicies()
+	if err != nil {
+		return nil, errs.New(errs.ErrQueryTwoFactor, "cannot get two-factor policies", err)
+	}
+
+	roles := make([]entity.UserRole, 0, len(policies))
+	for _, policy := range policies {
+		roles = append(roles, policy.Role)
+	}
+
+	return roles, nil
+}
+
+func (u twoFactorUseCase) UpdatePolicy(roles []entity.UserRole) error {
+	policies := []entity.TwoFactorPolicy{}
+	existed := map[entity.UserRole]bool{}
+	for _, role := range roles {
+		if existed[role] {
+			continue
+		}
+		existed[role] = true
+		policies = append(policies, entity.TwoFactorPolicy{Role: role})
+	}
+
+	err := u.twoFactorRepo.ReplacePolicies(policies)
+	if err != nil {
+		return errs.New(errs.ErrUpdateTwoFactor, "cannot update two-factor policies", err)
+	}
+
+	return nil
+}
+
+func (u twoFactorUseCase) replaceRecoveryCodes(userId string) ([]string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	recoveryCodes := make([]entity.RecoveryCode, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		code, err := generateRecoveryCode()
+		if err != nil {
+			return nil, errs.New(errs.ErrUpdateTwoFactor, "cannot generate recovery code", err)
+		}
+
+		codes = append(codes, code)
+		recoveryCodes = append(recoveryCodes, entity.RecoveryCode{
+			Id:       ulid.Make().String(),
+			UserId:   userId,
+			CodeHash: hashRecoveryCode(code),
+		})
+	}
+
+	err := u.twoFactorRepo.ReplaceRecoveryCodes(userId, recoveryCodes)
+	if err != nil {
+		return nil, errs.New(errs.ErrUpdateTwoFactor, "cannot save recovery codes of user id %s", userId, err)
+	}
+
+	return codes, nil
+}
+
+// generateRecoveryCode returns a code like "k3j9x-2mzqa"
+func generateRecoveryCode() (string, error) {
+	b := make([]byte, 7)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	code := strings.ToLower(base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(b))[:10]
+	return code[:5] + "-" + code[5:], nil
+}
+
+// hashRecoveryCode ignores case, spaces and dashes the user may type differently
+func hashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	hash := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(hash[:])
+}
@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
@@ -14,15 +15,16 @@ import (
 )
 
 type userUseCase struct {
-	userRepo entity.UserRepository
+	userRepo        entity.UserRepository
+	passwordUseCase entity.PasswordUseCase
 	// courseUseCase entity.CourseUseCase
 	// scoreUseCase  entity.ScoreUseCase
 }
 
-func NewUserUseCase(userRepo entity.UserRepository) entity.UserUseCase {
+func NewUserUseCase(userRepo entity.UserRepository, passwordUseCase entity.PasswordUseCase) entity.UserUseCase {
 	// func NewUserUseCase(userRepo entity.UserRepository, courseUseCase entity.CourseUseCase, scoreUseCase entity.ScoreUseCase) entity.UserUseCase {
 	// return &userUseCase{userRepo: userRepo, courseUseCase: courseUseCase, scoreUseCase: scoreUseCase}
-	return &userUseCase{userRepo: userRepo}
+	return &userUseCase{userRepo: userRepo, passwordUseCase: passwordUseCase}
 }
 
 func (u userUseCase) GetAll(query string, pageIndex string, pageSize string) (*entity.Pagination, error) {
@@ -77,8 +79,17 @@ func (u userUseCase) GetByParams(params *entity.User, limit int, offset int) ([]
 }
 
 func (u userUseCase) Create(payload entity.CreateUserPayload) error {
+	var passwordChangedAt *time.Time
 	if payload.Password == "" {
 		payload.Password = uuid.New().String()
+	} else {
+		err := u.passwordUseCase.Validate(payload.Password, nil)
+		if err != nil {
+			return errs.New(errs.SameCode, "cannot validate password of user %s", payload.Email, err)
+		}
+
+		now := time.Now()
+		passwordChangedAt = &now
 	}
 
 	hashPassword, err := utils.HashPassword(payload.Password)
@@ -111,6 +122,7 @@ func (u userUseCase) Create(payload entity.CreateUserPayload) error {
 		DegreeTH:           payload.DegreeTH,
 		DegreeEN:           payload.DegreeEN,
 		Tel:                payload.Tel,
+		PasswordChangedAt:  passwordChangedAt,
 	}
 
 	if !user.IsRoles(entity.Roles) {
@@ -126,14 +138,26 @@ func (u userUseCase) Create(payload entity.CreateUserPayload) error {
 }
 
 func (u userUseCase) CreateMany(users []entity.User) error {
-	//encrypt password
+	// bulk created users share passwords through other channels, they must choose their own on the first sign in
+	now := time.Now()
 	for i := range users {
-		bcryptPassword, err := bcrypt.GenerateFromPassword([]byte((users)[i].Password), bcrypt.DefaultCost)
+		if users[i].Password == "" {
+			users[i].Password = uuid.New().String()
+		} else {
+			err := u.passwordUseCase.Validate(users[i].Password, nil)
+			if err != nil {
+				return errs.New(errs.SameCode, "cannot validate password of user %s", users[i].Email, err)
+			}
+		}
+
+		bcryptPassword, err := bcrypt.GenerateFromPassword([]byte(users[i].Password), bcrypt.DefaultCost)
 		if err != nil {
 			return errs.New(errs.ErrCreateUser, "cannot create user", err)
 		}
 		users[i].Id = ulid.Make().String()
-		(users)[i].Password = string(bcryptPassword)
+		users[i].Password = string(bcryptPassword)
+		users[i].PasswordChangedAt = &now
+		users[i].MustChangePassword = true
 	}
 
 	err := u.userRepo.CreateMany(users)